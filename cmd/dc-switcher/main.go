@@ -8,13 +8,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/api"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/cache"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/healthcheck"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/logger"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/notify"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/util"
 	"github.com/kirychukyurii/webitel-dc-switcher/pkg/httpserver"
 )
 
@@ -35,12 +40,43 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Rebuild the logger with the configured level and format
+	log, err = logger.NewFromConfig(cfg.Logging.Level, cfg.Logging.Format)
+	if err != nil {
+		// Unreachable after config validation, kept as a safety net
+		logger.New().Error("failed to create logger",
+			"error", err.Error(),
+		)
+		os.Exit(1)
+	}
+
 	log.Info("configuration loaded",
 		"clusters", len(cfg.Clusters),
 	)
 
 	// Create cache
-	appCache := cache.New(cfg.Cache.TTL)
+	appCache := cache.New(cfg.Cache.TTL, cfg.Cache.CleanupInterval)
+
+	// Expose cache effectiveness on /metrics
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name: "dc_switcher_cache_hits_total",
+		Help: "Total number of cache lookups that found a value.",
+	}, func() float64 { return float64(appCache.Stats().Hits) })
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name: "dc_switcher_cache_misses_total",
+		Help: "Total number of cache lookups that found nothing.",
+	}, func() float64 { return float64(appCache.Stats().Misses) })
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name: "dc_switcher_cache_type_mismatches_total",
+		Help: "Total number of cached values found with an unexpected type.",
+	}, func() float64 { return float64(appCache.Stats().TypeMismatches) })
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "dc_switcher_cache_items",
+		Help: "Number of items currently stored in the cache.",
+	}, func() float64 { return float64(appCache.Stats().Items) })
+
+	// Create failover event notifier (no-op when no webhook is configured)
+	notifier := notify.NewWebhookNotifier(cfg.Notifications.WebhookURL, cfg.Notifications.Timeout, log)
 
 	// Create Nomad repository
 	repo, err := repository.NewNomadRepository(cfg, log)
@@ -56,7 +92,7 @@ func main() {
 	)
 
 	// Create etcd repository
-	etcdRepo, err := repository.NewEtcdRepository(cfg.Etcd, log)
+	etcdRepo, err := repository.NewEtcdRepository(cfg.Etcd, cfg.Heartbeat.StaleThreshold, log)
 	if err != nil {
 		log.Error("failed to create etcd repository",
 			"error", err.Error(),
@@ -70,15 +106,7 @@ func main() {
 	)
 
 	// Create service
-	svc := service.NewDatacenterService(
-		repo,
-		etcdRepo,
-		appCache,
-		cfg.Cache.TTL,
-		cfg.MyDatacenter,
-		cfg.Heartbeat,
-		log,
-	)
+	svc := service.NewDatacenterService(repo, etcdRepo, appCache, service.NewConfig(cfg), notifier, log)
 
 	// Perform startup reconciliation with etcd
 	log.Info("performing startup reconciliation with etcd")
@@ -99,18 +127,21 @@ func main() {
 
 	if cfg.SkipUnhealthyClusters {
 		go func() {
-			ticker := time.NewTicker(cfg.ClusterRetryInterval)
-			defer ticker.Stop()
+			jitter := cfg.Heartbeat.BackgroundJitterPercent()
+			timer := time.NewTimer(util.Jitter(cfg.ClusterRetryInterval, jitter))
+			defer timer.Stop()
 
 			log.Info("starting cluster retry checker",
-				"interval", cfg.ClusterRetryInterval)
+				"interval", cfg.ClusterRetryInterval,
+				"jitter_percent", jitter)
 
 			for {
 				select {
 				case <-ctx.Done():
 					log.Info("stopping cluster retry checker")
 					return
-				case <-ticker.C:
+				case <-timer.C:
+					timer.Reset(util.Jitter(cfg.ClusterRetryInterval, jitter))
 					added := repo.RetryUnavailableClusters()
 					if added > 0 {
 						log.Info("added previously unavailable clusters",
@@ -121,43 +152,73 @@ func main() {
 		}()
 	}
 
+	// Reload the cluster list on SIGHUP without touching etcd state or the heartbeat
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				log.Info("received reload signal, reloading clusters",
+					"config", *configPath)
+
+				newCfg, err := config.Load(*configPath)
+				if err != nil {
+					log.Error("failed to reload configuration, keeping current clusters",
+						"error", err.Error())
+					continue
+				}
+
+				// Only clusters are reloaded, other settings still require a restart
+				repo.ReloadClusters(newCfg)
+			}
+		}
+	}()
+
 	// Create and start health checker
 
-	healthChecker := healthcheck.NewChecker(&cfg.HealthCheck, svc, log)
+	healthChecker := healthcheck.NewChecker(&cfg.HealthCheck, cfg.Failover, svc, notifier, cfg.Heartbeat.BackgroundJitterPercent(), log)
 	svc.SetHealthChecker(healthChecker) // Link service with health checker for region change notifications
 	healthChecker.Start(ctx)
 
 	// Create HTTP handler
-	handler := api.NewHandler(svc, cfg.Server.BasePath, log)
+	handler := api.NewHandler(svc, healthChecker, api.NewConfig(cfg), log)
 
 	// Setup signal handling for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
+	// Load HTTPS certificates (nil serves plain HTTP)
+	serverTLS, err := util.LoadServerTLSConfig(cfg.Server.TLS)
+	if err != nil {
+		log.Error("failed to load server TLS config",
+			"error", err.Error(),
+		)
+		os.Exit(1)
+	}
+
 	// Create HTTP server
 	srv := httpserver.New(
 		cfg.Server.Addr,
 		handler.Router(),
-		cfg.Server.ReadTimeout,
-		cfg.Server.WriteTimeout,
+		httpserver.Timeouts{
+			Read:       cfg.Server.ReadTimeout,
+			ReadHeader: cfg.Server.ReadHeaderTimeout,
+			Write:      cfg.Server.WriteTimeout,
+			Idle:       cfg.Server.IdleTimeout,
+		},
+		serverTLS,
 		log,
 	)
 
 	log.Info("starting dc-switcher service")
 
-	// Start server in goroutine
-	serverErrors := make(chan error, 1)
-	go func() {
-		// Use internal server start method (without signal handling)
-		log.Info("starting http server",
-			"addr", cfg.Server.Addr,
-		)
-		if err := srv.Run(); err != nil {
-			serverErrors <- err
-		}
-	}()
+	serverErrors := srv.Start()
 
 	// Wait for shutdown signal or server error
+	signalled := false
 	select {
 	case err := <-serverErrors:
 		log.Error("server error",
@@ -167,12 +228,42 @@ func main() {
 		log.Info("received shutdown signal",
 			"signal", sig.String(),
 		)
+		signalled = true
 	}
 
-	// Graceful shutdown
+	// Graceful shutdown: let an in-progress activation finish before stopping anything it relies on
+	log.Info("waiting for in-progress activations",
+		"timeout", cfg.Server.ShutdownTimeout)
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	if err := svc.Shutdown(shutdownCtx); err != nil {
+		log.Error("activation still running at shutdown, it may be left half-applied",
+			"error", err.Error())
+	}
+	cancelShutdown()
+
+	log.Info("shutting down http server",
+		"timeout", cfg.Server.GracefulTimeout)
+	gracefulCtx, cancelGraceful := context.WithTimeout(context.Background(), cfg.Server.GracefulTimeout)
+	if err := srv.Shutdown(gracefulCtx); err != nil {
+		log.Error("failed to shut down http server",
+			"error", err.Error())
+	}
+	cancelGraceful()
+
 	log.Info("shutting down heartbeat updater")
 	svc.StopHeartbeat()
 
+	// Only a signalled shutdown hands over the active datacenter; after a crash or a server error
+	// the key stays until its lease expires
+	if signalled && cfg.Shutdown.RelinquishOnExit {
+		relinquishCtx, cancelRelinquish := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		if err := svc.RelinquishActive(relinquishCtx); err != nil {
+			log.Error("failed to relinquish active datacenter",
+				"error", err.Error())
+		}
+		cancelRelinquish()
+	}
+
 	log.Info("shutting down health checker")
 	cancel() // Cancel context for health checker
 	healthChecker.Stop()
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// activationService returns a mock service whose activations succeed and record the requested dry run
+func activationService(dryRun *bool, target *string) *mockService {
+	return &mockService{
+		activateDatacenter: func(_ context.Context, dc string, d, exclusive bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+			*dryRun, *target = d, dc
+			return &model.ActivationResult{Activated: dc, DryRun: d, Exclusive: exclusive, Errors: []string{}}, nil
+		},
+		activateRegion: func(_ context.Context, region string, d bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+			*dryRun, *target = d, region
+			return &model.ActivationResult{Activated: region, DryRun: d, Errors: []string{}}, nil
+		},
+	}
+}
+
+func TestActivationHandlerDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantTarget string
+		wantDryRun bool
+	}{
+		{name: "datacenter", target: "/api/datacenters/dc1/activate", wantTarget: "dc1"},
+		{name: "datacenter dry run query", target: "/api/datacenters/dc1/activate?dry_run=true", wantTarget: "dc1", wantDryRun: true},
+		{name: "datacenter dry run body", target: "/api/datacenters/dc1/activate", body: `{"dry_run":true}`, wantTarget: "dc1", wantDryRun: true},
+		{name: "region", target: "/api/regions/eu/activate", wantTarget: "eu"},
+		{name: "region dry run query", target: "/api/regions/eu/activate?dry_run=true", wantTarget: "eu", wantDryRun: true},
+		{name: "region dry run body", target: "/api/regions/eu/activate", body: `{"dry_run":true}`, wantTarget: "eu", wantDryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dryRun bool
+			var target string
+			rec := serve(t, newTestRouter(activationService(&dryRun, &target)), http.MethodPost, tt.target, tt.body)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if target != tt.wantTarget || dryRun != tt.wantDryRun {
+				t.Errorf("service called for %q with dry run %v, want %q with %v", target, dryRun, tt.wantTarget, tt.wantDryRun)
+			}
+			var got model.ActivationResult
+			decodeBody(t, rec, &got)
+			if got.DryRun != tt.wantDryRun {
+				t.Errorf("result dry_run = %v, want %v", got.DryRun, tt.wantDryRun)
+			}
+		})
+	}
+}
+
+func TestActivationHandlerDryRunUnknownTarget(t *testing.T) {
+	svc := &mockService{
+		activateDatacenter: func(_ context.Context, dc string, _, _ bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, fmt.Errorf("target datacenter: %w: %s", repository.ErrClusterNotFound, dc)
+		},
+	}
+
+	rec := serve(t, newTestRouter(svc), http.MethodPost, "/api/datacenters/dc9/activate?dry_run=true", "")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestActivationHandlerTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{name: "no activation timeout", timeout: 0},
+		{name: "activation timeout", timeout: time.Minute, wantDeadline: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			svc := &mockService{
+				activateDatacenter: func(ctx context.Context, dc string, _, _ bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+					deadline, hasDeadline = ctx.Deadline()
+					return &model.ActivationResult{Activated: dc, Errors: []string{}}, nil
+				},
+			}
+			h := NewHandler(svc, nil, Config{ActivationTimeout: tt.timeout}, slog.New(slog.DiscardHandler))
+
+			rec := serve(t, h.Router(), http.MethodPost, "/api/datacenters/dc1/activate", "")
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("activation context has deadline %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if tt.wantDeadline && deadline.After(time.Now().Add(tt.timeout)) {
+				t.Errorf("deadline %v is later than the activation timeout %v", deadline, tt.timeout)
+			}
+		})
+	}
+}
+
+func TestActivationHandlerCancelled(t *testing.T) {
+	svc := &mockService{
+		activateDatacenter: func(ctx context.Context, dc string, _, _ bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+			err := fmt.Errorf("activation of %s cancelled: %w", dc, context.DeadlineExceeded)
+			return &model.ActivationResult{Activated: dc, DrainedNodes: 1, Cancelled: true, Errors: []string{err.Error()}}, err
+		},
+	}
+
+	rec := serve(t, newTestRouter(svc), http.MethodPost, "/api/datacenters/dc1/activate", "")
+
+	if rec.Code == http.StatusOK {
+		t.Fatalf("status = %d for a cancelled activation", rec.Code)
+	}
+	var got model.ActivationResult
+	decodeBody(t, rec, &got)
+	if !got.Cancelled || got.DrainedNodes != 1 {
+		t.Errorf("result = %+v, want the cancelled partial result", got)
+	}
+}
+
+func TestActivationHandlerOutcomeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *model.ActivationResult
+		err        error
+		wantStatus int
+		wantResult bool // The activation result is the response body
+	}{
+		{
+			name:       "full success",
+			result:     &model.ActivationResult{DrainedNodes: 2, UnDrainedNodes: 2, Errors: []string{}},
+			wantStatus: http.StatusOK,
+			wantResult: true,
+		},
+		{
+			name:       "partial success",
+			result:     &model.ActivationResult{DrainedNodes: 2, UnDrainedNodes: 1, Errors: []string{"node n3: rejected"}},
+			wantStatus: http.StatusMultiStatus,
+			wantResult: true,
+		},
+		{
+			name:       "total failure",
+			result:     &model.ActivationResult{Errors: []string{"node n1: rejected", "node n2: rejected"}},
+			wantStatus: http.StatusInternalServerError,
+			wantResult: true,
+		},
+		{
+			name:       "failure without result",
+			err:        repository.ErrClusterNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	targets := []struct {
+		name string
+		path string
+	}{
+		{name: "datacenter", path: "/api/datacenters/dc1/activate"},
+		{name: "region", path: "/api/regions/eu/activate"},
+	}
+
+	for _, target := range targets {
+		for _, tt := range tests {
+			t.Run(target.name+" "+tt.name, func(t *testing.T) {
+				svc := &mockService{
+					activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+						return tt.result, tt.err
+					},
+					activateRegion: func(context.Context, string, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+						return tt.result, tt.err
+					},
+				}
+
+				rec := serve(t, newTestRouter(svc), http.MethodPost, target.path, "")
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+				}
+				if !tt.wantResult {
+					return
+				}
+				var got model.ActivationResult
+				decodeBody(t, rec, &got)
+				if got.DrainedNodes != tt.result.DrainedNodes || got.UnDrainedNodes != tt.result.UnDrainedNodes || len(got.Errors) != len(tt.result.Errors) {
+					t.Errorf("result = %+v, want %+v", got, *tt.result)
+				}
+			})
+		}
+	}
+}
+
+func TestActivationHandlerConflict(t *testing.T) {
+	inProgress := &service.ActivationInProgressError{Target: "us"}
+	svc := &mockService{
+		activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, inProgress
+		},
+		activateRegion: func(context.Context, string, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, inProgress
+		},
+	}
+
+	for _, target := range []string{"/api/datacenters/dc1/activate", "/api/regions/eu/activate"} {
+		t.Run(target, func(t *testing.T) {
+			rec := serve(t, newTestRouter(svc), http.MethodPost, target, "")
+
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusConflict, rec.Body.String())
+			}
+			var got activationConflictResponse
+			decodeBody(t, rec, &got)
+			want := activationConflictResponse{Error: "activation of us is already in progress", Running: "us"}
+			if got != want {
+				t.Errorf("body = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestActivationHandlerExclusive(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   bool
+	}{
+		{name: "default", target: "/api/datacenters/dc1/activate"},
+		{name: "query", target: "/api/datacenters/dc1/activate?exclusive=true", want: true},
+		{name: "body", target: "/api/datacenters/dc1/activate", body: `{"exclusive":true}`, want: true},
+		{name: "query false", target: "/api/datacenters/dc1/activate?exclusive=false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exclusive bool
+			svc := &mockService{
+				activateDatacenter: func(_ context.Context, dc string, dryRun, e bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+					exclusive = e
+					return &model.ActivationResult{Activated: dc, Exclusive: e, Errors: []string{}}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, tt.target, tt.body)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if exclusive != tt.want {
+				t.Errorf("service called with exclusive %v, want %v", exclusive, tt.want)
+			}
+			var got model.ActivationResult
+			decodeBody(t, rec, &got)
+			if got.Exclusive != tt.want {
+				t.Errorf("response exclusive = %v, want %v", got.Exclusive, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivationHandlerTooManyNodes(t *testing.T) {
+	tooMany := func() error { return &service.TooManyNodesAffectedError{Affected: 40, Max: 10} }
+	svc := &mockService{
+		activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, tooMany()
+		},
+		activateRegion: func(context.Context, string, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, tooMany()
+		},
+	}
+
+	for name, path := range map[string]string{"datacenter": "/api/datacenters/dc1/activate", "region": "/api/regions/eu/activate"} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(t, newTestRouter(svc), http.MethodPost, path, "")
+
+			if rec.Code != http.StatusPreconditionFailed {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusPreconditionFailed, rec.Body.String())
+			}
+			var got tooManyNodesResponse
+			decodeBody(t, rec, &got)
+			if got.AffectedNodes != 40 || got.MaxNodesAffected != 10 || !strings.Contains(got.Error, "confirm") {
+				t.Errorf("body = %+v, want 40 of 10 nodes and a hint to confirm", got)
+			}
+		})
+	}
+}
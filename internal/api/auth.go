@@ -0,0 +1,47 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authMiddleware requires a valid bearer token on mutating requests, and on all requests
+// when auth.require_for_reads is set
+func (h *Handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.auth.RequireForReads && isReadOnlyMethod(r.Method) && !isActivationStream(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !h.validToken(token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dc-switcher"`)
+			h.respondError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken reports whether token matches one of the configured tokens.
+// Every token is compared in constant time so the response time doesn't reveal a match.
+func (h *Handler) validToken(token string) bool {
+	valid := 0
+	for _, expected := range h.auth.Tokens {
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(expected))
+	}
+	return valid == 1
+}
+
+// isActivationStream reports whether r is a streamed activation, which mutates state despite being a GET
+func isActivationStream(r *http.Request) bool {
+	return strings.HasSuffix(r.URL.Path, "/activate/stream")
+}
+
+// isReadOnlyMethod reports whether the HTTP method doesn't change state
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		requireForReads bool
+		method          string
+		path            string
+		authorization   string
+		wantAllowed     bool
+	}{
+		{name: "write without token", method: http.MethodPost, path: "/api/datacenters/dc1/activate"},
+		{name: "write with invalid token", method: http.MethodPost, path: "/api/datacenters/dc1/activate", authorization: "Bearer wrong"},
+		{name: "write with token of another scheme", method: http.MethodPost, path: "/api/datacenters/dc1/activate", authorization: "Basic secret-1"},
+		{name: "write with valid token", method: http.MethodPost, path: "/api/datacenters/dc1/activate", authorization: "Bearer secret-1", wantAllowed: true},
+		{name: "write with second token", method: http.MethodPost, path: "/api/datacenters/dc1/activate", authorization: "Bearer secret-2", wantAllowed: true},
+		{name: "read without token", method: http.MethodGet, path: "/api/status", wantAllowed: true},
+		{name: "head without token", method: http.MethodHead, path: "/api/status", wantAllowed: true},
+		{name: "read without token when reads require one", requireForReads: true, method: http.MethodGet, path: "/api/status"},
+		{name: "read with valid token when reads require one", requireForReads: true, method: http.MethodGet, path: "/api/status", authorization: "Bearer secret-1", wantAllowed: true},
+		{name: "activation stream without token", method: http.MethodGet, path: "/api/datacenters/dc1/activate/stream"},
+		{name: "activation stream with valid token", method: http.MethodGet, path: "/api/datacenters/dc1/activate/stream", authorization: "Bearer secret-1", wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&mockService{}, nil, Config{
+				Auth: config.AuthConfig{Tokens: []string{"secret-1", "secret-2"}, RequireForReads: tt.requireForReads},
+			}, slog.New(slog.DiscardHandler))
+
+			allowed := false
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { allowed = true })
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.authMiddleware(next).ServeHTTP(rec, req)
+
+			if allowed != tt.wantAllowed {
+				t.Fatalf("request allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+			if tt.wantAllowed {
+				return
+			}
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="dc-switcher"` {
+				t.Errorf("WWW-Authenticate = %q", got)
+			}
+		})
+	}
+}
+
+func TestAuthDisabledWithoutTokens(t *testing.T) {
+	var dryRun bool
+	var target string
+	rec := serve(t, newTestRouter(activationService(&dryRun, &target)), http.MethodPost, "/api/datacenters/dc1/activate", "")
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestGetConfig(t *testing.T) {
+	cfg := &config.Config{
+		Server:       config.ServerConfig{Addr: ":8080"},
+		Clusters:     []config.ClusterConfig{{Name: "dc1", Region: "eu", Address: "http://nomad-dc1:4646", ExtraHeaders: map[string]string{"X-Proxy-Token": "proxy-secret"}}},
+		MyDatacenter: "dc1",
+		Etcd:         config.EtcdConfig{Endpoints: []string{"etcd:2379"}, Password: "etcd-password"},
+		Auth:         config.AuthConfig{Tokens: []string{"api-token"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	svc := &mockService{listClusters: func() []model.ClusterInfo {
+		return []model.ClusterInfo{{Name: "dc1", Region: "eu"}}
+	}}
+	h := NewHandler(svc, nil, Config{EffectiveConfig: cfg.Redacted()}, slog.New(slog.DiscardHandler))
+
+	rec := serve(t, h.Router(), http.MethodGet, "/api/config", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+	for _, secret := range []string{"api-token", "etcd-password", "proxy-secret"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("response contains secret %q: %s", secret, body)
+		}
+	}
+	var got model.EffectiveConfig
+	decodeBody(t, rec, &got)
+	if got.Config["my_datacenter"] != "dc1" {
+		t.Errorf("my_datacenter = %v, want dc1", got.Config["my_datacenter"])
+	}
+	if len(got.Clusters) != 1 || got.Clusters[0] != (model.ClusterInfo{Name: "dc1", Region: "eu"}) {
+		t.Errorf("clusters = %+v, want dc1 in eu", got.Clusters)
+	}
+}
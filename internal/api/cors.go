@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// corsMiddleware adds CORS headers for allowed origins and answers preflight requests
+func (h *Handler) corsMiddleware(next http.Handler) http.Handler {
+	allowAll := slices.Contains(h.cors.AllowedOrigins, "*")
+	methods := strings.Join(h.cors.AllowedMethods, ", ")
+	headers := strings.Join(h.cors.AllowedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The response depends on the Origin header, so caches must key on it
+		w.Header().Add("Vary", "Origin")
+		if !allowAll && !slices.Contains(h.cors.AllowedOrigins, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if allowAll {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		// Preflight: answer directly instead of routing the OPTIONS request
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			if h.cors.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(h.cors.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		method      string
+		origin      string
+		preflight   bool // Sets Access-Control-Request-Method
+		wantOrigin  string
+		wantNext    bool // The request reached the router
+		wantStatus  int
+		wantMethods string
+		wantMaxAge  string
+	}{
+		{
+			name:       "request without origin",
+			origins:    []string{"https://ui.example.com"},
+			method:     http.MethodGet,
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "allowed origin",
+			origins:    []string{"https://ui.example.com"},
+			method:     http.MethodGet,
+			origin:     "https://ui.example.com",
+			wantOrigin: "https://ui.example.com",
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "disallowed origin gets no CORS headers",
+			origins:    []string{"https://ui.example.com"},
+			method:     http.MethodGet,
+			origin:     "https://evil.example.com",
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "any origin",
+			origins:    []string{"*"},
+			method:     http.MethodGet,
+			origin:     "https://other.example.com",
+			wantOrigin: "*",
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "preflight of an allowed origin",
+			origins:     []string{"https://ui.example.com"},
+			method:      http.MethodOptions,
+			origin:      "https://ui.example.com",
+			preflight:   true,
+			wantOrigin:  "https://ui.example.com",
+			wantStatus:  http.StatusNoContent,
+			wantMethods: "GET, POST",
+			wantMaxAge:  "600",
+		},
+		{
+			name:       "preflight of a disallowed origin",
+			origins:    []string{"https://ui.example.com"},
+			method:     http.MethodOptions,
+			origin:     "https://evil.example.com",
+			preflight:  true,
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OPTIONS without a requested method is routed",
+			origins:    []string{"https://ui.example.com"},
+			method:     http.MethodOptions,
+			origin:     "https://ui.example.com",
+			wantOrigin: "https://ui.example.com",
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&mockService{}, nil, Config{
+				CORS: config.CORSConfig{
+					Enabled:        true,
+					AllowedOrigins: tt.origins,
+					AllowedMethods: []string{"GET", "POST"},
+					AllowedHeaders: []string{"Authorization", "Content-Type"},
+					MaxAge:         10 * time.Minute,
+				},
+			}, slog.New(slog.DiscardHandler))
+
+			reached := false
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { reached = true })
+
+			req := httptest.NewRequest(tt.method, "/api/status", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			h.corsMiddleware(next).ServeHTTP(rec, req)
+
+			if reached != tt.wantNext {
+				t.Errorf("request routed = %v, want %v", reached, tt.wantNext)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.wantMaxAge)
+			}
+			if tt.preflight && tt.wantOrigin != "" {
+				if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Authorization, Content-Type" {
+					t.Errorf("Access-Control-Allow-Headers = %q", got)
+				}
+			}
+			if wantVary := tt.origin != ""; (rec.Header().Get("Vary") == "Origin") != wantVary {
+				t.Errorf("Vary = %q, want Origin only for requests with an origin", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	var dryRun bool
+	var target string
+	router := newTestRouter(activationService(&dryRun, &target))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/datacenters/dc1/activate", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q with CORS disabled", got)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
 )
 
 // ListDatacenters handles GET /api/datacenters
 func (h *Handler) ListDatacenters(w http.ResponseWriter, r *http.Request) {
-	datacenters, err := h.service.ListDatacenters(r.Context())
+	datacenters, err := h.service.ListDatacenters(readContext(r))
 	if err != nil {
 		h.logger.Error("failed to list datacenters",
 			slog.String("error", err.Error()),
@@ -29,7 +32,13 @@ func (h *Handler) GetNodes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nodes, err := h.service.GetNodes(r.Context(), name)
+	// ?with_allocs=true adds per-node allocation counts at the cost of one extra call per node
+	getNodes := h.service.GetNodes
+	if r.URL.Query().Get("with_allocs") == "true" {
+		getNodes = h.service.GetNodesWithAllocations
+	}
+
+	nodes, err := getNodes(readContext(r), name)
 	if err != nil {
 		h.logger.Warn("datacenter unavailable or unreachable",
 			slog.String("datacenter", name),
@@ -44,7 +53,126 @@ func (h *Handler) GetNodes(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, nodes)
 }
 
+// ListAllNodes handles GET /api/nodes
+// Nodes are grouped by datacenter; unreachable datacenters are listed with an error and no nodes
+func (h *Handler) ListAllNodes(w http.ResponseWriter, r *http.Request) {
+	nodes, err := h.service.ListAllNodes(readContext(r))
+	if err != nil {
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, nodes)
+}
+
+// GetLeader handles GET /api/datacenters/{name}/leader
+func (h *Handler) GetLeader(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+
+	status, err := h.service.GetClusterLeader(r.Context(), name)
+	if err != nil {
+		code := errorStatus(err)
+		if code != http.StatusNotFound {
+			h.logger.Warn("failed to check cluster leader",
+				slog.String("datacenter", name),
+				slog.String("error", err.Error()),
+			)
+		}
+		// An unreachable cluster that returned no typed error is still reported as a bad gateway
+		if code == http.StatusInternalServerError {
+			code = http.StatusBadGateway
+		}
+		h.respondError(w, code, err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, status)
+}
+
+// GetClusterHealth handles GET /api/datacenters/{name}/health
+// Responds 200 when the cluster is healthy and 503 with the same report when it is not
+func (h *Handler) GetClusterHealth(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+
+	health, err := h.service.GetClusterHealth(r.Context(), name)
+	if err != nil {
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	if !health.Healthy {
+		h.logger.Warn("cluster health check failed",
+			slog.String("datacenter", name),
+			slog.String("error", health.Error),
+		)
+		h.respondJSON(w, http.StatusServiceUnavailable, health)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, health)
+}
+
+// DrainNode handles POST /api/datacenters/{name}/nodes/{node_id}/drain
+func (h *Handler) DrainNode(w http.ResponseWriter, r *http.Request) {
+	h.setNodeDrain(w, r, true)
+}
+
+// UndrainNode handles POST /api/datacenters/{name}/nodes/{node_id}/undrain
+func (h *Handler) UndrainNode(w http.ResponseWriter, r *http.Request) {
+	h.setNodeDrain(w, r, false)
+}
+
+// setNodeDrain changes the drain state of a single node and responds with its updated state
+func (h *Handler) setNodeDrain(w http.ResponseWriter, r *http.Request, drain bool) {
+	name := chi.URLParam(r, "name")
+	nodeID := chi.URLParam(r, "node_id")
+
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+	if nodeID == "" {
+		h.respondError(w, http.StatusBadRequest, "node ID is required")
+		return
+	}
+
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	node, err := h.service.SetNodeDrain(r.Context(), name, nodeID, drain, drainOverride)
+	if err != nil {
+		h.logger.Error("failed to change node drain state",
+			slog.String("datacenter", name),
+			slog.String("node_id", nodeID),
+			slog.Bool("drain", drain),
+			slog.String("error", err.Error()),
+		)
+
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, node)
+}
+
 // ActivateDatacenter handles POST /api/datacenters/{name}/activate
+// Supports ?dry_run=true to preview node changes without applying them,
+// ?exclusive=true to also drain other datacenters in the same region
+// ?drain_deadline=/ignore_system_jobs= to override the configured drain options
+// ?strategy=immediate|staged to override how nodes are undrained
+// and ?confirm=true to exceed safety.max_nodes_affected.
+// dry_run, exclusive and confirm can also be sent as a JSON body.
 func (h *Handler) ActivateDatacenter(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	if name == "" {
@@ -52,24 +180,104 @@ func (h *Handler) ActivateDatacenter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.ActivateDatacenter(r.Context(), name)
+	opts, err := parseActivationOptions(w, r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	strategy, err := parseActivationStrategy(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !opts.DryRun && !h.allowRequest(w, h.datacenterActivationLimiter) {
+		return
+	}
+
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+	if strategy != "" {
+		ctx = service.WithActivationStrategy(ctx, strategy)
+	}
+	if opts.Confirm {
+		ctx = service.WithConfirmed(ctx)
+	}
+
+	result, err := h.service.ActivateDatacenter(ctx, name, opts.DryRun, opts.Exclusive, drainOverride)
 	if err != nil {
 		h.logger.Error("failed to activate datacenter",
 			slog.String("datacenter", name),
 			slog.String("error", err.Error()),
 		)
+	}
 
-		// If we have a result with rollback info, return it with the error
-		if result != nil && len(result.Errors) > 0 {
-			h.respondJSON(w, http.StatusInternalServerError, result)
-			return
-		}
+	h.respondActivation(w, result, err)
+}
 
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+// DeactivateDatacenter handles POST /api/datacenters/{name}/deactivate
+// Drains the datacenter without activating another one
+func (h *Handler) DeactivateDatacenter(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, result)
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.allowRequest(w, h.datacenterActivationLimiter) {
+		return
+	}
+
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+
+	result, err := h.service.DeactivateDatacenter(ctx, name, drainOverride)
+	if err != nil {
+		h.logger.Error("failed to deactivate datacenter",
+			slog.String("datacenter", name),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	h.respondActivation(w, result, err)
+}
+
+// VerifyActivation handles GET /api/datacenters/{name}/verify
+// Reports whether the live node and allocation state matches an activation of the datacenter;
+// ?exclusive=true expects same-region datacenters to be drained as well
+func (h *Handler) VerifyActivation(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+
+	exclusive := r.URL.Query().Get("exclusive") == "true"
+
+	report, err := h.service.VerifyActivation(r.Context(), name, exclusive)
+	if err != nil {
+		h.logger.Warn("failed to verify activation",
+			slog.String("datacenter", name),
+			slog.String("error", err.Error()),
+		)
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
 }
 
 // GetJobs handles GET /api/datacenters/{name}/jobs
@@ -80,20 +288,58 @@ func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobs, err := h.service.GetJobs(r.Context(), name)
+	filter, err := parseJobFilter(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobs, err := h.service.GetJobs(readContext(r), name, filter)
 	if err != nil {
 		h.logger.Warn("datacenter unavailable or unreachable",
 			slog.String("datacenter", name),
 			slog.String("error", err.Error()),
 		)
 		// Return empty list with 200 instead of 500
-		h.respondJSON(w, http.StatusOK, []interface{}{})
+		h.respondJSON(w, http.StatusOK, &model.JobList{
+			Jobs:   []model.Job{},
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		})
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, jobs)
 }
 
+// GetJob handles GET /api/datacenters/{name}/jobs/{job_id}
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	jobID := chi.URLParam(r, "job_id")
+
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+	if jobID == "" {
+		h.respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	job, err := h.service.GetJob(r.Context(), name, jobID)
+	if err != nil {
+		h.logger.Error("failed to get job",
+			slog.String("datacenter", name),
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, job)
+}
+
 // StartJob handles POST /api/datacenters/{name}/jobs/{job_id}/start
 func (h *Handler) StartJob(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
@@ -118,18 +364,56 @@ func (h *Handler) StartJob(w http.ResponseWriter, r *http.Request) {
 
 		// Return result with error details
 		if result != nil {
-			h.respondJSON(w, http.StatusInternalServerError, result)
+			h.respondJSON(w, errorStatus(err), result)
 			return
 		}
 
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		h.respondError(w, errorStatus(err), err.Error())
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, result)
 }
 
+// BulkJobAction handles POST /api/datacenters/{name}/jobs/actions
+func (h *Handler) BulkJobAction(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+
+	var req model.BulkJobActionRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.service.BulkJobAction(r.Context(), name, req)
+	if err != nil {
+		h.logger.Error("failed to execute bulk job action",
+			slog.String("datacenter", name),
+			slog.String("action", req.Action),
+			slog.String("error", err.Error()),
+		)
+
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	// Same status semantics as activations: 207 on partial, 500 when every job failed
+	switch {
+	case result.IsPartial():
+		h.respondJSON(w, http.StatusMultiStatus, result)
+	case result.Failed > 0:
+		h.respondJSON(w, http.StatusInternalServerError, result)
+	default:
+		h.respondJSON(w, http.StatusOK, result)
+	}
+}
+
 // StopJob handles POST /api/datacenters/{name}/jobs/{job_id}/stop
+// Supports ?purge=true to also remove the job from Nomad
 func (h *Handler) StopJob(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	jobID := chi.URLParam(r, "job_id")
@@ -143,7 +427,9 @@ func (h *Handler) StopJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.StopJob(r.Context(), name, jobID)
+	purge := r.URL.Query().Get("purge") == "true"
+
+	result, err := h.service.StopJob(r.Context(), name, jobID, purge)
 	if err != nil {
 		h.logger.Error("failed to stop job",
 			slog.String("datacenter", name),
@@ -153,11 +439,46 @@ func (h *Handler) StopJob(w http.ResponseWriter, r *http.Request) {
 
 		// Return result with error details
 		if result != nil {
-			h.respondJSON(w, http.StatusInternalServerError, result)
+			h.respondJSON(w, errorStatus(err), result)
+			return
+		}
+
+		h.respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// RestartJob handles POST /api/datacenters/{name}/jobs/{job_id}/restart
+func (h *Handler) RestartJob(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	jobID := chi.URLParam(r, "job_id")
+
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+	if jobID == "" {
+		h.respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	result, err := h.service.RestartJob(r.Context(), name, jobID)
+	if err != nil {
+		h.logger.Error("failed to restart job",
+			slog.String("datacenter", name),
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+
+		// Return result with error details
+		if result != nil {
+			h.respondJSON(w, errorStatus(err), result)
 			return
 		}
 
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		h.respondError(w, errorStatus(err), err.Error())
 		return
 	}
 
@@ -0,0 +1,649 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+func TestRestartJobHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *model.JobActionResult
+		err        error
+		wantStatus int
+		wantResult bool
+	}{
+		{
+			name:       "restarted",
+			result:     &model.JobActionResult{JobID: "api", Action: "restart", Success: true, Errors: []string{}},
+			wantStatus: http.StatusOK,
+			wantResult: true,
+		},
+		{
+			name:       "already stopped",
+			result:     &model.JobActionResult{JobID: "api", Action: "restart", Success: true, AlreadyStopped: true, Errors: []string{}},
+			wantStatus: http.StatusOK,
+			wantResult: true,
+		},
+		{
+			name:       "read-only",
+			err:        service.ErrReadOnly,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unknown job",
+			result:     &model.JobActionResult{JobID: "api", Action: "restart", Errors: []string{"not found"}},
+			err:        fmt.Errorf("restart: %w", repository.ErrJobNotFound),
+			wantStatus: http.StatusNotFound,
+			wantResult: true,
+		},
+		{
+			name:       "nomad unavailable",
+			result:     &model.JobActionResult{JobID: "api", Action: "restart", Errors: []string{"unavailable"}},
+			err:        repository.ErrNomadUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+			wantResult: true,
+		},
+		{
+			name:       "other error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDC, gotJob string
+			svc := &mockService{
+				restartJob: func(_ context.Context, dc, jobID string) (*model.JobActionResult, error) {
+					gotDC, gotJob = dc, jobID
+					return tt.result, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, "/api/datacenters/dc1/jobs/api/restart", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if gotDC != "dc1" || gotJob != "api" {
+				t.Errorf("service called with %q/%q, want dc1/api", gotDC, gotJob)
+			}
+
+			if tt.wantResult {
+				var got model.JobActionResult
+				decodeBody(t, rec, &got)
+				if got.JobID != "api" || got.AlreadyStopped != tt.result.AlreadyStopped || got.Success != tt.result.Success {
+					t.Errorf("result = %+v, want %+v", got, *tt.result)
+				}
+			} else {
+				var got errorResponse
+				decodeBody(t, rec, &got)
+				if got.Error != tt.err.Error() {
+					t.Errorf("error = %q, want %q", got.Error, tt.err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestStopJobHandlerPurge(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		wantPurge bool
+	}{
+		{name: "stop", target: "/api/datacenters/dc1/jobs/api/stop"},
+		{name: "stop with purge", target: "/api/datacenters/dc1/jobs/api/stop?purge=true", wantPurge: true},
+		{name: "purge not true", target: "/api/datacenters/dc1/jobs/api/stop?purge=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPurge bool
+			svc := &mockService{
+				stopJob: func(_ context.Context, _, jobID string, purge bool) (*model.JobActionResult, error) {
+					gotPurge = purge
+					return &model.JobActionResult{JobID: jobID, Action: "stop", Success: true, Purged: purge, Errors: []string{}}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, tt.target, "")
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if gotPurge != tt.wantPurge {
+				t.Errorf("purge = %v, want %v", gotPurge, tt.wantPurge)
+			}
+			var got model.JobActionResult
+			decodeBody(t, rec, &got)
+			if got.Purged != tt.wantPurge {
+				t.Errorf("Purged = %v, want %v", got.Purged, tt.wantPurge)
+			}
+		})
+	}
+}
+
+func TestBulkJobActionHandlerPurge(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCalled bool
+		wantPurge  bool
+	}{
+		{
+			name:       "stop",
+			body:       `{"action":"stop","job_ids":["api"]}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "stop with purge",
+			body:       `{"action":"stop","purge":true,"job_ids":["api"]}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantPurge:  true,
+		},
+		{
+			name:       "start with purge",
+			body:       `{"action":"start","purge":true,"job_ids":["api"]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			var gotPurge bool
+			svc := &mockService{
+				bulkJobAction: func(_ context.Context, _ string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error) {
+					called, gotPurge = true, req.Purge
+					return &model.BulkJobActionResult{Action: req.Action, Succeeded: len(req.JobIDs)}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, "/api/datacenters/dc1/jobs/actions", tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("service called = %v, want %v", called, tt.wantCalled)
+			}
+			if gotPurge != tt.wantPurge {
+				t.Errorf("purge = %v, want %v", gotPurge, tt.wantPurge)
+			}
+		})
+	}
+}
+
+func TestGetJobHandler(t *testing.T) {
+	detail := &model.JobDetail{
+		Job:        model.Job{ID: "api", Type: "service", Status: "running", Running: 1, Desired: 1},
+		Version:    2,
+		TaskGroups: []model.TaskGroupStatus{{Name: "web", Count: 1, Running: 1, Desired: 1}},
+		Allocations: []model.Allocation{
+			{ID: "a1", JobID: "api", JobType: "service", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		detail     *model.JobDetail
+		err        error
+		wantStatus int
+	}{
+		{name: "found", detail: detail, wantStatus: http.StatusOK},
+		{name: "unknown job", err: fmt.Errorf("failed to get job api: %w", repository.ErrJobNotFound), wantStatus: http.StatusNotFound},
+		{name: "unknown datacenter", err: fmt.Errorf("failed to get job api: %w", repository.ErrClusterNotFound), wantStatus: http.StatusNotFound},
+		{name: "nomad unavailable", err: repository.ErrNomadUnavailable, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				getJob: func(_ context.Context, dc, jobID string) (*model.JobDetail, error) {
+					if dc != "dc1" || jobID != "api" {
+						t.Errorf("service called with %q/%q, want dc1/api", dc, jobID)
+					}
+					return tt.detail, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, "/api/datacenters/dc1/jobs/api", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.err != nil {
+				return
+			}
+			var got model.JobDetail
+			decodeBody(t, rec, &got)
+			if !reflect.DeepEqual(&got, tt.detail) {
+				t.Errorf("detail = %+v, want %+v", got, *tt.detail)
+			}
+		})
+	}
+}
+
+func TestSetNodeDrainHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		err          error
+		wantStatus   int
+		wantDrain    bool
+		wantOverride bool
+		wantCalled   bool
+	}{
+		{name: "drain", target: "/api/datacenters/dc1/nodes/n1/drain", wantStatus: http.StatusOK, wantDrain: true, wantCalled: true},
+		{name: "undrain", target: "/api/datacenters/dc1/nodes/n1/undrain", wantStatus: http.StatusOK, wantCalled: true},
+		{name: "drain override", target: "/api/datacenters/dc1/nodes/n1/drain?drain_deadline=5m", wantStatus: http.StatusOK, wantDrain: true, wantOverride: true, wantCalled: true},
+		{name: "invalid drain override", target: "/api/datacenters/dc1/nodes/n1/drain?drain_deadline=soon", wantStatus: http.StatusBadRequest},
+		{name: "unknown node", target: "/api/datacenters/dc1/nodes/n1/drain", err: service.ErrNodeNotFound, wantStatus: http.StatusNotFound, wantDrain: true, wantCalled: true},
+		{name: "unknown datacenter", target: "/api/datacenters/dc1/nodes/n1/drain", err: repository.ErrClusterNotFound, wantStatus: http.StatusNotFound, wantDrain: true, wantCalled: true},
+		{name: "read-only", target: "/api/datacenters/dc1/nodes/n1/drain", err: service.ErrReadOnly, wantStatus: http.StatusForbidden, wantDrain: true, wantCalled: true},
+		{name: "nomad failure", target: "/api/datacenters/dc1/nodes/n1/drain", err: errors.New("nomad down"), wantStatus: http.StatusInternalServerError, wantDrain: true, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			svc := &mockService{
+				setNodeDrain: func(_ context.Context, dc, nodeID string, drain bool, override *model.DrainOverride) (*model.Node, error) {
+					called = true
+					if dc != "dc1" || nodeID != "n1" || drain != tt.wantDrain || (override != nil) != tt.wantOverride {
+						t.Errorf("SetNodeDrain(%s, %s, %v, %+v), want dc1, n1, %v, override %v", dc, nodeID, drain, override, tt.wantDrain, tt.wantOverride)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					eligibility := "eligible"
+					if drain {
+						eligibility = "ineligible"
+					}
+					return &model.Node{ID: nodeID, Drain: drain, SchedulingEligibility: eligibility}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("service called = %v, want %v", called, tt.wantCalled)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var node model.Node
+			decodeBody(t, rec, &node)
+			if node.ID != "n1" || node.Drain != tt.wantDrain {
+				t.Errorf("node = %+v, want n1 with drain %v", node, tt.wantDrain)
+			}
+		})
+	}
+}
+
+func TestGetJobsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		wantFilter model.JobFilter
+		wantCalled bool
+	}{
+		{name: "no filter", target: "/api/datacenters/dc1/jobs", wantStatus: http.StatusOK, wantCalled: true},
+		{
+			name:       "every parameter",
+			target:     "/api/datacenters/dc1/jobs?status=running&type=service&prefix=api&limit=10&offset=20",
+			wantStatus: http.StatusOK,
+			wantFilter: model.JobFilter{Status: "running", Type: "service", Prefix: "api", Limit: 10, Offset: 20},
+			wantCalled: true,
+		},
+		{
+			name:       "type and page",
+			target:     "/api/datacenters/dc1/jobs?type=batch&limit=5",
+			wantStatus: http.StatusOK,
+			wantFilter: model.JobFilter{Type: "batch", Limit: 5},
+			wantCalled: true,
+		},
+		{name: "unknown status", target: "/api/datacenters/dc1/jobs?status=stopped", wantStatus: http.StatusBadRequest},
+		{name: "unknown type", target: "/api/datacenters/dc1/jobs?type=daemon", wantStatus: http.StatusBadRequest},
+		{name: "zero limit", target: "/api/datacenters/dc1/jobs?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "malformed limit", target: "/api/datacenters/dc1/jobs?limit=ten", wantStatus: http.StatusBadRequest},
+		{name: "negative offset", target: "/api/datacenters/dc1/jobs?offset=-1", wantStatus: http.StatusBadRequest},
+		{
+			name:       "unreachable datacenter lists nothing",
+			target:     "/api/datacenters/dc1/jobs?limit=5",
+			err:        repository.ErrNomadUnavailable,
+			wantStatus: http.StatusOK,
+			wantFilter: model.JobFilter{Limit: 5},
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			var gotFilter model.JobFilter
+			list := &model.JobList{Jobs: []model.Job{{ID: "api"}}, Total: 3}
+			svc := &mockService{
+				getJobs: func(_ context.Context, dc string, filter model.JobFilter) (*model.JobList, error) {
+					called, gotFilter = true, filter
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					list.Limit, list.Offset = filter.Limit, filter.Offset
+					return list, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Fatalf("service called = %v, want %v", called, tt.wantCalled)
+			}
+			if !called {
+				return
+			}
+			if gotFilter != tt.wantFilter {
+				t.Errorf("filter = %+v, want %+v", gotFilter, tt.wantFilter)
+			}
+
+			var got model.JobList
+			decodeBody(t, rec, &got)
+			want := model.JobList{Jobs: list.Jobs, Total: list.Total, Limit: tt.wantFilter.Limit, Offset: tt.wantFilter.Offset}
+			if tt.err != nil {
+				want = model.JobList{Jobs: []model.Job{}, Limit: tt.wantFilter.Limit, Offset: tt.wantFilter.Offset}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("response = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBulkJobActionHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		result     *model.BulkJobActionResult
+		err        error
+		wantStatus int
+		wantReq    *model.BulkJobActionRequest // nil when the service isn't called
+	}{
+		{
+			name:       "all succeeded",
+			body:       `{"action":"start","job_ids":["api","web"]}`,
+			result:     &model.BulkJobActionResult{Action: "start", Succeeded: 2},
+			wantStatus: http.StatusOK,
+			wantReq:    &model.BulkJobActionRequest{Action: "start", JobIDs: []string{"api", "web"}},
+		},
+		{
+			name:       "partial failure",
+			body:       `{"action":"stop","job_ids":["api","web"]}`,
+			result:     &model.BulkJobActionResult{Action: "stop", Succeeded: 1, Failed: 1},
+			wantStatus: http.StatusMultiStatus,
+			wantReq:    &model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api", "web"}},
+		},
+		{
+			name:       "every job failed",
+			body:       `{"action":"stop","job_ids":["api","web"]}`,
+			result:     &model.BulkJobActionResult{Action: "stop", Failed: 2},
+			wantStatus: http.StatusInternalServerError,
+			wantReq:    &model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api", "web"}},
+		},
+		{
+			name:       "all matching a filter",
+			body:       `{"action":"stop","all":true,"filter":{"type":"batch","prefix":"nightly"}}`,
+			result:     &model.BulkJobActionResult{Action: "stop", Succeeded: 3},
+			wantStatus: http.StatusOK,
+			wantReq:    &model.BulkJobActionRequest{Action: "stop", All: true, Filter: model.JobFilter{Type: "batch", Prefix: "nightly"}},
+		},
+		{
+			name:       "unknown datacenter",
+			body:       `{"action":"start","all":true}`,
+			err:        fmt.Errorf("list jobs: %w", repository.ErrClusterNotFound),
+			wantStatus: http.StatusNotFound,
+			wantReq:    &model.BulkJobActionRequest{Action: "start", All: true},
+		},
+		{name: "unknown action", body: `{"action":"restart","job_ids":["api"]}`, wantStatus: http.StatusBadRequest},
+		{name: "no jobs selected", body: `{"action":"start"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid filter", body: `{"action":"start","all":true,"filter":{"status":"stopped"}}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed body", body: `{"action":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq *model.BulkJobActionRequest
+			svc := &mockService{
+				bulkJobAction: func(_ context.Context, dc string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error) {
+					gotReq = &req
+					return tt.result, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodPost, "/api/datacenters/dc1/jobs/actions", tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !reflect.DeepEqual(gotReq, tt.wantReq) {
+				t.Errorf("service request = %+v, want %+v", gotReq, tt.wantReq)
+			}
+			if tt.result == nil {
+				return
+			}
+			var got model.BulkJobActionResult
+			decodeBody(t, rec, &got)
+			if got.Succeeded != tt.result.Succeeded || got.Failed != tt.result.Failed {
+				t.Errorf("succeeded/failed = %d/%d, want %d/%d", got.Succeeded, got.Failed, tt.result.Succeeded, tt.result.Failed)
+			}
+		})
+	}
+}
+
+func TestGetNodesHandler(t *testing.T) {
+	count := 3
+	tests := []struct {
+		name           string
+		target         string
+		err            error
+		wantWithAllocs bool
+		wantNodes      int
+		wantAllocCount bool // alloc_count present in the response
+	}{
+		{name: "without counts", target: "/api/datacenters/dc1/nodes", wantNodes: 1},
+		{name: "with counts", target: "/api/datacenters/dc1/nodes?with_allocs=true", wantWithAllocs: true, wantNodes: 1, wantAllocCount: true},
+		{name: "with_allocs=false", target: "/api/datacenters/dc1/nodes?with_allocs=false", wantNodes: 1},
+		{name: "unreachable datacenter", target: "/api/datacenters/dc1/nodes?with_allocs=true", err: repository.ErrNomadUnavailable, wantWithAllocs: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called, withAllocs bool
+			svc := &mockService{
+				getNodes: func(context.Context, string) ([]model.Node, error) {
+					called = true
+					return []model.Node{{ID: "n1"}}, tt.err
+				},
+				getNodesWithAllocs: func(context.Context, string) ([]model.Node, error) {
+					called, withAllocs = true, true
+					return []model.Node{{ID: "n1", AllocCount: &count}}, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, tt.target, "")
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if !called || withAllocs != tt.wantWithAllocs {
+				t.Fatalf("allocation counts requested = %v, want %v", withAllocs, tt.wantWithAllocs)
+			}
+			var got []map[string]any
+			decodeBody(t, rec, &got)
+			if len(got) != tt.wantNodes {
+				t.Fatalf("%d nodes, want %d", len(got), tt.wantNodes)
+			}
+			for _, node := range got {
+				if _, ok := node["alloc_count"]; ok != tt.wantAllocCount {
+					t.Errorf("alloc_count present = %v, want %v", ok, tt.wantAllocCount)
+				}
+			}
+		})
+	}
+}
+
+func TestListAllNodesHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodes      *model.AllNodes
+		err        error
+		wantStatus int
+	}{
+		{
+			name: "grouped by datacenter",
+			nodes: &model.AllNodes{
+				Datacenters: []model.DatacenterNodes{
+					{Datacenter: "dc1", Region: "eu", Nodes: []model.Node{{ID: "n1", Datacenter: "dc1", Region: "eu"}}},
+					{Datacenter: "dc2", Region: "us", Nodes: []model.Node{}, Error: "nomad unavailable"},
+				},
+				Total: 1,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{name: "service failure", err: repository.ErrNomadUnavailable, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				listAllNodes: func(context.Context) (*model.AllNodes, error) {
+					return tt.nodes, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, "/api/nodes", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.err != nil {
+				return
+			}
+
+			var got struct {
+				Datacenters []struct {
+					Datacenter string           `json:"datacenter"`
+					Region     string           `json:"region"`
+					Nodes      []map[string]any `json:"nodes"`
+					Error      string           `json:"error"`
+				} `json:"datacenters"`
+				Total int `json:"total"`
+			}
+			decodeBody(t, rec, &got)
+			if got.Total != 1 || len(got.Datacenters) != 2 {
+				t.Fatalf("total = %d with %d datacenters, want 1 with 2", got.Total, len(got.Datacenters))
+			}
+			dc1, dc2 := got.Datacenters[0], got.Datacenters[1]
+			if dc1.Datacenter != "dc1" || dc1.Region != "eu" || len(dc1.Nodes) != 1 || dc1.Error != "" {
+				t.Errorf("dc1 = %+v, want its node in region eu", dc1)
+			}
+			if node := dc1.Nodes[0]; node["datacenter"] != "dc1" || node["region"] != "eu" {
+				t.Errorf("node = %v, want datacenter dc1 and region eu", node)
+			}
+			// An unreachable datacenter is an empty list with its error, not null
+			if dc2.Nodes == nil || len(dc2.Nodes) != 0 || dc2.Error != "nomad unavailable" {
+				t.Errorf("dc2 = %+v, want no nodes and its error", dc2)
+			}
+		})
+	}
+}
+
+func TestVerifyActivationHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		err           error
+		wantStatus    int
+		wantExclusive bool
+	}{
+		{name: "report", target: "/api/datacenters/dc1/verify", wantStatus: http.StatusOK},
+		{name: "exclusive", target: "/api/datacenters/dc1/verify?exclusive=true", wantStatus: http.StatusOK, wantExclusive: true},
+		{name: "unknown datacenter", target: "/api/datacenters/dc1/verify", err: repository.ErrClusterNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDC string
+			var gotExclusive bool
+			svc := &mockService{
+				verifyActivation: func(_ context.Context, dc string, exclusive bool) (*model.ActivationVerification, error) {
+					gotDC, gotExclusive = dc, exclusive
+					if tt.err != nil {
+						return nil, fmt.Errorf("target datacenter: %w", tt.err)
+					}
+					return &model.ActivationVerification{
+						Datacenter: dc,
+						Exclusive:  exclusive,
+						Clusters: []model.ClusterVerification{
+							{Cluster: "dc1", Region: "eu", Expected: model.ExpectedEligible, Converged: true, NodesTotal: 1, NodesMatching: 1},
+							{
+								Cluster:    "dc3",
+								Region:     "us",
+								Expected:   model.ExpectedDrained,
+								NodesTotal: 1,
+								MismatchedNodes: []model.NodeMismatch{{
+									NodeID:   "n3",
+									Expected: model.NodeState{Drain: true, SchedulingEligibility: "ineligible"},
+									Actual:   model.NodeState{SchedulingEligibility: "eligible"},
+								}},
+								ActiveAllocations: 2,
+							},
+						},
+					}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if gotDC != "dc1" || gotExclusive != tt.wantExclusive {
+				t.Errorf("VerifyActivation(%q, %v), want (dc1, %v)", gotDC, gotExclusive, tt.wantExclusive)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			var got model.ActivationVerification
+			decodeBody(t, rec, &got)
+			if got.Datacenter != "dc1" || got.Converged || got.Exclusive != tt.wantExclusive || len(got.Clusters) != 2 {
+				t.Fatalf("report = %+v, want dc1 not converged with 2 clusters", got)
+			}
+			if dc3 := got.Clusters[1]; dc3.Expected != model.ExpectedDrained || len(dc3.MismatchedNodes) != 1 || dc3.MismatchedNodes[0].NodeID != "n3" || dc3.ActiveAllocations != 2 {
+				t.Errorf("dc3 = %+v, want drained with mismatched node n3 and 2 allocations", dc3)
+			}
+		})
+	}
+}
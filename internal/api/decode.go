@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRequestBodyBytes bounds JSON request bodies
+const maxRequestBodyBytes = 1 << 20
+
+// errEmptyBody is returned by decodeJSONBody for a request without a body
+var errEmptyBody = errors.New("request body is required")
+
+// validator is implemented by request bodies that check their fields after decoding
+type validator interface {
+	Validate() error
+}
+
+// decodeJSONBody decodes a single JSON object from the request body into dst and validates it
+// when dst implements validator. Unknown fields, trailing data and bodies larger than
+// maxRequestBodyBytes are rejected. Errors are meant for the client and answered with 400.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.New("invalid request body: must contain a single JSON object")
+	}
+
+	if v, ok := dst.(validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// decodeOptionalJSONBody is decodeJSONBody for endpoints whose body may be omitted;
+// dst is left unchanged when the request has no body
+func decodeOptionalJSONBody(w http.ResponseWriter, r *http.Request, dst any) error {
+	err := decodeJSONBody(w, r, dst)
+	if errors.Is(err, errEmptyBody) {
+		return nil
+	}
+	return err
+}
+
+// decodeError turns a JSON decoding error into a message that points the client at the problem
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return errEmptyBody
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("invalid request body: unexpected end of JSON")
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("invalid request body: malformed JSON at offset %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return errors.New("invalid request body: must be a JSON object")
+		}
+		return fmt.Errorf("invalid request body: field %q must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("invalid request body: larger than %d bytes", maxBytesErr.Limit)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for DisallowUnknownFields
+		return fmt.Errorf("invalid request body: unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	default:
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decodeTestBody is a request body with a validated field
+type decodeTestBody struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (b *decodeTestBody) Validate() error {
+	if b.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+	return nil
+}
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    decodeTestBody
+		wantErr string // Exact error message, empty for success
+	}{
+		{name: "valid object", body: `{"name":"dc1","count":2}`, want: decodeTestBody{Name: "dc1", Count: 2}},
+		{name: "surrounding whitespace", body: " {\"name\":\"dc1\"}\n", want: decodeTestBody{Name: "dc1"}},
+		{name: "empty body", body: "", wantErr: "request body is required"},
+		{name: "unknown field", body: `{"name":"dc1","force":true}`, wantErr: `invalid request body: unknown field "force"`},
+		{name: "trailing object", body: `{"name":"dc1"}{"name":"dc2"}`, wantErr: "invalid request body: must contain a single JSON object"},
+		{name: "trailing garbage", body: `{"name":"dc1"} x`, wantErr: "invalid request body: must contain a single JSON object"},
+		{name: "truncated JSON", body: `{"name":"dc1"`, wantErr: "invalid request body: unexpected end of JSON"},
+		{name: "malformed JSON", body: `{"name":dc1}`, wantErr: "invalid request body: malformed JSON at offset 9"},
+		{name: "not an object", body: `["dc1"]`, wantErr: "invalid request body: must be a JSON object"},
+		{name: "wrong field type", body: `{"count":"two"}`, wantErr: `invalid request body: field "count" must be int, got string`},
+		{name: "failed validation", body: `{"count":-1}`, wantErr: "count must not be negative"},
+		{
+			name:    "body over the size limit",
+			body:    `{"name":"` + strings.Repeat("a", maxRequestBodyBytes) + `"}`,
+			wantErr: "invalid request body: larger than 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			var got decodeTestBody
+
+			err := decodeJSONBody(httptest.NewRecorder(), req, &got)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("decodeJSONBody() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeJSONBody() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("decoded = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeOptionalJSONBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    decodeTestBody
+		wantErr bool
+	}{
+		{name: "no body keeps the defaults", want: decodeTestBody{Name: "default"}},
+		{name: "body", body: `{"name":"dc1"}`, want: decodeTestBody{Name: "dc1"}},
+		{name: "invalid body", body: `{"unknown":1}`, want: decodeTestBody{Name: "default"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			got := decodeTestBody{Name: "default"}
+
+			err := decodeOptionalJSONBody(httptest.NewRecorder(), req, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeOptionalJSONBody() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decoded = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// EmergencyDrainAll handles POST /api/emergency/drain-all
+// Drains every datacenter of every region and clears the active datacenter, so nothing stays active.
+// The body must confirm the drain with {"confirm": "drain-all"}.
+func (h *Handler) EmergencyDrainAll(w http.ResponseWriter, r *http.Request) {
+	var req model.EmergencyDrainRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+
+	result, err := h.service.EmergencyDrainAll(ctx)
+	if err != nil {
+		h.logger.Error("failed to drain all datacenters",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	h.respondActivation(w, result, err)
+}
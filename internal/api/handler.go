@@ -1,29 +1,88 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
 )
 
+// HealthChecker runs the active region health check on demand and reports its state
+type HealthChecker interface {
+	RunCheck(ctx context.Context) model.HealthCheckState
+	SetPaused(paused bool)
+	State() model.HealthCheckState
+}
+
 // Handler holds the HTTP handlers and dependencies
 type Handler struct {
-	service  service.DatacenterService
-	logger   *slog.Logger
-	basePath string
+	service           service.DatacenterService
+	healthChecker     HealthChecker
+	logger            *slog.Logger
+	basePath          string
+	activationTimeout time.Duration // Upper bound for a single activation (0 means no timeout)
+	requestTimeout    time.Duration // Upper bound for other API requests (0 means no timeout)
+	cors              config.CORSConfig
+	auth              config.AuthConfig
+	effectiveConfig   map[string]any // Loaded configuration without secrets
+
+	// Per-endpoint activation rate limiters (nil when disabled); dry runs are not limited
+	datacenterActivationLimiter *rateLimiter
+	regionActivationLimiter     *rateLimiter
+}
+
+// Config holds the settings of the HTTP handler, taken from the application config
+type Config struct {
+	BasePath            string
+	ActivationTimeout   time.Duration // Upper bound for a single activation (0 means no timeout)
+	RequestTimeout      time.Duration // Upper bound for other API requests (0 means no timeout)
+	CORS                config.CORSConfig
+	Auth                config.AuthConfig
+	ActivationRateLimit int            // Activations per minute per endpoint (0 disables limiting)
+	EffectiveConfig     map[string]any // Loaded configuration without secrets
+}
+
+// NewConfig takes the HTTP handler settings from the application config
+func NewConfig(cfg *config.Config) Config {
+	return Config{
+		BasePath:            cfg.Server.BasePath,
+		ActivationTimeout:   cfg.Server.WriteTimeout,
+		RequestTimeout:      cfg.Server.RequestTimeout,
+		CORS:                cfg.CORS,
+		Auth:                cfg.Auth,
+		ActivationRateLimit: cfg.ActivationRateLimit,
+		EffectiveConfig:     cfg.Redacted(),
+	}
 }
 
 // NewHandler creates a new HTTP handler
-func NewHandler(service service.DatacenterService, basePath string, logger *slog.Logger) *Handler {
+func NewHandler(service service.DatacenterService, healthChecker HealthChecker, cfg Config, logger *slog.Logger) *Handler {
 	return &Handler{
-		service:  service,
-		logger:   logger,
-		basePath: basePath,
+		service:           service,
+		healthChecker:     healthChecker,
+		logger:            logger,
+		basePath:          cfg.BasePath,
+		activationTimeout: cfg.ActivationTimeout,
+		requestTimeout:    cfg.RequestTimeout,
+		cors:              cfg.CORS,
+		auth:              cfg.Auth,
+		effectiveConfig:   cfg.EffectiveConfig,
+
+		datacenterActivationLimiter: newRateLimiter(cfg.ActivationRateLimit),
+		regionActivationLimiter:     newRateLimiter(cfg.ActivationRateLimit),
 	}
 }
 
@@ -36,6 +95,13 @@ func (h *Handler) Router() http.Handler {
 	r.Use(middleware.RealIP)
 	r.Use(h.loggingMiddleware)
 	r.Use(middleware.Recoverer)
+	if h.cors.Enabled {
+		r.Use(h.corsMiddleware)
+	}
+
+	// Kubernetes probes are served outside the base path so probe configuration doesn't depend on it
+	r.Get("/healthz", h.Liveness)
+	r.Get("/readyz", h.Readiness)
 
 	// Create routes handler
 	routesHandler := h.createRoutes()
@@ -56,48 +122,275 @@ func (h *Handler) createRoutes() http.Handler {
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
+		if len(h.auth.Tokens) > 0 {
+			r.Use(h.authMiddleware)
+		}
+		r.Use(h.timeoutMiddleware)
+
 		// Datacenter routes
 		r.Get("/datacenters", h.ListDatacenters)
+		r.Get("/nodes", h.ListAllNodes)
 		r.Get("/datacenters/{name}/nodes", h.GetNodes)
+		r.Get("/datacenters/{name}/leader", h.GetLeader)
+		r.Get("/datacenters/{name}/health", h.GetClusterHealth)
 		r.Post("/datacenters/{name}/activate", h.ActivateDatacenter)
+		r.Get("/datacenters/{name}/activate/stream", h.ActivateDatacenterStream)
+		r.Post("/datacenters/{name}/deactivate", h.DeactivateDatacenter)
+		r.Get("/datacenters/{name}/verify", h.VerifyActivation)
+		r.Post("/datacenters/{name}/nodes/{node_id}/drain", h.DrainNode)
+		r.Post("/datacenters/{name}/nodes/{node_id}/undrain", h.UndrainNode)
 
 		// Job routes
 		r.Get("/datacenters/{name}/jobs", h.GetJobs)
+		r.Post("/datacenters/{name}/jobs/actions", h.BulkJobAction)
+		r.Get("/datacenters/{name}/jobs/{job_id}", h.GetJob)
 		r.Post("/datacenters/{name}/jobs/{job_id}/start", h.StartJob)
 		r.Post("/datacenters/{name}/jobs/{job_id}/stop", h.StopJob)
+		r.Post("/datacenters/{name}/jobs/{job_id}/restart", h.RestartJob)
 
 		// Region routes
 		r.Get("/regions", h.ListRegions)
 		r.Get("/regions/{name}/datacenters", h.GetDatacentersByRegion)
 		r.Post("/regions/{name}/activate", h.ActivateRegion)
+		r.Post("/regions/{name}/deactivate", h.DeactivateRegion)
 
-		// Status route
+		// Emergency routes
+		r.Post("/emergency/drain-all", h.EmergencyDrainAll)
+
+		// Status routes
 		r.Get("/status", h.GetStatus)
+		r.Get("/config", h.GetConfig)
+		r.Get("/active", h.GetActive)
+
+		// History route
+		r.Get("/history", h.GetHistory)
+
+		// Health check routes
+		r.Get("/healthcheck/status", h.GetHealthCheckStatus)
+		r.Post("/healthcheck/run", h.RunHealthCheck)
+		r.Post("/healthcheck/pause", h.PauseHealthCheck)
+		r.Post("/healthcheck/resume", h.ResumeHealthCheck)
+
+		// API description
+		r.Get("/openapi.json", h.GetOpenAPISpec)
 	})
 
+	// Prometheus metrics
+	r.Handle("/metrics", promhttp.Handler())
+
 	// Serve UI (must be last to act as catch-all)
 	r.HandleFunc("/*", h.ServeUI())
 
 	return r
 }
 
-// loggingMiddleware logs HTTP requests
+// loggingMiddleware writes one access log line per request after it is served.
+// Successful requests are logged at debug level to keep UI asset and polling traffic
+// out of production logs; everything else is logged at info so errors stay visible.
 func (h *Handler) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h.logger.Info("http request",
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK // Handler wrote nothing, net/http sends 200
+		}
+
+		level := slog.LevelInfo
+		if status >= 200 && status < 300 {
+			level = slog.LevelDebug
+		}
+
+		elapsed := time.Since(start)
+		h.logger.LogAttrs(r.Context(), level, "http request",
 			slog.String("method", r.Method),
 			slog.String("path", r.URL.Path),
 			slog.String("remote_addr", r.RemoteAddr),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		next.ServeHTTP(w, r)
 	})
 }
 
+// activationContext derives the context for an activation from the request
+// The activation stops when the client disconnects or the activation timeout elapses.
+// ?wait_for_drain=true makes it report drained nodes that still run allocations.
+func (h *Handler) activationContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx := r.Context()
+	if r.URL.Query().Get("wait_for_drain") == "true" {
+		ctx = service.WithDrainWait(ctx)
+	}
+
+	if h.activationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, h.activationTimeout)
+}
+
+// readContext returns the request context, marked to bypass the cache when ?fresh=true is set
+func readContext(r *http.Request) context.Context {
+	if r.URL.Query().Get("fresh") == "true" {
+		return service.WithFresh(r.Context())
+	}
+	return r.Context()
+}
+
+// parseActivationOptions decodes the optional activation body and enables the options
+// also set through ?dry_run=true, ?exclusive=true or ?confirm=true
+func parseActivationOptions(w http.ResponseWriter, r *http.Request) (model.ActivationOptions, error) {
+	var opts model.ActivationOptions
+	if err := decodeOptionalJSONBody(w, r, &opts); err != nil {
+		return opts, err
+	}
+
+	query := r.URL.Query()
+	opts.DryRun = opts.DryRun || query.Get("dry_run") == "true"
+	opts.Exclusive = opts.Exclusive || query.Get("exclusive") == "true"
+	opts.Confirm = opts.Confirm || query.Get("confirm") == "true"
+	return opts, nil
+}
+
+// parseActivationStrategy reads the optional strategy query parameter, empty keeps the configured strategy
+func parseActivationStrategy(r *http.Request) (string, error) {
+	strategy := r.URL.Query().Get("strategy")
+	if strategy == "" {
+		return "", nil
+	}
+	if err := config.ValidateActivationStrategy(strategy); err != nil {
+		return "", err
+	}
+	return strategy, nil
+}
+
+// parseDrainOverride reads optional drain_deadline and ignore_system_jobs query parameters
+// A drain_deadline of 0 force-stops allocations immediately, a negative value means no deadline
+func parseDrainOverride(r *http.Request) (*model.DrainOverride, error) {
+	query := r.URL.Query()
+	if !query.Has("drain_deadline") && !query.Has("ignore_system_jobs") {
+		return nil, nil
+	}
+
+	override := &model.DrainOverride{}
+	if value := query.Get("drain_deadline"); value != "" {
+		deadline, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid drain_deadline %q: %w", value, err)
+		}
+		override.Deadline = &deadline
+	}
+	if value := query.Get("ignore_system_jobs"); value != "" {
+		ignore, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_system_jobs %q: %w", value, err)
+		}
+		override.IgnoreSystemJobs = &ignore
+	}
+
+	return override, nil
+}
+
+// parseJobFilter reads the optional status, type, prefix, limit and offset query parameters
+func parseJobFilter(r *http.Request) (model.JobFilter, error) {
+	query := r.URL.Query()
+	filter := model.JobFilter{
+		Status: query.Get("status"),
+		Type:   query.Get("type"),
+		Prefix: query.Get("prefix"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return filter, fmt.Errorf("invalid limit %q: must be a positive integer", value)
+		}
+		filter.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("invalid offset %q: must be a non-negative integer", value)
+		}
+		filter.Offset = offset
+	}
+
+	return filter, filter.Validate()
+}
+
+// respondActivation writes an activation result with a status reflecting its outcome:
+// 200 when every node change succeeded, 207 when some failed, 500 when none succeeded
+func (h *Handler) respondActivation(w http.ResponseWriter, result *model.ActivationResult, err error) {
+	var inProgress *service.ActivationInProgressError
+	var tooManyNodes *service.TooManyNodesAffectedError
+	switch {
+	case errors.As(err, &inProgress):
+		h.respondJSON(w, http.StatusConflict, activationConflictResponse{
+			Error:   err.Error(),
+			Running: inProgress.Target,
+		})
+	case errors.As(err, &tooManyNodes):
+		h.respondJSON(w, http.StatusPreconditionFailed, tooManyNodesResponse{
+			Error:            err.Error(),
+			AffectedNodes:    tooManyNodes.Affected,
+			MaxNodesAffected: tooManyNodes.Max,
+		})
+	case result == nil:
+		h.respondError(w, errorStatus(err), err.Error())
+	case result.IsPartial():
+		h.respondJSON(w, http.StatusMultiStatus, result)
+	case err != nil || len(result.Errors) > 0:
+		h.respondJSON(w, http.StatusInternalServerError, result)
+	default:
+		h.respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// errorStatus maps a service or repository error to the HTTP status code reported to clients
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReadOnly):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrActivationInProgress):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTooManyNodesAffected):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrDatacenterDisabled):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrClusterNotFound),
+		errors.Is(err, repository.ErrRegionNotFound),
+		errors.Is(err, repository.ErrJobNotFound),
+		errors.Is(err, service.ErrNodeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrShuttingDown),
+		errors.Is(err, repository.ErrNomadUnavailable),
+		errors.Is(err, repository.ErrEtcdTimeout):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // errorResponse represents an error response
 type errorResponse struct {
 	Error string `json:"error"`
 }
 
+// activationConflictResponse is returned when another activation is already running
+type activationConflictResponse struct {
+	Error   string `json:"error"`
+	Running string `json:"running"` // Target of the running activation
+}
+
+// tooManyNodesResponse is returned when an unconfirmed activation exceeds safety.max_nodes_affected
+type tooManyNodesResponse struct {
+	Error            string `json:"error"`
+	AffectedNodes    int    `json:"affected_nodes"`
+	MaxNodesAffected int    `json:"max_nodes_affected"`
+}
+
 // respondJSON writes a JSON response
 func (h *Handler) respondJSON(w http.ResponseWriter, statusCode int, data any) {
 	w.Header().Set("Content-Type", "application/json")
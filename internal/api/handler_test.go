@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		basePath   string
+		target     string
+		wantStatus int
+	}{
+		{name: "without base path", target: "/metrics", wantStatus: http.StatusOK},
+		{name: "under base path", basePath: "/switcher", target: "/switcher/metrics", wantStatus: http.StatusOK},
+		{name: "base path is required", basePath: "/switcher", target: "/metrics", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&mockService{}, nil, Config{BasePath: tt.basePath}, slog.New(slog.DiscardHandler))
+
+			rec := serve(t, h.Router(), http.MethodGet, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), "dc_switcher_nodes_drained_total") {
+				t.Errorf("response is not the metrics exposition:\n%s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestParseDrainOverride(t *testing.T) {
+	immediate := time.Duration(0)
+	infinite := time.Duration(-1)
+	tenMinutes := 10 * time.Minute
+	ignore := true
+
+	tests := []struct {
+		name    string
+		query   string
+		want    *model.DrainOverride
+		wantErr bool
+	}{
+		{name: "no override", query: "", want: nil},
+		{name: "deadline", query: "?drain_deadline=10m", want: &model.DrainOverride{Deadline: &tenMinutes}},
+		{name: "immediate", query: "?drain_deadline=0s", want: &model.DrainOverride{Deadline: &immediate}},
+		{name: "infinite", query: "?drain_deadline=-1ns", want: &model.DrainOverride{Deadline: &infinite}},
+		{name: "ignore system jobs", query: "?ignore_system_jobs=true", want: &model.DrainOverride{IgnoreSystemJobs: &ignore}},
+		{name: "invalid deadline", query: "?drain_deadline=soon", wantErr: true},
+		{name: "invalid ignore system jobs", query: "?ignore_system_jobs=maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDrainOverride(httptest.NewRequest(http.MethodPost, "/api/datacenters/dc1/activate"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDrainOverride() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDrainOverride() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlyEndpoints(t *testing.T) {
+	svc := &mockService{
+		activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		activateRegion: func(context.Context, string, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		startJob: func(context.Context, string, string) (*model.JobActionResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		stopJob: func(context.Context, string, string, bool) (*model.JobActionResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		restartJob: func(context.Context, string, string) (*model.JobActionResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		bulkJobAction: func(context.Context, string, model.BulkJobActionRequest) (*model.BulkJobActionResult, error) {
+			return nil, service.ErrReadOnly
+		},
+		setNodeDrain: func(context.Context, string, string, bool, *model.DrainOverride) (*model.Node, error) {
+			return nil, service.ErrReadOnly
+		},
+		getStatus: func(context.Context) (*model.ServiceStatus, error) {
+			return &model.ServiceStatus{MyDatacenter: "dc1"}, nil
+		},
+	}
+	router := newTestRouter(svc)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "activate datacenter", method: http.MethodPost, target: "/api/datacenters/dc1/activate", wantStatus: http.StatusForbidden},
+		{name: "activate region", method: http.MethodPost, target: "/api/regions/eu/activate", wantStatus: http.StatusForbidden},
+		{name: "start job", method: http.MethodPost, target: "/api/datacenters/dc1/jobs/api/start", wantStatus: http.StatusForbidden},
+		{name: "stop job", method: http.MethodPost, target: "/api/datacenters/dc1/jobs/api/stop", wantStatus: http.StatusForbidden},
+		{name: "restart job", method: http.MethodPost, target: "/api/datacenters/dc1/jobs/api/restart", wantStatus: http.StatusForbidden},
+		{name: "bulk job action", method: http.MethodPost, target: "/api/datacenters/dc1/jobs/actions", body: `{"action":"stop","job_ids":["api"]}`, wantStatus: http.StatusForbidden},
+		{name: "drain node", method: http.MethodPost, target: "/api/datacenters/dc1/nodes/n1/drain", wantStatus: http.StatusForbidden},
+		{name: "undrain node", method: http.MethodPost, target: "/api/datacenters/dc1/nodes/n1/undrain", wantStatus: http.StatusForbidden},
+		{name: "status still served", method: http.MethodGet, target: "/api/status", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, router, tt.method, tt.target, tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusForbidden {
+				return
+			}
+			var got errorResponse
+			decodeBody(t, rec, &got)
+			if got.Error != service.ErrReadOnly.Error() {
+				t.Errorf("error = %q, want %q", got.Error, service.ErrReadOnly.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// GetHealthCheckStatus handles GET /api/healthcheck/status
+// Reports the region the health checker monitors and its consecutive failure counts
+func (h *Handler) GetHealthCheckStatus(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.healthChecker.State())
+}
+
+// RunHealthCheck handles POST /api/healthcheck/run
+// Checks the active region immediately and returns the checker state, including failure counters.
+// A failed check counts towards the failure threshold like a periodic one.
+func (h *Handler) RunHealthCheck(w http.ResponseWriter, r *http.Request) {
+	if !h.healthChecker.State().Enabled {
+		h.respondError(w, http.StatusConflict, "health check is disabled")
+		return
+	}
+
+	// A drain started by the check must not be interrupted when the client goes away
+	state := h.healthChecker.RunCheck(context.WithoutCancel(r.Context()))
+
+	h.respondJSON(w, http.StatusOK, state)
+}
+
+// PauseHealthCheck handles POST /api/healthcheck/pause
+// Switches on maintenance mode: checks keep running and logging, but failures never drain the region
+func (h *Handler) PauseHealthCheck(w http.ResponseWriter, r *http.Request) {
+	h.healthChecker.SetPaused(true)
+	h.respondJSON(w, http.StatusOK, h.healthChecker.State())
+}
+
+// ResumeHealthCheck handles POST /api/healthcheck/resume
+func (h *Handler) ResumeHealthCheck(w http.ResponseWriter, r *http.Request) {
+	h.healthChecker.SetPaused(false)
+	h.respondJSON(w, http.StatusOK, h.healthChecker.State())
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// fakeHealthChecker is a HealthChecker reporting a fixed state
+type fakeHealthChecker struct {
+	state model.HealthCheckState
+	runs  int
+}
+
+func (f *fakeHealthChecker) RunCheck(context.Context) model.HealthCheckState {
+	f.runs++
+	return f.state
+}
+
+func (f *fakeHealthChecker) SetPaused(paused bool) {
+	f.state.Paused = paused
+}
+
+func (f *fakeHealthChecker) State() model.HealthCheckState {
+	return f.state
+}
+
+func TestGetHealthCheckStatusHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		state model.HealthCheckState
+	}{
+		{
+			name: "restored failure counts",
+			state: model.HealthCheckState{
+				Enabled:         true,
+				ActiveRegion:    "eu",
+				Interval:        30000,
+				FailedThreshold: 3,
+				Failures:        map[string]int{"eu": 2, "us": 1},
+			},
+		},
+		{
+			name:  "disabled",
+			state: model.HealthCheckState{Failures: map[string]int{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &fakeHealthChecker{state: tt.state}
+			router := NewHandler(&mockService{}, checker, Config{}, slog.New(slog.DiscardHandler)).Router()
+
+			rec := serve(t, router, http.MethodGet, "/api/healthcheck/status", "")
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+			}
+			var got model.HealthCheckState
+			decodeBody(t, rec, &got)
+			if got.Enabled != tt.state.Enabled || got.ActiveRegion != tt.state.ActiveRegion ||
+				got.Interval != tt.state.Interval || got.FailedThreshold != tt.state.FailedThreshold {
+				t.Errorf("state = %+v, want %+v", got, tt.state)
+			}
+			if got.Failures == nil || !maps.Equal(got.Failures, tt.state.Failures) {
+				t.Errorf("failures = %v, want %v", got.Failures, tt.state.Failures)
+			}
+		})
+	}
+}
+
+func TestRunHealthCheckHandlerDisabled(t *testing.T) {
+	checker := &fakeHealthChecker{state: model.HealthCheckState{Failures: map[string]int{}}}
+	router := NewHandler(&mockService{}, checker, Config{}, slog.New(slog.DiscardHandler)).Router()
+
+	rec := serve(t, router, http.MethodPost, "/api/healthcheck/run", "")
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409 (body %s)", rec.Code, rec.Body.String())
+	}
+	if checker.runs != 0 {
+		t.Errorf("ran %d checks while disabled", checker.runs)
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// defaultHistoryLimit is the number of events returned when no limit is given
+const defaultHistoryLimit = 50
+
+// GetHistory handles GET /api/history
+func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	limit := defaultHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			h.respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	events, err := h.service.GetActivationHistory(r.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed to get activation history",
+			slog.String("error", err.Error()),
+		)
+		h.respondError(w, http.StatusInternalServerError, "failed to get activation history")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestGetHistoryHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantLimit  int // 0 when the service must not be called
+	}{
+		{name: "default limit", query: "", wantStatus: http.StatusOK, wantLimit: defaultHistoryLimit},
+		{name: "explicit limit", query: "?limit=5", wantStatus: http.StatusOK, wantLimit: 5},
+		{name: "not a number", query: "?limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "zero", query: "?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "negative", query: "?limit=-3", wantStatus: http.StatusBadRequest},
+		{name: "service error", query: "", err: errors.New("etcd unavailable"), wantStatus: http.StatusInternalServerError, wantLimit: defaultHistoryLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLimit := 0
+			svc := &mockService{
+				getHistory: func(_ context.Context, limit int) ([]model.ActivationEvent, error) {
+					gotLimit = limit
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return []model.ActivationEvent{{Target: "dc2"}, {Target: "dc1"}}, nil
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, "/api/history"+tt.query, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("service limit = %d, want %d", gotLimit, tt.wantLimit)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var events []model.ActivationEvent
+			decodeBody(t, rec, &events)
+			if len(events) != 2 || events[0].Target != "dc2" || events[1].Target != "dc1" {
+				t.Errorf("events = %+v, want dc2, dc1", events)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// accessLogRecord is the JSON access log line written by loggingMiddleware
+type accessLogRecord struct {
+	Level      string  `json:"level"`
+	Msg        string  `json:"msg"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	DurationMs float64 `json:"duration_ms"`
+	Bytes      int     `json:"bytes"`
+	RequestID  string  `json:"request_id"`
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	const delay = 5 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBytes  int
+		wantLevel  string
+	}{
+		{
+			name: "success",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(delay)
+				_, _ = io.WriteString(w, "done")
+			},
+			wantStatus: http.StatusOK,
+			wantBytes:  4,
+			wantLevel:  "DEBUG",
+		},
+		{
+			name: "error",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(delay)
+				http.Error(w, "missing", http.StatusNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBytes:  len("missing\n"),
+			wantLevel:  "INFO",
+		},
+		{
+			name: "handler writing nothing",
+			handler: func(http.ResponseWriter, *http.Request) {
+				time.Sleep(delay)
+			},
+			wantStatus: http.StatusOK,
+			wantLevel:  "DEBUG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := NewHandler(&mockService{}, nil, Config{}, logger)
+
+			rec := httptest.NewRecorder()
+			middleware.RequestID(h.loggingMiddleware(tt.handler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+
+			var got accessLogRecord
+			if err := json.Unmarshal(logs.Bytes(), &got); err != nil {
+				t.Fatalf("access log %q is not a single JSON record: %v", logs.String(), err)
+			}
+			if got.Msg != "http request" || got.Method != http.MethodGet || got.Path != "/api/status" {
+				t.Errorf("logged %s %s %q, want GET /api/status \"http request\"", got.Method, got.Path, got.Msg)
+			}
+			if got.Status != tt.wantStatus || got.Bytes != tt.wantBytes || got.Level != tt.wantLevel {
+				t.Errorf("status/bytes/level = %d/%d/%s, want %d/%d/%s", got.Status, got.Bytes, got.Level, tt.wantStatus, tt.wantBytes, tt.wantLevel)
+			}
+			if got.DurationMs < float64(delay.Milliseconds()) {
+				t.Errorf("duration_ms = %v, want at least %d", got.DurationMs, delay.Milliseconds())
+			}
+			if got.RequestID == "" {
+				t.Error("access log has no request_id")
+			}
+		})
+	}
+}
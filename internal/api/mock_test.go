@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// mockService is a service.DatacenterService whose methods are set per test.
+// Calling a method that isn't set panics through the nil embedded interface.
+type mockService struct {
+	service.DatacenterService
+
+	activateDatacenter func(ctx context.Context, dc string, dryRun, exclusive bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
+	activateRegion     func(ctx context.Context, region string, dryRun bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
+	getStatus          func(ctx context.Context) (*model.ServiceStatus, error)
+	getNodes           func(ctx context.Context, dc string) ([]model.Node, error)
+	getNodesWithAllocs func(ctx context.Context, dc string) ([]model.Node, error)
+	getJobs            func(ctx context.Context, dc string, filter model.JobFilter) (*model.JobList, error)
+	getJob             func(ctx context.Context, dc, jobID string) (*model.JobDetail, error)
+	startJob           func(ctx context.Context, dc, jobID string) (*model.JobActionResult, error)
+	stopJob            func(ctx context.Context, dc, jobID string, purge bool) (*model.JobActionResult, error)
+	restartJob         func(ctx context.Context, dc, jobID string) (*model.JobActionResult, error)
+	bulkJobAction      func(ctx context.Context, dc string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error)
+	getHistory         func(ctx context.Context, limit int) ([]model.ActivationEvent, error)
+	setNodeDrain       func(ctx context.Context, dc, nodeID string, drain bool, drainOverride *model.DrainOverride) (*model.Node, error)
+	healthSnapshot     func(ctx context.Context) *model.HealthSnapshot
+	listClusters       func() []model.ClusterInfo
+	listAllNodes       func(ctx context.Context) (*model.AllNodes, error)
+	verifyActivation   func(ctx context.Context, dc string, exclusive bool) (*model.ActivationVerification, error)
+}
+
+func (m *mockService) ActivateDatacenter(ctx context.Context, dc string, dryRun, exclusive bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	return m.activateDatacenter(ctx, dc, dryRun, exclusive, drainOverride)
+}
+
+func (m *mockService) ActivateRegion(ctx context.Context, region string, dryRun bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	return m.activateRegion(ctx, region, dryRun, drainOverride)
+}
+
+func (m *mockService) GetStatus(ctx context.Context) (*model.ServiceStatus, error) {
+	return m.getStatus(ctx)
+}
+
+func (m *mockService) GetNodes(ctx context.Context, dc string) ([]model.Node, error) {
+	return m.getNodes(ctx, dc)
+}
+
+func (m *mockService) ListAllNodes(ctx context.Context) (*model.AllNodes, error) {
+	return m.listAllNodes(ctx)
+}
+
+func (m *mockService) GetNodesWithAllocations(ctx context.Context, dc string) ([]model.Node, error) {
+	return m.getNodesWithAllocs(ctx, dc)
+}
+
+func (m *mockService) GetJobs(ctx context.Context, dc string, filter model.JobFilter) (*model.JobList, error) {
+	return m.getJobs(ctx, dc, filter)
+}
+
+func (m *mockService) GetJob(ctx context.Context, dc, jobID string) (*model.JobDetail, error) {
+	return m.getJob(ctx, dc, jobID)
+}
+
+func (m *mockService) StartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error) {
+	return m.startJob(ctx, dc, jobID)
+}
+
+func (m *mockService) StopJob(ctx context.Context, dc, jobID string, purge bool) (*model.JobActionResult, error) {
+	return m.stopJob(ctx, dc, jobID, purge)
+}
+
+func (m *mockService) RestartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error) {
+	return m.restartJob(ctx, dc, jobID)
+}
+
+func (m *mockService) BulkJobAction(ctx context.Context, dc string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error) {
+	return m.bulkJobAction(ctx, dc, req)
+}
+
+func (m *mockService) GetActivationHistory(ctx context.Context, limit int) ([]model.ActivationEvent, error) {
+	return m.getHistory(ctx, limit)
+}
+
+func (m *mockService) SetNodeDrain(ctx context.Context, dc, nodeID string, drain bool, drainOverride *model.DrainOverride) (*model.Node, error) {
+	return m.setNodeDrain(ctx, dc, nodeID, drain, drainOverride)
+}
+
+func (m *mockService) HealthSnapshot(ctx context.Context) *model.HealthSnapshot {
+	return m.healthSnapshot(ctx)
+}
+
+func (m *mockService) ListClusters() []model.ClusterInfo {
+	return m.listClusters()
+}
+
+func (m *mockService) VerifyActivation(ctx context.Context, dc string, exclusive bool) (*model.ActivationVerification, error) {
+	return m.verifyActivation(ctx, dc, exclusive)
+}
+
+// newTestRouter returns the router of a handler backed by svc, without auth and base path
+func newTestRouter(svc service.DatacenterService) http.Handler {
+	h := NewHandler(svc, nil, Config{}, slog.New(slog.DiscardHandler))
+	return h.Router()
+}
+
+// serve sends a request to the router and returns the recorded response
+func serve(t *testing.T, router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeBody decodes the JSON body of a recorded response into v
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+}
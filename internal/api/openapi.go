@@ -0,0 +1,18 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI 3 document describing the API; keep it in sync with the routes
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// GetOpenAPISpec handles GET /api/openapi.json
+func (h *Handler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
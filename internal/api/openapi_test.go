@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// openAPIDocument is the part of the OpenAPI spec checked by the tests
+type openAPIDocument struct {
+	OpenAPI string                    `json:"openapi"`
+	Paths   map[string]map[string]any `json:"paths"`
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	rec := serve(t, newTestRouter(&mockService{}), http.MethodGet, "/api/openapi.json", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var spec openAPIDocument
+	decodeBody(t, rec, &spec)
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("openapi version = %q, want 3.x", spec.OpenAPI)
+	}
+}
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	var spec openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	var documented []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	h := NewHandler(&mockService{}, nil, Config{}, slog.New(slog.DiscardHandler))
+	routes, ok := h.createRoutes().(chi.Routes)
+	if !ok {
+		t.Fatal("API router doesn't list its routes")
+	}
+	var served []string
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/") {
+			served = append(served, method+" "+route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk the routes: %v", err)
+	}
+
+	slices.Sort(documented)
+	slices.Sort(served)
+	for _, route := range served {
+		if !slices.Contains(documented, route) {
+			t.Errorf("%s is served but missing from openapi.json", route)
+		}
+	}
+	for _, route := range documented {
+		if !slices.Contains(served, route) {
+			t.Errorf("%s is in openapi.json but not served", route)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket holding a single token that refills at a fixed rate,
+// so accepted requests are spaced at least one interval apart
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Time to refill the token
+	next     time.Time     // When the token is available again
+}
+
+// newRateLimiter creates a limiter accepting perMinute requests per minute, or nil when perMinute is not positive
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// allow takes the token if it is available, otherwise it returns how long to wait for it
+func (l *rateLimiter) allow() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.next) {
+		return false, l.next.Sub(now)
+	}
+	l.next = now.Add(l.interval)
+	return true, 0
+}
+
+// allowRequest applies limiter to a request and responds with 429 and Retry-After when it is exceeded.
+// A nil limiter allows every request.
+func (h *Handler) allowRequest(w http.ResponseWriter, limiter *rateLimiter) bool {
+	if limiter == nil {
+		return true
+	}
+
+	ok, wait := limiter.allow()
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		h.respondError(w, http.StatusTooManyRequests, "activation rate limit exceeded, retry later")
+		return false
+	}
+	return true
+}
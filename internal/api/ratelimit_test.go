@@ -0,0 +1,105 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestActivationRateLimit(t *testing.T) {
+	type request struct {
+		path       string
+		wantStatus int
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		requests []request
+	}{
+		{
+			name:  "second activation within the interval is rejected",
+			limit: 1,
+			requests: []request{
+				{path: "/api/datacenters/dc1/activate", wantStatus: http.StatusOK},
+				{path: "/api/datacenters/dc2/activate", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:  "dry runs are not limited",
+			limit: 1,
+			requests: []request{
+				{path: "/api/datacenters/dc1/activate", wantStatus: http.StatusOK},
+				{path: "/api/datacenters/dc1/activate?dry_run=true", wantStatus: http.StatusOK},
+				{path: "/api/regions/eu/activate?dry_run=true", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name:  "datacenter and region activations are limited separately",
+			limit: 1,
+			requests: []request{
+				{path: "/api/datacenters/dc1/activate", wantStatus: http.StatusOK},
+				{path: "/api/regions/eu/activate", wantStatus: http.StatusOK},
+				{path: "/api/regions/us/activate", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:  "no limit",
+			limit: 0,
+			requests: []request{
+				{path: "/api/datacenters/dc1/activate", wantStatus: http.StatusOK},
+				{path: "/api/datacenters/dc2/activate", wantStatus: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dryRun bool
+			var target string
+			h := NewHandler(activationService(&dryRun, &target), nil, Config{ActivationRateLimit: tt.limit}, slog.New(slog.DiscardHandler))
+			router := h.Router()
+
+			for _, req := range tt.requests {
+				rec := serve(t, router, http.MethodPost, req.path, "")
+				if rec.Code != req.wantStatus {
+					t.Fatalf("POST %s status = %d, want %d (body %s)", req.path, rec.Code, req.wantStatus, rec.Body.String())
+				}
+				if req.wantStatus != http.StatusTooManyRequests {
+					continue
+				}
+
+				// One activation per minute: the token is back in just under a minute
+				if got := rec.Header().Get("Retry-After"); got != "60" {
+					t.Errorf("Retry-After = %q, want 60", got)
+				}
+				var body errorResponse
+				decodeBody(t, rec, &body)
+				if body.Error == "" {
+					t.Error("429 response has no error message")
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	limiter := &rateLimiter{interval: 50 * time.Millisecond}
+
+	if ok, _ := limiter.allow(); !ok {
+		t.Fatal("first request rejected")
+	}
+	ok, wait := limiter.allow()
+	if ok {
+		t.Fatal("second request within the interval allowed")
+	}
+	if wait <= 0 || wait > limiter.interval {
+		t.Errorf("wait = %v, want within (0, %v]", wait, limiter.interval)
+	}
+
+	time.Sleep(wait)
+	if ok, _ := limiter.allow(); !ok {
+		t.Error("request after the wait rejected")
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
 )
 
 // ListRegions handles GET /api/regions
 func (h *Handler) ListRegions(w http.ResponseWriter, r *http.Request) {
-	regions, err := h.service.ListRegions(r.Context())
+	regions, err := h.service.ListRegions(readContext(r))
 	if err != nil {
 		h.logger.Warn("failed to list regions",
 			slog.String("error", err.Error()),
@@ -30,14 +32,13 @@ func (h *Handler) GetDatacentersByRegion(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	datacenters, err := h.service.GetDatacentersByRegion(r.Context(), name)
+	datacenters, err := h.service.GetDatacentersByRegion(readContext(r), name)
 	if err != nil {
 		h.logger.Warn("region not found or unavailable",
 			slog.String("region", name),
 			slog.String("error", err.Error()),
 		)
-		// Return 404 for not found region
-		h.respondError(w, http.StatusNotFound, err.Error())
+		h.respondError(w, errorStatus(err), err.Error())
 		return
 	}
 
@@ -45,6 +46,11 @@ func (h *Handler) GetDatacentersByRegion(w http.ResponseWriter, r *http.Request)
 }
 
 // ActivateRegion handles POST /api/regions/{name}/activate
+// Supports ?dry_run=true to preview node changes without applying them
+// ?drain_deadline=/ignore_system_jobs= to override the configured drain options
+// ?strategy=immediate|staged to override how nodes are undrained
+// and ?confirm=true to exceed safety.max_nodes_affected.
+// dry_run and confirm can also be sent as a JSON body.
 func (h *Handler) ActivateRegion(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	if name == "" {
@@ -52,22 +58,77 @@ func (h *Handler) ActivateRegion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.ActivateRegion(r.Context(), name)
+	opts, err := parseActivationOptions(w, r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	strategy, err := parseActivationStrategy(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !opts.DryRun && !h.allowRequest(w, h.regionActivationLimiter) {
+		return
+	}
+
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+	if strategy != "" {
+		ctx = service.WithActivationStrategy(ctx, strategy)
+	}
+	if opts.Confirm {
+		ctx = service.WithConfirmed(ctx)
+	}
+
+	result, err := h.service.ActivateRegion(ctx, name, opts.DryRun, drainOverride)
 	if err != nil {
 		h.logger.Error("failed to activate region",
 			slog.String("region", name),
 			slog.String("error", err.Error()),
 		)
+	}
+
+	h.respondActivation(w, result, err)
+}
 
-		// If we have a result with rollback info, return it with the error
-		if result != nil && len(result.Errors) > 0 {
-			h.respondJSON(w, http.StatusInternalServerError, result)
-			return
-		}
+// DeactivateRegion handles POST /api/regions/{name}/deactivate
+// Drains every datacenter of the region without activating another one
+func (h *Handler) DeactivateRegion(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "region name is required")
+		return
+	}
 
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, result)
+	if !h.allowRequest(w, h.regionActivationLimiter) {
+		return
+	}
+
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+
+	result, err := h.service.DeactivateRegion(ctx, name, drainOverride)
+	if err != nil {
+		h.logger.Error("failed to deactivate region",
+			slog.String("region", name),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	h.respondActivation(w, result, err)
 }
@@ -1,8 +1,14 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
 )
 
 // GetStatus handles GET /api/status
@@ -18,3 +24,54 @@ func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 
 	h.respondJSON(w, http.StatusOK, status)
 }
+
+// GetActive handles GET /api/active
+// Returns the active datacenter in a minimal form for frequent polling, or 503 when none is active
+func (h *Handler) GetActive(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetActiveSummary(readContext(r))
+	if errors.Is(err, repository.ErrNoActiveDatacenter) {
+		h.respondError(w, http.StatusServiceUnavailable, "no active datacenter")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get active datacenter",
+			slog.String("error", err.Error()),
+		)
+		h.respondError(w, errorStatus(err), "failed to get active datacenter")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(service.ActiveSummaryTTL.Seconds())))
+	h.respondJSON(w, http.StatusOK, summary)
+}
+
+// GetConfig handles GET /api/config
+// Returns the effective configuration with secrets redacted and the connected clusters
+func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, model.EffectiveConfig{
+		Config:   h.effectiveConfig,
+		Clusters: h.service.ListClusters(),
+	})
+}
+
+// Liveness handles GET /healthz
+// Reports that the process is up without checking any dependency
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness handles GET /readyz
+// Returns 503 when etcd is unreachable or no cluster has an elected leader
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.service.HealthSnapshot(r.Context())
+	if !snapshot.IsReady() {
+		h.logger.Warn("service is not ready",
+			slog.Bool("etcd_connected", snapshot.EtcdConnected),
+			slog.Int("healthy_clusters", snapshot.HealthyClusters),
+		)
+		h.respondJSON(w, http.StatusServiceUnavailable, snapshot)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, snapshot)
+}
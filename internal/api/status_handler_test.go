@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestGetStatusHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     *model.ServiceStatus
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "status",
+			status:     &model.ServiceStatus{MyDatacenter: "dc1", EtcdConnected: true, ActiveDatacenter: "dc2", ActiveDatacenters: []string{"dc2"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "no active datacenter",
+			status:     &model.ServiceStatus{MyDatacenter: "dc1", AmDrained: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "failure",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				getStatus: func(context.Context) (*model.ServiceStatus, error) {
+					return tt.status, tt.err
+				},
+			}
+
+			rec := serve(t, newTestRouter(svc), http.MethodGet, "/api/status", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.err != nil {
+				return
+			}
+			var got model.ServiceStatus
+			decodeBody(t, rec, &got)
+			if got.MyDatacenter != tt.status.MyDatacenter || got.ActiveDatacenter != tt.status.ActiveDatacenter ||
+				got.AmDrained != tt.status.AmDrained || got.EtcdConnected != tt.status.EtcdConnected {
+				t.Errorf("status = %+v, want %+v", got, *tt.status)
+			}
+		})
+	}
+}
+
+func TestProbeHandlers(t *testing.T) {
+	ready := &model.HealthSnapshot{EtcdConnected: true, Clusters: map[string]bool{"dc1": true, "dc2": false}, HealthyClusters: 1}
+
+	tests := []struct {
+		name       string
+		basePath   string
+		target     string
+		snapshot   *model.HealthSnapshot
+		wantStatus int
+	}{
+		{name: "liveness", target: "/healthz", wantStatus: http.StatusOK},
+		{name: "ready", target: "/readyz", snapshot: ready, wantStatus: http.StatusOK},
+		{
+			name:       "etcd unreachable",
+			target:     "/readyz",
+			snapshot:   &model.HealthSnapshot{Clusters: map[string]bool{"dc1": true}, HealthyClusters: 1},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "all clusters down",
+			target:     "/readyz",
+			snapshot:   &model.HealthSnapshot{EtcdConnected: true, Clusters: map[string]bool{"dc1": false}},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{name: "liveness outside the base path", basePath: "/switcher", target: "/healthz", wantStatus: http.StatusOK},
+		{name: "readiness outside the base path", basePath: "/switcher", target: "/readyz", snapshot: ready, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				healthSnapshot: func(context.Context) *model.HealthSnapshot { return tt.snapshot },
+			}
+			h := NewHandler(svc, nil, Config{BasePath: tt.basePath}, slog.New(slog.DiscardHandler))
+
+			rec := serve(t, h.Router(), http.MethodGet, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.snapshot == nil {
+				return
+			}
+			var got model.HealthSnapshot
+			decodeBody(t, rec, &got)
+			if got.EtcdConnected != tt.snapshot.EtcdConnected || got.HealthyClusters != tt.snapshot.HealthyClusters ||
+				len(got.Clusters) != len(tt.snapshot.Clusters) {
+				t.Errorf("snapshot = %+v, want %+v", got, *tt.snapshot)
+			}
+		})
+	}
+}
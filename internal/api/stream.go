@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// SSE event names sent by the activation stream
+const (
+	eventProgress = "progress" // A node change finished
+	eventResult   = "result"   // The activation finished, data is the activation result
+	eventError    = "error"    // The activation failed without a result
+)
+
+// activationOutcome is the return value of an activation running in the background
+type activationOutcome struct {
+	result *model.ActivationResult
+	err    error
+}
+
+// ActivateDatacenterStream handles GET /api/datacenters/{name}/activate/stream
+// Runs the same activation as POST /api/datacenters/{name}/activate and streams a progress event
+// per node change as Server-Sent Events, followed by the result. Clients that don't accept
+// text/event-stream get the plain JSON response instead.
+func (h *Handler) ActivateDatacenterStream(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		h.ActivateDatacenter(w, r)
+		return
+	}
+
+	// EventSource reconnects when the stream ends; never run the activation a second time
+	if r.Header.Get("Last-Event-ID") != "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "datacenter name is required")
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	exclusive := r.URL.Query().Get("exclusive") == "true"
+
+	drainOverride, err := parseDrainOverride(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	strategy, err := parseActivationStrategy(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !dryRun && !h.allowRequest(w, h.datacenterActivationLimiter) {
+		return
+	}
+
+	// The request context is cancelled when the client disconnects, which cancels the activation
+	ctx, cancel := h.activationContext(r)
+	defer cancel()
+	if strategy != "" {
+		ctx = service.WithActivationStrategy(ctx, strategy)
+	}
+	if r.URL.Query().Get("confirm") == "true" {
+		ctx = service.WithConfirmed(ctx)
+	}
+
+	progress := make(chan model.ActivationProgress)
+	done := make(chan activationOutcome, 1)
+	go func() {
+		result, err := h.service.ActivateDatacenter(service.WithProgress(ctx, progress), name, dryRun, exclusive, drainOverride)
+		done <- activationOutcome{result: result, err: err}
+	}()
+
+	stream := &eventStream{w: w, rc: http.NewResponseController(w)}
+	for {
+		select {
+		case event := <-progress:
+			if err := stream.send(eventProgress, event); err != nil {
+				cancel()
+			}
+		case outcome := <-done:
+			if outcome.err != nil {
+				h.logger.Error("failed to activate datacenter",
+					slog.String("datacenter", name),
+					slog.String("error", outcome.err.Error()),
+				)
+			}
+
+			switch {
+			case outcome.result == nil && !stream.started:
+				// Nothing was streamed yet, so the error can still be reported with its status code
+				h.respondActivation(w, nil, outcome.err)
+			case outcome.result == nil:
+				_ = stream.send(eventError, errorResponse{Error: outcome.err.Error()})
+			default:
+				_ = stream.send(eventResult, outcome.result)
+			}
+			return
+		}
+	}
+}
+
+// eventStream writes Server-Sent Events, sending the response headers with the first event
+type eventStream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+	nextID  int
+}
+
+// send writes one event and flushes it to the client
+func (s *eventStream) send(event string, data any) error {
+	if !s.started {
+		s.start()
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	s.nextID++
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", s.nextID, event, payload); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// start writes the stream headers and lifts the server write timeout for the long-lived response
+func (s *eventStream) start() {
+	_ = s.rc.SetWriteDeadline(time.Time{})
+
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.Header().Set("X-Accel-Buffering", "no") // Disable nginx response buffering
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+}
+
+// acceptsEventStream reports whether the client asked for Server-Sent Events
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
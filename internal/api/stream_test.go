@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// sseEvent is one parsed Server-Sent Event
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// parseEvents splits an event stream body into its events
+func parseEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			events = append(events, current)
+			current = sseEvent{}
+			continue
+		}
+		field, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("malformed event line %q", line)
+		}
+		switch field {
+		case "id":
+			current.id = value
+		case "event":
+			current.event = value
+		case "data":
+			current.data = value
+		default:
+			t.Fatalf("unexpected event field %q", field)
+		}
+	}
+	return events
+}
+
+// streamRequest sends a GET to the activation stream of dc1 accepting accept
+func streamRequest(t *testing.T, svc service.DatacenterService, query, accept string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/datacenters/dc1/activate/stream"+query, nil)
+	req.Header.Set("Accept", accept)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	newTestRouter(svc).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestActivateDatacenterStream(t *testing.T) {
+	var dryRun bool
+	var target string
+	svc := activationService(&dryRun, &target)
+
+	rec := streamRequest(t, svc, "?dry_run=true", "text/event-stream", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+	if target != "dc1" || !dryRun {
+		t.Errorf("service called for %q with dry run %v, want dc1 with true", target, dryRun)
+	}
+
+	events := parseEvents(t, rec.Body.String())
+	if len(events) != 1 || events[0].event != eventResult || events[0].id != "1" {
+		t.Fatalf("events = %+v, want a single result event with id 1", events)
+	}
+	var result model.ActivationResult
+	if err := json.Unmarshal([]byte(events[0].data), &result); err != nil {
+		t.Fatalf("result event data %q: %v", events[0].data, err)
+	}
+	if result.Activated != "dc1" || !result.DryRun {
+		t.Errorf("result = %+v, want the dry run of dc1", result)
+	}
+}
+
+func TestActivateDatacenterStreamWithoutEventStream(t *testing.T) {
+	var dryRun bool
+	var target string
+
+	rec := streamRequest(t, activationService(&dryRun, &target), "", "application/json", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); strings.Contains(got, "text/event-stream") {
+		t.Errorf("Content-Type = %q, want the plain JSON response", got)
+	}
+	var result model.ActivationResult
+	decodeBody(t, rec, &result)
+	if result.Activated != "dc1" {
+		t.Errorf("result = %+v, want the activation of dc1", result)
+	}
+}
+
+func TestActivateDatacenterStreamReconnect(t *testing.T) {
+	called := false
+	svc := &mockService{
+		activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			called = true
+			return &model.ActivationResult{}, nil
+		},
+	}
+
+	rec := streamRequest(t, svc, "", "text/event-stream", http.Header{"Last-Event-Id": {"3"}})
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if called {
+		t.Error("reconnecting EventSource ran the activation again")
+	}
+}
+
+func TestActivateDatacenterStreamError(t *testing.T) {
+	svc := &mockService{
+		activateDatacenter: func(context.Context, string, bool, bool, *model.DrainOverride) (*model.ActivationResult, error) {
+			return nil, &service.ActivationInProgressError{Target: "dc2"}
+		},
+	}
+
+	rec := streamRequest(t, svc, "", "text/event-stream", nil)
+
+	// Nothing was streamed before the failure, so it keeps its status code
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got == "text/event-stream" {
+		t.Errorf("Content-Type = %q for an activation that never started", got)
+	}
+	var got activationConflictResponse
+	decodeBody(t, rec, &got)
+	if got.Running != "dc2" {
+		t.Errorf("running = %q, want dc2", got.Running)
+	}
+}
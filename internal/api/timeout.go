@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// longRunningSuffixes are the paths of requests bounded by the activation timeout instead of the request timeout
+var longRunningSuffixes = []string{
+	"/activate",
+	"/activate/stream",
+	"/deactivate",
+	"/emergency/drain-all",
+	"/healthcheck/run",
+}
+
+// timeoutMiddleware bounds the context of each request by requestTimeout and answers
+// 503 Service Unavailable when it runs out, discarding whatever the handler writes late.
+// Activations and other long-running requests are not bounded by it.
+func (h *Handler) timeoutMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.requestTimeout <= 0 || isLongRunning(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), h.requestTimeout)
+		defer cancel()
+
+		tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+		next.ServeHTTP(tw, r.WithContext(ctx))
+
+		if tw.timedOut || (!tw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+			h.respondError(w, http.StatusServiceUnavailable, "request timed out after "+h.requestTimeout.String())
+		}
+	})
+}
+
+// timeoutWriter passes a response through unless it starts after the request context deadline
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+// WriteHeader forwards the status code, or marks the response as timed out after the deadline
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	if tw.wroteHeader || tw.timedOut {
+		return
+	}
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		return
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write forwards the body unless the response timed out
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.WriteHeader(http.StatusOK)
+	if tw.timedOut {
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// isLongRunning reports whether r is an activation or another request with its own time bound
+func isLongRunning(r *http.Request) bool {
+	for _, suffix := range longRunningSuffixes {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	// Handlers standing for a request finishing before or after the deadline
+	fast := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "done")
+	}
+	late := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, "late")
+	}
+	silent := func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+	answeredInTime := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "done")
+		<-r.Context().Done()
+	}
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		path         string
+		handler      http.HandlerFunc
+		wantStatus   int
+		wantBody     string // Substring of the response body
+		wantDeadline bool   // The handler's context has a deadline
+	}{
+		{name: "request within the timeout", timeout: timeout, path: "/api/status", handler: fast, wantStatus: http.StatusOK, wantBody: "done", wantDeadline: true},
+		{name: "late response is replaced", timeout: timeout, path: "/api/status", handler: late, wantStatus: http.StatusServiceUnavailable, wantBody: "request timed out after 50ms", wantDeadline: true},
+		{name: "handler writing nothing", timeout: timeout, path: "/api/status", handler: silent, wantStatus: http.StatusServiceUnavailable, wantBody: "request timed out", wantDeadline: true},
+		{name: "response started in time is kept", timeout: timeout, path: "/api/status", handler: answeredInTime, wantStatus: http.StatusOK, wantBody: "done", wantDeadline: true},
+		{name: "activation is exempt", timeout: timeout, path: "/api/datacenters/dc1/activate", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+		{name: "activation stream is exempt", timeout: timeout, path: "/api/datacenters/dc1/activate/stream", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+		{name: "deactivation is exempt", timeout: timeout, path: "/api/regions/eu/deactivate", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+		{name: "emergency drain is exempt", timeout: timeout, path: "/api/emergency/drain-all", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+		{name: "health check run is exempt", timeout: timeout, path: "/api/healthcheck/run", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+		{name: "no timeout configured", path: "/api/status", handler: fast, wantStatus: http.StatusOK, wantBody: "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&mockService{}, nil, Config{RequestTimeout: tt.timeout}, slog.New(slog.DiscardHandler))
+
+			var hasDeadline bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+				tt.handler(w, r)
+			})
+
+			rec := httptest.NewRecorder()
+			h.timeoutMiddleware(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if hasDeadline != tt.wantDeadline {
+				t.Errorf("request context has deadline %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if body := rec.Body.String(); !strings.Contains(body, tt.wantBody) || strings.Contains(body, "late") {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+		})
+	}
+}
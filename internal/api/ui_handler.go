@@ -53,7 +53,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 		// Get the requested path
 		path := r.URL.Path
 
-		h.logger.Info("UI handler request",
+		h.logger.Debug("UI handler request",
 			"path", path,
 			"basePath", h.basePath,
 		)
@@ -70,7 +70,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 			if path == "" {
 				path = "/"
 			}
-			h.logger.Info("stripped base path", "newPath", path)
+			h.logger.Debug("stripped base path", "newPath", path)
 		}
 
 		// Clean the path for filesystem lookup
@@ -84,7 +84,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 		if !isIndexRequest {
 			file, err := fsys.Open(cleanPath)
 			if err != nil {
-				h.logger.Info("file not found in embedded fs, serving index",
+				h.logger.Debug("file not found in embedded fs, serving index",
 					"path", path,
 					"cleanPath", cleanPath,
 					"error", err.Error(),
@@ -92,7 +92,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 				isIndexRequest = true // File not found - serve index for SPA routing
 			} else {
 				file.Close()
-				h.logger.Info("file found in embedded fs",
+				h.logger.Debug("file found in embedded fs",
 					"path", path,
 					"cleanPath", cleanPath,
 				)
@@ -101,7 +101,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 
 		// Serve modified index.html for SPA routes
 		if isIndexRequest && len(indexHTML) > 0 {
-			h.logger.Info("serving modified index.html")
+			h.logger.Debug("serving modified index.html")
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
 			w.WriteHeader(http.StatusOK)
 			w.Write(indexHTML)
@@ -111,7 +111,7 @@ func (h *Handler) ServeUI() http.HandlerFunc {
 		// Serve static files via file server
 		// Update request path to stripped path for fileServer
 		r.URL.Path = path
-		h.logger.Info("serving static file via fileServer", "path", path)
+		h.logger.Debug("serving static file via fileServer", "path", path)
 		fileServer.ServeHTTP(w, r)
 	}
 }
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// logRecorder is a slog.Handler keeping the records at or above Info
+type logRecorder struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (l *logRecorder) Enabled(context.Context, slog.Level) bool { return true }
+
+func (l *logRecorder) Handle(_ context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, r)
+	return nil
+}
+
+func (l *logRecorder) WithAttrs([]slog.Attr) slog.Handler { return l }
+func (l *logRecorder) WithGroup(string) slog.Handler      { return l }
+
+// messages returns the messages of the recorded records
+func (l *logRecorder) messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages := make([]string, 0, len(l.records))
+	for _, r := range l.records {
+		messages = append(messages, r.Message)
+	}
+	return messages
+}
+
+func TestRequestLogLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		basePath   string
+		statusErr  error
+		wantStatus int
+		wantInfo   bool // The access log is written at Info; no Info records at all otherwise
+	}{
+		{name: "index", target: "/", wantStatus: http.StatusOK},
+		{name: "SPA route", target: "/datacenters/dc1", wantStatus: http.StatusOK},
+		{name: "index under the base path", basePath: "/switcher", target: "/switcher/", wantStatus: http.StatusOK},
+		{name: "successful API call", target: "/api/status", wantStatus: http.StatusOK},
+		{name: "failed API call", target: "/api/status", statusErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantInfo: true},
+		{name: "unknown API route", target: "/api/unknown", wantStatus: http.StatusNotFound, wantInfo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &logRecorder{}
+			svc := &mockService{
+				getStatus: func(context.Context) (*model.ServiceStatus, error) {
+					return &model.ServiceStatus{MyDatacenter: "dc1"}, tt.statusErr
+				},
+			}
+			h := NewHandler(svc, nil, Config{BasePath: tt.basePath}, slog.New(recorder))
+
+			rec := serve(t, h.Router(), http.MethodGet, tt.target, "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			messages := recorder.messages()
+			if tt.wantInfo && !slices.Contains(messages, "http request") {
+				t.Errorf("info logs = %q, want the access log", messages)
+			}
+			if !tt.wantInfo && len(messages) > 0 {
+				t.Errorf("info logs = %q, want none", messages)
+			}
+		})
+	}
+}
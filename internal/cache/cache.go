@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"sync/atomic"
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
@@ -12,16 +13,28 @@ type Cache interface {
 	Set(key string, value any, ttl time.Duration)
 	Delete(key string)
 	Clear()
+	Stats() Stats
+}
+
+// Stats represents cache effectiveness counters
+type Stats struct {
+	Hits   uint64 // Number of Get calls that found a value
+	Misses uint64 // Number of Get calls that found nothing
+	Items  int    // Number of items currently stored (may include expired, not yet cleaned up items)
+
+	TypeMismatches uint64 // Number of GetTyped calls that found a value of another type (also counted as misses)
 }
 
 // TTLCache implements Cache interface with time-to-live support
 type TTLCache struct {
-	data *gocache.Cache
+	data       *gocache.Cache
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	mismatches atomic.Uint64
 }
 
-// New creates a new TTL cache with default cleanup interval
-func New(defaultTTL time.Duration) *TTLCache {
-	cleanupInterval := defaultTTL * 2
+// New creates a new TTL cache whose expired items are removed every cleanupInterval
+func New(defaultTTL, cleanupInterval time.Duration) *TTLCache {
 	return &TTLCache{
 		data: gocache.New(defaultTTL, cleanupInterval),
 	}
@@ -29,7 +42,47 @@ func New(defaultTTL time.Duration) *TTLCache {
 
 // Get retrieves a value from the cache
 func (c *TTLCache) Get(key string) (any, bool) {
-	return c.data.Get(key)
+	value, ok := c.data.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, ok
+}
+
+// GetTyped retrieves the value stored under key when it has type T.
+// A value of another type is removed and reported as a miss, so the caller refetches
+// and overwrites it; TTLCache counts it in Stats.TypeMismatches.
+func GetTyped[T any](c Cache, key string) (T, bool) {
+	var zero T
+
+	value, ok := c.Get(key)
+	if !ok {
+		return zero, false
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		c.Delete(key)
+		if recorder, ok := c.(typeMismatchRecorder); ok {
+			recorder.recordTypeMismatch()
+		}
+		return zero, false
+	}
+	return typed, true
+}
+
+// typeMismatchRecorder is implemented by caches that count GetTyped type mismatches
+type typeMismatchRecorder interface {
+	recordTypeMismatch()
+}
+
+// recordTypeMismatch turns the hit counted by Get into a miss and counts the mismatch
+func (c *TTLCache) recordTypeMismatch() {
+	c.hits.Add(^uint64(0))
+	c.misses.Add(1)
+	c.mismatches.Add(1)
 }
 
 // Set stores a value in the cache with the specified TTL
@@ -46,3 +99,14 @@ func (c *TTLCache) Delete(key string) {
 func (c *TTLCache) Clear() {
 	c.data.Flush()
 }
+
+// Stats returns hit/miss counters and the current item count
+func (c *TTLCache) Stats() Stats {
+	return Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Items:  c.data.ItemCount(),
+
+		TypeMismatches: c.mismatches.Load(),
+	}
+}
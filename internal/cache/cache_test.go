@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(c *TTLCache)
+		want  Stats
+	}{
+		{
+			name:  "empty",
+			steps: func(*TTLCache) {},
+		},
+		{
+			name: "hit and miss",
+			steps: func(c *TTLCache) {
+				c.Set("a", 1, time.Minute)
+				c.Get("a")
+				c.Get("b")
+			},
+			want: Stats{Hits: 1, Misses: 1, Items: 1},
+		},
+		{
+			name: "deleted key misses",
+			steps: func(c *TTLCache) {
+				c.Set("a", 1, time.Minute)
+				c.Delete("a")
+				c.Get("a")
+			},
+			want: Stats{Misses: 1},
+		},
+		{
+			name: "expired key misses",
+			steps: func(c *TTLCache) {
+				c.Set("a", 1, time.Millisecond)
+				time.Sleep(5 * time.Millisecond)
+				c.Get("a")
+			},
+			want: Stats{Misses: 1, Items: 1}, // Not cleaned up yet
+		},
+		{
+			name: "clear keeps the counters",
+			steps: func(c *TTLCache) {
+				c.Set("a", 1, time.Minute)
+				c.Set("b", 2, time.Minute)
+				c.Get("a")
+				c.Clear()
+				c.Get("a")
+			},
+			want: Stats{Hits: 1, Misses: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(time.Minute, time.Hour)
+			tt.steps(c)
+
+			if got := c.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	const (
+		workers = 16
+		rounds  = 500
+		keys    = 10
+	)
+
+	c := New(time.Minute, time.Hour)
+	for i := range keys {
+		c.Set(fmt.Sprintf("key-%d", i), i, time.Minute)
+	}
+
+	// Half the lookups target stored keys, the other half keys that are never set
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				c.Set(fmt.Sprintf("key-%d", i%keys), w, time.Minute)
+				c.Get(fmt.Sprintf("key-%d", i%keys))
+				c.Get(fmt.Sprintf("missing-%d", i%keys))
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := Stats{Hits: workers * rounds, Misses: workers * rounds, Items: keys}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCleanupInterval(t *testing.T) {
+	const ttl = 5 * time.Millisecond
+
+	tests := []struct {
+		name            string
+		cleanupInterval time.Duration
+		wantRemoved     bool
+	}{
+		{name: "expired items are removed", cleanupInterval: 10 * time.Millisecond, wantRemoved: true},
+		{name: "cleanup disabled", cleanupInterval: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(time.Minute, tt.cleanupInterval)
+			c.Set("nodes", []string{"n1"}, ttl)
+			c.Set("jobs", []string{"j1"}, time.Minute)
+
+			time.Sleep(ttl + 100*time.Millisecond)
+
+			// Expired items are never returned, they only count until removed
+			if _, ok := c.Get("nodes"); ok {
+				t.Error("expired item returned")
+			}
+			wantItems := 2
+			if tt.wantRemoved {
+				wantItems = 1
+			}
+			if got := c.Stats().Items; got != wantItems {
+				t.Errorf("Items = %d, want %d", got, wantItems)
+			}
+		})
+	}
+}
+
+// mapCache is a Cache without type mismatch counting
+type mapCache map[string]any
+
+func (m mapCache) Get(key string) (any, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+func (m mapCache) Set(key string, value any, _ time.Duration) {
+	m[key] = value
+}
+
+func (m mapCache) Delete(key string) {
+	delete(m, key)
+}
+
+func (m mapCache) Clear() {
+	clear(m)
+}
+
+func (m mapCache) Stats() Stats {
+	return Stats{Items: len(m)}
+}
+
+func TestGetTyped(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    any // Value stored under the key, nil for none
+		want      []string
+		wantOK    bool
+		wantStats Stats
+	}{
+		{name: "hit", stored: []string{"n1"}, want: []string{"n1"}, wantOK: true, wantStats: Stats{Hits: 1, Items: 1}},
+		{name: "miss", wantStats: Stats{Misses: 1}},
+		{name: "wrong type", stored: []int{1}, wantStats: Stats{Misses: 1, TypeMismatches: 1}},
+		{name: "pointer to the type", stored: &[]string{"n1"}, wantStats: Stats{Misses: 1, TypeMismatches: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(time.Minute, time.Hour)
+			if tt.stored != nil {
+				c.Set("nodes", tt.stored, time.Minute)
+			}
+
+			got, ok := GetTyped[[]string](c, "nodes")
+			if ok != tt.wantOK || !slices.Equal(got, tt.want) {
+				t.Errorf("GetTyped() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+			if stats := c.Stats(); stats != tt.wantStats {
+				t.Errorf("Stats() = %+v, want %+v", stats, tt.wantStats)
+			}
+
+			// A mismatched value is removed so the caller's refetch replaces it
+			if _, stored := c.Get("nodes"); stored != tt.wantOK {
+				t.Errorf("value still stored = %v, want %v", stored, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetTypedWithoutMismatchCounting(t *testing.T) {
+	c := mapCache{"nodes": 42}
+
+	if got, ok := GetTyped[[]string](c, "nodes"); ok || got != nil {
+		t.Errorf("GetTyped() = %v, %v, want nil, false", got, ok)
+	}
+	if _, ok := c["nodes"]; ok {
+		t.Error("mismatched value kept in the cache")
+	}
+}
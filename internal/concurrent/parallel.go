@@ -17,6 +17,7 @@ type Task[T any] func(ctx context.Context) (T, error)
 
 // ParallelExecute executes tasks in parallel and returns all results
 // It waits for all tasks to complete, even if some fail
+// Tasks that have not started when ctx is cancelled are skipped and report ctx.Err()
 func ParallelExecute[T any](ctx context.Context, tasks []Task[T]) []Result[T] {
 	results := make([]Result[T], len(tasks))
 	var wg sync.WaitGroup
@@ -25,6 +26,13 @@ func ParallelExecute[T any](ctx context.Context, tasks []Task[T]) []Result[T] {
 		wg.Add(1)
 		go func(index int, t Task[T]) {
 			defer wg.Done()
+
+			// Don't start new work once the context is cancelled
+			if err := ctx.Err(); err != nil {
+				results[index] = Result[T]{Error: err, Index: index}
+				return
+			}
+
 			value, err := t(ctx)
 			results[index] = Result[T]{
 				Value: value,
@@ -40,6 +48,7 @@ func ParallelExecute[T any](ctx context.Context, tasks []Task[T]) []Result[T] {
 
 // ParallelExecuteWithLimit executes tasks in parallel with a concurrency limit
 // maxConcurrent specifies the maximum number of tasks running simultaneously
+// Tasks that have not started when ctx is cancelled are skipped and report ctx.Err()
 func ParallelExecuteWithLimit[T any](ctx context.Context, tasks []Task[T], maxConcurrent int) []Result[T] {
 	if maxConcurrent <= 0 {
 		maxConcurrent = len(tasks) // No limit
@@ -60,6 +69,12 @@ func ParallelExecuteWithLimit[T any](ctx context.Context, tasks []Task[T], maxCo
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }() // Release semaphore
 
+			// Don't start new work once the context is cancelled
+			if err := ctx.Err(); err != nil {
+				results[index] = Result[T]{Error: err, Index: index}
+				return
+			}
+
 			value, err := t(ctx)
 			results[index] = Result[T]{
 				Value: value,
@@ -0,0 +1,77 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelExecuteStopsOnCancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		execute func(ctx context.Context, tasks []Task[int]) []Result[int]
+	}{
+		{
+			name:    "unlimited",
+			execute: ParallelExecute[int],
+		},
+		{
+			name: "limited",
+			execute: func(ctx context.Context, tasks []Task[int]) []Result[int] {
+				return ParallelExecuteWithLimit(ctx, tasks, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" cancelled before start", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			var started atomic.Int32
+			tasks := make([]Task[int], 5)
+			for i := range tasks {
+				tasks[i] = func(context.Context) (int, error) {
+					started.Add(1)
+					return i, nil
+				}
+			}
+
+			results := tt.execute(ctx, tasks)
+
+			if started.Load() != 0 {
+				t.Errorf("%d tasks started after cancellation", started.Load())
+			}
+			for i, result := range results {
+				if !errors.Is(result.Error, context.Canceled) || result.Index != i {
+					t.Errorf("result %d = %+v, want context.Canceled", i, result)
+				}
+			}
+		})
+	}
+
+	t.Run("limited cancelled by the first task", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var started atomic.Int32
+		tasks := make([]Task[int], 5)
+		for i := range tasks {
+			tasks[i] = func(context.Context) (int, error) {
+				started.Add(1)
+				cancel()
+				return i, nil
+			}
+		}
+
+		results := ParallelExecuteWithLimit(ctx, tasks, 1)
+
+		if started.Load() != 1 {
+			t.Errorf("%d tasks started, want 1", started.Load())
+		}
+		if got := len(AllErrors(results)); got != 4 {
+			t.Errorf("%d skipped tasks, want 4", got)
+		}
+	})
+}
@@ -1,92 +1,323 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/logger"
 )
 
+// LogLevelEnv is the environment variable overriding logging.level
+const LogLevelEnv = "DC_SWITCHER_LOG_LEVEL"
+
+// DefaultEtcdKeyPrefix namespaces etcd keys when etcd.key_prefix is not set
+const DefaultEtcdKeyPrefix = "dc-switcher/"
+
 // Config represents the application configuration
 type Config struct {
-	Server                ServerConfig      `koanf:"server"`
-	Cache                 CacheConfig       `koanf:"cache"`
-	HealthCheck           HealthCheckConfig `koanf:"health_check"`
-	Etcd                  EtcdConfig        `koanf:"etcd"`
-	Heartbeat             HeartbeatConfig   `koanf:"heartbeat"`
-	MyDatacenter          string            `koanf:"my_datacenter"`          // Name of the local datacenter this instance manages
-	ClusterRetryInterval  time.Duration     `koanf:"cluster_retry_interval"` // How often to retry unavailable clusters
-	Clusters              []ClusterConfig   `koanf:"clusters"`
-	SkipUnhealthyClusters bool              `koanf:"skip_unhealthy_clusters"`
+	Server                      ServerConfig        `koanf:"server"`
+	Logging                     LoggingConfig       `koanf:"logging"`
+	CORS                        CORSConfig          `koanf:"cors"`
+	Auth                        AuthConfig          `koanf:"auth"`
+	Cache                       CacheConfig         `koanf:"cache"`
+	HealthCheck                 HealthCheckConfig   `koanf:"health_check"`
+	Failover                    FailoverConfig      `koanf:"failover"`
+	Etcd                        EtcdConfig          `koanf:"etcd"`
+	Heartbeat                   HeartbeatConfig     `koanf:"heartbeat"`
+	Startup                     StartupConfig       `koanf:"startup"`
+	Shutdown                    ShutdownConfig      `koanf:"shutdown"`
+	MyDatacenter                string              `koanf:"my_datacenter"`                  // Name of the local datacenter this instance manages
+	PreferredDatacenter         string              `koanf:"preferred_datacenter"`           // Datacenter whose region is kept when several regions are active
+	ActiveMode                  string              `koanf:"active_mode"`                    // single | region-wide
+	ClusterRetryInterval        time.Duration       `koanf:"cluster_retry_interval"`         // How often to retry unavailable clusters
+	MaxConcurrentNodeOperations int                 `koanf:"max_concurrent_node_operations"` // Maximum number of simultaneous node drain operations
+	ActivationRateLimit         int                 `koanf:"activation_rate_limit"`          // Activations per minute per endpoint (0 disables the limit)
+	DegradedJobFailureRatio     float64             `koanf:"degraded_job_failure_ratio"`     // Fraction of jobs with failed allocations that marks an active region as degraded
+	Drain                       DrainConfig         `koanf:"drain"`
+	Activation                  ActivationConfig    `koanf:"activation"`
+	Safety                      SafetyConfig        `koanf:"safety"`
+	Retry                       RetryConfig         `koanf:"retry"`
+	Nomad                       NomadConfig         `koanf:"nomad"`
+	Evaluations                 EvaluationsConfig   `koanf:"evaluations"`
+	Notifications               NotificationsConfig `koanf:"notifications"`
+	Clusters                    []ClusterConfig     `koanf:"clusters"`
+	SkipUnhealthyClusters       bool                `koanf:"skip_unhealthy_clusters"`
+	ReadOnly                    bool                `koanf:"read_only"` // Observe only - disable all mutating operations
 }
 
 // ServerConfig represents HTTP server configuration
 type ServerConfig struct {
-	Addr         string        `koanf:"addr"`
-	ReadTimeout  time.Duration `koanf:"read_timeout"`
-	WriteTimeout time.Duration `koanf:"write_timeout"`
-	BasePath     string        `koanf:"base_path"` // Optional base path for reverse proxy (e.g., "/dc-switcher")
+	Addr              string        `koanf:"addr"`
+	ReadTimeout       time.Duration `koanf:"read_timeout"`
+	ReadHeaderTimeout time.Duration `koanf:"read_header_timeout"` // 0 falls back to read_timeout
+	WriteTimeout      time.Duration `koanf:"write_timeout"`
+	IdleTimeout       time.Duration `koanf:"idle_timeout"`    // Keep-alive idle time, 0 falls back to read_timeout
+	RequestTimeout    time.Duration `koanf:"request_timeout"` // Upper bound for API requests other than activations
+	BasePath          string        `koanf:"base_path"`       // Optional base path for reverse proxy (e.g., "/dc-switcher")
+	TLS               *TLSConfig    `koanf:"tls"`             // Serve HTTPS when set; ca enables client certificate verification
+
+	ShutdownTimeout time.Duration `koanf:"shutdown_timeout"` // How long shutdown waits for an in-progress activation
+	GracefulTimeout time.Duration `koanf:"graceful_timeout"` // How long shutdown waits for in-flight HTTP requests
+}
+
+// LoggingConfig represents logger configuration
+type LoggingConfig struct {
+	Level  string `koanf:"level"`  // debug | info | warn | error (overridden by DC_SWITCHER_LOG_LEVEL)
+	Format string `koanf:"format"` // json | text
+}
+
+// CORSConfig represents cross-origin request configuration, meant for UI development
+type CORSConfig struct {
+	Enabled        bool          `koanf:"enabled"`
+	AllowedOrigins []string      `koanf:"allowed_origins"` // Exact origins, or "*" for any origin
+	AllowedMethods []string      `koanf:"allowed_methods"`
+	AllowedHeaders []string      `koanf:"allowed_headers"`
+	MaxAge         time.Duration `koanf:"max_age"` // How long browsers may cache preflight results
+}
+
+// AuthConfig represents API bearer-token authentication; it is enabled when any token is set
+type AuthConfig struct {
+	Token           string   `koanf:"token"`             // Single token, merged into Tokens
+	Tokens          []string `koanf:"tokens"`            // Accepted tokens, e.g. one per client to allow rotation
+	RequireForReads bool     `koanf:"require_for_reads"` // Also require a token for GET requests
 }
 
 // CacheConfig represents cache configuration
 type CacheConfig struct {
-	TTL time.Duration `koanf:"ttl"`
+	TTL             time.Duration `koanf:"ttl"`              // Default TTL for all cached resources
+	NodesTTL        time.Duration `koanf:"nodes_ttl"`        // TTL for node lists (falls back to ttl)
+	JobsTTL         time.Duration `koanf:"jobs_ttl"`         // TTL for job lists (falls back to ttl)
+	CleanupInterval time.Duration `koanf:"cleanup_interval"` // How often expired items are removed (default: twice the shortest ttl)
+}
+
+// defaultCleanupInterval returns twice the shortest configured TTL, or a minute when no TTL is set.
+// A zero interval would disable cleanup, and items cached with their own TTL would never be removed.
+func (c CacheConfig) defaultCleanupInterval() time.Duration {
+	shortest := time.Duration(0)
+	for _, ttl := range []time.Duration{c.TTL, c.NodesTTL, c.JobsTTL} {
+		if ttl > 0 && (shortest == 0 || ttl < shortest) {
+			shortest = ttl
+		}
+	}
+	if shortest == 0 {
+		return time.Minute
+	}
+	return 2 * shortest
 }
 
 // HealthCheckConfig represents health check configuration for active region monitoring
 type HealthCheckConfig struct {
-	Enabled         bool          `koanf:"enabled"`
-	Interval        time.Duration `koanf:"interval"`
-	FailedThreshold int           `koanf:"failed_threshold"`
+	Enabled                   bool               `koanf:"enabled"`
+	Interval                  time.Duration      `koanf:"interval"`
+	FailedThreshold           int                `koanf:"failed_threshold"`
+	RequireQuorumConfirmation bool               `koanf:"require_quorum_confirmation"` // Re-verify leader and etcd before draining
+	ConfirmationBackoff       time.Duration      `koanf:"confirmation_backoff"`        // Delay before the confirmation check
+	BackoffMultiplier         float64            `koanf:"backoff_multiplier"`          // Growth of the check interval per consecutive failure (1 disables backoff)
+	MaxInterval               time.Duration      `koanf:"max_interval"`                // Upper bound of the check interval while backing off
+	Paused                    bool               `koanf:"paused"`                      // Start in maintenance mode: failed checks are logged but never drain
+	CheckAllDatacenters       bool               `koanf:"check_all_datacenters"`       // Check the leader of every datacenter instead of the first one
+	DatacenterQuorum          int                `koanf:"datacenter_quorum"`           // Datacenters that must report a leader when checking all (0 = majority)
+	PersistFailures           bool               `koanf:"persist_failures"`            // Save failure counters in etcd and reload them on startup
+	PersistMaxAge             time.Duration      `koanf:"persist_max_age"`             // Saved failure counters older than this are ignored on startup
+	AutoFailback              AutoFailbackConfig `koanf:"auto_failback"`
+}
+
+// AutoFailbackConfig controls automatic re-activation of a higher-priority region that recovered
+// after the health checker drained it
+type AutoFailbackConfig struct {
+	Enabled        bool          `koanf:"enabled"`
+	Priority       []string      `koanf:"priority"`        // Regions, highest priority first
+	RecoveryPeriod time.Duration `koanf:"recovery_period"` // How long a region must keep passing checks before failing back
+	Cooldown       time.Duration `koanf:"cooldown"`        // Minimum time between automatic drains and failbacks
+}
+
+// FailoverConfig controls automatic promotion of another region after the health checker drained the active one
+type FailoverConfig struct {
+	Candidates []string      `koanf:"candidates"` // Regions to promote, in order; empty disables failover
+	Cooldown   time.Duration `koanf:"cooldown"`   // Minimum time between automatic promotions
 }
 
 // EtcdConfig represents etcd cluster configuration for distributed state
 type EtcdConfig struct {
-	Endpoints   []string      `koanf:"endpoints"`
-	DialTimeout time.Duration `koanf:"dial_timeout"`
-	Username    string        `koanf:"username"`
-	Password    string        `koanf:"password"`
-	TLS         *TLSConfig    `koanf:"tls"`
+	Endpoints         []string      `koanf:"endpoints"`
+	DialTimeout       time.Duration `koanf:"dial_timeout"`
+	Username          string        `koanf:"username"`
+	Password          string        `koanf:"password"`
+	TLS               *TLSConfig    `koanf:"tls"`
+	MaxHistoryEntries int           `koanf:"max_history_entries"` // Maximum number of activation events kept in etcd
+	PingInterval      time.Duration `koanf:"ping_interval"`       // How often etcd connectivity is checked in the background
+	KeyPrefix         string        `koanf:"key_prefix"`          // Namespace of every key written by this deployment
+	OperationTimeout  time.Duration `koanf:"operation_timeout"`   // Deadline of every single etcd request
+	StartupTimeout    time.Duration `koanf:"startup_timeout"`     // Deadline per endpoint of the startup connection test (default: dial_timeout)
 }
 
 // HeartbeatConfig represents heartbeat configuration for split-brain protection
 type HeartbeatConfig struct {
 	UpdateInterval time.Duration `koanf:"update_interval"` // How often to update heartbeat in etcd
 	MaxFailures    int           `koanf:"max_failures"`    // Number of consecutive failures before draining nodes
-	StaleThreshold time.Duration `koanf:"stale_threshold"` // Age after which heartbeat is considered stale
+	StaleThreshold time.Duration `koanf:"stale_threshold"` // Age after which heartbeat is considered stale (and active key lease TTL)
+
+	WaitForDrainComplete bool          `koanf:"wait_for_drain_complete"` // Wait for allocations to leave drained nodes before reporting drained
+	DrainCompleteTimeout time.Duration `koanf:"drain_complete_timeout"`  // Maximum time to wait for allocations to leave
+	DrainPollInterval    time.Duration `koanf:"drain_poll_interval"`     // How often to poll node allocations while waiting
+
+	ReadRetries      int           `koanf:"read_retries"`       // Immediate retries of a failed active datacenter read before it counts as a failure
+	ReadRetryBackoff time.Duration `koanf:"read_retry_backoff"` // Delay before the first read retry, doubled on each attempt
+
+	JitterPercent         int  `koanf:"jitter_percent"`          // Randomize each interval by up to this percentage so instances don't hit etcd in lockstep
+	JitterBackgroundLoops bool `koanf:"jitter_background_loops"` // Apply the same jitter to the cluster retry and health check intervals
+}
+
+// BackgroundJitterPercent returns the jitter applied to the cluster retry and health check intervals
+func (h HeartbeatConfig) BackgroundJitterPercent() int {
+	if !h.JitterBackgroundLoops {
+		return 0
+	}
+	return h.JitterPercent
+}
+
+// StartupConfig represents how the startup reconciliation with etcd treats this instance
+type StartupConfig struct {
+	AutoActivateIfSoleInstance bool `koanf:"auto_activate_if_sole_instance"` // Stay active instead of draining when etcd records no active datacenter
+}
+
+// ShutdownConfig controls what the instance leaves behind in etcd on graceful shutdown
+type ShutdownConfig struct {
+	RelinquishOnExit bool `koanf:"relinquish_on_exit"` // Remove my datacenter from the active datacenter key on SIGINT/SIGTERM
+}
+
+// Active modes control how many datacenters of the active region are recorded as active
+const (
+	ActiveModeSingle     = "single"      // One datacenter is active, region activations record the first datacenter of the region
+	ActiveModeRegionWide = "region-wide" // Region activations record every datacenter of the region as active
+)
+
+// DrainConfig represents how nodes are drained when a datacenter is deactivated
+type DrainConfig struct {
+	Deadline         time.Duration `koanf:"deadline"`           // Negative means no deadline, 0 force-stops allocations immediately
+	IgnoreSystemJobs bool          `koanf:"ignore_system_jobs"` // Leave system jobs running on drained nodes
+}
+
+// Activation strategies control how the nodes of an activated datacenter are undrained
+const (
+	ActivationStrategyImmediate = "immediate" // Undrain all nodes at once
+	ActivationStrategyStaged    = "staged"    // Undrain nodes in batches, evaluating jobs after each batch
+)
+
+// ActivationConfig represents how an activation undrains the nodes of the activated datacenter
+type ActivationConfig struct {
+	Strategy   string        `koanf:"strategy"`    // immediate | staged
+	BatchSize  int           `koanf:"batch_size"`  // Nodes undrained per batch with the staged strategy
+	BatchPause time.Duration `koanf:"batch_pause"` // Wait between batches with the staged strategy
+}
+
+// ValidateActivationStrategy checks that strategy is a known activation strategy
+func ValidateActivationStrategy(strategy string) error {
+	switch strategy {
+	case ActivationStrategyImmediate, ActivationStrategyStaged:
+		return nil
+	default:
+		return fmt.Errorf("unknown activation strategy %q (want %s or %s)", strategy, ActivationStrategyImmediate, ActivationStrategyStaged)
+	}
+}
+
+// SafetyConfig represents guardrails against activations with an unexpectedly large impact
+type SafetyConfig struct {
+	MaxNodesAffected int `koanf:"max_nodes_affected"` // Node changes an activation may apply without ?confirm=true (0 disables the cap)
+}
+
+// RetryConfig represents the retry policy for Nomad node drain updates
+type RetryConfig struct {
+	MaxRetries  int           `koanf:"max_retries"`  // Retries after the first attempt (0 disables retries)
+	BaseBackoff time.Duration `koanf:"base_backoff"` // Delay before the first retry, doubled on each attempt
+	MaxBackoff  time.Duration `koanf:"max_backoff"`  // Upper bound for the delay between retries
+}
+
+// NomadConfig controls how node drains reach Nomad when the Server API fails
+type NomadConfig struct {
+	EnableDirectFallback  bool          `koanf:"enable_direct_fallback"`  // Retry failed drain updates against the node's own Client API
+	DirectFallbackTimeout time.Duration `koanf:"direct_fallback_timeout"` // Deadline of a direct Client API request
+}
+
+// EvaluationsConfig controls the job evaluations forced after a datacenter is activated
+type EvaluationsConfig struct {
+	OnlyUnplaced  bool `koanf:"only_unplaced"`  // Evaluate only pending jobs and jobs with queued allocations
+	MaxConcurrent int  `koanf:"max_concurrent"` // Maximum number of simultaneous evaluation requests
+}
+
+// NotificationsConfig represents failover event notification configuration
+type NotificationsConfig struct {
+	WebhookURL string        `koanf:"webhook_url"` // Generic webhook receiving JSON events (empty disables notifications)
+	Timeout    time.Duration `koanf:"timeout"`     // Timeout for a single webhook request
 }
 
 // ClusterConfig represents a single Nomad cluster configuration
 type ClusterConfig struct {
-	Name    string     `koanf:"name"`
-	Region  string     `koanf:"region"`
-	Address string     `koanf:"address"`
-	TLS     *TLSConfig `koanf:"tls"`
+	Name         string            `koanf:"name"`
+	Region       string            `koanf:"region"`
+	Address      string            `koanf:"address"`
+	Namespace    string            `koanf:"namespace"` // Nomad namespace for job operations ("*" for all namespaces)
+	TLS          *TLSConfig        `koanf:"tls"`
+	ExtraHeaders map[string]string `koanf:"extra_headers"` // Sent with every Nomad request, e.g. for an auth proxy in front of Nomad
+	Enabled      *bool             `koanf:"enabled"`       // Disabled clusters are listed read-only and left out of drain logic (default: true)
 }
 
-// TLSConfig represents TLS configuration for Nomad client
+// IsEnabled reports whether the cluster takes part in activation; unset means enabled
+func (c ClusterConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// TLSConfig represents TLS configuration for Nomad and etcd clients and the HTTP server
 type TLSConfig struct {
 	CA   string `koanf:"ca"`
 	Cert string `koanf:"cert"`
 	Key  string `koanf:"key"`
 }
 
-// Load loads configuration from the specified file
+// Load loads configuration from the specified file and merges DC_SWITCHER_* environment
+// variables over it. The file is optional when the environment provides the configuration.
 func Load(configPath string) (*Config, error) {
 	k := koanf.New(".")
 
 	// Load YAML config
-	if err := k.Load(file.Provider(configPath), yaml.Parser()); err != nil {
+	if _, err := os.Stat(configPath); err == nil {
+		if err := k.Load(file.Provider(configPath), yaml.Parser()); err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	var cfg Config
+	// Environment variables take precedence over the file
+	if err := k.Load(&envProvider{prefix: EnvPrefix}, nil); err != nil {
+		return nil, fmt.Errorf("failed to load environment overrides: %w", err)
+	}
+
+	// Defaults that can't be detected from a zero value in Validate (0 is a valid drain deadline)
+	cfg := Config{
+		Drain:     DrainConfig{Deadline: -1}, // No deadline
+		Retry:     RetryConfig{MaxRetries: 3},
+		Nomad:     NomadConfig{EnableDirectFallback: true},
+		Heartbeat: HeartbeatConfig{ReadRetries: 2},
+	}
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Allow raising verbosity for debugging without editing the config file
+	if level := os.Getenv(LogLevelEnv); level != "" {
+		cfg.Logging.Level = level
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -109,9 +340,88 @@ func (c *Config) Validate() error {
 		if cluster.Address == "" {
 			return fmt.Errorf("cluster[%d].address is required", i)
 		}
+		address, err := normalizeClusterAddress(cluster.Address, cluster.TLS != nil)
+		if err != nil {
+			return fmt.Errorf("cluster[%d].address %q is invalid: %w", i, cluster.Address, err)
+		}
+		c.Clusters[i].Address = address
 		// Name and Region are optional - they will be auto-detected from Nomad API if not specified
 	}
 
+	// Validate server configuration
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 ||
+		c.Server.IdleTimeout < 0 || c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server timeouts must not be negative")
+	}
+	if c.Server.RequestTimeout == 0 {
+		c.Server.RequestTimeout = 30 * time.Second // Default
+	}
+	if c.Server.TLS != nil && (c.Server.TLS.Cert == "" || c.Server.TLS.Key == "") {
+		return fmt.Errorf("server.tls.cert and server.tls.key are required when server.tls is set")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		c.Server.ShutdownTimeout = 30 * time.Second // Default
+	}
+	if c.Server.GracefulTimeout <= 0 {
+		c.Server.GracefulTimeout = 30 * time.Second // Default
+	}
+
+	// Validate logging configuration
+	if c.Logging.Level == "" {
+		c.Logging.Level = "info" // Default
+	}
+	if _, err := logger.ParseLevel(c.Logging.Level); err != nil {
+		return fmt.Errorf("logging.level: %w", err)
+	}
+	if c.Logging.Format == "" {
+		c.Logging.Format = logger.FormatJSON // Default
+	}
+	if err := logger.ValidateFormat(c.Logging.Format); err != nil {
+		return fmt.Errorf("logging.format: %w", err)
+	}
+
+	// Validate CORS configuration
+	if c.CORS.Enabled {
+		if len(c.CORS.AllowedOrigins) == 0 {
+			return fmt.Errorf("cors.allowed_origins is required when cors is enabled")
+		}
+		if len(c.CORS.AllowedMethods) == 0 {
+			c.CORS.AllowedMethods = []string{"GET", "POST", "OPTIONS"} // Default
+		}
+		if len(c.CORS.AllowedHeaders) == 0 {
+			c.CORS.AllowedHeaders = []string{"Content-Type", "Authorization"} // Default
+		}
+	}
+
+	// Validate auth configuration
+	if c.Auth.Token != "" {
+		c.Auth.Tokens = append(c.Auth.Tokens, c.Auth.Token)
+	}
+	for i, token := range c.Auth.Tokens {
+		if token == "" {
+			return fmt.Errorf("auth.tokens[%d] must not be empty", i)
+		}
+	}
+
+	// Validate cache configuration
+	if c.Cache.NodesTTL <= 0 {
+		c.Cache.NodesTTL = c.Cache.TTL // Default: global TTL
+	}
+	if c.Cache.JobsTTL <= 0 {
+		c.Cache.JobsTTL = c.Cache.TTL // Default: global TTL
+	}
+	if c.Cache.CleanupInterval < 0 {
+		return fmt.Errorf("cache.cleanup_interval must not be negative")
+	}
+	if c.Cache.CleanupInterval == 0 {
+		c.Cache.CleanupInterval = c.Cache.defaultCleanupInterval()
+	}
+
+	// Validate notifications configuration
+	if c.Notifications.Timeout <= 0 {
+		c.Notifications.Timeout = 5 * time.Second // Default
+	}
+
 	// Validate health check configuration
 	if c.HealthCheck.Enabled {
 		if c.HealthCheck.Interval <= 0 {
@@ -120,6 +430,64 @@ func (c *Config) Validate() error {
 		if c.HealthCheck.FailedThreshold <= 0 {
 			return fmt.Errorf("health_check.failed_threshold must be positive when health check is enabled")
 		}
+		if c.HealthCheck.ConfirmationBackoff <= 0 {
+			c.HealthCheck.ConfirmationBackoff = 5 * time.Second // Default
+		}
+		if c.HealthCheck.BackoffMultiplier == 0 {
+			c.HealthCheck.BackoffMultiplier = 2 // Default
+		}
+		if c.HealthCheck.BackoffMultiplier < 1 {
+			return fmt.Errorf("health_check.backoff_multiplier must be at least 1")
+		}
+		if c.HealthCheck.MaxInterval == 0 {
+			c.HealthCheck.MaxInterval = 4 * c.HealthCheck.Interval // Default
+		}
+		if c.HealthCheck.MaxInterval < c.HealthCheck.Interval {
+			return fmt.Errorf("health_check.max_interval must not be shorter than health_check.interval")
+		}
+		if c.HealthCheck.DatacenterQuorum < 0 {
+			return fmt.Errorf("health_check.datacenter_quorum must not be negative")
+		}
+		if c.HealthCheck.PersistMaxAge < 0 {
+			return fmt.Errorf("health_check.persist_max_age must not be negative")
+		}
+		if c.HealthCheck.PersistMaxAge == 0 {
+			c.HealthCheck.PersistMaxAge = 10 * time.Minute // Default
+		}
+		if c.HealthCheck.AutoFailback.Enabled {
+			if len(c.HealthCheck.AutoFailback.Priority) == 0 {
+				return fmt.Errorf("health_check.auto_failback.priority is required when auto failback is enabled")
+			}
+			if c.HealthCheck.AutoFailback.RecoveryPeriod < 0 || c.HealthCheck.AutoFailback.Cooldown < 0 {
+				return fmt.Errorf("health_check.auto_failback periods must not be negative")
+			}
+			if c.HealthCheck.AutoFailback.RecoveryPeriod == 0 {
+				c.HealthCheck.AutoFailback.RecoveryPeriod = 5 * time.Minute // Default
+			}
+			if c.HealthCheck.AutoFailback.Cooldown == 0 {
+				c.HealthCheck.AutoFailback.Cooldown = 15 * time.Minute // Default
+			}
+		}
+	}
+
+	// Validate failover configuration
+	if len(c.Failover.Candidates) > 0 {
+		if !c.HealthCheck.Enabled {
+			return fmt.Errorf("failover.candidates requires health_check.enabled")
+		}
+		seen := make(map[string]bool, len(c.Failover.Candidates))
+		for _, region := range c.Failover.Candidates {
+			if region == "" || seen[region] {
+				return fmt.Errorf("failover.candidates must list distinct, non-empty regions")
+			}
+			seen[region] = true
+		}
+		if c.Failover.Cooldown < 0 {
+			return fmt.Errorf("failover.cooldown must not be negative")
+		}
+		if c.Failover.Cooldown == 0 {
+			c.Failover.Cooldown = 15 * time.Minute // Default
+		}
 	}
 
 	// Validate my_datacenter
@@ -134,6 +502,27 @@ func (c *Config) Validate() error {
 	if c.Etcd.DialTimeout <= 0 {
 		c.Etcd.DialTimeout = 5 * time.Second // Default
 	}
+	if c.Etcd.StartupTimeout <= 0 {
+		c.Etcd.StartupTimeout = c.Etcd.DialTimeout // Default
+	}
+	if c.Etcd.MaxHistoryEntries <= 0 {
+		c.Etcd.MaxHistoryEntries = 100 // Default
+	}
+	if c.Etcd.PingInterval <= 0 {
+		c.Etcd.PingInterval = 10 * time.Second // Default
+	}
+	if c.Etcd.OperationTimeout <= 0 {
+		c.Etcd.OperationTimeout = 5 * time.Second // Default
+	}
+	if c.Etcd.KeyPrefix == "" {
+		c.Etcd.KeyPrefix = DefaultEtcdKeyPrefix
+	}
+	if strings.Trim(c.Etcd.KeyPrefix, "/ ") == "" {
+		return fmt.Errorf("etcd.key_prefix must not be empty")
+	}
+	if !strings.HasSuffix(c.Etcd.KeyPrefix, "/") {
+		c.Etcd.KeyPrefix += "/"
+	}
 
 	// Validate heartbeat configuration
 	if c.Heartbeat.UpdateInterval <= 0 {
@@ -145,11 +534,133 @@ func (c *Config) Validate() error {
 	if c.Heartbeat.StaleThreshold <= 0 {
 		c.Heartbeat.StaleThreshold = 2 * time.Minute // Default
 	}
+	if c.Heartbeat.DrainCompleteTimeout <= 0 {
+		c.Heartbeat.DrainCompleteTimeout = 5 * time.Minute // Default
+	}
+	if c.Heartbeat.DrainPollInterval <= 0 {
+		c.Heartbeat.DrainPollInterval = 5 * time.Second // Default
+	}
+	if c.Heartbeat.ReadRetries < 0 {
+		return fmt.Errorf("heartbeat.read_retries must not be negative")
+	}
+	if c.Heartbeat.ReadRetryBackoff <= 0 {
+		c.Heartbeat.ReadRetryBackoff = 500 * time.Millisecond // Default
+	}
+	if c.Heartbeat.JitterPercent < 0 || c.Heartbeat.JitterPercent > 100 {
+		return fmt.Errorf("heartbeat.jitter_percent must be between 0 and 100")
+	}
 
 	// Validate cluster retry interval
 	if c.ClusterRetryInterval <= 0 {
 		c.ClusterRetryInterval = 5 * time.Minute // Default: retry every 5 minutes
 	}
 
+	// Validate node operation concurrency
+	if c.MaxConcurrentNodeOperations < 0 {
+		return fmt.Errorf("max_concurrent_node_operations must be positive")
+	}
+	if c.MaxConcurrentNodeOperations == 0 {
+		c.MaxConcurrentNodeOperations = 10 // Default
+	}
+
+	// Validate activation rate limit
+	if c.ActivationRateLimit < 0 {
+		return fmt.Errorf("activation_rate_limit must not be negative")
+	}
+
+	// Validate degraded region threshold
+	if c.DegradedJobFailureRatio == 0 {
+		c.DegradedJobFailureRatio = 0.5 // Default
+	}
+	if c.DegradedJobFailureRatio < 0 || c.DegradedJobFailureRatio > 1 {
+		return fmt.Errorf("degraded_job_failure_ratio must be between 0 and 1")
+	}
+
+	// Validate active mode
+	if c.ActiveMode == "" {
+		c.ActiveMode = ActiveModeSingle // Default
+	}
+	if c.ActiveMode != ActiveModeSingle && c.ActiveMode != ActiveModeRegionWide {
+		return fmt.Errorf("unknown active_mode %q (want %s or %s)", c.ActiveMode, ActiveModeSingle, ActiveModeRegionWide)
+	}
+	if c.ActiveMode == ActiveModeRegionWide {
+		// Every datacenter of the active region serves traffic, so the health check covers all of them
+		c.HealthCheck.CheckAllDatacenters = true
+	}
+
+	// Validate activation strategy
+	if c.Activation.Strategy == "" {
+		c.Activation.Strategy = ActivationStrategyImmediate // Default
+	}
+	if err := ValidateActivationStrategy(c.Activation.Strategy); err != nil {
+		return fmt.Errorf("activation.strategy: %w", err)
+	}
+	if c.Activation.BatchSize < 0 {
+		return fmt.Errorf("activation.batch_size must not be negative")
+	}
+	if c.Activation.BatchSize == 0 {
+		c.Activation.BatchSize = 5 // Default
+	}
+	if c.Activation.BatchPause < 0 {
+		return fmt.Errorf("activation.batch_pause must not be negative")
+	}
+	if c.Activation.BatchPause == 0 {
+		c.Activation.BatchPause = 30 * time.Second // Default
+	}
+
+	// Validate safety guardrails
+	if c.Safety.MaxNodesAffected < 0 {
+		return fmt.Errorf("safety.max_nodes_affected must not be negative")
+	}
+
+	// Validate retry policy
+	if c.Retry.MaxRetries < 0 {
+		return fmt.Errorf("retry.max_retries must not be negative")
+	}
+	if c.Retry.BaseBackoff <= 0 {
+		c.Retry.BaseBackoff = 500 * time.Millisecond // Default
+	}
+	if c.Retry.MaxBackoff <= 0 {
+		c.Retry.MaxBackoff = 10 * time.Second // Default
+	}
+
+	// Validate direct fallback
+	if c.Nomad.DirectFallbackTimeout <= 0 {
+		c.Nomad.DirectFallbackTimeout = 5 * time.Second // Default
+	}
+
+	// Validate job evaluations
+	if c.Evaluations.MaxConcurrent < 0 {
+		return fmt.Errorf("evaluations.max_concurrent must not be negative")
+	}
+	if c.Evaluations.MaxConcurrent == 0 {
+		c.Evaluations.MaxConcurrent = 10 // Default
+	}
+
 	return nil
 }
+
+// normalizeClusterAddress checks that address is an http(s) URL with a host.
+// A bare host:port gets the https scheme when TLS is configured and http otherwise.
+func normalizeClusterAddress(address string, tls bool) (string, error) {
+	if !strings.Contains(address, "://") {
+		scheme := "http"
+		if tls {
+			scheme = "https"
+		}
+		address = scheme + "://" + address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", errors.New("host is required")
+	}
+
+	return address, nil
+}
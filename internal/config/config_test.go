@@ -0,0 +1,329 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns the smallest configuration that passes Validate
+func validConfig() *Config {
+	return &Config{
+		Server:       ServerConfig{Addr: ":8080"},
+		Clusters:     []ClusterConfig{{Name: "dc1", Region: "eu", Address: "http://nomad-dc1:4646"}},
+		MyDatacenter: "dc1",
+		Etcd:         EtcdConfig{Endpoints: []string{"etcd:2379"}},
+	}
+}
+
+// checkValidate runs Validate and compares the error with wantErr, a substring of the message ("" for none)
+func checkValidate(t *testing.T, cfg *Config, wantErr string) {
+	t.Helper()
+
+	err := cfg.Validate()
+	switch {
+	case wantErr == "" && err != nil:
+		t.Fatalf("Validate() error = %v, want nil", err)
+	case wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)):
+		t.Fatalf("Validate() error = %v, want %q", err, wantErr)
+	}
+}
+
+func TestValidateMaxConcurrentNodeOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   int
+		want    int
+		wantErr string
+	}{
+		{name: "default", value: 0, want: 10},
+		{name: "explicit", value: 3, want: 3},
+		{name: "negative", value: -1, wantErr: "max_concurrent_node_operations must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.MaxConcurrentNodeOperations = tt.value
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && cfg.MaxConcurrentNodeOperations != tt.want {
+				t.Errorf("max_concurrent_node_operations = %d, want %d", cfg.MaxConcurrentNodeOperations, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   RetryConfig
+		want    RetryConfig
+		wantErr string
+	}{
+		{name: "defaults", want: RetryConfig{BaseBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second}},
+		{
+			name:  "explicit",
+			retry: RetryConfig{MaxRetries: 5, BaseBackoff: time.Second, MaxBackoff: time.Minute},
+			want:  RetryConfig{MaxRetries: 5, BaseBackoff: time.Second, MaxBackoff: time.Minute},
+		},
+		{name: "negative retries", retry: RetryConfig{MaxRetries: -1}, wantErr: "retry.max_retries must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Retry = tt.retry
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && cfg.Retry != tt.want {
+				t.Errorf("retry = %+v, want %+v", cfg.Retry, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCacheTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		cache     CacheConfig
+		wantNodes time.Duration
+		wantJobs  time.Duration
+	}{
+		{name: "fall back to the global TTL", cache: CacheConfig{TTL: 30 * time.Second}, wantNodes: 30 * time.Second, wantJobs: 30 * time.Second},
+		{
+			name:      "per resource",
+			cache:     CacheConfig{TTL: 30 * time.Second, NodesTTL: 5 * time.Second, JobsTTL: time.Minute},
+			wantNodes: 5 * time.Second,
+			wantJobs:  time.Minute,
+		},
+		{name: "only nodes overridden", cache: CacheConfig{TTL: 30 * time.Second, NodesTTL: 5 * time.Second}, wantNodes: 5 * time.Second, wantJobs: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Cache = tt.cache
+
+			checkValidate(t, cfg, "")
+			if cfg.Cache.NodesTTL != tt.wantNodes || cfg.Cache.JobsTTL != tt.wantJobs {
+				t.Errorf("nodes_ttl = %v, jobs_ttl = %v, want %v and %v", cfg.Cache.NodesTTL, cfg.Cache.JobsTTL, tt.wantNodes, tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestValidateLogging(t *testing.T) {
+	tests := []struct {
+		name       string
+		logging    LoggingConfig
+		wantLevel  string
+		wantFormat string
+		wantErr    string
+	}{
+		{name: "defaults", wantLevel: "info", wantFormat: "json"},
+		{name: "explicit", logging: LoggingConfig{Level: "debug", Format: "text"}, wantLevel: "debug", wantFormat: "text"},
+		{name: "unknown level", logging: LoggingConfig{Level: "verbose"}, wantErr: "logging.level"},
+		{name: "unknown format", logging: LoggingConfig{Format: "yaml"}, wantErr: "logging.format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Logging = tt.logging
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && (cfg.Logging.Level != tt.wantLevel || cfg.Logging.Format != tt.wantFormat) {
+				t.Errorf("logging = %+v, want level %q and format %q", cfg.Logging, tt.wantLevel, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestLoadLogLevelEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileLevel string
+		env       string
+		wantLevel string
+		wantErr   bool
+	}{
+		{name: "from the file", fileLevel: "warn", wantLevel: "warn"},
+		{name: "env overrides the file", fileLevel: "warn", env: "debug", wantLevel: "debug"},
+		{name: "env without file setting", env: "error", wantLevel: "error"},
+		{name: "invalid env level", fileLevel: "warn", env: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yaml := "server:\n  addr: \":8080\"\nmy_datacenter: dc1\netcd:\n  endpoints: [\"etcd:2379\"]\n" +
+				"clusters:\n  - name: dc1\n    region: eu\n    address: http://nomad-dc1:4646\n"
+			if tt.fileLevel != "" {
+				yaml += "logging:\n  level: " + tt.fileLevel + "\n"
+			}
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv(LogLevelEnv, tt.env)
+
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.Logging.Level != tt.wantLevel {
+				t.Errorf("logging.level = %q, want %q", cfg.Logging.Level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestValidateClusterAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		tls     bool
+		want    string
+		wantErr string
+	}{
+		{name: "http URL", address: "http://nomad:4646", want: "http://nomad:4646"},
+		{name: "https URL", address: "https://nomad.example.com:4646", want: "https://nomad.example.com:4646"},
+		{name: "bare host and port", address: "localhost:4646", want: "http://localhost:4646"},
+		{name: "bare IP", address: "10.0.0.1:4646", want: "http://10.0.0.1:4646"},
+		{name: "bare host with TLS", address: "nomad:4646", tls: true, want: "https://nomad:4646"},
+		{name: "explicit http with TLS kept", address: "http://nomad:4646", tls: true, want: "http://nomad:4646"},
+		{name: "empty", address: "", wantErr: `cluster[1].address is required`},
+		{name: "unsupported scheme", address: "ftp://nomad:4646", wantErr: `cluster[1].address "ftp://nomad:4646" is invalid: scheme must be http or https`},
+		{name: "scheme without host", address: "http://:4646", wantErr: `cluster[1].address "http://:4646" is invalid: host is required`},
+		{name: "bare port", address: ":4646", wantErr: `cluster[1].address ":4646" is invalid: host is required`},
+		{name: "unparsable", address: "http://nomad:port", wantErr: `cluster[1].address "http://nomad:port" is invalid`},
+		{name: "control character", address: "nomad\n:4646", wantErr: `cluster[1].address "nomad\n:4646" is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cluster := ClusterConfig{Name: "dc2", Region: "eu", Address: tt.address}
+			if tt.tls {
+				cluster.TLS = &TLSConfig{}
+			}
+			cfg.Clusters = append(cfg.Clusters, cluster)
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && cfg.Clusters[1].Address != tt.want {
+				t.Errorf("address = %q, want %q", cfg.Clusters[1].Address, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEtcdKeyPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		want    string
+		wantErr string
+	}{
+		{name: "default", want: DefaultEtcdKeyPrefix},
+		{name: "trailing slash added", prefix: "prod/dc-switcher", want: "prod/dc-switcher/"},
+		{name: "trailing slash kept", prefix: "/staging/", want: "/staging/"},
+		{name: "only slashes", prefix: "//", wantErr: "etcd.key_prefix must not be empty"},
+		{name: "only whitespace", prefix: "  ", wantErr: "etcd.key_prefix must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Etcd.KeyPrefix = tt.prefix
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && cfg.Etcd.KeyPrefix != tt.want {
+				t.Errorf("key_prefix = %q, want %q", cfg.Etcd.KeyPrefix, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEtcdStartupTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		dial    time.Duration
+		startup time.Duration
+		want    time.Duration
+	}{
+		{name: "defaults to the default dial timeout", want: 5 * time.Second},
+		{name: "defaults to the dial timeout", dial: 2 * time.Second, want: 2 * time.Second},
+		{name: "explicit", dial: 2 * time.Second, startup: 30 * time.Second, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Etcd.DialTimeout = tt.dial
+			cfg.Etcd.StartupTimeout = tt.startup
+
+			checkValidate(t, cfg, "")
+			if cfg.Etcd.StartupTimeout != tt.want {
+				t.Errorf("startup_timeout = %v, want %v", cfg.Etcd.StartupTimeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateHeartbeatJitter(t *testing.T) {
+	tests := []struct {
+		name           string
+		heartbeat      HeartbeatConfig
+		wantErr        string
+		wantBackground int
+	}{
+		{name: "no jitter"},
+		{name: "heartbeat only", heartbeat: HeartbeatConfig{JitterPercent: 20}},
+		{name: "background loops", heartbeat: HeartbeatConfig{JitterPercent: 20, JitterBackgroundLoops: true}, wantBackground: 20},
+		{name: "full interval", heartbeat: HeartbeatConfig{JitterPercent: 100}},
+		{name: "negative", heartbeat: HeartbeatConfig{JitterPercent: -1}, wantErr: "heartbeat.jitter_percent must be between 0 and 100"},
+		{name: "over 100", heartbeat: HeartbeatConfig{JitterPercent: 101}, wantErr: "heartbeat.jitter_percent must be between 0 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Heartbeat = tt.heartbeat
+
+			checkValidate(t, cfg, tt.wantErr)
+			if got := cfg.Heartbeat.BackgroundJitterPercent(); tt.wantErr == "" && got != tt.wantBackground {
+				t.Errorf("BackgroundJitterPercent() = %d, want %d", got, tt.wantBackground)
+			}
+		})
+	}
+}
+
+func TestValidateCacheCleanupInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   CacheConfig
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "twice the ttl", cache: CacheConfig{TTL: 30 * time.Second}, want: time.Minute},
+		{name: "twice the shortest ttl", cache: CacheConfig{TTL: 30 * time.Second, NodesTTL: 5 * time.Second}, want: 10 * time.Second},
+		{name: "per resource ttl without a global one", cache: CacheConfig{JobsTTL: 20 * time.Second}, want: 40 * time.Second},
+		{name: "no ttl keeps cleanup running", want: time.Minute},
+		{name: "explicit", cache: CacheConfig{TTL: 30 * time.Second, CleanupInterval: 5 * time.Minute}, want: 5 * time.Minute},
+		{name: "negative", cache: CacheConfig{CleanupInterval: -time.Second}, wantErr: "cache.cleanup_interval must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Cache = tt.cache
+
+			checkValidate(t, cfg, tt.wantErr)
+			if tt.wantErr == "" && cfg.Cache.CleanupInterval != tt.want {
+				t.Errorf("cleanup_interval = %v, want %v", cfg.Cache.CleanupInterval, tt.want)
+			}
+		})
+	}
+}
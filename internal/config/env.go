@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of environment variables overriding configuration values
+const EnvPrefix = "DC_SWITCHER_"
+
+// envKind tells how an environment variable value is converted
+type envKind int
+
+const (
+	envScalar   envKind = iota // Passed as is, koanf converts it to the field type
+	envList                    // Comma-separated list of strings
+	envClusters                // Comma-separated list of cluster addresses
+)
+
+// envKey is a configuration key that can be set from the environment
+type envKey struct {
+	path []string
+	kind envKind
+}
+
+// envProvider is a koanf provider reading configuration overrides from environment variables.
+// A variable name is EnvPrefix followed by the upper-cased koanf path with dots replaced by
+// underscores, e.g. etcd.endpoints is set by DC_SWITCHER_ETCD_ENDPOINTS. Variables are matched
+// against the keys declared on Config, so underscores inside key names are not ambiguous.
+type envProvider struct {
+	prefix string
+}
+
+// ReadBytes is not supported, the provider only returns parsed values
+func (p *envProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("env provider does not support this method")
+}
+
+// Read returns the configuration values set in the environment as a nested map
+func (p *envProvider) Read() (map[string]interface{}, error) {
+	keys := make(map[string]envKey)
+	collectEnvKeys(reflect.TypeOf(Config{}), nil, keys)
+
+	values := make(map[string]interface{})
+	for _, variable := range os.Environ() {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+
+		key, ok := keys[strings.TrimPrefix(name, p.prefix)]
+		if !ok {
+			continue
+		}
+
+		setPath(values, key.path, parseEnvValue(key.kind, value))
+	}
+
+	return values, nil
+}
+
+// collectEnvKeys walks the koanf tags of t and registers every settable key by its variable name
+func collectEnvKeys(t reflect.Type, path []string, keys map[string]envKey) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("koanf")
+		if tag == "" {
+			continue
+		}
+
+		fieldPath := append(append([]string(nil), path...), tag)
+		name := strings.ToUpper(strings.Join(fieldPath, "_"))
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Duration(0)):
+			collectEnvKeys(fieldType, fieldPath, keys)
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem() == reflect.TypeOf(ClusterConfig{}):
+			keys[name] = envKey{path: fieldPath, kind: envClusters}
+		case fieldType.Kind() == reflect.Slice:
+			keys[name] = envKey{path: fieldPath, kind: envList}
+		default:
+			keys[name] = envKey{path: fieldPath, kind: envScalar}
+		}
+	}
+}
+
+// parseEnvValue converts a raw variable value according to the key kind
+func parseEnvValue(kind envKind, value string) interface{} {
+	switch kind {
+	case envList:
+		return splitList(value)
+	case envClusters:
+		// Only addresses can be given, names and regions are auto-detected from Nomad
+		addresses := splitList(value)
+		clusters := make([]interface{}, len(addresses))
+		for i, address := range addresses {
+			clusters[i] = map[string]interface{}{"address": address}
+		}
+		return clusters
+	default:
+		return value
+	}
+}
+
+// splitList splits a comma-separated value, dropping empty items and surrounding spaces
+func splitList(value string) []interface{} {
+	items := []interface{}{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setPath stores value in the nested map m under path, creating intermediate maps
+func setPath(m map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[key] = child
+		}
+		m = child
+	}
+	m[path[len(path)-1]] = value
+}
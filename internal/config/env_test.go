@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []interface{}
+	}{
+		{value: "a", want: []interface{}{"a"}},
+		{value: "a,b", want: []interface{}{"a", "b"}},
+		{value: " a , b ,", want: []interface{}{"a", "b"}},
+		{value: "", want: []interface{}{}},
+		{value: ",,", want: []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		if got := splitList(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEnvProviderRead(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string]interface{}
+	}{
+		{
+			name: "scalar",
+			env:  map[string]string{"DC_SWITCHER_MY_DATACENTER": "dc2"},
+			want: map[string]interface{}{"my_datacenter": "dc2"},
+		},
+		{
+			name: "nested key with underscores",
+			env:  map[string]string{"DC_SWITCHER_HEARTBEAT_UPDATE_INTERVAL": "5s"},
+			want: map[string]interface{}{"heartbeat": map[string]interface{}{"update_interval": "5s"}},
+		},
+		{
+			name: "list",
+			env:  map[string]string{"DC_SWITCHER_ETCD_ENDPOINTS": "etcd1:2379,etcd2:2379"},
+			want: map[string]interface{}{"etcd": map[string]interface{}{"endpoints": []interface{}{"etcd1:2379", "etcd2:2379"}}},
+		},
+		{
+			name: "clusters by address",
+			env:  map[string]string{"DC_SWITCHER_CLUSTERS": "http://a:4646, http://b:4646"},
+			want: map[string]interface{}{"clusters": []interface{}{
+				map[string]interface{}{"address": "http://a:4646"},
+				map[string]interface{}{"address": "http://b:4646"},
+			}},
+		},
+		{
+			name: "unknown and unprefixed variables ignored",
+			env:  map[string]string{"DC_SWITCHER_NOT_A_KEY": "x", "MY_DATACENTER": "dc3"},
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			got, err := (&envProvider{prefix: EnvPrefix}).Read()
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	const file = "server:\n  addr: \":8080\"\nmy_datacenter: dc1\netcd:\n  endpoints: [\"etcd:2379\"]\n" +
+		"heartbeat:\n  update_interval: 10s\n" +
+		"clusters:\n  - name: dc1\n    region: eu\n    address: http://nomad-dc1:4646\n"
+
+	tests := []struct {
+		name    string
+		file    string // Config file content, no file when empty
+		env     map[string]string
+		check   func(t *testing.T, cfg *Config)
+		wantErr bool
+	}{
+		{
+			name: "file only",
+			file: file,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.MyDatacenter != "dc1" || cfg.Heartbeat.UpdateInterval != 10*time.Second {
+					t.Errorf("my_datacenter %q, update interval %v, want the file values", cfg.MyDatacenter, cfg.Heartbeat.UpdateInterval)
+				}
+			},
+		},
+		{
+			name: "env over file",
+			file: file,
+			env: map[string]string{
+				"DC_SWITCHER_MY_DATACENTER":             "dc2",
+				"DC_SWITCHER_ETCD_ENDPOINTS":            "etcd1:2379,etcd2:2379",
+				"DC_SWITCHER_HEARTBEAT_UPDATE_INTERVAL": "3s",
+				"DC_SWITCHER_READ_ONLY":                 "true",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.MyDatacenter != "dc2" {
+					t.Errorf("my_datacenter = %q, want dc2", cfg.MyDatacenter)
+				}
+				if want := []string{"etcd1:2379", "etcd2:2379"}; !reflect.DeepEqual(cfg.Etcd.Endpoints, want) {
+					t.Errorf("etcd endpoints = %v, want %v", cfg.Etcd.Endpoints, want)
+				}
+				if cfg.Heartbeat.UpdateInterval != 3*time.Second {
+					t.Errorf("update interval = %v, want 3s", cfg.Heartbeat.UpdateInterval)
+				}
+				if !cfg.ReadOnly {
+					t.Error("read_only not set from the environment")
+				}
+				if len(cfg.Clusters) != 1 || cfg.Clusters[0].Name != "dc1" {
+					t.Errorf("clusters = %+v, want the file clusters", cfg.Clusters)
+				}
+			},
+		},
+		{
+			name: "env without a file",
+			env: map[string]string{
+				"DC_SWITCHER_SERVER_ADDR":    ":9090",
+				"DC_SWITCHER_MY_DATACENTER":  "dc1",
+				"DC_SWITCHER_ETCD_ENDPOINTS": "etcd:2379",
+				"DC_SWITCHER_CLUSTERS":       "http://nomad-dc1:4646,http://nomad-dc2:4646",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Addr != ":9090" {
+					t.Errorf("server addr = %q, want :9090", cfg.Server.Addr)
+				}
+				if len(cfg.Clusters) != 2 || cfg.Clusters[0].Address != "http://nomad-dc1:4646" || cfg.Clusters[1].Address != "http://nomad-dc2:4646" {
+					t.Errorf("clusters = %+v, want both addresses", cfg.Clusters)
+				}
+			},
+		},
+		{
+			name:    "invalid env value",
+			file:    file,
+			env:     map[string]string{"DC_SWITCHER_HEARTBEAT_UPDATE_INTERVAL": "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// redactedValue replaces secrets in the effective configuration view
+const redactedValue = "[redacted]"
+
+// Redacted returns the configuration keyed like the config file, for showing it at runtime.
+// Auth tokens, the etcd password, the webhook URL (which may embed a token) and cluster extra header
+// values are replaced, TLS settings only list file paths. Durations are rendered as strings, e.g. "30s".
+func (c *Config) Redacted() map[string]any {
+	safe := *c
+	safe.Auth.Token = redactSecret(c.Auth.Token)
+	safe.Auth.Tokens = make([]string, len(c.Auth.Tokens))
+	for i, token := range c.Auth.Tokens {
+		safe.Auth.Tokens[i] = redactSecret(token)
+	}
+	safe.Etcd.Password = redactSecret(c.Etcd.Password)
+	safe.Notifications.WebhookURL = redactSecret(c.Notifications.WebhookURL)
+	safe.Clusters = make([]ClusterConfig, len(c.Clusters))
+	for i, cluster := range c.Clusters {
+		safe.Clusters[i] = cluster
+		if len(cluster.ExtraHeaders) == 0 {
+			continue
+		}
+		safe.Clusters[i].ExtraHeaders = make(map[string]string, len(cluster.ExtraHeaders))
+		for name, value := range cluster.ExtraHeaders {
+			safe.Clusters[i].ExtraHeaders[name] = redactSecret(value)
+		}
+	}
+
+	view, _ := configValue(reflect.ValueOf(safe)).(map[string]any)
+	return view
+}
+
+// redactSecret hides a configured secret while still showing whether it is set
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// configValue converts v into maps keyed by koanf tags, slices and plain values
+func configValue(v reflect.Value) any {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return configValue(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]any, v.NumField())
+		for i := range v.NumField() {
+			key := v.Type().Field(i).Tag.Get("koanf")
+			if key == "" || key == "-" {
+				continue
+			}
+			fields[key] = configValue(v.Field(i))
+		}
+		return fields
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range v.Len() {
+			items[i] = configValue(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
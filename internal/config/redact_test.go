@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secretConfig returns a configuration with every secret set
+func secretConfig() *Config {
+	cfg := validConfig()
+	cfg.Auth = AuthConfig{Token: "single-token", Tokens: []string{"token-a", "token-b"}, RequireForReads: true}
+	cfg.Etcd.Username = "switcher"
+	cfg.Etcd.Password = "etcd-password"
+	cfg.Etcd.TLS = &TLSConfig{CA: "/etc/etcd/ca.pem", Cert: "/etc/etcd/cert.pem", Key: "/etc/etcd/key.pem"}
+	cfg.Notifications.WebhookURL = "https://hooks.example.com/T0/secret-path"
+	cfg.Clusters[0].ExtraHeaders = map[string]string{"X-Proxy-Token": "proxy-secret"}
+	cfg.HealthCheck.Interval = 30 * time.Second
+	return cfg
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := secretConfig()
+
+	view := cfg.Redacted()
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("marshal redacted config: %v", err)
+	}
+	body := string(data)
+
+	for _, secret := range []string{"single-token", "token-a", "token-b", "etcd-password", "secret-path", "proxy-secret"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("redacted config contains secret %q: %s", secret, body)
+		}
+	}
+
+	// Everything that is not a secret is kept, so the view is still useful for debugging
+	for _, want := range []string{
+		`"my_datacenter":"dc1"`,
+		`"username":"switcher"`,
+		`"key":"/etc/etcd/key.pem"`,
+		`"require_for_reads":true`,
+		`"interval":"30s"`,
+		`"tokens":["[redacted]","[redacted]"]`,
+		`"X-Proxy-Token":"[redacted]"`,
+		`"password":"[redacted]"`,
+		`"webhook_url":"[redacted]"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("redacted config doesn't contain %s: %s", want, body)
+		}
+	}
+
+	// Redacting works on a copy
+	if cfg.Auth.Tokens[0] != "token-a" || cfg.Clusters[0].ExtraHeaders["X-Proxy-Token"] != "proxy-secret" || cfg.Etcd.Password != "etcd-password" {
+		t.Error("Redacted() changed the loaded configuration")
+	}
+}
+
+func TestRedactedKeepsUnsetSecretsEmpty(t *testing.T) {
+	view := validConfig().Redacted()
+
+	etcd, ok := view["etcd"].(map[string]any)
+	if !ok {
+		t.Fatalf("etcd section = %T, want a map", view["etcd"])
+	}
+	if etcd["password"] != "" {
+		t.Errorf("unset etcd password shown as %q, want empty", etcd["password"])
+	}
+	if etcd["tls"] != nil {
+		t.Errorf("unset etcd TLS shown as %v, want nil", etcd["tls"])
+	}
+}
@@ -0,0 +1,36 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	const base = 10 * time.Second
+
+	tests := []struct {
+		name        string
+		maxInterval time.Duration
+		multiplier  float64
+		failures    int
+		want        time.Duration
+	}{
+		{name: "no failures", maxInterval: time.Minute, multiplier: 2, want: base},
+		{name: "one failure", maxInterval: time.Minute, multiplier: 2, failures: 1, want: 20 * time.Second},
+		{name: "two failures", maxInterval: time.Minute, multiplier: 2, failures: 2, want: 40 * time.Second},
+		{name: "capped", maxInterval: time.Minute, multiplier: 2, failures: 3, want: time.Minute},
+		{name: "stays capped", maxInterval: time.Minute, multiplier: 2, failures: 1000, want: time.Minute},
+		{name: "fractional multiplier", maxInterval: time.Minute, multiplier: 1.5, failures: 2, want: 22500 * time.Millisecond},
+		{name: "multiplier of one disables backoff", maxInterval: time.Minute, multiplier: 1, failures: 5, want: base},
+		{name: "maximum equal to the base", maxInterval: base, multiplier: 2, failures: 3, want: base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := backoffInterval(base, tt.maxInterval, tt.multiplier, tt.failures)
+			if got != tt.want {
+				t.Errorf("backoffInterval(%v, %v, %v, %d) = %v, want %v", base, tt.maxInterval, tt.multiplier, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
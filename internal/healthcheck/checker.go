@@ -2,39 +2,61 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/notify"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/util"
 )
 
 // Checker performs periodic health checks on the active region
 type Checker struct {
 	cfg            *config.HealthCheckConfig
+	failoverCfg    config.FailoverConfig
+	jitterPercent  int // Randomization of the check interval, see util.Jitter
 	dcService      service.DatacenterService
+	notifier       notify.Notifier
 	logger         *slog.Logger
 	stopCh         chan struct{}
 	wg             sync.WaitGroup
-	activeRegion   string         // Currently active region to monitor
-	failureCounter map[string]int // region -> consecutive failure count
+	activeRegion   string               // Currently active region to monitor
+	failureCounter map[string]int       // region -> consecutive failure count
+	lastSaved      map[string]int       // Non-zero counters last saved in etcd with persist_failures
+	paused         bool                 // Maintenance mode: failures are not counted and never drain
+	drainedRegions map[string]time.Time // Regions drained after failing checks -> since when they pass checks again (zero while failing)
+	lastSwitch     time.Time            // Last automatic drain or failback, for the failback cooldown
+	lastFailover   time.Time            // Last automatic promotion of a failover candidate
 	mu             sync.RWMutex
+	checkMu        sync.Mutex // Serializes periodic and manually triggered checks
 }
 
 // NewChecker creates a new health checker
 func NewChecker(
 	cfg *config.HealthCheckConfig,
+	failoverCfg config.FailoverConfig,
 	dcService service.DatacenterService,
+	notifier notify.Notifier,
+	jitterPercent int,
 	logger *slog.Logger,
 ) *Checker {
 	return &Checker{
 		cfg:            cfg,
+		failoverCfg:    failoverCfg,
+		jitterPercent:  jitterPercent,
 		dcService:      dcService,
+		notifier:       notifier,
 		logger:         logger,
 		stopCh:         make(chan struct{}),
 		failureCounter: make(map[string]int),
+		drainedRegions: make(map[string]time.Time),
+		paused:         cfg.Paused,
 	}
 }
 
@@ -59,12 +81,17 @@ func (c *Checker) Start(ctx context.Context) {
 		c.logger.Info("initial active region detected",
 			slog.String("region", activeRegion),
 		)
+
+		if c.cfg.PersistFailures {
+			c.loadFailures(ctx, activeRegion)
+		}
 	} else {
 		c.logger.Info("no active region at startup")
 	}
 
 	c.logger.Info("starting health checker",
 		slog.Duration("interval", c.cfg.Interval),
+		slog.Duration("max_interval", c.cfg.MaxInterval),
 		slog.Int("failed_threshold", c.cfg.FailedThreshold),
 	)
 
@@ -92,6 +119,64 @@ func (c *Checker) SetActiveRegion(region string) {
 	}
 }
 
+// RunCheck performs a health check immediately, outside the periodic schedule, and returns the resulting state.
+// It waits for a check that is already running to finish first.
+func (c *Checker) RunCheck(ctx context.Context) model.HealthCheckState {
+	c.logger.Info("health check triggered manually")
+	c.performCheck(ctx)
+	return c.State()
+}
+
+// SetPaused switches maintenance mode on or off. While paused, failed checks are logged
+// but not counted, so the region is never drained. Pausing clears the failure counters.
+func (c *Checker) SetPaused(paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paused == paused {
+		return
+	}
+	c.paused = paused
+
+	if paused {
+		c.failureCounter = make(map[string]int)
+		c.logger.Warn("health checker paused, failed checks will not drain the active region",
+			slog.String("region", c.activeRegion),
+		)
+	} else {
+		c.logger.Info("health checker resumed",
+			slog.String("region", c.activeRegion),
+		)
+	}
+}
+
+// isPaused reports whether maintenance mode is on
+func (c *Checker) isPaused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused
+}
+
+// State returns the monitored region and the consecutive failure counters
+func (c *Checker) State() model.HealthCheckState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	failures := make(map[string]int, len(c.failureCounter))
+	for region, count := range c.failureCounter {
+		failures[region] = count
+	}
+
+	return model.HealthCheckState{
+		Enabled:         c.cfg.Enabled,
+		Paused:          c.paused,
+		ActiveRegion:    c.activeRegion,
+		Interval:        c.cfg.Interval.Milliseconds(),
+		FailedThreshold: c.cfg.FailedThreshold,
+		Failures:        failures,
+	}
+}
+
 // Stop gracefully stops the health checker
 func (c *Checker) Stop() {
 	if !c.cfg.Enabled {
@@ -108,30 +193,70 @@ func (c *Checker) Stop() {
 func (c *Checker) run(ctx context.Context) {
 	defer c.wg.Done()
 
-	ticker := time.NewTicker(c.cfg.Interval)
-	defer ticker.Stop()
-
 	// Perform initial check after a short delay
 	c.logger.Info("waiting 5 seconds before first health check")
 	time.Sleep(5 * time.Second)
 	c.logger.Info("performing initial health check")
 	c.performCheck(ctx)
 
+	timer := time.NewTimer(c.nextCheckInterval())
+	defer timer.Stop()
+
 	for {
 		select {
 		case <-c.stopCh:
 			return
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			c.logger.Info("health check timer triggered")
 			c.performCheck(ctx)
+			timer.Reset(c.nextCheckInterval())
 		}
 	}
 }
 
+// nextCheckInterval returns the delay before the next check, backing off while the active region keeps failing
+func (c *Checker) nextCheckInterval() time.Duration {
+	c.mu.RLock()
+	failures := c.failureCounter[c.activeRegion]
+	c.mu.RUnlock()
+
+	interval := backoffInterval(c.cfg.Interval, c.cfg.MaxInterval, c.cfg.BackoffMultiplier, failures)
+	if interval != c.cfg.Interval {
+		c.logger.Info("backing off health checks",
+			slog.Int("consecutive_failures", failures),
+			slog.Duration("next_check_in", interval),
+		)
+	}
+	return util.Jitter(interval, c.jitterPercent)
+}
+
+// backoffInterval multiplies base by multiplier once per failure, capped at maxInterval
+func backoffInterval(base, maxInterval time.Duration, multiplier float64, failures int) time.Duration {
+	interval := float64(base)
+	for i := 0; i < failures && interval < float64(maxInterval); i++ {
+		interval *= multiplier
+	}
+	return min(time.Duration(interval), maxInterval)
+}
+
 // performCheck executes a single health check cycle
 func (c *Checker) performCheck(ctx context.Context) {
+	c.checkMu.Lock()
+	defer c.checkMu.Unlock()
+
+	c.checkActiveRegion(ctx)
+	if c.cfg.PersistFailures {
+		c.persistFailures(ctx)
+	}
+	if c.cfg.AutoFailback.Enabled {
+		c.checkFailback(ctx)
+	}
+}
+
+// checkActiveRegion checks the leader of the active region and drains it once the failure threshold is reached
+func (c *Checker) checkActiveRegion(ctx context.Context) {
 	// Sync active region with actual state before checking
 	realActiveRegion, err := c.detectActiveRegion(ctx)
 	if err != nil {
@@ -169,7 +294,7 @@ func (c *Checker) performCheck(ctx context.Context) {
 	)
 
 	// Check if region has a leader
-	hasLeader, err := c.checkRegionLeader(ctx, activeRegion)
+	leader, hasLeader, err := c.checkRegionLeader(ctx, activeRegion)
 	if err != nil {
 		c.logger.Warn("health check failed",
 			slog.String("region", activeRegion),
@@ -196,66 +321,191 @@ func (c *Checker) performCheck(ctx context.Context) {
 	if previousFailures > 0 {
 		c.logger.Info("region health check passed - health restored",
 			slog.String("region", activeRegion),
+			slog.String("leader", leader),
 			slog.Int("previous_failures", previousFailures),
 		)
 	} else {
 		c.logger.Info("region health check passed",
 			slog.String("region", activeRegion),
+			slog.String("leader", leader),
 		)
 	}
 }
 
-// detectActiveRegion determines which region is currently active (has un-drained DCs)
-// This is called only once during startup
+// detectActiveRegion determines which region is currently active: the region of the active datacenter
+// recorded in etcd, or when etcd has no record or can't be read, the first region with un-drained DCs.
+// An auto-drain leaves the etcd record in place, so a recorded region that is drained is ignored.
 func (c *Checker) detectActiveRegion(ctx context.Context) (string, error) {
+	activeRegion, err := c.dcService.GetActiveRegion(ctx)
+	if err != nil {
+		c.logger.Warn("failed to read active region from etcd, falling back to region states",
+			slog.String("error", err.Error()),
+		)
+	}
+
 	regions, err := c.dcService.ListRegions(ctx)
 	if err != nil {
+		if activeRegion != "" {
+			return activeRegion, nil
+		}
 		return "", err
 	}
 
-	// Find region with status "active" or "partial" (has some un-drained DCs)
+	// The etcd active datacenter record is authoritative unless its region is drained
+	if activeRegion != "" {
+		if !isDrainedRegion(activeRegion, regions) {
+			return activeRegion, nil
+		}
+		c.logger.Warn("region recorded as active in etcd is drained, ignoring the record",
+			slog.String("region", activeRegion),
+		)
+	}
+
+	// Find region with status "active", "degraded" or "partial" (has some un-drained DCs)
+	var candidates []string
 	for _, region := range regions {
-		if region.Status == "active" || region.Status == "partial" {
-			return region.Name, nil
+		switch region.Status {
+		case model.DatacenterStatusActive, model.RegionStatusDegraded, model.RegionStatusPartial:
+			candidates = append(candidates, region.Name)
 		}
 	}
 
-	return "", nil
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	if len(candidates) > 1 {
+		c.logger.Warn("several regions have serving nodes and etcd records no usable active region, monitoring the first",
+			slog.Any("regions", candidates),
+		)
+	}
+	return candidates[0], nil
 }
 
-// checkRegionLeader checks if any cluster in the region has an elected leader
-func (c *Checker) checkRegionLeader(ctx context.Context, region string) (bool, error) {
+// isDrainedRegion reports whether all datacenters of region are draining
+func isDrainedRegion(region string, regions []model.Region) bool {
+	for _, r := range regions {
+		if r.Name == region {
+			return r.Status == model.DatacenterStatusDraining
+		}
+	}
+	return false
+}
+
+// checkRegionLeader checks if the region's Nomad servers have an elected leader and returns its address
+func (c *Checker) checkRegionLeader(ctx context.Context, region string) (string, bool, error) {
 	// Get region details to access datacenters
 	regionDetails, err := c.dcService.GetRegionDatacenters(ctx, region)
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
 
 	if regionDetails == nil || len(regionDetails.Datacenters) == 0 {
 		c.logger.Warn("region has no datacenters",
 			slog.String("region", region),
 		)
-		return false, nil
+		return "", false, nil
+	}
+
+	if c.cfg.CheckAllDatacenters {
+		return c.checkDatacenterLeaders(ctx, region, regionDetails.Datacenters)
 	}
 
 	// Check leader on first datacenter (all DCs in region share same Nomad Server cluster)
 	firstDC := regionDetails.Datacenters[0]
 
-	hasLeader, err := c.dcService.CheckClusterLeader(ctx, firstDC.Name)
+	leader, hasLeader, err := c.dcService.CheckClusterLeader(ctx, firstDC.Name)
 	if err != nil {
 		c.logger.Warn("failed to check leader",
 			slog.String("region", region),
 			slog.String("datacenter", firstDC.Name),
 			slog.String("error", err.Error()),
 		)
-		return false, err
+		return "", false, err
+	}
+
+	return leader, hasLeader, nil
+}
+
+// checkDatacenterLeaders checks the leader of every datacenter in the region.
+// The region has a leader if at least the configured quorum of datacenters report one;
+// the returned leader is the one of the first healthy datacenter.
+func (c *Checker) checkDatacenterLeaders(ctx context.Context, region string, datacenters []model.Datacenter) (string, bool, error) {
+	type leaderResult struct {
+		leader    string
+		hasLeader bool
 	}
 
-	return hasLeader, nil
+	results := concurrent.ParallelMap(ctx, datacenters, func(ctx context.Context, dc model.Datacenter) (leaderResult, error) {
+		leader, hasLeader, err := c.dcService.CheckClusterLeader(ctx, dc.Name)
+		return leaderResult{leader: leader, hasLeader: hasLeader}, err
+	})
+
+	quorum := datacenterQuorum(c.cfg.DatacenterQuorum, len(datacenters))
+	healthy := 0
+	leader := ""
+	var errs []error
+
+	for i, result := range results {
+		dcName := datacenters[i].Name
+		switch {
+		case result.Error != nil:
+			errs = append(errs, fmt.Errorf("datacenter %s: %w", dcName, result.Error))
+			c.logger.Warn("failed to check leader",
+				slog.String("region", region),
+				slog.String("datacenter", dcName),
+				slog.String("error", result.Error.Error()),
+			)
+		case !result.Value.hasLeader:
+			c.logger.Warn("datacenter has no leader",
+				slog.String("region", region),
+				slog.String("datacenter", dcName),
+			)
+		default:
+			healthy++
+			if leader == "" {
+				leader = result.Value.leader
+			}
+			c.logger.Debug("datacenter has a leader",
+				slog.String("region", region),
+				slog.String("datacenter", dcName),
+				slog.String("leader", result.Value.leader),
+			)
+		}
+	}
+
+	c.logger.Info("checked leaders of region datacenters",
+		slog.String("region", region),
+		slog.Int("healthy", healthy),
+		slog.Int("total", len(datacenters)),
+		slog.Int("quorum", quorum),
+	)
+
+	// Report an error only when no datacenter could be queried at all
+	if len(errs) == len(datacenters) {
+		return "", false, errors.Join(errs...)
+	}
+
+	return leader, healthy >= quorum, nil
+}
+
+// datacenterQuorum returns how many of total datacenters must report a leader.
+// Zero means a majority; a quorum larger than the region requires all datacenters.
+func datacenterQuorum(configured, total int) int {
+	if configured <= 0 {
+		return total/2 + 1
+	}
+	return min(configured, total)
 }
 
 // handleFailure increments failure counter and drains region if threshold is reached
 func (c *Checker) handleFailure(ctx context.Context, region string) {
+	if c.isPaused() {
+		c.logger.Warn("region health check failure ignored, health checker is paused",
+			slog.String("region", region),
+		)
+		return
+	}
+
 	c.mu.Lock()
 	c.failureCounter[region]++
 	currentFailures := c.failureCounter[region]
@@ -269,6 +519,18 @@ func (c *Checker) handleFailure(ctx context.Context, region string) {
 
 	// Check if threshold is reached
 	if currentFailures >= c.cfg.FailedThreshold {
+		if c.cfg.RequireQuorumConfirmation && !c.confirmFailure(ctx, region) {
+			return
+		}
+
+		// Maintenance mode may have been switched on during the confirmation backoff
+		if c.isPaused() {
+			c.logger.Warn("health checker paused before draining, drain cancelled",
+				slog.String("region", region),
+			)
+			return
+		}
+
 		c.logger.Error("region health check threshold reached, draining region",
 			slog.String("region", region),
 			slog.Int("failures", currentFailures),
@@ -284,27 +546,135 @@ func (c *Checker) handleFailure(ctx context.Context, region string) {
 			c.logger.Info("successfully drained unhealthy region",
 				slog.String("region", region),
 			)
-			// Reset counter after successful drain
+			// Reset counter after successful drain and remember the region as a failback candidate.
+			// Nothing is active anymore until a region is activated again.
 			c.mu.Lock()
 			c.failureCounter[region] = 0
+			if c.activeRegion == region {
+				c.activeRegion = ""
+			}
+			c.drainedRegions[region] = time.Time{}
+			c.lastSwitch = time.Now()
 			c.mu.Unlock()
+
+			c.failover(ctx, region)
 		}
 	}
 }
 
+// confirmFailure re-verifies a region failure before draining
+// The failure is confirmed only if the leader is still missing after a backoff
+// and etcd is reachable (otherwise the failure is likely on our side of the network)
+func (c *Checker) confirmFailure(ctx context.Context, region string) bool {
+	c.logger.Warn("failure threshold reached, confirming before draining",
+		slog.String("region", region),
+		slog.Duration("backoff", c.cfg.ConfirmationBackoff),
+	)
+
+	select {
+	case <-time.After(c.cfg.ConfirmationBackoff):
+	case <-c.stopCh:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+
+	leader, hasLeader, leaderErr := c.checkRegionLeader(ctx, region)
+	leaderFailed := leaderErr != nil || !hasLeader
+
+	etcdErr := c.dcService.CheckEtcdConnection(ctx)
+	etcdReachable := etcdErr == nil
+
+	attrs := []any{
+		slog.String("region", region),
+		slog.Bool("leader_check_failed", leaderFailed),
+		slog.String("leader", leader),
+		slog.Bool("etcd_reachable", etcdReachable),
+	}
+	if leaderErr != nil {
+		attrs = append(attrs, slog.String("leader_error", leaderErr.Error()))
+	}
+	if etcdErr != nil {
+		attrs = append(attrs, slog.String("etcd_error", etcdErr.Error()))
+	}
+
+	if !leaderFailed {
+		c.logger.Info("region recovered during confirmation check, drain cancelled",
+			append(attrs, slog.String("decision", "skip_drain"))...,
+		)
+		c.mu.Lock()
+		c.failureCounter[region] = 0
+		c.mu.Unlock()
+		return false
+	}
+
+	if !etcdReachable {
+		c.logger.Warn("etcd unreachable during confirmation check, drain suppressed",
+			append(attrs, slog.String("decision", "skip_drain"))...,
+		)
+		return false
+	}
+
+	c.logger.Error("region failure confirmed",
+		append(attrs, slog.String("decision", "drain"))...,
+	)
+	return true
+}
+
 // drainRegion drains all datacenters in the region by setting all nodes to drain
 func (c *Checker) drainRegion(ctx context.Context, region string) error {
 	c.logger.Info("draining unhealthy region",
 		slog.String("region", region),
 	)
 
-	err := c.dcService.DrainAllNodesInRegion(ctx, region)
+	result, err := c.dcService.DrainAllNodesInRegion(ctx, region)
+	if errors.Is(err, service.ErrReadOnly) {
+		// Suppression is logged by the service - nothing was drained, so don't notify
+		return err
+	}
+	if errors.Is(err, service.ErrActivationInProgress) {
+		// Nothing was drained; the failure counter stays at the threshold, so the next failed check retries
+		c.logger.Warn("activation in progress, region drain skipped",
+			slog.String("region", region),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	event := model.NotificationEvent{
+		Type:   model.NotificationAutoDrain,
+		Region: region,
+		Reason: fmt.Sprintf("active region failed %d consecutive health checks", c.cfg.FailedThreshold),
+	}
+	switch {
+	case result != nil:
+		event.ErrorCount = result.ErrorCount()
+	case err != nil:
+		event.ErrorCount = 1
+	}
+	c.notifier.Notify(event)
+
 	if err != nil {
 		return fmt.Errorf("failed to drain region: %w", err)
 	}
 
-	c.logger.Info("successfully drained unhealthy region",
+	for _, cluster := range result.PerCluster {
+		if len(cluster.Errors) > 0 {
+			c.logger.Warn("region drain incomplete in cluster",
+				slog.String("region", region),
+				slog.String("cluster", cluster.Cluster),
+				slog.Int("drained_nodes", cluster.NodesDrained),
+				slog.Int("total_nodes", cluster.NodesTotal),
+				slog.Any("errors", cluster.Errors),
+			)
+		}
+	}
+
+	c.logger.Info("drained unhealthy region",
 		slog.String("region", region),
+		slog.Int("clusters_processed", result.ClustersProcessed),
+		slog.Int("drained_nodes", result.NodesDrained),
+		slog.Int("error_count", result.ErrorCount()),
 	)
 
 	return nil
@@ -0,0 +1,173 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+func TestDetectActiveRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     *mockService
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "etcd record wins over region states",
+			svc: &mockService{
+				activeRegion: "eu",
+				regions: []model.Region{
+					{Name: "us", Status: model.DatacenterStatusActive},
+					{Name: "eu", Status: model.DatacenterStatusActive},
+				},
+			},
+			want: "eu",
+		},
+		{
+			name: "etcd record of an unreachable region is kept so it can fail checks",
+			svc: &mockService{
+				activeRegion: "eu",
+				regions:      []model.Region{{Name: "eu", Status: model.DatacenterStatusError}},
+			},
+			want: "eu",
+		},
+		{
+			name: "drained etcd region without other serving region",
+			svc: &mockService{
+				activeRegion: "eu",
+				regions: []model.Region{
+					{Name: "eu", Status: model.DatacenterStatusDraining},
+					{Name: "us", Status: model.DatacenterStatusDraining},
+				},
+			},
+			want: "",
+		},
+		{
+			name: "drained etcd region falls back to the serving region",
+			svc: &mockService{
+				activeRegion: "eu",
+				regions: []model.Region{
+					{Name: "eu", Status: model.DatacenterStatusDraining},
+					{Name: "us", Status: model.RegionStatusPartial},
+				},
+			},
+			want: "us",
+		},
+		{
+			name: "no etcd record uses the first serving region",
+			svc: &mockService{
+				regions: []model.Region{
+					{Name: "eu", Status: model.DatacenterStatusDraining},
+					{Name: "us", Status: model.RegionStatusDegraded},
+					{Name: "ap", Status: model.DatacenterStatusActive},
+				},
+			},
+			want: "us",
+		},
+		{
+			name: "etcd error falls back to region states",
+			svc: &mockService{
+				activeRegionErr: errors.New("etcd down"),
+				regions:         []model.Region{{Name: "us", Status: model.DatacenterStatusActive}},
+			},
+			want: "us",
+		},
+		{
+			name: "region listing error keeps the etcd record",
+			svc: &mockService{
+				activeRegion: "eu",
+				regionsErr:   errors.New("nomad down"),
+			},
+			want: "eu",
+		},
+		{
+			name:    "region listing error without etcd record",
+			svc:     &mockService{regionsErr: errors.New("nomad down")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestChecker(tt.svc).detectActiveRegion(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("detectActiveRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detectActiveRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckActiveRegionAfterDrain(t *testing.T) {
+	svc := &mockService{
+		activeRegion: "eu",
+		regions:      []model.Region{{Name: "eu", Status: model.DatacenterStatusDraining}},
+	}
+	c := newTestChecker(svc)
+
+	// State after handleFailure drained the region: the etcd record still names it
+	c.activeRegion = ""
+	c.drainedRegions["eu"] = time.Time{}
+
+	c.checkActiveRegion(context.Background())
+
+	if c.activeRegion != "" {
+		t.Errorf("activeRegion = %q after drain, want none", c.activeRegion)
+	}
+	if _, ok := c.drainedRegions["eu"]; !ok {
+		t.Error("drained region was forgotten, failback could never fire")
+	}
+}
+
+func TestDrainRegionNotifies(t *testing.T) {
+	tests := []struct {
+		name     string
+		drainErr error
+		want     []model.NotificationEvent
+	}{
+		{
+			name: "drained",
+			want: []model.NotificationEvent{{Type: model.NotificationAutoDrain, Region: "eu", Reason: "active region failed 3 consecutive health checks"}},
+		},
+		{
+			name:     "drain failure",
+			drainErr: errors.New("nomad unavailable"),
+			want:     []model.NotificationEvent{{Type: model.NotificationAutoDrain, Region: "eu", Reason: "active region failed 3 consecutive health checks", ErrorCount: 1}},
+		},
+		{
+			name:     "read-only mode",
+			drainErr: service.ErrReadOnly,
+		},
+		{
+			name:     "activation in progress",
+			drainErr: &service.ActivationInProgressError{Target: "us"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestChecker(&mockService{drainErr: tt.drainErr})
+			notifier := c.notifier.(*recordingNotifier)
+
+			_ = c.drainRegion(context.Background(), "eu")
+
+			notifier.mu.Lock()
+			defer notifier.mu.Unlock()
+			if len(notifier.events) != len(tt.want) {
+				t.Fatalf("notifications = %+v, want %+v", notifier.events, tt.want)
+			}
+			for i, got := range notifier.events {
+				if got != tt.want[i] {
+					t.Errorf("notification = %+v, want %+v", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestQuorumConfirmation(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirm      bool
+		leaders      []leaderAnswer // answers for dc1: the failing check, then the confirmation
+		etcdErr      error
+		wantDrained  bool
+		wantFailures int
+	}{
+		{
+			name:        "drains without confirmation",
+			leaders:     []leaderAnswer{{hasLeader: false}},
+			wantDrained: true,
+		},
+		{
+			name:         "transient failure recovers on confirmation",
+			confirm:      true,
+			leaders:      []leaderAnswer{{hasLeader: false}, {hasLeader: true}},
+			wantFailures: 0,
+		},
+		{
+			name:        "confirmed failure drains",
+			confirm:     true,
+			leaders:     []leaderAnswer{{hasLeader: false}, {err: errors.New("connection refused")}},
+			wantDrained: true,
+		},
+		{
+			name:         "etcd unreachable suppresses the drain",
+			confirm:      true,
+			leaders:      []leaderAnswer{{hasLeader: false}},
+			etcdErr:      errors.New("etcd down"),
+			wantFailures: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				activeRegion: "eu",
+				regions:      activeRegions(),
+				leaders:      map[string][]leaderAnswer{"dc1": tt.leaders},
+				etcdErr:      tt.etcdErr,
+			}
+			c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{
+				Enabled:                   true,
+				FailedThreshold:           3,
+				RequireQuorumConfirmation: tt.confirm,
+				ConfirmationBackoff:       time.Millisecond,
+			}, config.FailoverConfig{})
+			c.activeRegion = "eu"
+			c.failureCounter["eu"] = 2
+
+			c.checkActiveRegion(context.Background())
+
+			drained := slices.Contains(svc.drainedRegions(), "eu")
+			if drained != tt.wantDrained {
+				t.Fatalf("drained = %v, want %v", drained, tt.wantDrained)
+			}
+			if tt.wantDrained {
+				if c.activeRegion != "" {
+					t.Errorf("activeRegion = %q after drain, want none", c.activeRegion)
+				}
+				return
+			}
+			if got := c.failureCounter["eu"]; got != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", got, tt.wantFailures)
+			}
+			if c.activeRegion != "eu" {
+				t.Errorf("activeRegion = %q, want eu", c.activeRegion)
+			}
+		})
+	}
+}
+
+func TestQuorumConfirmationStopsWithChecker(t *testing.T) {
+	svc := &mockService{
+		activeRegion: "eu",
+		regions:      activeRegions(),
+		leaders:      map[string][]leaderAnswer{"dc1": {{hasLeader: false}}},
+	}
+	c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{
+		Enabled:                   true,
+		FailedThreshold:           1,
+		RequireQuorumConfirmation: true,
+		ConfirmationBackoff:       time.Hour,
+	}, config.FailoverConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if c.confirmFailure(ctx, "eu") {
+		t.Error("failure confirmed after the context was cancelled")
+	}
+	if len(svc.drainedRegions()) != 0 {
+		t.Errorf("drained %v", svc.drainedRegions())
+	}
+}
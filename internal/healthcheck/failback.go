@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// failbackActivatedBy records automatic failbacks in etcd, the history and notifications
+const failbackActivatedBy = "auto-failback"
+
+// checkFailback re-activates the highest-priority region drained by the checker once it kept passing
+// checks for the recovery period. A failed check restarts the period (hysteresis), and no failback
+// happens within the cooldown after the last automatic drain or failback, so a flapping region
+// can't bounce the active region back and forth.
+func (c *Checker) checkFailback(ctx context.Context) {
+	if c.isPaused() {
+		return
+	}
+
+	candidates := c.failbackCandidates()
+	for _, region := range candidates {
+		_, hasLeader, err := c.checkRegionLeader(ctx, region)
+		healthy := err == nil && hasLeader
+
+		c.mu.Lock()
+		if !healthy {
+			c.drainedRegions[region] = time.Time{}
+		} else if c.drainedRegions[region].IsZero() {
+			c.drainedRegions[region] = time.Now()
+		}
+		healthySince := c.drainedRegions[region]
+		lastSwitch := c.lastSwitch
+		c.mu.Unlock()
+
+		if !healthy {
+			c.logger.Info("drained region still unhealthy, no failback",
+				slog.String("region", region),
+			)
+			continue
+		}
+
+		// Wait for the highest-priority healthy region instead of failing back to a lower one first
+		healthyFor := time.Since(healthySince)
+		if healthyFor < c.cfg.AutoFailback.RecoveryPeriod {
+			c.logger.Info("drained region recovering, waiting before failback",
+				slog.String("region", region),
+				slog.Duration("healthy_for", healthyFor),
+				slog.Duration("recovery_period", c.cfg.AutoFailback.RecoveryPeriod),
+			)
+			return
+		}
+		if sinceSwitch := time.Since(lastSwitch); sinceSwitch < c.cfg.AutoFailback.Cooldown {
+			c.logger.Info("region recovered, failback waits for cooldown",
+				slog.String("region", region),
+				slog.Duration("cooldown_left", c.cfg.AutoFailback.Cooldown-sinceSwitch),
+			)
+			return
+		}
+
+		c.failback(ctx, region, healthyFor)
+		return
+	}
+}
+
+// failbackCandidates returns the regions drained by the checker that rank above the active region,
+// highest priority first. Drained regions that became active again are forgotten.
+func (c *Checker) failbackCandidates() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.drainedRegions, c.activeRegion)
+
+	// Regions missing from the priority list rank below all listed ones
+	priority := c.cfg.AutoFailback.Priority
+	activeRank := slices.Index(priority, c.activeRegion)
+	if c.activeRegion == "" || activeRank < 0 {
+		activeRank = len(priority)
+	}
+
+	var candidates []string
+	for _, region := range priority[:activeRank] {
+		if _, drained := c.drainedRegions[region]; drained {
+			candidates = append(candidates, region)
+		}
+	}
+	return candidates
+}
+
+// failback activates region, which replaces the active region
+func (c *Checker) failback(ctx context.Context, region string, healthyFor time.Duration) {
+	c.mu.RLock()
+	activeRegion := c.activeRegion
+	c.mu.RUnlock()
+
+	c.logger.Warn("higher-priority region recovered, failing back",
+		slog.String("region", region),
+		slog.String("active_region", activeRegion),
+		slog.Duration("healthy_for", healthyFor),
+	)
+
+	// Activation calls SetActiveRegion, so c.mu must not be held here
+	result, err := c.dcService.ActivateRegion(service.WithActivatedBy(ctx, failbackActivatedBy), region, false, nil)
+
+	c.mu.Lock()
+	c.lastSwitch = time.Now()
+	if err == nil {
+		delete(c.drainedRegions, region)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		c.logger.Error("automatic failback failed",
+			slog.String("region", region),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	c.logger.Info("automatic failback completed",
+		slog.String("region", region),
+		slog.Int("drained_nodes", result.DrainedNodes),
+		slog.Int("un_drained_nodes", result.UnDrainedNodes),
+		slog.Int("errors_count", len(result.Errors)),
+	)
+}
@@ -0,0 +1,99 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// failoverActivatedBy records automatic failovers in etcd, the history and notifications
+const failoverActivatedBy = "auto-failover"
+
+// failover promotes the first healthy failover candidate after failedRegion was drained, so the
+// service doesn't stay without an active region until an operator steps in. Candidates drained by
+// the checker are skipped, and no promotion happens within the cooldown after the last one.
+func (c *Checker) failover(ctx context.Context, failedRegion string) {
+	if len(c.failoverCfg.Candidates) == 0 || c.isPaused() {
+		return
+	}
+
+	c.mu.RLock()
+	sinceFailover := time.Since(c.lastFailover)
+	c.mu.RUnlock()
+	if sinceFailover < c.failoverCfg.Cooldown {
+		c.logger.Warn("failover waits for cooldown, no region promoted",
+			slog.String("failed_region", failedRegion),
+			slog.Duration("cooldown_left", c.failoverCfg.Cooldown-sinceFailover),
+		)
+		return
+	}
+
+	for _, region := range c.failoverCandidates(failedRegion) {
+		_, hasLeader, err := c.checkRegionLeader(ctx, region)
+		if err != nil || !hasLeader {
+			attrs := []any{slog.String("region", region)}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			c.logger.Warn("failover candidate unhealthy, trying the next one", attrs...)
+			continue
+		}
+
+		c.promote(ctx, region, failedRegion)
+		return
+	}
+
+	c.logger.Error("no healthy failover candidate, no region is active",
+		slog.String("failed_region", failedRegion),
+		slog.Any("candidates", c.failoverCfg.Candidates),
+	)
+}
+
+// failoverCandidates returns the configured candidates in order, without the failed region
+// and regions the checker drained
+func (c *Checker) failoverCandidates(failedRegion string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var candidates []string
+	for _, region := range c.failoverCfg.Candidates {
+		if _, drained := c.drainedRegions[region]; region == failedRegion || drained {
+			continue
+		}
+		candidates = append(candidates, region)
+	}
+	return candidates
+}
+
+// promote activates region in place of the drained failedRegion
+func (c *Checker) promote(ctx context.Context, region, failedRegion string) {
+	c.logger.Warn("promoting failover candidate",
+		slog.String("region", region),
+		slog.String("failed_region", failedRegion),
+	)
+
+	// Activation calls SetActiveRegion, so c.mu must not be held here
+	result, err := c.dcService.ActivateRegion(service.WithActivatedBy(ctx, failoverActivatedBy), region, false, nil)
+
+	c.mu.Lock()
+	c.lastFailover = time.Now()
+	c.lastSwitch = c.lastFailover
+	c.mu.Unlock()
+
+	if err != nil {
+		c.logger.Error("automatic failover failed",
+			slog.String("region", region),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	c.logger.Info("automatic failover completed",
+		slog.String("region", region),
+		slog.Int("drained_nodes", result.DrainedNodes),
+		slog.Int("un_drained_nodes", result.UnDrainedNodes),
+		slog.Int("errors_count", len(result.Errors)),
+	)
+}
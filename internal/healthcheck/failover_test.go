@@ -0,0 +1,173 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// failoverRegions returns the failed region eu and the candidates us and ap, one datacenter each
+func failoverRegions() []model.Region {
+	return []model.Region{
+		{Name: "eu", Datacenters: []model.Datacenter{{Name: "dc1"}}},
+		{Name: "us", Datacenters: []model.Datacenter{{Name: "dc3"}}},
+		{Name: "ap", Datacenters: []model.Datacenter{{Name: "dc4"}}},
+	}
+}
+
+func TestFailoverCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		drained    []string
+		want       []string
+	}{
+		{name: "configured order", candidates: []string{"ap", "us"}, want: []string{"ap", "us"}},
+		{name: "failed region skipped", candidates: []string{"eu", "us", "ap"}, want: []string{"us", "ap"}},
+		{name: "drained region skipped", candidates: []string{"us", "ap"}, drained: []string{"us"}, want: []string{"ap"}},
+		{name: "every candidate drained", candidates: []string{"us", "ap"}, drained: []string{"us", "ap"}},
+		{name: "no candidates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCheckerWithConfig(&mockService{}, config.HealthCheckConfig{Enabled: true, FailedThreshold: 3},
+				config.FailoverConfig{Candidates: tt.candidates})
+			for _, region := range tt.drained {
+				c.drainedRegions[region] = time.Time{}
+			}
+
+			if got := c.failoverCandidates("eu"); !slices.Equal(got, tt.want) {
+				t.Errorf("failoverCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailover(t *testing.T) {
+	noLeader := []leaderAnswer{{hasLeader: false}}
+
+	tests := []struct {
+		name          string
+		candidates    []string
+		cooldown      time.Duration
+		lastFailover  time.Duration // How long ago the last failover happened, never when zero
+		drained       []string
+		leaders       map[string][]leaderAnswer
+		paused        bool
+		activateErr   error
+		wantActivated []string
+		wantFailover  bool // lastFailover is updated
+	}{
+		{
+			name:          "first candidate promoted",
+			candidates:    []string{"us", "ap"},
+			wantActivated: []string{"us"},
+			wantFailover:  true,
+		},
+		{
+			name:          "unhealthy candidate skipped",
+			candidates:    []string{"us", "ap"},
+			leaders:       map[string][]leaderAnswer{"dc3": noLeader},
+			wantActivated: []string{"ap"},
+			wantFailover:  true,
+		},
+		{
+			name:          "drained candidate skipped",
+			candidates:    []string{"us", "ap"},
+			drained:       []string{"us"},
+			wantActivated: []string{"ap"},
+			wantFailover:  true,
+		},
+		{
+			name:       "no healthy candidate",
+			candidates: []string{"us", "ap"},
+			leaders:    map[string][]leaderAnswer{"dc3": noLeader, "dc4": {{err: errors.New("connection refused")}}},
+		},
+		{
+			name:         "within the cooldown",
+			candidates:   []string{"us"},
+			cooldown:     time.Hour,
+			lastFailover: time.Minute,
+		},
+		{
+			name:          "after the cooldown",
+			candidates:    []string{"us"},
+			cooldown:      time.Hour,
+			lastFailover:  2 * time.Hour,
+			wantActivated: []string{"us"},
+			wantFailover:  true,
+		},
+		{
+			name:       "paused",
+			candidates: []string{"us"},
+			paused:     true,
+		},
+		{
+			name: "failover disabled",
+		},
+		{
+			name:         "failed activation starts the cooldown",
+			candidates:   []string{"us", "ap"},
+			activateErr:  errors.New("activation failed"),
+			wantFailover: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{regions: failoverRegions(), leaders: tt.leaders, activateErr: tt.activateErr}
+			c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{Enabled: true, FailedThreshold: 3},
+				config.FailoverConfig{Candidates: tt.candidates, Cooldown: tt.cooldown})
+			for _, region := range tt.drained {
+				c.drainedRegions[region] = time.Time{}
+			}
+			if tt.lastFailover > 0 {
+				c.lastFailover = time.Now().Add(-tt.lastFailover)
+			}
+			before := c.lastFailover
+			c.SetPaused(tt.paused)
+
+			c.failover(context.Background(), "eu")
+
+			if !slices.Equal(svc.activated, tt.wantActivated) {
+				t.Errorf("activated %v, want %v", svc.activated, tt.wantActivated)
+			}
+			if updated := c.lastFailover.After(before); updated != tt.wantFailover {
+				t.Errorf("lastFailover updated = %v, want %v", updated, tt.wantFailover)
+			}
+		})
+	}
+}
+
+func TestDrainTriggersFailover(t *testing.T) {
+	regions := failoverRegions()
+	regions[0].Status = model.DatacenterStatusActive
+	svc := &mockService{
+		activeRegion: "eu",
+		regions:      regions,
+		leaders:      map[string][]leaderAnswer{"dc1": {{hasLeader: false}}},
+	}
+	c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{Enabled: true, FailedThreshold: 1},
+		config.FailoverConfig{Candidates: []string{"eu", "us"}})
+	c.activeRegion = "eu"
+
+	c.checkActiveRegion(context.Background())
+
+	if drained := svc.drainedRegions(); !slices.Equal(drained, []string{"eu"}) {
+		t.Fatalf("drained %v, want [eu]", drained)
+	}
+	if !slices.Equal(svc.activated, []string{"us"}) {
+		t.Errorf("activated %v after draining eu, want [us]", svc.activated)
+	}
+
+	// The drained region is no candidate for the next failover
+	if got := c.failoverCandidates("us"); len(got) != 0 {
+		t.Errorf("failoverCandidates() = %v after draining eu, want none", got)
+	}
+}
@@ -0,0 +1,180 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/service"
+)
+
+// leaderAnswer is one scripted answer of CheckClusterLeader
+type leaderAnswer struct {
+	hasLeader bool
+	err       error
+}
+
+// mockService implements the parts of service.DatacenterService the checker uses;
+// calling any other method panics through the nil embedded interface
+type mockService struct {
+	service.DatacenterService
+
+	mu              sync.Mutex
+	activeRegion    string
+	activeRegionErr error
+	regions         []model.Region
+	regionsErr      error
+	leaders         map[string][]leaderAnswer // datacenter -> answers in call order, the last one repeats; none means a leader
+	leaderCalls     map[string]int
+	etcdErr         error
+	onEtcdCheck     func() // Called by CheckEtcdConnection, the last step of a failure confirmation
+	drainErr        error
+	drained         []string // regions drained through DrainAllNodesInRegion
+	activated       []string // regions activated through ActivateRegion
+	activateErr     error
+	saved           []*model.HealthFailureCounts
+	loaded          *model.HealthFailureCounts
+}
+
+func (m *mockService) GetActiveRegion(context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.activeRegion, m.activeRegionErr
+}
+
+func (m *mockService) ListRegions(context.Context) ([]model.Region, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.regions, m.regionsErr
+}
+
+func (m *mockService) GetRegionDatacenters(_ context.Context, region string) (*model.Region, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.regions {
+		if m.regions[i].Name == region {
+			r := m.regions[i]
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockService) CheckClusterLeader(_ context.Context, clusterName string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.leaderCalls == nil {
+		m.leaderCalls = make(map[string]int)
+	}
+	call := m.leaderCalls[clusterName]
+	m.leaderCalls[clusterName]++
+
+	answers := m.leaders[clusterName]
+	if len(answers) == 0 {
+		return clusterName + "-leader", true, nil
+	}
+	answer := answers[min(call, len(answers)-1)]
+	if !answer.hasLeader || answer.err != nil {
+		return "", false, answer.err
+	}
+	return clusterName + "-leader", true, nil
+}
+
+func (m *mockService) CheckEtcdConnection(context.Context) error {
+	if m.onEtcdCheck != nil {
+		m.onEtcdCheck()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.etcdErr
+}
+
+func (m *mockService) DrainAllNodesInRegion(_ context.Context, region string) (*model.DrainResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.drainErr != nil {
+		return nil, m.drainErr
+	}
+	m.drained = append(m.drained, region)
+	return &model.DrainResult{Region: region}, nil
+}
+
+func (m *mockService) ActivateRegion(_ context.Context, region string, _ bool, _ *model.DrainOverride) (*model.ActivationResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.activateErr != nil {
+		return nil, m.activateErr
+	}
+	m.activated = append(m.activated, region)
+	return &model.ActivationResult{Activated: region, Errors: []string{}}, nil
+}
+
+func (m *mockService) SaveHealthFailures(_ context.Context, counts *model.HealthFailureCounts) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.saved = append(m.saved, counts)
+	return nil
+}
+
+func (m *mockService) LoadHealthFailures(context.Context) (*model.HealthFailureCounts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.loaded, nil
+}
+
+// drainedRegions returns the regions drained so far
+func (m *mockService) drainedRegions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.drained...)
+}
+
+// recordingNotifier collects notification events
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []model.NotificationEvent
+}
+
+func (n *recordingNotifier) Notify(event model.NotificationEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+}
+
+// types returns the types of the recorded events
+func (n *recordingNotifier) types() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	types := make([]string, 0, len(n.events))
+	for _, event := range n.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// newTestChecker returns an enabled checker backed by svc
+func newTestChecker(svc *mockService) *Checker {
+	return newTestCheckerWithConfig(svc, config.HealthCheckConfig{Enabled: true, FailedThreshold: 3}, config.FailoverConfig{})
+}
+
+// newTestCheckerWithConfig returns a checker backed by svc with the given configuration
+func newTestCheckerWithConfig(svc *mockService, cfg config.HealthCheckConfig, failoverCfg config.FailoverConfig) *Checker {
+	return NewChecker(&cfg, failoverCfg, svc, &recordingNotifier{}, 0, slog.New(slog.DiscardHandler))
+}
+
+// activeRegions returns region eu with dc1 and dc2 serving and region us with dc3 drained
+func activeRegions() []model.Region {
+	return []model.Region{
+		{Name: "eu", Status: model.DatacenterStatusActive, Datacenters: []model.Datacenter{{Name: "dc1"}, {Name: "dc2"}}},
+		{Name: "us", Status: model.DatacenterStatusDraining, Datacenters: []model.Datacenter{{Name: "dc3"}}},
+	}
+}
@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestSetPaused(t *testing.T) {
+	svc := &mockService{
+		activeRegion: "eu",
+		regions:      activeRegions(),
+		leaders:      map[string][]leaderAnswer{"dc1": {{hasLeader: false}}},
+	}
+	c := newTestChecker(svc)
+	c.activeRegion = "eu"
+	c.failureCounter["eu"] = 2
+
+	c.SetPaused(true)
+
+	state := c.State()
+	if !state.Paused {
+		t.Error("state not paused after SetPaused(true)")
+	}
+	if len(state.Failures) != 0 {
+		t.Errorf("failures = %v after pausing, want cleared", state.Failures)
+	}
+
+	// Failed checks are neither counted nor drained while paused
+	for range 5 {
+		c.checkActiveRegion(context.Background())
+	}
+	if got := c.State().Failures["eu"]; got != 0 {
+		t.Errorf("failures = %d while paused, want 0", got)
+	}
+	if drained := svc.drainedRegions(); len(drained) != 0 {
+		t.Fatalf("drained %v while paused", drained)
+	}
+
+	// Pausing twice changes nothing
+	c.SetPaused(true)
+	if !c.State().Paused {
+		t.Error("state not paused after pausing twice")
+	}
+
+	// Once resumed, failures count again and reach the threshold
+	c.SetPaused(false)
+	if c.State().Paused {
+		t.Error("state paused after SetPaused(false)")
+	}
+	for range 3 {
+		c.checkActiveRegion(context.Background())
+	}
+	if drained := svc.drainedRegions(); len(drained) != 1 || drained[0] != "eu" {
+		t.Errorf("drained %v after resuming, want [eu]", drained)
+	}
+}
+
+func TestSetPausedCancelsConfirmedDrain(t *testing.T) {
+	svc := &mockService{
+		activeRegion: "eu",
+		regions:      activeRegions(),
+		leaders:      map[string][]leaderAnswer{"dc1": {{hasLeader: false}}},
+	}
+	c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{
+		Enabled:                   true,
+		FailedThreshold:           3,
+		RequireQuorumConfirmation: true,
+		ConfirmationBackoff:       time.Millisecond,
+	}, config.FailoverConfig{})
+	c.activeRegion = "eu"
+	c.failureCounter["eu"] = 2
+
+	// Maintenance mode is switched on while the failure is being confirmed
+	svc.onEtcdCheck = func() { c.SetPaused(true) }
+
+	c.checkActiveRegion(context.Background())
+
+	if drained := svc.drainedRegions(); len(drained) != 0 {
+		t.Errorf("drained %v after pausing during the confirmation", drained)
+	}
+	if c.activeRegion != "eu" {
+		t.Errorf("activeRegion = %q, want eu", c.activeRegion)
+	}
+}
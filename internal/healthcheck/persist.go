@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// loadFailures restores the failure counters saved before a restart, so escalation toward the
+// drain threshold continues. Counters saved for another active region or older than
+// health_check.persist_max_age are ignored.
+func (c *Checker) loadFailures(ctx context.Context, activeRegion string) {
+	saved, err := c.dcService.LoadHealthFailures(ctx)
+	if err != nil {
+		c.logger.Warn("failed to load saved health check failures, starting from zero",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if saved == nil {
+		return
+	}
+
+	age := time.Since(saved.UpdatedAt)
+	if saved.ActiveRegion != activeRegion || age > c.cfg.PersistMaxAge {
+		c.logger.Info("ignoring saved health check failures",
+			slog.String("saved_region", saved.ActiveRegion),
+			slog.String("active_region", activeRegion),
+			slog.Duration("age", age),
+		)
+		return
+	}
+
+	c.mu.Lock()
+	for region, count := range saved.Failures {
+		if count > 0 {
+			c.failureCounter[region] = count
+		}
+	}
+	c.lastSaved = maps.Clone(c.failureCounter)
+	c.mu.Unlock()
+
+	c.logger.Info("restored saved health check failures",
+		slog.String("region", activeRegion),
+		slog.Int("consecutive_failures", saved.Failures[activeRegion]),
+		slog.Duration("age", age),
+	)
+}
+
+// persistFailures saves the failure counters after a check. Nothing is written while
+// no region is failing and the saved counters are already cleared.
+func (c *Checker) persistFailures(ctx context.Context) {
+	c.mu.RLock()
+	counts := &model.HealthFailureCounts{
+		ActiveRegion: c.activeRegion,
+		Failures:     make(map[string]int, len(c.failureCounter)),
+		UpdatedAt:    time.Now(),
+	}
+	for region, count := range c.failureCounter {
+		if count > 0 {
+			counts.Failures[region] = count
+		}
+	}
+	unchanged := len(counts.Failures) == 0 && len(c.lastSaved) == 0
+	c.mu.RUnlock()
+
+	if unchanged {
+		return
+	}
+
+	if err := c.dcService.SaveHealthFailures(ctx, counts); err != nil {
+		c.logger.Warn("failed to save health check failures",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	c.mu.Lock()
+	c.lastSaved = counts.Failures
+	c.mu.Unlock()
+}
@@ -0,0 +1,112 @@
+package healthcheck
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// newPersistingChecker returns a checker that saves its failure counters, ignoring saves older than 10 minutes
+func newPersistingChecker(svc *mockService) *Checker {
+	return newTestCheckerWithConfig(svc, config.HealthCheckConfig{
+		Enabled:         true,
+		FailedThreshold: 3,
+		PersistFailures: true,
+		PersistMaxAge:   10 * time.Minute,
+	}, config.FailoverConfig{})
+}
+
+func TestLoadFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		saved *model.HealthFailureCounts
+		want  map[string]int
+	}{
+		{
+			name:  "restored",
+			saved: &model.HealthFailureCounts{ActiveRegion: "eu", Failures: map[string]int{"eu": 2}, UpdatedAt: time.Now().Add(-time.Minute)},
+			want:  map[string]int{"eu": 2},
+		},
+		{
+			name:  "zero counters are skipped",
+			saved: &model.HealthFailureCounts{ActiveRegion: "eu", Failures: map[string]int{"eu": 2, "us": 0}, UpdatedAt: time.Now()},
+			want:  map[string]int{"eu": 2},
+		},
+		{
+			name:  "saved for another active region",
+			saved: &model.HealthFailureCounts{ActiveRegion: "us", Failures: map[string]int{"us": 2}, UpdatedAt: time.Now()},
+			want:  map[string]int{},
+		},
+		{
+			name:  "older than the maximum age",
+			saved: &model.HealthFailureCounts{ActiveRegion: "eu", Failures: map[string]int{"eu": 2}, UpdatedAt: time.Now().Add(-11 * time.Minute)},
+			want:  map[string]int{},
+		},
+		{
+			name: "nothing saved",
+			want: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{loaded: tt.saved}
+			c := newPersistingChecker(svc)
+
+			c.loadFailures(context.Background(), "eu")
+
+			if got := c.State().Failures; !maps.Equal(got, tt.want) {
+				t.Errorf("failures = %v, want %v", got, tt.want)
+			}
+
+			// The next check saves restored counters again; without any it skips the write
+			c.persistFailures(context.Background())
+			wantSaves := 1
+			if len(tt.want) == 0 {
+				wantSaves = 0
+			}
+			if len(svc.saved) != wantSaves {
+				t.Errorf("saved %d times after loading, want %d", len(svc.saved), wantSaves)
+			}
+		})
+	}
+}
+
+func TestPersistFailures(t *testing.T) {
+	svc := &mockService{}
+	c := newPersistingChecker(svc)
+	c.activeRegion = "eu"
+
+	// Nothing failing and nothing saved: no write
+	c.persistFailures(context.Background())
+	if len(svc.saved) != 0 {
+		t.Fatalf("saved %+v while healthy", svc.saved)
+	}
+
+	// A failure is saved with the monitored region
+	c.failureCounter["eu"] = 1
+	c.failureCounter["us"] = 0
+	c.persistFailures(context.Background())
+	if len(svc.saved) != 1 {
+		t.Fatalf("saved %d times after a failure, want 1", len(svc.saved))
+	}
+	saved := svc.saved[0]
+	if saved.ActiveRegion != "eu" || !maps.Equal(saved.Failures, map[string]int{"eu": 1}) || saved.UpdatedAt.IsZero() {
+		t.Errorf("saved %+v, want eu with one failure", saved)
+	}
+
+	// Recovery clears the saved counters once, then writes stop again
+	c.failureCounter["eu"] = 0
+	c.persistFailures(context.Background())
+	c.persistFailures(context.Background())
+	if len(svc.saved) != 2 {
+		t.Fatalf("saved %d times after recovering, want 2", len(svc.saved))
+	}
+	if cleared := svc.saved[1]; len(cleared.Failures) != 0 {
+		t.Errorf("saved %+v after recovering, want no failures", cleared)
+	}
+}
@@ -0,0 +1,143 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestDatacenterQuorum(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured int
+		total      int
+		want       int
+	}{
+		{name: "majority of one", total: 1, want: 1},
+		{name: "majority of two", total: 2, want: 2},
+		{name: "majority of three", total: 3, want: 2},
+		{name: "majority of four", total: 4, want: 3},
+		{name: "negative means majority", configured: -1, total: 3, want: 2},
+		{name: "explicit", configured: 1, total: 3, want: 1},
+		{name: "larger than the region requires all", configured: 5, total: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := datacenterQuorum(tt.configured, tt.total); got != tt.want {
+				t.Errorf("datacenterQuorum(%d, %d) = %d, want %d", tt.configured, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRegionLeaderAllDatacenters(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	noLeader := []leaderAnswer{{hasLeader: false}}
+	unreachable := []leaderAnswer{{err: errRefused}}
+
+	tests := []struct {
+		name          string
+		checkAll      bool
+		quorum        int
+		leaders       map[string][]leaderAnswer
+		wantLeader    string
+		wantHasLeader bool
+		wantErr       bool
+		wantChecked   []string // Datacenters asked for their leader
+	}{
+		{
+			name:          "first datacenter only",
+			leaders:       map[string][]leaderAnswer{"dc2": noLeader, "dc3": noLeader},
+			wantLeader:    "dc1-leader",
+			wantHasLeader: true,
+			wantChecked:   []string{"dc1"},
+		},
+		{
+			name:        "first datacenter only misses the healthy ones",
+			leaders:     map[string][]leaderAnswer{"dc1": noLeader},
+			wantChecked: []string{"dc1"},
+		},
+		{
+			name:          "majority healthy",
+			checkAll:      true,
+			leaders:       map[string][]leaderAnswer{"dc1": noLeader},
+			wantLeader:    "dc2-leader",
+			wantHasLeader: true,
+			wantChecked:   []string{"dc1", "dc2", "dc3"},
+		},
+		{
+			name:        "majority without a leader",
+			checkAll:    true,
+			leaders:     map[string][]leaderAnswer{"dc1": noLeader, "dc3": unreachable},
+			wantLeader:  "dc2-leader",
+			wantChecked: []string{"dc1", "dc2", "dc3"},
+		},
+		{
+			name:          "configured quorum of one",
+			checkAll:      true,
+			quorum:        1,
+			leaders:       map[string][]leaderAnswer{"dc1": noLeader, "dc2": unreachable},
+			wantLeader:    "dc3-leader",
+			wantHasLeader: true,
+			wantChecked:   []string{"dc1", "dc2", "dc3"},
+		},
+		{
+			name:        "quorum larger than the region requires all",
+			checkAll:    true,
+			quorum:      5,
+			leaders:     map[string][]leaderAnswer{"dc3": noLeader},
+			wantLeader:  "dc1-leader",
+			wantChecked: []string{"dc1", "dc2", "dc3"},
+		},
+		{
+			name:        "every datacenter unreachable",
+			checkAll:    true,
+			leaders:     map[string][]leaderAnswer{"dc1": unreachable, "dc2": unreachable, "dc3": unreachable},
+			wantErr:     true,
+			wantChecked: []string{"dc1", "dc2", "dc3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				regions: []model.Region{
+					{Name: "eu", Datacenters: []model.Datacenter{{Name: "dc1"}, {Name: "dc2"}, {Name: "dc3"}}},
+				},
+				leaders: tt.leaders,
+			}
+			c := newTestCheckerWithConfig(svc, config.HealthCheckConfig{
+				Enabled:             true,
+				FailedThreshold:     3,
+				CheckAllDatacenters: tt.checkAll,
+				DatacenterQuorum:    tt.quorum,
+			}, config.FailoverConfig{})
+
+			leader, hasLeader, err := c.checkRegionLeader(context.Background(), "eu")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRegionLeader() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errRefused) {
+				t.Errorf("error = %v, want it to wrap %v", err, errRefused)
+			}
+			if leader != tt.wantLeader || hasLeader != tt.wantHasLeader {
+				t.Errorf("checkRegionLeader() = %q, %v, want %q, %v", leader, hasLeader, tt.wantLeader, tt.wantHasLeader)
+			}
+			for _, dc := range []string{"dc1", "dc2", "dc3"} {
+				want := 0
+				if slices.Contains(tt.wantChecked, dc) {
+					want = 1
+				}
+				if got := svc.leaderCalls[dc]; got != want {
+					t.Errorf("%s checked %d times, want %d", dc, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,26 +1,96 @@
 package logger
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 )
 
-// New creates a new structured logger using slog
-func New() *slog.Logger {
-	opts := &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Option customizes a logger created by NewWithLevel
+type Option func(*options)
+
+type options struct {
+	text bool
+}
+
+// WithTextHandler makes the logger write human-readable key=value lines instead of JSON
+func WithTextHandler() Option {
+	return func(o *options) {
+		o.text = true
 	}
+}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	return slog.New(handler)
+// New creates a new structured logger using slog
+func New() *slog.Logger {
+	return NewWithLevel(slog.LevelInfo)
 }
 
 // NewWithLevel creates a new logger with specified log level
-func NewWithLevel(level slog.Level) *slog.Logger {
-	opts := &slog.HandlerOptions{
+func NewWithLevel(level slog.Level, opts ...Option) *slog.Logger {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	handlerOpts := &slog.HandlerOptions{
 		Level: level,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	var handler slog.Handler
+	if o.text {
+		handler = slog.NewTextHandler(os.Stdout, handlerOpts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, handlerOpts)
+	}
 	return slog.New(handler)
 }
+
+// NewFromConfig creates a logger from level and format strings as found in the configuration
+func NewFromConfig(level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if strings.ToLower(format) == FormatText {
+		opts = append(opts, WithTextHandler())
+	}
+	return NewWithLevel(lvl, opts...), nil
+}
+
+// ParseLevel converts debug, info, warn or error (case-insensitive) into a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q: must be debug, info, warn or error", level)
+	}
+}
+
+// ValidateFormat checks that format is json or text (case-insensitive)
+func ValidateFormat(format string) error {
+	switch strings.ToLower(format) {
+	case FormatJSON, FormatText:
+		return nil
+	default:
+		return fmt.Errorf("unknown log format %q: must be json or text", format)
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{level: "debug", want: slog.LevelDebug},
+		{level: "info", want: slog.LevelInfo},
+		{level: "warn", want: slog.LevelWarn},
+		{level: "warning", want: slog.LevelWarn},
+		{level: "error", want: slog.LevelError},
+		{level: "DEBUG", want: slog.LevelDebug},
+		{level: "Warn", want: slog.LevelWarn},
+		{level: "", wantErr: true},
+		{level: "trace", wantErr: true},
+		{level: "info ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			got, err := ParseLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.level, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: "json"},
+		{format: "text"},
+		{format: "JSON"},
+		{format: "Text"},
+		{format: "", wantErr: true},
+		{format: "logfmt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if err := ValidateFormat(tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		format    string
+		wantText  bool
+		wantLevel slog.Level // Lowest enabled level
+		wantErr   bool
+	}{
+		{name: "json info", level: "info", format: "json", wantLevel: slog.LevelInfo},
+		{name: "text debug", level: "debug", format: "text", wantText: true, wantLevel: slog.LevelDebug},
+		{name: "upper-case text", level: "ERROR", format: "TEXT", wantText: true, wantLevel: slog.LevelError},
+		{name: "unknown level", level: "verbose", format: "json", wantErr: true},
+		{name: "unknown format", level: "info", format: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := NewFromConfig(tt.level, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFromConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			_, isText := log.Handler().(*slog.TextHandler)
+			if isText != tt.wantText {
+				t.Errorf("handler = %T, want text %v", log.Handler(), tt.wantText)
+			}
+			ctx := context.Background()
+			if !log.Enabled(ctx, tt.wantLevel) || log.Enabled(ctx, tt.wantLevel-1) {
+				t.Errorf("logger isn't enabled from level %v", tt.wantLevel)
+			}
+		})
+	}
+}
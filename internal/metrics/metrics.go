@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Application metrics, registered with the default Prometheus registry served on /metrics
+var (
+	ActivationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dc_switcher_activations_total",
+		Help: "Total number of datacenter and region activations by target and result.",
+	}, []string{"datacenter", "result"})
+	NodesDrainedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dc_switcher_nodes_drained_total",
+		Help: "Total number of nodes drained by the switcher.",
+	})
+	HeartbeatFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dc_switcher_heartbeat_failures_total",
+		Help: "Total number of failed heartbeat reads or writes in etcd.",
+	})
+	AmDrained = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dc_switcher_am_drained",
+		Help: "Whether this instance has drained its own datacenter (1) or not (0).",
+	})
+	ActivationDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dc_switcher_activation_duration_seconds",
+		Help:    "Duration of datacenter and region activations in seconds.",
+		Buckets: []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	})
+)
+
+// Activation results used as the "result" label of ActivationsTotal
+const (
+	ResultSuccess = "success"
+	ResultPartial = "partial"
+	ResultError   = "error"
+)
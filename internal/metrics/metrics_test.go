@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestApplicationMetricsRegistered(t *testing.T) {
+	// Vectors are gathered only once they have a series
+	ActivationsTotal.WithLabelValues("dc1", ResultSuccess).Inc()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	types := make(map[string]string, len(families))
+	for _, family := range families {
+		types[family.GetName()] = family.GetType().String()
+	}
+
+	want := map[string]string{
+		"dc_switcher_activations_total":           "COUNTER",
+		"dc_switcher_nodes_drained_total":         "COUNTER",
+		"dc_switcher_heartbeat_failures_total":    "COUNTER",
+		"dc_switcher_am_drained":                  "GAUGE",
+		"dc_switcher_activation_duration_seconds": "HISTOGRAM",
+	}
+	for name, metricType := range want {
+		if got, ok := types[name]; !ok {
+			t.Errorf("%s is not registered", name)
+		} else if got != metricType {
+			t.Errorf("%s type = %s, want %s", name, got, metricType)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+// Allocation represents a Nomad allocation placed on a node
+type Allocation struct {
+	ID            string `json:"id"`
+	JobID         string `json:"job_id"`
+	JobType       string `json:"job_type"` // service | batch | system
+	TaskGroup     string `json:"task_group"`
+	DesiredStatus string `json:"desired_status"` // run | stop | evict
+	ClientStatus  string `json:"client_status"`  // pending | running | complete | failed | lost
+	NodeID        string `json:"node_id,omitempty"`
+	NodeName      string `json:"node_name,omitempty"`
+	ModifyTime    int64  `json:"modify_time,omitempty"` // unix nanoseconds of the last status change
+}
+
+// IsActive returns true if the allocation is still pending or running on its node
+func (a *Allocation) IsActive() bool {
+	return a.ClientStatus == "pending" || a.ClientStatus == "running"
+}
@@ -4,30 +4,65 @@ package model
 type Datacenter struct {
 	Name          string `json:"name"`
 	Region        string `json:"region"`
-	Status        string `json:"status"` // active | draining | error
+	Status        string `json:"status"` // active | draining | empty | error
 	NodesTotal    int    `json:"nodes_total"`
 	NodesReady    int    `json:"nodes_ready"`
 	NodesDraining int    `json:"nodes_draining"`
 	JobsTotal     int    `json:"jobs_total"`
 	JobsRunning   int    `json:"jobs_running"`
 	JobsStopped   int    `json:"jobs_stopped"`
+	JobsFailing   int    `json:"jobs_failing"`  // Jobs with at least one failed allocation
 	HeartbeatAge  int64  `json:"heartbeat_age"` // Age of heartbeat in milliseconds (0 if no heartbeat)
 	IsMyDC        bool   `json:"is_my_dc"`      // Whether this is the datacenter managed by this switcher instance
+	Enabled       bool   `json:"enabled"`       // False when the cluster is disabled in config and skipped by activation
 }
 
 // DatacenterStatus represents possible datacenter states
 const (
 	DatacenterStatusActive   = "active"
 	DatacenterStatusDraining = "draining"
+	DatacenterStatusEmpty    = "empty" // The cluster has no nodes
 	DatacenterStatusError    = "error"
 )
 
+// Region-only states, next to the datacenter states
+const (
+	RegionStatusPartial  = "partial"  // Some datacenters active, some draining
+	RegionStatusDegraded = "degraded" // Active, but too many jobs have failed allocations
+)
+
 // Region represents a Nomad region with its datacenters
 type Region struct {
 	Name        string       `json:"name"`
 	Datacenters []Datacenter `json:"datacenters"`
-	Status      string       `json:"status"` // active | partial | draining | error
+	Status      string       `json:"status"` // active | degraded | partial | draining | empty | error
 	JobsTotal   int          `json:"jobs_total"`
 	JobsRunning int          `json:"jobs_running"`
 	JobsStopped int          `json:"jobs_stopped"`
+	JobsFailing int          `json:"jobs_failing"` // Jobs with at least one failed allocation
+}
+
+// LeaderStatus represents the Nomad leader state of a datacenter's cluster
+type LeaderStatus struct {
+	Datacenter string `json:"datacenter"`
+	HasLeader  bool   `json:"has_leader"`
+	Leader     string `json:"leader"` // Leader RPC address, empty without a leader
+}
+
+// ClusterHealth is the result of an on-demand health check of a datacenter's Nomad cluster
+type ClusterHealth struct {
+	Datacenter string      `json:"datacenter"`
+	Healthy    bool        `json:"healthy"`   // Reachable with an elected leader and every reported agent check ok
+	Reachable  bool        `json:"reachable"` // The cluster answered the leader query
+	HasLeader  bool        `json:"has_leader"`
+	Leader     string      `json:"leader"`           // Leader RPC address, empty without a leader
+	Client     *AgentCheck `json:"client,omitempty"` // Agent client health, absent when the agent runs no client
+	Server     *AgentCheck `json:"server,omitempty"` // Agent server health, absent when the agent runs no server
+	Error      string      `json:"error,omitempty"`  // First failed check
+}
+
+// AgentCheck is the client or server part of a Nomad agent health response
+type AgentCheck struct {
+	Ok      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import "fmt"
+
+// EmergencyDrainConfirmation is the value an emergency drain request must confirm with
+const EmergencyDrainConfirmation = "drain-all"
+
+// EmergencyDrainRequest confirms draining every datacenter of every region
+type EmergencyDrainRequest struct {
+	Confirm string `json:"confirm"` // Must be EmergencyDrainConfirmation
+}
+
+// Validate checks that the request carries the confirmation, so the drain can't be triggered by accident
+func (r *EmergencyDrainRequest) Validate() error {
+	if r.Confirm != EmergencyDrainConfirmation {
+		return fmt.Errorf("confirm must be %q to drain all datacenters", EmergencyDrainConfirmation)
+	}
+	return nil
+}
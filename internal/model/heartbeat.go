@@ -1,13 +1,19 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // ActiveDatacenter represents the currently active datacenter information stored in etcd
 type ActiveDatacenter struct {
-	Datacenter    string    `json:"datacenter"`
-	ActivatedAt   time.Time `json:"activated_at"`
-	ActivatedBy   string    `json:"activated_by"` // "api", "startup", "recovery", etc.
-	LastHeartbeat time.Time `json:"last_heartbeat"`
+	Datacenter        string    `json:"datacenter"`                   // Active datacenter, the first of ActiveDatacenters when several are active
+	Region            string    `json:"region,omitempty"`             // Region of the active datacenters
+	ActiveDatacenters []string  `json:"active_datacenters,omitempty"` // Every active datacenter of the region (region-wide active mode)
+	ActivatedAt       time.Time `json:"activated_at"`
+	ActivatedBy       string    `json:"activated_by"` // "api", "startup", "recovery", etc.
+	LastHeartbeat     time.Time `json:"last_heartbeat"`
+	Revision          int64     `json:"-"` // etcd ModRevision of the key when read (0 if not read from etcd)
 }
 
 // HeartbeatInfo represents heartbeat information for a specific datacenter
@@ -16,6 +22,22 @@ type HeartbeatInfo struct {
 	LastSeen   time.Time `json:"last_seen"`
 }
 
+// IsActive reports whether dc is one of the active datacenters
+func (a *ActiveDatacenter) IsActive(dc string) bool {
+	if len(a.ActiveDatacenters) > 0 {
+		return slices.Contains(a.ActiveDatacenters, dc)
+	}
+	return a.Datacenter == dc
+}
+
+// Datacenters returns every active datacenter
+func (a *ActiveDatacenter) Datacenters() []string {
+	if len(a.ActiveDatacenters) > 0 {
+		return a.ActiveDatacenters
+	}
+	return []string{a.Datacenter}
+}
+
 // IsStale checks if the heartbeat is older than the given threshold
 func (a *ActiveDatacenter) IsStale(threshold time.Duration) bool {
 	return time.Since(a.LastHeartbeat) > threshold
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ActivationEvent represents a single activation recorded in the history audit trail
+type ActivationEvent struct {
+	Target         string    `json:"target"`       // Activated (or deactivated) datacenter or region name
+	TargetType     string    `json:"target_type"`  // datacenter | region | all
+	ActivatedBy    string    `json:"activated_by"` // "api", "api-region", etc.
+	Timestamp      time.Time `json:"timestamp"`
+	DrainedNodes   int       `json:"drained_nodes"`
+	UnDrainedNodes int       `json:"un_drained_nodes"`
+	ErrorCount     int       `json:"error_count"`
+}
+
+// ActivationEvent target types
+const (
+	ActivationTargetDatacenter = "datacenter"
+	ActivationTargetRegion     = "region"
+	ActivationTargetAll        = "all" // Emergency drain of every datacenter, nothing was activated
+)
@@ -1,9 +1,15 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Job represents a Nomad job with its status
 type Job struct {
 	ID          string   `json:"id"`
 	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
 	Type        string   `json:"type"`        // service | batch | system
 	Status      string   `json:"status"`      // running | pending | dead
 	Running     int      `json:"running"`     // number of running allocations
@@ -14,6 +20,27 @@ type Job struct {
 	Datacenters []string `json:"datacenters"` // list of datacenters job is targeting
 }
 
+// JobDetail is a job with its task groups and most recent allocations
+type JobDetail struct {
+	Job
+	Version     uint64            `json:"version"`
+	Stopped     bool              `json:"stopped"` // the job was stopped and is kept as dead
+	TaskGroups  []TaskGroupStatus `json:"task_groups"`
+	Allocations []Allocation      `json:"allocations"` // most recently modified first
+}
+
+// TaskGroupStatus holds the allocation counts of a single task group
+type TaskGroupStatus struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`   // allocations requested by the job definition
+	Running  int    `json:"running"` // number of running allocations
+	Desired  int    `json:"desired"` // queued, starting and running allocations
+	Failed   int    `json:"failed"`  // failed and lost allocations
+	Queued   int    `json:"queued"`
+	Starting int    `json:"starting"`
+	Complete int    `json:"complete"`
+}
+
 // JobAction represents an action to perform on a job
 type JobAction struct {
 	Action string `json:"action"` // start | stop
@@ -21,8 +48,90 @@ type JobAction struct {
 
 // JobActionResult represents the result of a job action
 type JobActionResult struct {
-	JobID   string   `json:"job_id"`
-	Action  string   `json:"action"`
-	Success bool     `json:"success"`
-	Errors  []string `json:"errors,omitempty"`
+	JobID          string   `json:"job_id"`
+	Action         string   `json:"action"`
+	Success        bool     `json:"success"`
+	Purged         bool     `json:"purged,omitempty"`          // The stopped job was also removed from Nomad
+	AlreadyStopped bool     `json:"already_stopped,omitempty"` // A restart found the job stopped and only started it
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// JobFilter narrows and paginates a job listing; zero values disable the corresponding filter
+type JobFilter struct {
+	Status string `json:"status,omitempty"` // running | pending | dead
+	Type   string `json:"type,omitempty"`   // service | batch | system | sysbatch
+	Prefix string `json:"prefix,omitempty"` // job ID prefix
+	Limit  int    `json:"limit,omitempty"`  // maximum number of jobs returned, 0 for no limit
+	Offset int    `json:"offset,omitempty"` // number of matching jobs skipped
+}
+
+// Validate checks that the filter only contains known statuses and types and a sane page
+func (f JobFilter) Validate() error {
+	switch f.Status {
+	case "", "running", "pending", "dead":
+	default:
+		return fmt.Errorf("invalid status %q: must be running, pending or dead", f.Status)
+	}
+	switch f.Type {
+	case "", "service", "batch", "system", "sysbatch":
+	default:
+		return fmt.Errorf("invalid type %q: must be service, batch, system or sysbatch", f.Type)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("invalid limit %d: must not be negative", f.Limit)
+	}
+	if f.Offset < 0 {
+		return fmt.Errorf("invalid offset %d: must not be negative", f.Offset)
+	}
+	return nil
+}
+
+// JobList is a page of jobs matching a JobFilter
+type JobList struct {
+	Jobs   []Job `json:"jobs"`
+	Total  int   `json:"total"` // number of jobs matching the filter before pagination
+	Limit  int   `json:"limit,omitempty"`
+	Offset int   `json:"offset"`
+}
+
+// BulkJobActionRequest applies one action to several jobs of a datacenter
+type BulkJobActionRequest struct {
+	Action string    `json:"action"`            // start | stop
+	JobIDs []string  `json:"job_ids,omitempty"` // explicit job selection
+	All    bool      `json:"all,omitempty"`     // select every job matching Filter instead of JobIDs
+	Filter JobFilter `json:"filter"`            // only used with All
+	Purge  bool      `json:"purge,omitempty"`   // remove stopped jobs from Nomad, only with stop
+}
+
+// Validate checks that the request has a known action and exactly one job selector
+func (r *BulkJobActionRequest) Validate() error {
+	if r.Action != "start" && r.Action != "stop" {
+		return fmt.Errorf("invalid action %q: must be start or stop", r.Action)
+	}
+	if r.Purge && r.Action != "stop" {
+		return errors.New("purge is only supported with the stop action")
+	}
+	if r.All && len(r.JobIDs) > 0 {
+		return errors.New("job_ids and all are mutually exclusive")
+	}
+	if !r.All && len(r.JobIDs) == 0 {
+		return errors.New("job_ids is required unless all is set")
+	}
+	if r.All {
+		return r.Filter.Validate()
+	}
+	return nil
+}
+
+// BulkJobActionResult holds the per-job results of a bulk job action
+type BulkJobActionResult struct {
+	Action    string            `json:"action"`
+	Results   []JobActionResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// IsPartial reports whether some job actions succeeded while others failed
+func (r *BulkJobActionResult) IsPartial() bool {
+	return r.Failed > 0 && r.Succeeded > 0
 }
@@ -0,0 +1,54 @@
+package model
+
+import "testing"
+
+func TestJobFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  JobFilter
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "every field", filter: JobFilter{Status: "running", Type: "service", Prefix: "api", Limit: 10, Offset: 20}},
+		{name: "pending sysbatch", filter: JobFilter{Status: "pending", Type: "sysbatch"}},
+		{name: "unknown status", filter: JobFilter{Status: "stopped"}, wantErr: true},
+		{name: "unknown type", filter: JobFilter{Type: "daemon"}, wantErr: true},
+		{name: "negative limit", filter: JobFilter{Limit: -1}, wantErr: true},
+		{name: "negative offset", filter: JobFilter{Offset: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBulkJobActionRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     BulkJobActionRequest
+		wantErr bool
+	}{
+		{name: "start", req: BulkJobActionRequest{Action: "start", JobIDs: []string{"api"}}},
+		{name: "stop", req: BulkJobActionRequest{Action: "stop", JobIDs: []string{"api"}}},
+		{name: "stop with purge", req: BulkJobActionRequest{Action: "stop", Purge: true, JobIDs: []string{"api"}}},
+		{name: "all with purge", req: BulkJobActionRequest{Action: "stop", Purge: true, All: true}},
+		{name: "start with purge", req: BulkJobActionRequest{Action: "start", Purge: true, JobIDs: []string{"api"}}, wantErr: true},
+		{name: "unknown action", req: BulkJobActionRequest{Action: "restart", JobIDs: []string{"api"}}, wantErr: true},
+		{name: "no jobs", req: BulkJobActionRequest{Action: "stop"}, wantErr: true},
+		{name: "job ids and all", req: BulkJobActionRequest{Action: "stop", All: true, JobIDs: []string{"api"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"fmt"
+	"time"
+)
+
 // Node represents a Nomad node
 type Node struct {
 	ID                    string `json:"id"`
@@ -7,6 +12,26 @@ type Node struct {
 	Drain                 bool   `json:"drain"`
 	SchedulingEligibility string `json:"scheduling_eligibility"` // "eligible" or "ineligible"
 	Status                string `json:"status"`
+	Datacenter            string `json:"datacenter"`
+	Region                string `json:"region,omitempty"` // Region of the node's cluster, only set in the all-nodes listing
+	NodeClass             string `json:"node_class,omitempty"`
+	Version               string `json:"version"` // Nomad agent version
+	Address               string `json:"address"`
+	AllocCount            *int   `json:"alloc_count,omitempty"` // running allocations, only set when requested
+}
+
+// AllNodes lists the nodes of every datacenter, grouped by datacenter
+type AllNodes struct {
+	Datacenters []DatacenterNodes `json:"datacenters"`
+	Total       int               `json:"total"` // Nodes across all datacenters that could be listed
+}
+
+// DatacenterNodes are the nodes of one datacenter in the all-nodes listing
+type DatacenterNodes struct {
+	Datacenter string `json:"datacenter"`
+	Region     string `json:"region"`
+	Nodes      []Node `json:"nodes"`
+	Error      string `json:"error,omitempty"` // Set when the datacenter's nodes could not be listed
 }
 
 // IsReady returns true if node can accept new allocations
@@ -15,10 +40,133 @@ func (n *Node) IsReady() bool {
 	return !n.Drain && n.SchedulingEligibility == "eligible"
 }
 
+// NodeState represents the drain and scheduling state of a node
+type NodeState struct {
+	Drain                 bool   `json:"drain"`
+	SchedulingEligibility string `json:"scheduling_eligibility"`
+}
+
+// DrainOptions controls how nodes are drained
+type DrainOptions struct {
+	Deadline         time.Duration // Negative means no deadline, 0 force-stops allocations immediately
+	IgnoreSystemJobs bool          // Leave system jobs running on drained nodes
+}
+
+// DrainOverride overrides configured drain options for a single request (nil fields keep the configured value)
+type DrainOverride struct {
+	Deadline         *time.Duration
+	IgnoreSystemJobs *bool
+}
+
+// ActivationOptions is the optional JSON body of the activate endpoints.
+// Each option can also be enabled with the query parameter of the same name.
+type ActivationOptions struct {
+	DryRun    bool `json:"dry_run"`
+	Exclusive bool `json:"exclusive"` // Only used by datacenter activation
+	Confirm   bool `json:"confirm"`   // Proceed beyond safety.max_nodes_affected
+}
+
+// PlannedNodeChange represents a node change that an activation would apply
+type PlannedNodeChange struct {
+	Cluster  string    `json:"cluster"`
+	NodeID   string    `json:"node_id"`
+	NodeName string    `json:"node_name"`
+	Before   NodeState `json:"before"`
+	After    NodeState `json:"after"`
+}
+
 // ActivationResult represents the result of datacenter activation
 type ActivationResult struct {
-	Activated      string   `json:"activated"`
-	DrainedNodes   int      `json:"drained_nodes"`
-	UnDrainedNodes int      `json:"un_drained_nodes"`
-	Errors         []string `json:"errors,omitempty"`
+	Activated      string                     `json:"activated"`
+	Deactivated    string                     `json:"deactivated,omitempty"` // Set instead of Activated by deactivations
+	DryRun         bool                       `json:"dry_run,omitempty"`
+	Exclusive      bool                       `json:"exclusive,omitempty"` // Same-region datacenters were drained too
+	Cancelled      bool                       `json:"cancelled,omitempty"` // Activation was interrupted before all nodes were processed
+	DrainedNodes   int                        `json:"drained_nodes"`
+	UnDrainedNodes int                        `json:"un_drained_nodes"`
+	PlannedChanges []PlannedNodeChange        `json:"planned_changes,omitempty"` // Populated only in dry-run mode
+	PerCluster     []ClusterActivationSummary `json:"per_cluster,omitempty"`     // Clusters whose nodes were processed
+	StuckNodes     []string                   `json:"stuck_nodes,omitempty"`     // Drained nodes ("cluster/node") still running allocations, with ?wait_for_drain=true
+	Errors         []string                   `json:"errors,omitempty"`
+}
+
+// DrainResult summarizes a drain of every node in a region, as done by the health checker
+type DrainResult struct {
+	Region            string               `json:"region"`
+	ClustersProcessed int                  `json:"clusters_processed"` // Clusters whose nodes could be listed
+	NodesDrained      int                  `json:"nodes_drained"`
+	PerCluster        []ClusterDrainResult `json:"per_cluster"`
+}
+
+// ClusterDrainResult describes the drain of one cluster
+type ClusterDrainResult struct {
+	Cluster      string   `json:"cluster"`
+	NodesTotal   int      `json:"nodes_total"`
+	NodesDrained int      `json:"nodes_drained"` // Nodes already drained before are not counted
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// ErrorCount returns the number of failures across all clusters
+func (r *DrainResult) ErrorCount() int {
+	count := 0
+	for _, cluster := range r.PerCluster {
+		count += len(cluster.Errors)
+	}
+	return count
+}
+
+// AllErrors returns the failures of all clusters, prefixed with the cluster name
+func (r *DrainResult) AllErrors() []string {
+	var errs []string
+	for _, cluster := range r.PerCluster {
+		for _, err := range cluster.Errors {
+			errs = append(errs, fmt.Sprintf("%s: %s", cluster.Cluster, err))
+		}
+	}
+	return errs
+}
+
+// ClusterActivationSummary describes what an activation did in one cluster
+type ClusterActivationSummary struct {
+	Cluster   string   `json:"cluster"`
+	Region    string   `json:"region"`
+	Drained   int      `json:"drained"`
+	UnDrained int      `json:"un_drained"`
+	Skipped   int      `json:"skipped"` // Nodes already in the wanted state
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// IsPartial reports whether some node changes succeeded while others failed
+func (r *ActivationResult) IsPartial() bool {
+	return len(r.Errors) > 0 && r.DrainedNodes+r.UnDrainedNodes > 0
+}
+
+// Cluster returns the summary of the named cluster, adding an empty one when it's missing.
+// The pointer is only valid until the next summary is added.
+func (r *ActivationResult) Cluster(name, region string) *ClusterActivationSummary {
+	for i := range r.PerCluster {
+		if r.PerCluster[i].Cluster == name {
+			return &r.PerCluster[i]
+		}
+	}
+	r.PerCluster = append(r.PerCluster, ClusterActivationSummary{Cluster: name, Region: region})
+	return &r.PerCluster[len(r.PerCluster)-1]
+}
+
+// AddClusterError records an error both in the result and in the summary of the cluster it occurred in
+func (r *ActivationResult) AddClusterError(name, region, errMsg string) {
+	r.Errors = append(r.Errors, errMsg)
+	summary := r.Cluster(name, region)
+	summary.Errors = append(summary.Errors, errMsg)
+}
+
+// ActivationProgress reports a node change applied during an activation
+type ActivationProgress struct {
+	Cluster   string `json:"cluster"`
+	NodeID    string `json:"node_id"`
+	NodeName  string `json:"node_name"`
+	Drain     bool   `json:"drain"`           // Whether the node was drained or undrained
+	Error     string `json:"error,omitempty"` // Set when the change failed
+	Completed int    `json:"completed"`       // Node changes finished in this cluster so far
+	Total     int    `json:"total"`           // Node changes needed in this cluster
 }
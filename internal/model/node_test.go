@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestActivationResultIsPartial(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ActivationResult
+		want   bool
+	}{
+		{name: "full success", result: ActivationResult{DrainedNodes: 2, UnDrainedNodes: 2}, want: false},
+		{name: "nothing to change", result: ActivationResult{}, want: false},
+		{name: "some drains failed", result: ActivationResult{DrainedNodes: 1, Errors: []string{"node n2: rejected"}}, want: true},
+		{name: "some undrains failed", result: ActivationResult{UnDrainedNodes: 1, Errors: []string{"node n2: rejected"}}, want: true},
+		{name: "total failure", result: ActivationResult{Errors: []string{"node n1: rejected", "node n2: rejected"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.IsPartial(); got != tt.want {
+				t.Errorf("IsPartial() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
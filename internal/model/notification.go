@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Notification event types
+const (
+	NotificationActivation      = "activation"        // A datacenter or region was activated
+	NotificationAutoDrain       = "automatic_drain"   // The health checker drained an unhealthy region
+	NotificationQuorumLossDrain = "quorum_loss_drain" // Nodes were drained after losing etcd quorum
+	NotificationEmergencyDrain  = "emergency_drain"   // Every datacenter was drained via the emergency endpoint
+	NotificationDeactivation    = "deactivation"      // A datacenter or region was drained without activating another
+)
+
+// NotificationEvent represents a failover event sent to external notification channels
+type NotificationEvent struct {
+	Type       string    `json:"type"` // One of the Notification* constants
+	Region     string    `json:"region,omitempty"`
+	Datacenter string    `json:"datacenter,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	Reason     string    `json:"reason"`
+	ErrorCount int       `json:"error_count"`
+}
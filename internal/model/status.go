@@ -4,14 +4,72 @@ import "time"
 
 // ServiceStatus represents the current status of the dc-switcher service
 type ServiceStatus struct {
-	MyDatacenter      string    `json:"my_datacenter"`      // Name of the datacenter this instance manages
-	AmDrained         bool      `json:"am_drained"`         // Whether this instance has drained its nodes
-	EtcdConnected     bool      `json:"etcd_connected"`     // Whether connected to etcd
-	ActiveDatacenter  string    `json:"active_datacenter"`  // Which datacenter is active according to etcd
-	HeartbeatAge      int64     `json:"heartbeat_age"`      // Age of the heartbeat in milliseconds
-	LastHeartbeat     time.Time `json:"last_heartbeat"`     // Last heartbeat time
-	ActivatedAt       time.Time `json:"activated_at"`       // When the active datacenter was activated
-	ActivatedBy       string    `json:"activated_by"`       // Who/what activated the datacenter
-	HeartbeatInterval int64     `json:"heartbeat_interval"` // Heartbeat update interval in milliseconds
-	StaleThreshold    int64     `json:"stale_threshold"`    // Heartbeat stale threshold in milliseconds
+	MyDatacenter      string     `json:"my_datacenter"`      // Name of the datacenter this instance manages
+	AmDrained         bool       `json:"am_drained"`         // Whether this instance has drained its nodes
+	EtcdConnected     bool       `json:"etcd_connected"`     // Whether connected to etcd
+	ActiveDatacenter  string     `json:"active_datacenter"`  // Which datacenter is active according to etcd
+	ActiveDatacenters []string   `json:"active_datacenters"` // Every active datacenter according to etcd (several in region-wide active mode)
+	HeartbeatAge      int64      `json:"heartbeat_age"`      // Age of the heartbeat in milliseconds
+	LastHeartbeat     time.Time  `json:"last_heartbeat"`     // Last heartbeat time
+	ActivatedAt       time.Time  `json:"activated_at"`       // When the active datacenter was activated
+	ActivatedBy       string     `json:"activated_by"`       // Who/what activated the datacenter
+	HeartbeatInterval int64      `json:"heartbeat_interval"` // Heartbeat update interval in milliseconds
+	StaleThreshold    int64      `json:"stale_threshold"`    // Heartbeat stale threshold in milliseconds
+	Cache             CacheStats `json:"cache"`              // Cache effectiveness counters
+}
+
+// HealthCheckState represents the state of the active region health checker
+type HealthCheckState struct {
+	Enabled         bool           `json:"enabled"`          // Whether periodic health checks run
+	Paused          bool           `json:"paused"`           // Maintenance mode: failed checks never drain
+	ActiveRegion    string         `json:"active_region"`    // Monitored region, empty when no region is active
+	Interval        int64          `json:"interval"`         // Check interval without backoff in milliseconds
+	FailedThreshold int            `json:"failed_threshold"` // Consecutive failures that drain the region
+	Failures        map[string]int `json:"failures"`         // Region -> consecutive failed checks
+}
+
+// HealthFailureCounts is the health checker state saved in etcd with health_check.persist_failures
+type HealthFailureCounts struct {
+	ActiveRegion string         `json:"active_region"` // Region monitored when the counters were saved
+	Failures     map[string]int `json:"failures"`      // Region -> consecutive failed checks
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// CacheStats represents cache hit/miss counters
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Items  int    `json:"items"`
+}
+
+// HealthSnapshot represents the readiness of the service and its dependencies
+type HealthSnapshot struct {
+	EtcdConnected   bool            `json:"etcd_connected"`   // Whether etcd is reachable
+	Clusters        map[string]bool `json:"clusters"`         // Cluster name -> whether it has an elected leader
+	HealthyClusters int             `json:"healthy_clusters"` // Number of clusters with an elected leader
+}
+
+// IsReady returns true if etcd is reachable and at least one cluster is healthy
+func (h *HealthSnapshot) IsReady() bool {
+	return h.EtcdConnected && h.HealthyClusters > 0
+}
+
+// EffectiveConfig represents the loaded configuration without secrets and the clusters detected from it
+type EffectiveConfig struct {
+	Config   map[string]any `json:"config"`   // Keyed like the config file, as loaded at startup
+	Clusters []ClusterInfo  `json:"clusters"` // Connected clusters with their detected names and regions, including reloads
+}
+
+// ClusterInfo represents a connected Nomad cluster
+type ClusterInfo struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+// ActiveSummary represents the active datacenter in the minimal form polled by load balancers and DNS automation
+type ActiveSummary struct {
+	Datacenter  string   `json:"datacenter"`
+	Datacenters []string `json:"datacenters"` // Every active datacenter, several in region-wide active mode
+	Region      string   `json:"region"`
+	Healthy     bool     `json:"healthy"` // The cluster has an elected leader and the heartbeat is not stale
 }
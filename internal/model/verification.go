@@ -0,0 +1,37 @@
+package model
+
+// Expected node states reported by an activation verification
+const (
+	ExpectedEligible  = "eligible"  // Nodes are undrained and eligible (the activated datacenter)
+	ExpectedDrained   = "drained"   // Nodes are drained and their allocations have moved away
+	ExpectedUnchanged = "unchanged" // Activation leaves the nodes alone (same-region and disabled datacenters)
+)
+
+// ActivationVerification reports whether the live cluster state matches an activation of Datacenter
+type ActivationVerification struct {
+	Datacenter string                `json:"datacenter"`
+	Exclusive  bool                  `json:"exclusive,omitempty"` // Same-region datacenters are expected to be drained too
+	Converged  bool                  `json:"converged"`           // Every cluster is in its expected state
+	Clusters   []ClusterVerification `json:"clusters"`
+}
+
+// ClusterVerification compares the expected and actual state of a cluster's nodes
+type ClusterVerification struct {
+	Cluster           string         `json:"cluster"`
+	Region            string         `json:"region"`
+	Expected          string         `json:"expected"` // eligible | drained | unchanged
+	Converged         bool           `json:"converged"`
+	NodesTotal        int            `json:"nodes_total"`
+	NodesMatching     int            `json:"nodes_matching"`             // Nodes already in the expected state
+	MismatchedNodes   []NodeMismatch `json:"mismatched_nodes,omitempty"` // Nodes not in the expected state
+	ActiveAllocations int            `json:"active_allocations"`         // Pending or running allocations on the cluster's nodes
+	Error             string         `json:"error,omitempty"`            // Set when the cluster state couldn't be read
+}
+
+// NodeMismatch describes a node that is not in its expected state
+type NodeMismatch struct {
+	NodeID   string    `json:"node_id"`
+	NodeName string    `json:"node_name"`
+	Expected NodeState `json:"expected"`
+	Actual   NodeState `json:"actual"`
+}
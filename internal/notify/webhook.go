@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// Notifier sends failover event notifications
+type Notifier interface {
+	// Notify sends the event in the background and never blocks the caller
+	Notify(event model.NotificationEvent)
+}
+
+// webhookNotifier posts events as JSON to a generic webhook
+type webhookNotifier struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// nopNotifier discards all events
+type nopNotifier struct{}
+
+// NewWebhookNotifier creates a notifier posting to url, or a no-op notifier if url is empty
+func NewWebhookNotifier(url string, timeout time.Duration, logger *slog.Logger) Notifier {
+	if url == "" {
+		return nopNotifier{}
+	}
+
+	return &webhookNotifier{
+		url:     url,
+		client:  &http.Client{Timeout: timeout},
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Notify posts the event to the webhook in a background goroutine
+func (n *webhookNotifier) Notify(event model.NotificationEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+		defer cancel()
+
+		if err := n.send(ctx, event); err != nil {
+			n.logger.Warn("failed to send webhook notification",
+				slog.String("event_type", event.Type),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		n.logger.Debug("sent webhook notification",
+			slog.String("event_type", event.Type),
+		)
+	}()
+}
+
+// send posts a single event to the webhook
+func (n *webhookNotifier) send(ctx context.Context, event model.NotificationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Notify discards the event
+func (nopNotifier) Notify(model.NotificationEvent) {}
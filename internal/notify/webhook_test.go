@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// webhookServer serves the webhook with status after delay, forwarding every decoded payload
+func webhookServer(t *testing.T, status int, delay time.Duration) (string, <-chan model.NotificationEvent) {
+	t.Helper()
+
+	received := make(chan model.NotificationEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		var event model.NotificationEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		received <- event
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, received
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		event  model.NotificationEvent
+		status int
+		delay  time.Duration
+	}{
+		{
+			name:   "activation",
+			event:  model.NotificationEvent{Type: model.NotificationActivation, Region: "eu", Datacenter: "dc1", Timestamp: timestamp, Reason: "datacenter activated via API"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "automatic drain with errors",
+			event:  model.NotificationEvent{Type: model.NotificationAutoDrain, Region: "us", Timestamp: timestamp, Reason: "active region failed 3 consecutive health checks", ErrorCount: 2},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "rejected by the webhook",
+			event:  model.NotificationEvent{Type: model.NotificationQuorumLossDrain, Datacenter: "dc1", Timestamp: timestamp},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "slow webhook",
+			event:  model.NotificationEvent{Type: model.NotificationActivation, Region: "eu", Timestamp: timestamp},
+			status: http.StatusOK,
+			delay:  time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, received := webhookServer(t, tt.status, tt.delay)
+			notifier := NewWebhookNotifier(url, 100*time.Millisecond, slog.New(slog.DiscardHandler))
+
+			start := time.Now()
+			notifier.Notify(tt.event)
+			if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+				t.Errorf("Notify() blocked for %v", elapsed)
+			}
+
+			select {
+			case got := <-received:
+				if !got.Timestamp.Equal(tt.event.Timestamp) {
+					t.Errorf("timestamp = %v, want %v", got.Timestamp, tt.event.Timestamp)
+				}
+				got.Timestamp = tt.event.Timestamp
+				if got != tt.event {
+					t.Errorf("payload = %+v, want %+v", got, tt.event)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("webhook not called")
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierFillsTimestamp(t *testing.T) {
+	url, received := webhookServer(t, http.StatusOK, 0)
+	notifier := NewWebhookNotifier(url, time.Second, slog.New(slog.DiscardHandler))
+
+	before := time.Now()
+	notifier.Notify(model.NotificationEvent{Type: model.NotificationActivation})
+
+	select {
+	case got := <-received:
+		if got.Timestamp.Before(before.Truncate(time.Second)) {
+			t.Errorf("timestamp = %v, want the send time", got.Timestamp)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestNewWebhookNotifierDisabled(t *testing.T) {
+	notifier := NewWebhookNotifier("", time.Second, slog.New(slog.DiscardHandler))
+	if _, ok := notifier.(nopNotifier); !ok {
+		t.Fatalf("NewWebhookNotifier(\"\") = %T, want a no-op notifier", notifier)
+	}
+	notifier.Notify(model.NotificationEvent{Type: model.NotificationActivation})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestTryClaimActiveDatacenter(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    string // Active datacenter written before the claim, "" for none
+		staleRev    bool   // Claim with the revision before the last write
+		useRevision bool   // Claim with the current revision instead of 0
+		wantClaimed bool
+		wantHolder  string
+		wantActive  string
+	}{
+		{name: "missing key", wantClaimed: true, wantActive: "dc1"},
+		{name: "existing key with revision 0", existing: "dc2", wantHolder: "dc2", wantActive: "dc2"},
+		{name: "current revision", existing: "dc2", useRevision: true, wantClaimed: true, wantActive: "dc1"},
+		{name: "stale revision", existing: "dc2", useRevision: true, staleRev: true, wantHolder: "dc2", wantActive: "dc2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newFakeEtcdClient(t)
+
+			var rev int64
+			if tt.existing != "" {
+				writeActive(t, client, tt.existing)
+				current, err := client.ReadActiveDatacenter(context.Background())
+				if err != nil {
+					t.Fatalf("ReadActiveDatacenter() error = %v", err)
+				}
+				if tt.useRevision {
+					rev = current.Revision
+				}
+				if tt.staleRev {
+					// Another instance refreshed the key after it was read
+					writeActive(t, client, tt.existing)
+				}
+			}
+
+			claimed, holder, err := client.TryClaimActiveDatacenter(context.Background(), &model.ActiveDatacenter{Datacenter: "dc1"}, rev)
+			if err != nil {
+				t.Fatalf("TryClaimActiveDatacenter() error = %v", err)
+			}
+			if claimed != tt.wantClaimed {
+				t.Errorf("claimed = %v, want %v", claimed, tt.wantClaimed)
+			}
+			holderDC := ""
+			if holder != nil {
+				holderDC = holder.Datacenter
+				if holder.Revision == 0 {
+					t.Error("holder has no revision")
+				}
+			}
+			if holderDC != tt.wantHolder {
+				t.Errorf("holder = %q, want %q", holderDC, tt.wantHolder)
+			}
+
+			current, err := client.ReadActiveDatacenter(context.Background())
+			if err != nil {
+				t.Fatalf("ReadActiveDatacenter() error = %v", err)
+			}
+			if current.Datacenter != tt.wantActive {
+				t.Errorf("active datacenter = %q, want %q", current.Datacenter, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestTryClaimActiveDatacenterConcurrent(t *testing.T) {
+	tests := []struct {
+		name     string
+		leaseTTL time.Duration
+	}{
+		{name: "without lease"},
+		{name: "with lease", leaseTTL: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, fake := newFakeEtcdClient(t)
+			datacenters := []string{"dc1", "dc2", "dc3", "dc4", "dc5"}
+
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				winners []string
+				holders = make(map[string]string) // loser -> holder reported
+			)
+			start := make(chan struct{})
+			for _, dc := range datacenters {
+				// Every instance has its own client and lease, like separate processes
+				client := fake.newClient(t)
+				client.leaseTTL = tt.leaseTTL
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+
+					claimed, holder, err := client.TryClaimActiveDatacenter(context.Background(), &model.ActiveDatacenter{Datacenter: dc}, 0)
+					if err != nil {
+						t.Errorf("%s: TryClaimActiveDatacenter() error = %v", dc, err)
+						return
+					}
+
+					mu.Lock()
+					defer mu.Unlock()
+					if claimed {
+						winners = append(winners, dc)
+					} else if holder != nil {
+						holders[dc] = holder.Datacenter
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if len(winners) != 1 {
+				t.Fatalf("winners = %v, want exactly one", winners)
+			}
+			for loser, holder := range holders {
+				if holder != winners[0] {
+					t.Errorf("%s was told %s holds the key, want %s", loser, holder, winners[0])
+				}
+			}
+			if len(holders) != len(datacenters)-1 {
+				t.Errorf("%d losers learned the holder, want %d", len(holders), len(datacenters)-1)
+			}
+
+			current, err := fake.newClient(t).ReadActiveDatacenter(context.Background())
+			if err != nil {
+				t.Fatalf("ReadActiveDatacenter() error = %v", err)
+			}
+			if current.Datacenter != winners[0] {
+				t.Errorf("active datacenter = %q, want the winner %q", current.Datacenter, winners[0])
+			}
+		})
+	}
+}
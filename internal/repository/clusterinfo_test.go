@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestConfigString(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]any
+		want string
+	}{
+		{name: "top level", cfg: map[string]any{"Datacenter": "dc1"}, want: "dc1"},
+		{name: "client section", cfg: map[string]any{"Client": map[string]any{"Datacenter": "dc1"}}, want: "dc1"},
+		{name: "server section", cfg: map[string]any{"Server": map[string]any{"Datacenter": "dc1"}}, want: "dc1"},
+		{
+			name: "top level wins",
+			cfg:  map[string]any{"Datacenter": "dc1", "Client": map[string]any{"Datacenter": "dc2"}},
+			want: "dc1",
+		},
+		{
+			name: "client wins over server",
+			cfg:  map[string]any{"Client": map[string]any{"Datacenter": "dc1"}, "Server": map[string]any{"Datacenter": "dc2"}},
+			want: "dc1",
+		},
+		{
+			name: "empty top level falls through",
+			cfg:  map[string]any{"Datacenter": "", "Server": map[string]any{"Datacenter": "dc2"}},
+			want: "dc2",
+		},
+		{name: "not a string", cfg: map[string]any{"Datacenter": 1}},
+		{name: "section not an object", cfg: map[string]any{"Client": "dc1"}},
+		{name: "missing", cfg: map[string]any{"Region": "eu"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configString(tt.cfg, "Datacenter"); got != tt.want {
+				t.Errorf("configString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfoFromConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            map[string]any
+		wantDatacenter string
+		wantRegion     string
+		wantErr        string
+	}{
+		{name: "top level", cfg: map[string]any{"Datacenter": "dc1", "Region": "eu"}, wantDatacenter: "dc1", wantRegion: "eu"},
+		{
+			name:           "nested sections",
+			cfg:            map[string]any{"Client": map[string]any{"Datacenter": "dc1"}, "Server": map[string]any{"Region": "eu"}},
+			wantDatacenter: "dc1",
+			wantRegion:     "eu",
+		},
+		{name: "default region", cfg: map[string]any{"Datacenter": "dc1"}, wantDatacenter: "dc1", wantRegion: "global"},
+		{name: "no datacenter", cfg: map[string]any{"Region": "eu"}, wantErr: "datacenter not found in config"},
+		{name: "no config", wantErr: "config section not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			datacenter, region, err := clusterInfoFromConfig(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("clusterInfoFromConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("clusterInfoFromConfig() error = %v", err)
+			}
+			if datacenter != tt.wantDatacenter || region != tt.wantRegion {
+				t.Errorf("clusterInfoFromConfig() = %q, %q, want %q, %q", datacenter, region, tt.wantDatacenter, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestDetectClusterInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		self           fakeResponse
+		nodes          fakeResponse
+		wantDatacenter string
+		wantRegion     string
+		wantErr        []string // Substrings of the error, one per failed source
+	}{
+		{
+			name:           "agent config",
+			self:           fakeResponse{body: nomad.AgentSelf{Config: map[string]any{"Datacenter": "dc1", "Region": "eu"}}},
+			wantDatacenter: "dc1",
+			wantRegion:     "eu",
+		},
+		{
+			name:           "server member tags",
+			self:           fakeResponse{body: nomad.AgentSelf{Config: map[string]any{}, Member: nomad.AgentMember{Tags: map[string]string{"dc": "dc1", "region": "eu"}}}},
+			wantDatacenter: "dc1",
+			wantRegion:     "eu",
+		},
+		{
+			name:           "node list",
+			self:           fakeResponse{body: nomad.AgentSelf{Config: map[string]any{"Region": "eu"}}},
+			nodes:          fakeResponse{body: []nomad.NodeListStub{{ID: "n0"}, {ID: "n1", Datacenter: "dc1"}}},
+			wantDatacenter: "dc1",
+			wantRegion:     "eu",
+		},
+		{
+			name:  "nothing reports a datacenter",
+			self:  fakeResponse{body: nomad.AgentSelf{Config: map[string]any{}}},
+			nodes: fakeResponse{body: []nomad.NodeListStub{{ID: "n1"}}},
+			wantErr: []string{
+				"agent self config: datacenter not found",
+				"agent member tags: datacenter not found",
+				"node list: no node reports a datacenter",
+			},
+		},
+		{
+			name:    "agent unreachable",
+			self:    fakeResponse{status: http.StatusInternalServerError},
+			nodes:   fakeResponse{status: http.StatusInternalServerError},
+			wantErr: []string{"failed to query agent self", "agent datacenter", "failed to list nodes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, srv := newFakeNomad(t, map[string]fakeResponse{
+				"GET /v1/agent/self": tt.self,
+				"GET /v1/nodes":      tt.nodes,
+			})
+			client, _, err := createNomadClient(config.ClusterConfig{Address: srv.URL})
+			if err != nil {
+				t.Fatalf("createNomadClient: %v", err)
+			}
+
+			datacenter, region, err := detectClusterInfo(client, slog.New(slog.DiscardHandler))
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("detectClusterInfo() = %q, %q, want an error", datacenter, region)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q doesn't contain %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectClusterInfo() error = %v", err)
+			}
+			if datacenter != tt.wantDatacenter || region != tt.wantRegion {
+				t.Errorf("detectClusterInfo() = %q, %q, want %q, %q", datacenter, region, tt.wantDatacenter, tt.wantRegion)
+			}
+		})
+	}
+}
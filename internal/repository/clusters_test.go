@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+// newClusterNomad returns a fake Nomad agent of datacenter in region; an unhealthy one has no leader
+func newClusterNomad(t *testing.T, datacenter, region string, healthy bool) *httptest.Server {
+	t.Helper()
+
+	leader := fakeResponse{body: "10.0.0.1:4647"}
+	if !healthy {
+		leader = fakeResponse{status: http.StatusInternalServerError}
+	}
+	_, srv := newFakeNomad(t, map[string]fakeResponse{
+		"GET /v1/status/leader": leader,
+		"GET /v1/agent/health":  {body: nomad.AgentHealthResponse{Server: &nomad.AgentHealth{Ok: true}}},
+		"GET /v1/agent/self":    {body: nomad.AgentSelf{Config: map[string]any{"Datacenter": datacenter, "Region": region}}},
+	})
+	return srv
+}
+
+// clusterAddresses returns the address of every cluster by key
+func clusterAddresses(r *nomadRepository) map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	addresses := make(map[string]string, len(r.clusters))
+	for key, meta := range r.clusters {
+		addresses[key] = meta.address
+	}
+	return addresses
+}
+
+func TestReloadClusters(t *testing.T) {
+	tests := []struct {
+		name string
+		// The repository starts with dc1 in eu at the "current" server; the reloaded config lists
+		// clusters by server name, with the name and region set as given
+		reload          []config.ClusterConfig
+		servers         map[string]bool // Server name -> healthy; "current" is always present
+		want            map[string]string
+		wantResult      ClusterReloadResult
+		wantUnavailable []string
+		wantNewClient   bool // dc1 got a client rebuilt from the new config
+	}{
+		{
+			name:          "unchanged cluster gets a new client",
+			reload:        []config.ClusterConfig{{Name: "dc1", Region: "eu", Address: "current"}},
+			servers:       map[string]bool{"current": true},
+			want:          map[string]string{"dc1": "current"},
+			wantResult:    ClusterReloadResult{Updated: []string{"dc1"}},
+			wantNewClient: true,
+		},
+		{
+			name: "new cluster is added under its detected name",
+			reload: []config.ClusterConfig{
+				{Name: "dc1", Region: "eu", Address: "current"},
+				{Address: "new"},
+			},
+			servers:       map[string]bool{"current": true, "new": true},
+			want:          map[string]string{"dc1": "current", "dc2": "new"},
+			wantResult:    ClusterReloadResult{Added: []string{"dc2"}, Updated: []string{"dc1"}},
+			wantNewClient: true,
+		},
+		{
+			name:       "cluster missing from the config is removed",
+			reload:     []config.ClusterConfig{{Address: "new"}},
+			servers:    map[string]bool{"current": true, "new": true},
+			want:       map[string]string{"dc2": "new"},
+			wantResult: ClusterReloadResult{Added: []string{"dc2"}, Removed: []string{"dc1"}},
+		},
+		{
+			name:       "cluster moved to a new address takes its key over",
+			reload:     []config.ClusterConfig{{Name: "dc1", Region: "eu", Address: "new"}},
+			servers:    map[string]bool{"current": true, "new": true},
+			want:       map[string]string{"dc1": "new"},
+			wantResult: ClusterReloadResult{Added: []string{"dc1"}},
+		},
+		{
+			name: "name of a kept cluster isn't taken over",
+			reload: []config.ClusterConfig{
+				{Name: "dc1", Region: "eu", Address: "current"},
+				{Name: "dc1", Region: "us", Address: "new"},
+			},
+			servers:       map[string]bool{"current": true, "new": true},
+			want:          map[string]string{"dc1": "current", "dc1-us": "new"},
+			wantResult:    ClusterReloadResult{Added: []string{"dc1-us"}, Updated: []string{"dc1"}},
+			wantNewClient: true,
+		},
+		{
+			name:       "unreachable cluster keeps its current client",
+			reload:     []config.ClusterConfig{{Name: "dc1", Region: "eu", Address: "current"}},
+			servers:    map[string]bool{"current": false},
+			want:       map[string]string{"dc1": "current"},
+			wantResult: ClusterReloadResult{Failed: []string{"current"}},
+		},
+		{
+			name: "unreachable new cluster is retried later",
+			reload: []config.ClusterConfig{
+				{Name: "dc1", Region: "eu", Address: "current"},
+				{Address: "new"},
+			},
+			servers:         map[string]bool{"current": true, "new": false},
+			want:            map[string]string{"dc1": "current"},
+			wantResult:      ClusterReloadResult{Updated: []string{"dc1"}, Failed: []string{"new"}},
+			wantUnavailable: []string{"new"},
+			wantNewClient:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Server names stand for their URLs until the fake agents are started
+			servers := make(map[string]*httptest.Server, len(tt.servers))
+			urls := make(map[string]string, len(tt.servers))
+			names := make(map[string]string, len(tt.servers))
+			datacenters := map[string]string{"current": "dc1", "new": "dc2"}
+			for name, healthy := range tt.servers {
+				servers[name] = newClusterNomad(t, datacenters[name], "eu", healthy)
+				urls[name] = servers[name].URL
+				names[servers[name].URL] = name
+			}
+
+			repo := newTestNomadRepository(t, servers["current"])
+			oldClient := repo.clusters["dc1"].client
+
+			cfg := &config.Config{}
+			for _, cluster := range tt.reload {
+				cluster.Address = urls[cluster.Address]
+				cfg.Clusters = append(cfg.Clusters, cluster)
+			}
+
+			result := repo.ReloadClusters(cfg)
+
+			for i, address := range result.Failed {
+				result.Failed[i] = names[address]
+			}
+			if !equalReloadResults(result, tt.wantResult) {
+				t.Errorf("result = %+v, want %+v", result, tt.wantResult)
+			}
+
+			got := make(map[string]string)
+			for key, address := range clusterAddresses(repo) {
+				got[key] = names[address]
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("clusters = %v, want %v", got, tt.want)
+			}
+
+			var unavailable []string
+			for _, cluster := range repo.unavailableClusters {
+				unavailable = append(unavailable, names[cluster.Address])
+			}
+			if !slices.Equal(unavailable, tt.wantUnavailable) {
+				t.Errorf("unavailable clusters = %v, want %v", unavailable, tt.wantUnavailable)
+			}
+
+			if meta, ok := repo.cluster("dc1"); ok && names[meta.address] == "current" {
+				if newClient := meta.client != oldClient; newClient != tt.wantNewClient {
+					t.Errorf("dc1 got a new client %v, want %v", newClient, tt.wantNewClient)
+				}
+			}
+		})
+	}
+}
+
+// equalReloadResults compares reload results, treating nil and empty lists alike
+func equalReloadResults(a, b ClusterReloadResult) bool {
+	return slices.Equal(a.Added, b.Added) && slices.Equal(a.Updated, b.Updated) &&
+		slices.Equal(a.Removed, b.Removed) && slices.Equal(a.Failed, b.Failed)
+}
+
+func TestRetryUnavailableClusters(t *testing.T) {
+	tests := []struct {
+		name            string
+		unavailable     []config.ClusterConfig // Addresses are server names
+		servers         map[string]bool        // Server name -> healthy; "current" serves dc1 and is always present
+		wantAdded       int
+		want            map[string]string
+		wantUnavailable []string
+	}{
+		{
+			name:    "nothing to retry",
+			servers: map[string]bool{"current": true},
+			want:    map[string]string{"dc1": "current"},
+		},
+		{
+			name:        "recovered cluster is added under its detected name",
+			unavailable: []config.ClusterConfig{{Address: "new"}},
+			servers:     map[string]bool{"current": true, "new": true},
+			wantAdded:   1,
+			want:        map[string]string{"dc1": "current", "dc2": "new"},
+		},
+		{
+			name:            "unhealthy cluster stays unavailable",
+			unavailable:     []config.ClusterConfig{{Address: "new"}},
+			servers:         map[string]bool{"current": true, "new": false},
+			want:            map[string]string{"dc1": "current"},
+			wantUnavailable: []string{"new"},
+		},
+		{
+			name:        "taken name gets the region appended",
+			unavailable: []config.ClusterConfig{{Name: "dc1", Region: "us", Address: "new"}},
+			servers:     map[string]bool{"current": true, "new": true},
+			wantAdded:   1,
+			want:        map[string]string{"dc1": "current", "dc1-us": "new"},
+		},
+		{
+			name: "only recovered clusters leave the retry list",
+			unavailable: []config.ClusterConfig{
+				{Address: "down"},
+				{Address: "new"},
+			},
+			servers:         map[string]bool{"current": true, "new": true, "down": false},
+			wantAdded:       1,
+			want:            map[string]string{"dc1": "current", "dc2": "new"},
+			wantUnavailable: []string{"down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := make(map[string]string, len(tt.servers))
+			names := make(map[string]string, len(tt.servers))
+			datacenters := map[string]string{"current": "dc1", "new": "dc2", "down": "dc3"}
+			var current *httptest.Server
+			for name, healthy := range tt.servers {
+				srv := newClusterNomad(t, datacenters[name], "eu", healthy)
+				if name == "current" {
+					current = srv
+				}
+				urls[name] = srv.URL
+				names[srv.URL] = name
+			}
+
+			repo := newTestNomadRepository(t, current)
+			for _, cluster := range tt.unavailable {
+				cluster.Address = urls[cluster.Address]
+				repo.unavailableClusters = append(repo.unavailableClusters, cluster)
+			}
+
+			if added := repo.RetryUnavailableClusters(); added != tt.wantAdded {
+				t.Errorf("RetryUnavailableClusters() = %d, want %d", added, tt.wantAdded)
+			}
+
+			got := make(map[string]string)
+			for key, address := range clusterAddresses(repo) {
+				got[key] = names[address]
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("clusters = %v, want %v", got, tt.want)
+			}
+
+			var unavailable []string
+			for _, cluster := range repo.unavailableClusters {
+				unavailable = append(unavailable, names[cluster.Address])
+			}
+			if !slices.Equal(unavailable, tt.wantUnavailable) {
+				t.Errorf("unavailable clusters = %v, want %v", unavailable, tt.wantUnavailable)
+			}
+		})
+	}
+}
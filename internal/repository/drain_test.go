@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestBuildDrainSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		drain bool
+		opts  model.DrainOptions
+		want  *nomad.DrainSpec
+	}{
+		{name: "undrain", drain: false, opts: model.DrainOptions{Deadline: time.Hour}, want: nil},
+		{name: "infinite", drain: true, opts: model.DrainOptions{Deadline: -1}, want: &nomad.DrainSpec{Deadline: 0}},
+		{name: "any negative deadline is infinite", drain: true, opts: model.DrainOptions{Deadline: -time.Minute}, want: &nomad.DrainSpec{Deadline: 0}},
+		{name: "immediate", drain: true, opts: model.DrainOptions{Deadline: 0}, want: &nomad.DrainSpec{Deadline: -1}},
+		{name: "deadline", drain: true, opts: model.DrainOptions{Deadline: 10 * time.Minute}, want: &nomad.DrainSpec{Deadline: 10 * time.Minute}},
+		{
+			name:  "ignore system jobs",
+			drain: true,
+			opts:  model.DrainOptions{Deadline: time.Minute, IgnoreSystemJobs: true},
+			want:  &nomad.DrainSpec{Deadline: time.Minute, IgnoreSystemJobs: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildDrainSpec(tt.drain, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildDrainSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// drainRequest is the part of a drain request body checked by the tests
+type drainRequest struct {
+	DrainSpec    *nomad.DrainSpec
+	MarkEligible bool
+}
+
+func TestSetNodeDrainPayload(t *testing.T) {
+	tests := []struct {
+		name         string
+		serverStatus int // Status of the Server API; a failure goes through the direct Client API
+		drain        bool
+		opts         model.DrainOptions
+		want         drainRequest
+	}{
+		{
+			name:  "server API deadline in nanoseconds",
+			drain: true,
+			opts:  model.DrainOptions{Deadline: 90 * time.Second},
+			want:  drainRequest{DrainSpec: &nomad.DrainSpec{Deadline: 90 * time.Second}},
+		},
+		{
+			name:  "server API immediate",
+			drain: true,
+			opts:  model.DrainOptions{Deadline: 0, IgnoreSystemJobs: true},
+			want:  drainRequest{DrainSpec: &nomad.DrainSpec{Deadline: -1, IgnoreSystemJobs: true}},
+		},
+		{
+			name:  "server API undrain",
+			drain: false,
+			want:  drainRequest{MarkEligible: true},
+		},
+		{
+			name:         "client API fallback deadline",
+			serverStatus: http.StatusInternalServerError,
+			drain:        true,
+			opts:         model.DrainOptions{Deadline: 5 * time.Minute},
+			want:         drainRequest{DrainSpec: &nomad.DrainSpec{Deadline: 5 * time.Minute}},
+		},
+		{
+			name:         "client API fallback infinite",
+			serverStatus: http.StatusInternalServerError,
+			drain:        true,
+			opts:         model.DrainOptions{Deadline: -1},
+			want:         drainRequest{DrainSpec: &nomad.DrainSpec{Deadline: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, srv := newFakeNomad(t, map[string]fakeResponse{
+				"PUT /v1/node/n1/drain": {status: tt.serverStatus, body: nomad.NodeDrainUpdateResponse{}},
+			})
+			client, clientSrv := newFakeNomad(t, map[string]fakeResponse{
+				"POST /v1/node/self/drain": {body: map[string]any{}},
+			})
+
+			repo := newTestNomadRepository(t, srv)
+			repo.nomadCfg.EnableDirectFallback = true
+			repo.nomadCfg.DirectFallbackTimeout = time.Second
+			repo.clusters["dc1"].setNode("n1", &nodeCache{HTTPAddr: strings.TrimPrefix(clientSrv.URL, "http://"), Name: "n1"})
+
+			if err := repo.SetNodeDrain(context.Background(), "dc1", "n1", tt.drain, tt.opts); err != nil {
+				t.Fatalf("SetNodeDrain() error = %v", err)
+			}
+
+			// The request that was applied is the last one: the client's after a server failure
+			applied := server
+			if tt.serverStatus != 0 {
+				applied = client
+			}
+			applied.mu.Lock()
+			bodies := applied.bodies
+			applied.mu.Unlock()
+			if len(bodies) == 0 {
+				t.Fatal("no drain request was sent")
+			}
+			var got drainRequest
+			if err := json.Unmarshal([]byte(bodies[len(bodies)-1]), &got); err != nil {
+				t.Fatalf("decode drain request %q: %v", bodies[len(bodies)-1], err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("drain request = %+v (spec %+v), want %+v (spec %+v)", got, got.DrainSpec, tt.want, tt.want.DrainSpec)
+			}
+			if tt.serverStatus == 0 && len(client.calls()) != 0 {
+				t.Error("direct Client API used although the Server API succeeded")
+			}
+		})
+	}
+}
+
+// Deadlines travel as integer nanoseconds, the unit Nomad expects
+func TestDrainSpecDeadlineEncoding(t *testing.T) {
+	data, err := json.Marshal(buildDrainSpec(true, model.DrainOptions{Deadline: 2 * time.Second}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"Deadline":2000000000`) {
+		t.Errorf("drain spec = %s, want Deadline 2000000000", data)
+	}
+}
+
+func TestSetNodeDrainRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxRetries   int
+		wantErr      bool
+		wantAttempts int
+		minElapsed   time.Duration // Backoff doubles from 10ms between attempts
+	}{
+		{name: "first attempt succeeds", failures: 0, maxRetries: 2, wantAttempts: 1},
+		{name: "succeeds after one failure", failures: 1, maxRetries: 2, wantAttempts: 2, minElapsed: 10 * time.Millisecond},
+		{name: "succeeds on the last retry", failures: 2, maxRetries: 2, wantAttempts: 3, minElapsed: 30 * time.Millisecond},
+		{name: "retries exhausted", failures: 3, maxRetries: 2, wantErr: true, wantAttempts: 3},
+		{name: "retries disabled", failures: 1, maxRetries: 0, wantErr: true, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, srv := newFakeNomad(t, map[string]fakeResponse{
+				"PUT /v1/node/n1/drain": {body: nomad.NodeDrainUpdateResponse{}},
+			})
+			server.failures = map[string]int{"PUT /v1/node/n1/drain": tt.failures}
+
+			repo := newTestNomadRepository(t, srv)
+			repo.retryCfg.MaxRetries = tt.maxRetries
+			repo.retryCfg.BaseBackoff = 10 * time.Millisecond
+			repo.retryCfg.MaxBackoff = time.Second
+
+			start := time.Now()
+			err := repo.SetNodeDrain(context.Background(), "dc1", "n1", true, model.DrainOptions{Deadline: -1})
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetNodeDrain() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := len(server.calls()); got != tt.wantAttempts {
+				t.Errorf("%d attempts, want %d", got, tt.wantAttempts)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("took %v, want at least %v of backoff", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestSetNodeDrainRetryHonoursContext(t *testing.T) {
+	server, srv := newFakeNomad(t, map[string]fakeResponse{
+		"PUT /v1/node/n1/drain": {body: nomad.NodeDrainUpdateResponse{}},
+	})
+	server.failures = map[string]int{"PUT /v1/node/n1/drain": 5}
+
+	repo := newTestNomadRepository(t, srv)
+	repo.retryCfg.MaxRetries = 5
+	repo.retryCfg.BaseBackoff = time.Hour
+	repo.nomadCfg.EnableDirectFallback = true // Not used once the context is done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := repo.SetNodeDrain(ctx, "dc1", "n1", true, model.DrainOptions{Deadline: -1}); err == nil {
+		t.Fatal("SetNodeDrain() error = nil, want the failed attempt")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("backoff ignored the cancelled context, took %v", elapsed)
+	}
+	if got := server.calls(); len(got) != 1 {
+		t.Errorf("calls = %v, want a single attempt", got)
+	}
+}
+
+func TestListNodeAllocations(t *testing.T) {
+	serviceType, systemType := "service", "system"
+	tests := []struct {
+		name      string
+		responses map[string]fakeResponse
+		want      []model.Allocation
+		wantErr   bool
+	}{
+		{
+			name: "allocations with job type",
+			responses: map[string]fakeResponse{"GET /v1/node/n1/allocations": {body: []nomad.Allocation{
+				{ID: "a1", JobID: "api", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running", Job: &nomad.Job{Type: &serviceType}},
+				{ID: "a2", JobID: "agent", TaskGroup: "agent", DesiredStatus: "run", ClientStatus: "running", Job: &nomad.Job{Type: &systemType}},
+				{ID: "a3", JobID: "old", DesiredStatus: "stop", ClientStatus: "complete"},
+			}}},
+			want: []model.Allocation{
+				{ID: "a1", JobID: "api", JobType: "service", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+				{ID: "a2", JobID: "agent", JobType: "system", TaskGroup: "agent", DesiredStatus: "run", ClientStatus: "running"},
+				{ID: "a3", JobID: "old", DesiredStatus: "stop", ClientStatus: "complete"},
+			},
+		},
+		{
+			name:      "empty node",
+			responses: map[string]fakeResponse{"GET /v1/node/n1/allocations": {body: []nomad.Allocation{}}},
+			want:      []model.Allocation{},
+		},
+		{
+			name:      "api error",
+			responses: map[string]fakeResponse{"GET /v1/node/n1/allocations": {status: http.StatusInternalServerError}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, srv := newFakeNomad(t, tt.responses)
+			repo := newTestNomadRepository(t, srv)
+
+			got, err := repo.ListNodeAllocations(context.Background(), "dc1", "n1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListNodeAllocations() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListNodeAllocations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
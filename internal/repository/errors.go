@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	nomad "github.com/hashicorp/nomad/api"
+)
+
+var (
+	// ErrClusterNotFound is returned when no cluster is configured under the requested name
+	ErrClusterNotFound = errors.New("cluster not found")
+
+	// ErrRegionNotFound is returned when no cluster belongs to the requested region
+	ErrRegionNotFound = errors.New("region not found")
+
+	// ErrJobNotFound is returned when a job does not exist in the requested cluster
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrNomadUnavailable is returned when a Nomad cluster cannot be reached or fails to serve a request
+	ErrNomadUnavailable = errors.New("nomad unavailable")
+)
+
+// clusterNotFound returns ErrClusterNotFound annotated with the cluster name
+func clusterNotFound(clusterName string) error {
+	return fmt.Errorf("%w: %s", ErrClusterNotFound, clusterName)
+}
+
+// nomadError wraps a failed Nomad API call. Connection failures and 5xx responses are
+// marked with ErrNomadUnavailable; other responses (e.g. 403 or 404) are wrapped as is.
+func nomadError(action string, err error) error {
+	var resp nomad.UnexpectedResponseError
+	if errors.As(err, &resp) && resp.HasStatusCode() && resp.StatusCode() < http.StatusInternalServerError {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return fmt.Errorf("%s: %w: %w", action, ErrNomadUnavailable, err)
+}
+
+// jobInfoError wraps a failed job lookup; a 404 response is reported as ErrJobNotFound
+func jobInfoError(jobID string, err error) error {
+	var resp nomad.UnexpectedResponseError
+	if errors.As(err, &resp) && resp.HasStatusCode() && resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	}
+	return nomadError("failed to get job info", err)
+}
@@ -3,8 +3,13 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
@@ -14,9 +19,25 @@ import (
 )
 
 const (
-	// etcd key prefixes
-	keyActiveDatacenter = "dc-switcher/active-datacenter"
-	keyHeartbeatPrefix  = "dc-switcher/heartbeats/"
+	// etcd key names, relative to etcd.key_prefix
+	keyActiveDatacenter = "active-datacenter"
+	keyHeartbeatPrefix  = "heartbeats/"
+	keyHistoryPrefix    = "history/"
+	keyHealthFailures   = "healthcheck/failures/"
+
+	// watchRetryDelay is the pause before re-establishing a closed watch
+	watchRetryDelay = time.Second
+)
+
+var (
+	// ErrLeaseLost is returned when the lease attached to the active datacenter key has expired or was revoked
+	ErrLeaseLost = errors.New("active datacenter lease lost")
+
+	// ErrNoActiveDatacenter is returned when no active datacenter is recorded in etcd
+	ErrNoActiveDatacenter = errors.New("no active datacenter found in etcd")
+
+	// ErrEtcdTimeout is returned when an etcd request doesn't complete within the operation timeout
+	ErrEtcdTimeout = errors.New("etcd operation timed out")
 )
 
 // EtcdRepository defines the interface for etcd operations
@@ -27,24 +48,102 @@ type EtcdRepository interface {
 	// ReadActiveDatacenter reads the active datacenter information from etcd
 	ReadActiveDatacenter(ctx context.Context) (*model.ActiveDatacenter, error)
 
+	// TryClaimActiveDatacenter atomically writes info only if the key is still at expectedRevision
+	// (0 means the key must not exist). On failure it returns the current holder.
+	TryClaimActiveDatacenter(ctx context.Context, info *model.ActiveDatacenter, expectedRevision int64) (bool, *model.ActiveDatacenter, error)
+
+	// WatchActiveDatacenter streams active datacenter updates until ctx is cancelled.
+	// A nil update means the key was deleted or expired with its lease.
+	WatchActiveDatacenter(ctx context.Context) (<-chan *model.ActiveDatacenter, error)
+
+	// DeleteActiveDatacenter removes the active datacenter key, leaving no datacenter active.
+	// Deleting a key that doesn't exist succeeds.
+	DeleteActiveDatacenter(ctx context.Context) error
+
+	// TryDeleteActiveDatacenter atomically removes the active datacenter key only if it is still
+	// at expectedRevision. It reports whether the key was deleted.
+	TryDeleteActiveDatacenter(ctx context.Context, expectedRevision int64) (bool, error)
+
+	// RenewActiveDatacenterLease keeps the active datacenter key lease alive.
+	// Returns ErrLeaseLost if the lease has expired; the next write grants a new one.
+	RenewActiveDatacenterLease(ctx context.Context) error
+
+	// DetachActiveDatacenterLease stops attaching the lease to later active datacenter writes,
+	// so a record written while shutting down outlives this instance
+	DetachActiveDatacenterLease()
+
 	// WriteHeartbeat writes heartbeat for a specific datacenter
 	WriteHeartbeat(ctx context.Context, datacenter string) error
 
 	// ReadHeartbeat reads heartbeat for a specific datacenter
 	ReadHeartbeat(ctx context.Context, datacenter string) (*model.HeartbeatInfo, error)
 
+	// Ping checks that at least one etcd endpoint is reachable
+	Ping(ctx context.Context) error
+
+	// Connected reports the result of the latest background ping
+	Connected() bool
+
+	// RotateEndpoints moves the first configured endpoint to the end before a read is retried,
+	// so the client reconnects starting from the next endpoint
+	RotateEndpoints(ctx context.Context)
+
+	// AppendActivationEvent records an activation event in the bounded history
+	AppendActivationEvent(ctx context.Context, event *model.ActivationEvent) error
+
+	// ListActivationEvents returns up to limit activation events, newest first
+	ListActivationEvents(ctx context.Context, limit int) ([]model.ActivationEvent, error)
+
+	// WriteHealthFailures saves the health checker failure counters of an instance
+	WriteHealthFailures(ctx context.Context, instance string, counts *model.HealthFailureCounts) error
+
+	// ReadHealthFailures reads the failure counters saved by an instance, or nil when there are none
+	ReadHealthFailures(ctx context.Context, instance string) (*model.HealthFailureCounts, error)
+
 	// Close closes the etcd client connection
 	Close() error
 }
 
+// etcdKeys holds the keys of one deployment, namespaced by etcd.key_prefix
+type etcdKeys struct {
+	activeDatacenter string
+	heartbeatPrefix  string
+	historyPrefix    string
+	healthFailures   string // Prefix of the health checker failure counters, one key per instance
+}
+
+// newEtcdKeys builds the keys under prefix, which must end with a slash
+func newEtcdKeys(prefix string) etcdKeys {
+	return etcdKeys{
+		activeDatacenter: prefix + keyActiveDatacenter,
+		heartbeatPrefix:  prefix + keyHeartbeatPrefix,
+		historyPrefix:    prefix + keyHistoryPrefix,
+		healthFailures:   prefix + keyHealthFailures,
+	}
+}
+
 // etcdClient implements EtcdRepository
 type etcdClient struct {
-	client *clientv3.Client
-	logger *slog.Logger
+	client            *clientv3.Client
+	keys              etcdKeys
+	maxHistoryEntries int
+	leaseTTL          time.Duration // TTL of the active datacenter key lease (0 disables the lease)
+	operationTimeout  time.Duration // Deadline of every single etcd request
+	logger            *slog.Logger
+
+	leaseMu       sync.Mutex
+	leaseID       clientv3.LeaseID
+	leaseDetached bool // Set by DetachActiveDatacenterLease, later writes carry no lease
+
+	connected  atomic.Bool
+	stopPinger context.CancelFunc
+
+	rotateMu sync.Mutex // Serializes endpoint rotations, Endpoints and SetEndpoints are not atomic together
 }
 
-// NewEtcdRepository creates a new etcd repository
-func NewEtcdRepository(cfg config.EtcdConfig, logger *slog.Logger) (EtcdRepository, error) {
+// NewEtcdRepository creates a new etcd repository.
+// The active datacenter key is attached to a lease with leaseTTL so it expires if its writer dies.
+func NewEtcdRepository(cfg config.EtcdConfig, leaseTTL time.Duration, logger *slog.Logger) (EtcdRepository, error) {
 	etcdCfg := clientv3.Config{
 		Endpoints:   cfg.Endpoints,
 		DialTimeout: cfg.DialTimeout,
@@ -66,22 +165,127 @@ func NewEtcdRepository(cfg config.EtcdConfig, logger *slog.Logger) (EtcdReposito
 		return nil, fmt.Errorf("failed to create etcd client: %w", err)
 	}
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	e := &etcdClient{
+		client:            client,
+		keys:              newEtcdKeys(cfg.KeyPrefix),
+		maxHistoryEntries: cfg.MaxHistoryEntries,
+		leaseTTL:          leaseTTL,
+		operationTimeout:  cfg.OperationTimeout,
+		logger:            logger,
+	}
 
-	_, err = client.Status(ctx, cfg.Endpoints[0])
-	if err != nil {
+	// Test connection; any reachable endpoint is enough, clientv3 fails over between them
+	if err := e.testEndpoints(cfg.StartupTimeout); err != nil {
 		client.Close()
 		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
 	}
+	e.connected.Store(true)
+
+	logger.Info("Connected to etcd cluster", "endpoints", cfg.Endpoints, "key_prefix", cfg.KeyPrefix)
 
-	logger.Info("Connected to etcd cluster", "endpoints", cfg.Endpoints)
+	pingCtx, stopPinger := context.WithCancel(context.Background())
+	e.stopPinger = stopPinger
+	go e.pingLoop(pingCtx, cfg.PingInterval, cfg.DialTimeout)
 
-	return &etcdClient{
-		client: client,
-		logger: logger,
-	}, nil
+	return e, nil
+}
+
+// testEndpoints checks the endpoints one by one, each with its own timeout, until one responds.
+// The responding endpoint is moved to the front so the client starts with it.
+// The returned error lists the failure of every endpoint.
+func (e *etcdClient) testEndpoints(timeout time.Duration) error {
+	endpoints := e.client.Endpoints()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no etcd endpoints configured")
+	}
+
+	var errs []error
+	for i, endpoint := range endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err := e.client.Status(ctx, endpoint)
+		cancel()
+		if err != nil {
+			e.logger.Warn("etcd endpoint unreachable at startup", "endpoint", endpoint, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+			continue
+		}
+
+		if i > 0 {
+			e.client.SetEndpoints(append(slices.Clone(endpoints[i:]), endpoints[:i]...)...)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("all %d etcd endpoints unreachable: %w", len(endpoints), errors.Join(errs...))
+}
+
+// pingLoop periodically pings etcd and updates the connected flag, logging state changes
+func (e *etcdClient) pingLoop(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := e.Ping(pingCtx)
+		cancel()
+
+		connected := err == nil
+		if e.connected.Swap(connected) == connected {
+			continue
+		}
+
+		if connected {
+			e.logger.Info("etcd connection restored")
+		} else {
+			e.logger.Warn("etcd connection lost", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// withOperationTimeout bounds an etcd operation by the configured operation timeout,
+// so a partitioned etcd returns an error instead of hanging the caller
+func (e *etcdClient) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.operationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.operationTimeout)
+}
+
+// RotateEndpoints moves the first configured endpoint to the end. The client balancer may still
+// pick any endpoint it considers healthy, so this only changes where reconnects start.
+// Nothing is rotated when the caller gave up on the request.
+func (e *etcdClient) RotateEndpoints(ctx context.Context) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+
+	e.rotateMu.Lock()
+	defer e.rotateMu.Unlock()
+
+	endpoints := e.client.Endpoints()
+	if len(endpoints) < 2 {
+		return
+	}
+
+	rotated := append(slices.Clone(endpoints[1:]), endpoints[0])
+	e.client.SetEndpoints(rotated...)
+	e.logger.Info("Rotated etcd endpoints before retrying a failed read",
+		"failed_endpoint", endpoints[0],
+		"next_endpoint", rotated[0])
+}
+
+// timeoutError marks err with ErrEtcdTimeout if ctx ran out of time
+func timeoutError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrEtcdTimeout, err)
+	}
+	return err
 }
 
 // WriteActiveDatacenter writes the active datacenter information to etcd
@@ -91,9 +295,15 @@ func (e *etcdClient) WriteActiveDatacenter(ctx context.Context, info *model.Acti
 		return fmt.Errorf("failed to marshal active datacenter info: %w", err)
 	}
 
-	_, err = e.client.Put(ctx, keyActiveDatacenter, string(data))
-	if err != nil {
-		return fmt.Errorf("failed to write active datacenter to etcd: %w", err)
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	if !e.useLease() {
+		if _, err := e.client.Put(ctx, e.keys.activeDatacenter, string(data)); err != nil {
+			return fmt.Errorf("failed to write active datacenter to etcd: %w", timeoutError(ctx, err))
+		}
+	} else if err := e.putWithLease(ctx, e.keys.activeDatacenter, string(data)); err != nil {
+		return fmt.Errorf("failed to write active datacenter to etcd: %w", timeoutError(ctx, err))
 	}
 
 	e.logger.Debug("Wrote active datacenter to etcd",
@@ -105,23 +315,328 @@ func (e *etcdClient) WriteActiveDatacenter(ctx context.Context, info *model.Acti
 
 // ReadActiveDatacenter reads the active datacenter information from etcd
 func (e *etcdClient) ReadActiveDatacenter(ctx context.Context) (*model.ActiveDatacenter, error) {
-	resp, err := e.client.Get(ctx, keyActiveDatacenter)
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Get(ctx, e.keys.activeDatacenter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read active datacenter from etcd: %w", err)
+		return nil, fmt.Errorf("failed to read active datacenter from etcd: %w", timeoutError(ctx, err))
 	}
 
 	if len(resp.Kvs) == 0 {
-		return nil, fmt.Errorf("no active datacenter found in etcd")
+		return nil, ErrNoActiveDatacenter
 	}
 
 	var info model.ActiveDatacenter
 	if err := json.Unmarshal(resp.Kvs[0].Value, &info); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal active datacenter info: %w", err)
 	}
+	info.Revision = resp.Kvs[0].ModRevision
 
 	return &info, nil
 }
 
+// DeleteActiveDatacenter removes the active datacenter key from etcd
+func (e *etcdClient) DeleteActiveDatacenter(ctx context.Context) error {
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Delete(ctx, e.keys.activeDatacenter)
+	if err != nil {
+		return fmt.Errorf("failed to delete active datacenter from etcd: %w", timeoutError(ctx, err))
+	}
+
+	if resp.Deleted == 0 {
+		e.logger.Info("No active datacenter to delete in etcd")
+		return nil
+	}
+
+	e.logger.Info("Deleted active datacenter from etcd")
+
+	return nil
+}
+
+// TryDeleteActiveDatacenter deletes the active datacenter in a transaction guarded by the key's revision
+func (e *etcdClient) TryDeleteActiveDatacenter(ctx context.Context, expectedRevision int64) (bool, error) {
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(e.keys.activeDatacenter), "=", expectedRevision)).
+		Then(clientv3.OpDelete(e.keys.activeDatacenter)).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete active datacenter from etcd: %w", timeoutError(ctx, err))
+	}
+
+	if !resp.Succeeded {
+		e.logger.Info("Active datacenter changed since read, not deleted",
+			"expected_revision", expectedRevision)
+		return false, nil
+	}
+
+	e.logger.Info("Deleted active datacenter from etcd",
+		"expected_revision", expectedRevision)
+	return true, nil
+}
+
+// TryClaimActiveDatacenter writes the active datacenter in a transaction guarded by the key's revision
+func (e *etcdClient) TryClaimActiveDatacenter(ctx context.Context, info *model.ActiveDatacenter, expectedRevision int64) (bool, *model.ActiveDatacenter, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal active datacenter info: %w", err)
+	}
+
+	// Claim only if nobody changed the key since it was read (or it still doesn't exist)
+	cmp := clientv3.Compare(clientv3.ModRevision(e.keys.activeDatacenter), "=", expectedRevision)
+	if expectedRevision == 0 {
+		cmp = clientv3.Compare(clientv3.CreateRevision(e.keys.activeDatacenter), "=", 0)
+	}
+
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	var putOpts []clientv3.OpOption
+	if e.useLease() {
+		leaseID, err := e.activeDatacenterLease(ctx)
+		if err != nil {
+			return false, nil, timeoutError(ctx, err)
+		}
+		putOpts = append(putOpts, clientv3.WithLease(leaseID))
+	}
+
+	resp, err := e.client.Txn(ctx).
+		If(cmp).
+		Then(clientv3.OpPut(e.keys.activeDatacenter, string(data), putOpts...)).
+		Else(clientv3.OpGet(e.keys.activeDatacenter)).
+		Commit()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to claim active datacenter in etcd: %w", timeoutError(ctx, err))
+	}
+
+	if resp.Succeeded {
+		e.logger.Info("Claimed active datacenter in etcd",
+			"datacenter", info.Datacenter,
+			"expected_revision", expectedRevision)
+		return true, nil, nil
+	}
+
+	// Claim lost - report who currently holds the key
+	if len(resp.Responses) == 0 || len(resp.Responses[0].GetResponseRange().Kvs) == 0 {
+		return false, nil, nil
+	}
+	kv := resp.Responses[0].GetResponseRange().Kvs[0]
+
+	var current model.ActiveDatacenter
+	if err := json.Unmarshal(kv.Value, &current); err != nil {
+		return false, nil, fmt.Errorf("failed to unmarshal active datacenter info: %w", err)
+	}
+	current.Revision = kv.ModRevision
+
+	e.logger.Info("Active datacenter claim lost",
+		"datacenter", info.Datacenter,
+		"holder", current.Datacenter,
+		"expected_revision", expectedRevision,
+		"current_revision", current.Revision)
+
+	return false, &current, nil
+}
+
+// putWithLease writes a key attached to the active datacenter lease.
+// If the current lease has expired, a new one is granted and the write is retried once.
+func (e *etcdClient) putWithLease(ctx context.Context, key, value string) error {
+	leaseID, err := e.activeDatacenterLease(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.client.Put(ctx, key, value, clientv3.WithLease(leaseID))
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	e.logger.Warn("Write with active datacenter lease failed, granting a new lease",
+		"lease_id", int64(leaseID),
+		"error", err.Error())
+
+	e.dropLease(leaseID)
+	leaseID, err = e.activeDatacenterLease(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.client.Put(ctx, key, value, clientv3.WithLease(leaseID))
+	return err
+}
+
+// useLease reports whether active datacenter writes are attached to the lease
+func (e *etcdClient) useLease() bool {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	return e.leaseTTL > 0 && !e.leaseDetached
+}
+
+// DetachActiveDatacenterLease makes later active datacenter writes plain puts. The lease itself
+// is left to expire, so a key still attached to it is only kept until a write detaches it.
+func (e *etcdClient) DetachActiveDatacenterLease() {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	e.leaseDetached = true
+}
+
+// activeDatacenterLease returns the current active datacenter lease, granting one if needed
+func (e *etcdClient) activeDatacenterLease(ctx context.Context) (clientv3.LeaseID, error) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	if e.leaseID != clientv3.NoLease {
+		return e.leaseID, nil
+	}
+
+	ttl := int64(math.Ceil(e.leaseTTL.Seconds()))
+	resp, err := e.client.Grant(ctx, ttl)
+	if err != nil {
+		return clientv3.NoLease, fmt.Errorf("failed to grant active datacenter lease: %w", err)
+	}
+
+	e.leaseID = resp.ID
+	e.logger.Info("Granted active datacenter lease",
+		"lease_id", int64(resp.ID),
+		"ttl_seconds", resp.TTL)
+
+	return resp.ID, nil
+}
+
+// dropLease forgets the given lease so the next write grants a new one
+func (e *etcdClient) dropLease(leaseID clientv3.LeaseID) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	if e.leaseID == leaseID {
+		e.leaseID = clientv3.NoLease
+	}
+}
+
+// RenewActiveDatacenterLease refreshes the active datacenter lease TTL
+func (e *etcdClient) RenewActiveDatacenterLease(ctx context.Context) error {
+	e.leaseMu.Lock()
+	leaseID := e.leaseID
+	e.leaseMu.Unlock()
+
+	// Nothing to renew - the next write grants a lease
+	if leaseID == clientv3.NoLease {
+		return nil
+	}
+
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.KeepAliveOnce(ctx, leaseID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to renew active datacenter lease: %w", timeoutError(ctx, err))
+		}
+		e.dropLease(leaseID)
+		return fmt.Errorf("%w: %v", ErrLeaseLost, err)
+	}
+
+	e.logger.Debug("Renewed active datacenter lease",
+		"lease_id", int64(leaseID),
+		"ttl_seconds", resp.TTL)
+
+	return nil
+}
+
+// WatchActiveDatacenter streams active datacenter updates until ctx is cancelled.
+// A deleted key, e.g. expired with its lease, is sent as a nil update.
+// The watch is re-established from the last seen revision if etcd closes it,
+// and the returned channel is closed only when ctx is done.
+func (e *etcdClient) WatchActiveDatacenter(ctx context.Context) (<-chan *model.ActiveDatacenter, error) {
+	// Start watching right after the current revision so no update is missed
+	getCtx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Get(getCtx, e.keys.activeDatacenter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read active datacenter revision from etcd: %w", timeoutError(getCtx, err))
+	}
+
+	out := make(chan *model.ActiveDatacenter)
+	go e.watchActiveDatacenterLoop(ctx, resp.Header.Revision+1, out)
+
+	return out, nil
+}
+
+// watchActiveDatacenterLoop forwards active datacenter updates and deletes, and reconnects closed watches
+func (e *etcdClient) watchActiveDatacenterLoop(ctx context.Context, rev int64, out chan<- *model.ActiveDatacenter) {
+	defer close(out)
+
+	for {
+		var stop bool
+		rev, stop = e.watchActiveDatacenterOnce(ctx, rev, out)
+		if stop {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+			e.logger.Info("Re-establishing active datacenter watch", "revision", rev)
+		}
+	}
+}
+
+// watchActiveDatacenterOnce runs a single watch stream and returns the revision to resume from.
+// stop is true when ctx is done and the caller should not reconnect.
+func (e *etcdClient) watchActiveDatacenterOnce(ctx context.Context, rev int64, out chan<- *model.ActiveDatacenter) (int64, bool) {
+	// Require a leader so the watch is closed instead of hanging on a partitioned member
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	for wresp := range e.client.Watch(watchCtx, e.keys.activeDatacenter, clientv3.WithRev(rev)) {
+		if wresp.CompactRevision != 0 {
+			// Requested revision was compacted - resume from the oldest available one
+			e.logger.Warn("Active datacenter watch revision compacted",
+				"requested_revision", rev,
+				"compact_revision", wresp.CompactRevision)
+			return wresp.CompactRevision, false
+		}
+		if err := wresp.Err(); err != nil {
+			e.logger.Warn("Active datacenter watch error", "error", err.Error())
+			return rev, ctx.Err() != nil
+		}
+
+		for _, ev := range wresp.Events {
+			rev = ev.Kv.ModRevision + 1
+
+			var info *model.ActiveDatacenter
+			if ev.Type == clientv3.EventTypePut {
+				info = &model.ActiveDatacenter{}
+				if err := json.Unmarshal(ev.Kv.Value, info); err != nil {
+					e.logger.Warn("Skipping malformed active datacenter update", "error", err.Error())
+					continue
+				}
+			} else {
+				e.logger.Info("Active datacenter key deleted", "revision", ev.Kv.ModRevision)
+			}
+
+			select {
+			case out <- info:
+			case <-ctx.Done():
+				return rev, true
+			}
+		}
+	}
+
+	return rev, ctx.Err() != nil
+}
+
 // WriteHeartbeat writes heartbeat for a specific datacenter
 func (e *etcdClient) WriteHeartbeat(ctx context.Context, datacenter string) error {
 	heartbeat := model.HeartbeatInfo{
@@ -134,10 +649,13 @@ func (e *etcdClient) WriteHeartbeat(ctx context.Context, datacenter string) erro
 		return fmt.Errorf("failed to marshal heartbeat info: %w", err)
 	}
 
-	key := keyHeartbeatPrefix + datacenter
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	key := e.keys.heartbeatPrefix + datacenter
 	_, err = e.client.Put(ctx, key, string(data))
 	if err != nil {
-		return fmt.Errorf("failed to write heartbeat to etcd: %w", err)
+		return fmt.Errorf("failed to write heartbeat to etcd: %w", timeoutError(ctx, err))
 	}
 
 	e.logger.Debug("Wrote heartbeat to etcd", "datacenter", datacenter)
@@ -147,10 +665,13 @@ func (e *etcdClient) WriteHeartbeat(ctx context.Context, datacenter string) erro
 
 // ReadHeartbeat reads heartbeat for a specific datacenter
 func (e *etcdClient) ReadHeartbeat(ctx context.Context, datacenter string) (*model.HeartbeatInfo, error) {
-	key := keyHeartbeatPrefix + datacenter
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	key := e.keys.heartbeatPrefix + datacenter
 	resp, err := e.client.Get(ctx, key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read heartbeat from etcd: %w", err)
+		return nil, fmt.Errorf("failed to read heartbeat from etcd: %w", timeoutError(ctx, err))
 	}
 
 	if len(resp.Kvs) == 0 {
@@ -165,8 +686,168 @@ func (e *etcdClient) ReadHeartbeat(ctx context.Context, datacenter string) (*mod
 	return &heartbeat, nil
 }
 
+// WriteHealthFailures saves the health checker failure counters of an instance
+func (e *etcdClient) WriteHealthFailures(ctx context.Context, instance string, counts *model.HealthFailureCounts) error {
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal health failure counts: %w", err)
+	}
+
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	if _, err := e.client.Put(ctx, e.keys.healthFailures+instance, string(data)); err != nil {
+		return fmt.Errorf("failed to write health failure counts to etcd: %w", timeoutError(ctx, err))
+	}
+	return nil
+}
+
+// ReadHealthFailures reads the failure counters saved by an instance, or nil when there are none
+func (e *etcdClient) ReadHealthFailures(ctx context.Context, instance string) (*model.HealthFailureCounts, error) {
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Get(ctx, e.keys.healthFailures+instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health failure counts from etcd: %w", timeoutError(ctx, err))
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+
+	var counts model.HealthFailureCounts
+	if err := json.Unmarshal(resp.Kvs[0].Value, &counts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal health failure counts: %w", err)
+	}
+	return &counts, nil
+}
+
+// AppendActivationEvent records an activation event keyed by timestamp and prunes old entries
+func (e *etcdClient) AppendActivationEvent(ctx context.Context, event *model.ActivationEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activation event: %w", err)
+	}
+
+	// Zero-padded nanosecond timestamp keeps keys sorted chronologically
+	putCtx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	key := fmt.Sprintf("%s%020d", e.keys.historyPrefix, event.Timestamp.UnixNano())
+	if _, err := e.client.Put(putCtx, key, string(data)); err != nil {
+		return fmt.Errorf("failed to write activation event to etcd: %w", timeoutError(putCtx, err))
+	}
+
+	e.logger.Debug("Wrote activation event to etcd",
+		"target", event.Target,
+		"key", key)
+
+	return e.pruneActivationEvents(ctx)
+}
+
+// pruneActivationEvents deletes the oldest activation events above the configured maximum
+func (e *etcdClient) pruneActivationEvents(ctx context.Context) error {
+	if e.maxHistoryEntries <= 0 {
+		return nil
+	}
+
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Get(ctx, e.keys.historyPrefix,
+		clientv3.WithPrefix(),
+		clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list activation events: %w", timeoutError(ctx, err))
+	}
+
+	excess := len(resp.Kvs) - e.maxHistoryEntries
+	for i := 0; i < excess; i++ {
+		if _, err := e.client.Delete(ctx, string(resp.Kvs[i].Key)); err != nil {
+			return fmt.Errorf("failed to prune activation event: %w", timeoutError(ctx, err))
+		}
+	}
+
+	if excess > 0 {
+		e.logger.Debug("Pruned old activation events", "count", excess)
+	}
+
+	return nil
+}
+
+// ListActivationEvents returns up to limit activation events, newest first
+func (e *etcdClient) ListActivationEvents(ctx context.Context, limit int) ([]model.ActivationEvent, error) {
+	opts := []clientv3.OpOption{
+		clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend),
+	}
+	if limit > 0 {
+		opts = append(opts, clientv3.WithLimit(int64(limit)))
+	}
+
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := e.client.Get(ctx, e.keys.historyPrefix, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read activation events from etcd: %w", timeoutError(ctx, err))
+	}
+
+	events := make([]model.ActivationEvent, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var event model.ActivationEvent
+		if err := json.Unmarshal(kv.Value, &event); err != nil {
+			e.logger.Warn("Skipping malformed activation event",
+				"key", string(kv.Key),
+				"error", err.Error())
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Ping checks that at least one etcd endpoint is reachable.
+// Every endpoint gets the operation timeout, so an unreachable one can't use up the time of the others.
+func (e *etcdClient) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, endpoint := range e.client.Endpoints() {
+		if lastErr = e.pingEndpoint(ctx, endpoint); lastErr == nil {
+			return nil
+		}
+	}
+
+	if lastErr == nil {
+		return fmt.Errorf("no etcd endpoints configured")
+	}
+
+	return fmt.Errorf("failed to reach etcd: %w", lastErr)
+}
+
+// pingEndpoint requests the status of a single endpoint within the operation timeout
+func (e *etcdClient) pingEndpoint(ctx context.Context, endpoint string) error {
+	ctx, cancel := e.withOperationTimeout(ctx)
+	defer cancel()
+
+	if _, err := e.client.Status(ctx, endpoint); err != nil {
+		return timeoutError(ctx, err)
+	}
+	return nil
+}
+
+// Connected reports the result of the latest background ping
+func (e *etcdClient) Connected() bool {
+	return e.connected.Load()
+}
+
 // Close closes the etcd client connection
 func (e *etcdClient) Close() error {
+	if e.stopPinger != nil {
+		e.stopPinger()
+	}
 	if e.client != nil {
 		return e.client.Close()
 	}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// blackholeEndpoint accepts connections and never answers, like an etcd behind a partition
+func blackholeEndpoint(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// newUnconnectedEtcdClient returns an etcdClient for endpoints without testing them
+func newUnconnectedEtcdClient(t *testing.T, endpoints []string, operationTimeout time.Duration) *etcdClient {
+	t.Helper()
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: endpoints, DialTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("failed to create etcd client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return &etcdClient{
+		client:           client,
+		keys:             newEtcdKeys("/test/"),
+		operationTimeout: operationTimeout,
+		logger:           slog.New(slog.DiscardHandler),
+	}
+}
+
+func TestPingTimesOut(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints int
+	}{
+		{name: "single blackholed endpoint", endpoints: 1},
+		{name: "every endpoint gets its own timeout", endpoints: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoints []string
+			for range tt.endpoints {
+				endpoints = append(endpoints, blackholeEndpoint(t))
+			}
+			const timeout = 100 * time.Millisecond
+			e := newUnconnectedEtcdClient(t, endpoints, timeout)
+
+			// The caller's context never ends, like the health checker's
+			start := time.Now()
+			err := e.Ping(context.Background())
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, ErrEtcdTimeout) {
+				t.Errorf("Ping() error = %v, want ErrEtcdTimeout", err)
+			}
+			if limit := time.Duration(tt.endpoints)*timeout + time.Second; elapsed > limit {
+				t.Errorf("Ping() took %v, want at most %v", elapsed, limit)
+			}
+		})
+	}
+}
+
+func TestRotateEndpoints(t *testing.T) {
+	endpoints := []string{"127.0.0.1:1", "127.0.0.1:2", "127.0.0.1:3"}
+
+	tests := []struct {
+		name      string
+		endpoints []string
+		cancelled bool // The caller gave up on the read
+		want      []string
+	}{
+		{
+			name:      "moves to the next endpoint",
+			endpoints: endpoints,
+			want:      []string{"127.0.0.1:2", "127.0.0.1:3", "127.0.0.1:1"},
+		},
+		{
+			name:      "cancelled caller keeps the order",
+			endpoints: endpoints,
+			cancelled: true,
+			want:      endpoints,
+		},
+		{
+			name:      "single endpoint",
+			endpoints: endpoints[:1],
+			want:      endpoints[:1],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newUnconnectedEtcdClient(t, tt.endpoints, time.Second)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			e.RotateEndpoints(ctx)
+			if got := e.client.Endpoints(); !slices.Equal(got, tt.want) {
+				t.Errorf("endpoints = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateEndpointsConcurrently(t *testing.T) {
+	endpoints := []string{"127.0.0.1:1", "127.0.0.1:2", "127.0.0.1:3"}
+	e := newUnconnectedEtcdClient(t, endpoints, time.Second)
+
+	// Rotations that interleave would lose or duplicate endpoints; serialized ones end where they started
+	const rotations = 3 * 20
+	var wg sync.WaitGroup
+	for range rotations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.RotateEndpoints(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if got := e.client.Endpoints(); !slices.Equal(got, endpoints) {
+		t.Errorf("endpoints = %v after %d rotations, want %v", got, rotations, endpoints)
+	}
+}
+
+func TestReadActiveDatacenterKeepsEndpoints(t *testing.T) {
+	endpoints := []string{"127.0.0.1:1", "127.0.0.1:2"}
+	e := newUnconnectedEtcdClient(t, endpoints, time.Second)
+	_, fake := newFakeEtcdClient(t)
+	fake.err = errors.New("connection refused")
+	e.client.KV = fake
+
+	if _, err := e.ReadActiveDatacenter(context.Background()); err == nil {
+		t.Fatal("ReadActiveDatacenter() succeeded, want the read error")
+	}
+	// Only the heartbeat rotates, before it retries the read
+	if got := e.client.Endpoints(); !slices.Equal(got, endpoints) {
+		t.Errorf("endpoints = %v, want %v", got, endpoints)
+	}
+}
+
+// fakeMaintenance answers Status for the reachable endpoints and blocks on the others until the deadline
+type fakeMaintenance struct {
+	clientv3.Maintenance
+	reachable map[string]bool
+	checked   []string
+}
+
+func (f *fakeMaintenance) Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	f.checked = append(f.checked, endpoint)
+	if f.reachable[endpoint] {
+		return &clientv3.StatusResponse{}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTestEndpoints(t *testing.T) {
+	endpoints := []string{"127.0.0.1:1", "127.0.0.1:2", "127.0.0.1:3"}
+
+	tests := []struct {
+		name        string
+		reachable   []string
+		wantChecked []string
+		want        []string // Endpoint order after the test
+		wantErr     bool
+	}{
+		{
+			name:        "first endpoint responds",
+			reachable:   endpoints,
+			wantChecked: endpoints[:1],
+			want:        endpoints,
+		},
+		{
+			name:        "responding endpoint moves to the front",
+			reachable:   []string{"127.0.0.1:2"},
+			wantChecked: endpoints[:2],
+			want:        []string{"127.0.0.1:2", "127.0.0.1:3", "127.0.0.1:1"},
+		},
+		{
+			name:        "last endpoint responds",
+			reachable:   []string{"127.0.0.1:3"},
+			wantChecked: endpoints,
+			want:        []string{"127.0.0.1:3", "127.0.0.1:1", "127.0.0.1:2"},
+		},
+		{
+			name:        "all unreachable",
+			wantChecked: endpoints,
+			want:        endpoints,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newUnconnectedEtcdClient(t, endpoints, time.Second)
+			maintenance := &fakeMaintenance{reachable: make(map[string]bool)}
+			for _, endpoint := range tt.reachable {
+				maintenance.reachable[endpoint] = true
+			}
+			e.client.Maintenance = maintenance
+
+			const timeout = 50 * time.Millisecond
+			start := time.Now()
+			err := e.testEndpoints(timeout)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("testEndpoints() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(maintenance.checked, tt.wantChecked) {
+				t.Errorf("checked %v, want %v", maintenance.checked, tt.wantChecked)
+			}
+			if got := e.client.Endpoints(); !slices.Equal(got, tt.want) {
+				t.Errorf("endpoints = %v, want %v", got, tt.want)
+			}
+
+			// Every unreachable endpoint waits out its own startup timeout, no more
+			unreachable := len(tt.wantChecked)
+			if !tt.wantErr {
+				unreachable-- // The last checked endpoint responded
+			}
+			if wantElapsed := time.Duration(unreachable) * timeout; elapsed < wantElapsed || elapsed > wantElapsed+time.Second {
+				t.Errorf("testEndpoints() took %v, want about %v", elapsed, wantElapsed)
+			}
+
+			if tt.wantErr {
+				for _, endpoint := range endpoints {
+					if !strings.Contains(err.Error(), endpoint) {
+						t.Errorf("error %q doesn't name endpoint %s", err, endpoint)
+					}
+				}
+			}
+		})
+	}
+}
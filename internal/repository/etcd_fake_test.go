@@ -0,0 +1,476 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd is an in-memory etcd keyspace implementing the KV, Lease and Watcher APIs of clientv3
+type fakeEtcd struct {
+	clientv3.Lease // Methods that aren't overridden panic
+
+	mu        sync.Mutex
+	revision  int64
+	kvs       map[string]*mvccpb.KeyValue
+	leases    map[clientv3.LeaseID]int64 // lease -> TTL in seconds
+	nextLease clientv3.LeaseID
+	err       error // Returned by every request when set
+	requests  int
+
+	events    []*clientv3.Event // Every change, replayed to watches started at an older revision
+	compacted int64             // Revisions up to this one can't be watched anymore
+	watches   []*fakeWatch
+}
+
+// fakeWatch is an open watch stream
+type fakeWatch struct {
+	key string
+	ch  chan clientv3.WatchResponse
+}
+
+// newFakeEtcdClient returns an etcdClient backed by a fake keyspace under the "/test/" prefix
+func newFakeEtcdClient(t *testing.T) (*etcdClient, *fakeEtcd) {
+	t.Helper()
+
+	fake := &fakeEtcd{
+		kvs:       make(map[string]*mvccpb.KeyValue),
+		leases:    make(map[clientv3.LeaseID]int64),
+		nextLease: 100,
+	}
+	return fake.newClient(t), fake
+}
+
+// newClient returns another etcdClient of the fake keyspace, like a second instance sharing the cluster
+func (f *fakeEtcd) newClient(t *testing.T) *etcdClient {
+	t.Helper()
+
+	client := clientv3.NewCtxClient(context.Background())
+	client.KV = f
+	client.Lease = f
+	client.Watcher = f
+	t.Cleanup(func() { client.Close() })
+
+	return &etcdClient{
+		client:           client,
+		keys:             newEtcdKeys("/test/"),
+		operationTimeout: time.Second,
+		logger:           slog.New(slog.DiscardHandler),
+	}
+}
+
+// keys returns the stored keys in ascending order
+func (f *fakeEtcd) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.kvs))
+	for key := range f.kvs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// get returns a copy of the stored key, nil when it doesn't exist
+func (f *fakeEtcd) get(key string) *mvccpb.KeyValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	kv, ok := f.kvs[key]
+	if !ok {
+		return nil
+	}
+	copied := *kv
+	return &copied
+}
+
+// expireLease drops a lease and the keys attached to it, like etcd does when its TTL runs out
+func (f *fakeEtcd) expireLease(id clientv3.LeaseID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.leases, id)
+	f.revision++
+	for key, kv := range f.kvs {
+		if kv.Lease == int64(id) {
+			delete(f.kvs, key)
+			f.notifyLocked(mvccpb.DELETE, &mvccpb.KeyValue{Key: []byte(key), ModRevision: f.revision})
+		}
+	}
+}
+
+// compact drops the history up to rev, like etcd compaction
+func (f *fakeEtcd) compact(rev int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.compacted = rev
+	f.events = slices.DeleteFunc(f.events, func(ev *clientv3.Event) bool { return ev.Kv.ModRevision <= rev })
+}
+
+// breakWatches cancels every open watch stream, like a lost connection to the etcd member
+func (f *fakeEtcd) breakWatches() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, w := range f.watches {
+		w.ch <- clientv3.WatchResponse{Canceled: true}
+		close(w.ch)
+	}
+	f.watches = nil
+}
+
+// watchCount returns the number of open watch streams
+func (f *fakeEtcd) watchCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.watches)
+}
+
+// notifyLocked records a change and sends it to the watches of its key
+func (f *fakeEtcd) notifyLocked(typ mvccpb.Event_EventType, kv *mvccpb.KeyValue) {
+	ev := &clientv3.Event{Type: typ, Kv: kv}
+	f.events = append(f.events, ev)
+	for _, w := range f.watches {
+		if w.key == string(kv.Key) {
+			w.ch <- clientv3.WatchResponse{Header: *f.header(), Events: []*clientv3.Event{ev}}
+		}
+	}
+}
+
+// opField reads an unexported field of an Op, e.g. its sort order or lease
+func opField(op clientv3.Op, name string) reflect.Value {
+	return reflect.ValueOf(op).FieldByName(name)
+}
+
+// inRange reports whether key is selected by a request for key/end
+func inRange(key, start, end []byte) bool {
+	switch {
+	case len(end) == 0:
+		return bytes.Equal(key, start)
+	case bytes.Equal(end, []byte{0}):
+		return bytes.Compare(key, start) >= 0
+	default:
+		return bytes.Compare(key, start) >= 0 && bytes.Compare(key, end) < 0
+	}
+}
+
+func (f *fakeEtcd) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: f.revision}
+}
+
+func (f *fakeEtcd) begin() error {
+	f.requests++
+	return f.err
+}
+
+// rangeLocked applies a get
+func (f *fakeEtcd) rangeLocked(op clientv3.Op) *pb.RangeResponse {
+	var kvs []*mvccpb.KeyValue
+	for key, kv := range f.kvs {
+		if inRange([]byte(key), op.KeyBytes(), op.RangeBytes()) {
+			copied := *kv
+			if op.IsKeysOnly() {
+				copied.Value = nil
+			}
+			kvs = append(kvs, &copied)
+		}
+	}
+
+	descend := false
+	if sort := opField(op, "sort"); !sort.IsNil() {
+		descend = clientv3.SortOrder(sort.Elem().FieldByName("Order").Int()) == clientv3.SortDescend
+	}
+	slices.SortFunc(kvs, func(a, b *mvccpb.KeyValue) int {
+		if descend {
+			return bytes.Compare(b.Key, a.Key)
+		}
+		return bytes.Compare(a.Key, b.Key)
+	})
+
+	count := len(kvs)
+	if limit := op.Limit(); limit > 0 && int64(len(kvs)) > limit {
+		kvs = kvs[:limit]
+	}
+	return &pb.RangeResponse{Header: f.header(), Kvs: kvs, Count: int64(count)}
+}
+
+// putLocked applies a put, failing like etcd when the lease doesn't exist
+func (f *fakeEtcd) putLocked(op clientv3.Op) (*pb.PutResponse, error) {
+	lease := opField(op, "leaseID").Int()
+	if lease != 0 {
+		if _, ok := f.leases[clientv3.LeaseID(lease)]; !ok {
+			return nil, rpctypes.ErrLeaseNotFound
+		}
+	}
+
+	f.revision++
+	key := string(op.KeyBytes())
+	kv, ok := f.kvs[key]
+	if !ok {
+		kv = &mvccpb.KeyValue{Key: op.KeyBytes(), CreateRevision: f.revision}
+		f.kvs[key] = kv
+	}
+	kv.Value = op.ValueBytes()
+	kv.ModRevision = f.revision
+	kv.Version++
+	kv.Lease = lease
+
+	copied := *kv
+	f.notifyLocked(mvccpb.PUT, &copied)
+	return &pb.PutResponse{Header: f.header()}, nil
+}
+
+// deleteLocked applies a delete
+func (f *fakeEtcd) deleteLocked(op clientv3.Op) *pb.DeleteRangeResponse {
+	var deleted []string
+	for key := range f.kvs {
+		if inRange([]byte(key), op.KeyBytes(), op.RangeBytes()) {
+			deleted = append(deleted, key)
+		}
+	}
+	if len(deleted) > 0 {
+		f.revision++
+	}
+	for _, key := range deleted {
+		delete(f.kvs, key)
+		f.notifyLocked(mvccpb.DELETE, &mvccpb.KeyValue{Key: []byte(key), ModRevision: f.revision})
+	}
+	return &pb.DeleteRangeResponse{Header: f.header(), Deleted: int64(len(deleted))}
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+	resp, err := f.putLocked(clientv3.OpPut(key, val, opts...))
+	return (*clientv3.PutResponse)(resp), err
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+	return (*clientv3.GetResponse)(f.rangeLocked(clientv3.OpGet(key, opts...))), nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+	return (*clientv3.DeleteResponse)(f.deleteLocked(clientv3.OpDelete(key, opts...))), nil
+}
+
+func (f *fakeEtcd) Compact(context.Context, int64, ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	panic("fakeEtcd: Compact is not supported")
+}
+
+func (f *fakeEtcd) Do(context.Context, clientv3.Op) (clientv3.OpResponse, error) {
+	panic("fakeEtcd: Do is not supported")
+}
+
+func (f *fakeEtcd) Txn(context.Context) clientv3.Txn {
+	return &fakeTxn{etcd: f}
+}
+
+// compareLocked evaluates a transaction comparison; missing keys compare as zero values
+func (f *fakeEtcd) compareLocked(cmp clientv3.Cmp) bool {
+	kv, ok := f.kvs[string(cmp.KeyBytes())]
+	if !ok {
+		kv = &mvccpb.KeyValue{}
+	}
+
+	var result int
+	switch cmp.Target {
+	case pb.Compare_VERSION:
+		result = compareInt(kv.Version, cmp.TargetUnion.(*pb.Compare_Version).Version)
+	case pb.Compare_CREATE:
+		result = compareInt(kv.CreateRevision, cmp.TargetUnion.(*pb.Compare_CreateRevision).CreateRevision)
+	case pb.Compare_MOD:
+		result = compareInt(kv.ModRevision, cmp.TargetUnion.(*pb.Compare_ModRevision).ModRevision)
+	case pb.Compare_LEASE:
+		result = compareInt(kv.Lease, cmp.TargetUnion.(*pb.Compare_Lease).Lease)
+	case pb.Compare_VALUE:
+		if !ok {
+			return false
+		}
+		result = bytes.Compare(kv.Value, cmp.ValueBytes())
+	}
+
+	switch cmp.Result {
+	case pb.Compare_EQUAL:
+		return result == 0
+	case pb.Compare_NOT_EQUAL:
+		return result != 0
+	case pb.Compare_GREATER:
+		return result > 0
+	default:
+		return result < 0
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// fakeTxn is a transaction against fakeEtcd
+type fakeTxn struct {
+	etcd    *fakeEtcd
+	cmps    []clientv3.Cmp
+	thenOps []clientv3.Op
+	elseOps []clientv3.Op
+}
+
+func (t *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.thenOps = append(t.thenOps, ops...)
+	return t
+}
+
+func (t *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.elseOps = append(t.elseOps, ops...)
+	return t
+}
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	f := t.etcd
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+
+	succeeded := true
+	for _, cmp := range t.cmps {
+		if !f.compareLocked(cmp) {
+			succeeded = false
+			break
+		}
+	}
+
+	ops := t.elseOps
+	if succeeded {
+		ops = t.thenOps
+	}
+
+	resp := &clientv3.TxnResponse{Succeeded: succeeded}
+	for _, op := range ops {
+		switch {
+		case op.IsGet():
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{ResponseRange: f.rangeLocked(op)}})
+		case op.IsPut():
+			put, err := f.putLocked(op)
+			if err != nil {
+				return nil, err
+			}
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{ResponsePut: put}})
+		case op.IsDelete():
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: f.deleteLocked(op)}})
+		}
+	}
+	resp.Header = f.header()
+	return resp, nil
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+	f.nextLease++
+	f.leases[f.nextLease] = ttl
+	return &clientv3.LeaseGrantResponse{ID: f.nextLease, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) KeepAliveOnce(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.begin(); err != nil {
+		return nil, err
+	}
+	ttl, ok := f.leases[id]
+	if !ok {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	return &clientv3.LeaseKeepAliveResponse{ID: id, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.expireLease(id)
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+// Watch streams the changes of a single key, replaying the history from the WithRev revision
+func (f *fakeEtcd) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan clientv3.WatchResponse, 64)
+	rev := clientv3.OpGet(key, opts...).Rev()
+	if rev > 0 && rev <= f.compacted {
+		ch <- clientv3.WatchResponse{CompactRevision: f.compacted + 1}
+		close(ch)
+		return ch
+	}
+
+	for _, ev := range f.events {
+		if string(ev.Kv.Key) == key && rev > 0 && ev.Kv.ModRevision >= rev {
+			ch <- clientv3.WatchResponse{Header: *f.header(), Events: []*clientv3.Event{ev}}
+		}
+	}
+
+	w := &fakeWatch{key: key, ch: ch}
+	f.watches = append(f.watches, w)
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		// The stream may have been broken already
+		if i := slices.Index(f.watches, w); i >= 0 {
+			f.watches = slices.Delete(f.watches, i, i+1)
+			close(ch)
+		}
+	}()
+	return ch
+}
+
+func (f *fakeEtcd) RequestProgress(context.Context) error { return nil }
+
+func (f *fakeEtcd) Close() error { return nil }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestTriggerJobEvaluationsOnlyUnplaced(t *testing.T) {
+	responses := map[string]fakeResponse{
+		"GET /v1/jobs": {body: []nomad.JobListStub{
+			{ID: "new", Status: "pending"},
+			{ID: "queued", Status: "running"},
+			{ID: "placed", Status: "running"},
+			{ID: "unknown", Status: "running"},
+			{ID: "old", Status: "dead"},
+		}},
+		"GET /v1/job/queued/summary": {body: nomad.JobSummary{JobID: "queued", Summary: map[string]nomad.TaskGroupSummary{
+			"web": {Running: 1},
+			"api": {Running: 1, Queued: 2},
+		}}},
+		"GET /v1/job/placed/summary": {body: nomad.JobSummary{JobID: "placed", Summary: map[string]nomad.TaskGroupSummary{
+			"web": {Running: 3},
+		}}},
+		"GET /v1/job/unknown/summary": {status: http.StatusInternalServerError},
+	}
+	for _, id := range []string{"new", "queued", "placed", "unknown", "old"} {
+		responses["PUT /v1/job/"+id+"/evaluate"] = fakeResponse{body: nomad.JobRegisterResponse{}}
+	}
+
+	tests := []struct {
+		name          string
+		onlyUnplaced  bool
+		wantEvaluated []string // sorted
+		wantSummaries []string // sorted
+	}{
+		{
+			name:          "every live job",
+			wantEvaluated: []string{"new", "placed", "queued", "unknown"},
+		},
+		{
+			name:          "only unplaced",
+			onlyUnplaced:  true,
+			wantEvaluated: []string{"new", "queued", "unknown"},
+			wantSummaries: []string{"placed", "queued", "unknown"}, // Pending jobs need no summary
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, responses)
+			repo := newTestNomadRepository(t, srv)
+			repo.evalCfg = config.EvaluationsConfig{MaxConcurrent: 2, OnlyUnplaced: tt.onlyUnplaced}
+
+			if err := repo.TriggerJobEvaluations(context.Background(), "dc1"); err != nil {
+				t.Fatalf("TriggerJobEvaluations() error = %v", err)
+			}
+
+			var evaluated, summaries []string
+			for _, call := range fake.calls() {
+				var id string
+				switch {
+				case parseJobCall(call, "PUT", "/evaluate", &id):
+					evaluated = append(evaluated, id)
+				case parseJobCall(call, "GET", "/summary", &id):
+					summaries = append(summaries, id)
+				}
+			}
+			slices.Sort(evaluated)
+			slices.Sort(summaries)
+			if !slices.Equal(evaluated, tt.wantEvaluated) {
+				t.Errorf("evaluated %v, want %v", evaluated, tt.wantEvaluated)
+			}
+			if !slices.Equal(summaries, tt.wantSummaries) {
+				t.Errorf("read summaries of %v, want %v", summaries, tt.wantSummaries)
+			}
+		})
+	}
+}
+
+// parseJobCall reports whether call is "METHOD /v1/job/<id><suffix>" and stores the job ID in id
+func parseJobCall(call, method, suffix string, id *string) bool {
+	rest, ok := strings.CutPrefix(call, method+" /v1/job/")
+	if !ok {
+		return false
+	}
+	*id, ok = strings.CutSuffix(rest, suffix)
+	return ok
+}
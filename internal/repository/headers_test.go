@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestExtraHeaders(t *testing.T) {
+	if got := extraHeaders(config.ClusterConfig{}); got != nil {
+		t.Errorf("extraHeaders() = %v without configured headers, want nil", got)
+	}
+
+	got := extraHeaders(config.ClusterConfig{ExtraHeaders: map[string]string{"x-proxy-token": "secret", "X-Tenant": "a"}})
+	if len(got) != 2 || got.Get("X-Proxy-Token") != "secret" || got.Get("X-Tenant") != "a" {
+		t.Errorf("extraHeaders() = %v, want both headers in canonical form", got)
+	}
+}
+
+func TestExtraHeadersSentToServerAndClientAPI(t *testing.T) {
+	cluster := config.ClusterConfig{ExtraHeaders: map[string]string{"X-Proxy-Token": "secret"}}
+
+	server, srv := newFakeNomad(t, map[string]fakeResponse{
+		"PUT /v1/node/n1/drain": {status: http.StatusInternalServerError},
+	})
+	client, clientSrv := newFakeNomad(t, map[string]fakeResponse{
+		"POST /v1/node/self/drain": {body: map[string]any{}},
+	})
+
+	cluster.Address = srv.URL
+	nomadClient, httpClient, err := createNomadClient(cluster)
+	if err != nil {
+		t.Fatalf("createNomadClient: %v", err)
+	}
+	repo := newTestNomadRepository(t, srv)
+	repo.nomadCfg.EnableDirectFallback = true
+	repo.nomadCfg.DirectFallbackTimeout = time.Second
+	meta := repo.clusters["dc1"]
+	meta.client, meta.httpClient, meta.headers = nomadClient, httpClient, extraHeaders(cluster)
+	meta.setNode("n1", &nodeCache{HTTPAddr: strings.TrimPrefix(clientSrv.URL, "http://"), Name: "n1"})
+
+	if err := repo.SetNodeDrain(context.Background(), "dc1", "n1", true, model.DrainOptions{Deadline: time.Minute}); err != nil {
+		t.Fatalf("SetNodeDrain() error = %v", err)
+	}
+
+	for name, fake := range map[string]*fakeNomad{"Server API": server, "Client API": client} {
+		fake.mu.Lock()
+		requests := fake.requests
+		fake.mu.Unlock()
+		if len(requests) == 0 {
+			t.Errorf("no request reached the %s", name)
+		}
+		for _, r := range requests {
+			if got := r.Header.Get("X-Proxy-Token"); got != "secret" {
+				t.Errorf("%s request %s %s has X-Proxy-Token %q, want secret", name, r.Method, r.URL.Path, got)
+			}
+		}
+	}
+}
+
+func TestExtraHeadersSentWithQueries(t *testing.T) {
+	fake, srv := newFakeNomad(t, map[string]fakeResponse{
+		"GET /v1/nodes": {body: []nomad.NodeListStub{}},
+	})
+	nomadClient, _, err := createNomadClient(config.ClusterConfig{
+		Address:      srv.URL,
+		ExtraHeaders: map[string]string{"X-Proxy-Token": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("createNomadClient: %v", err)
+	}
+	repo := newTestNomadRepository(t, srv)
+	repo.clusters["dc1"].client = nomadClient
+
+	if _, err := repo.ListNodes(context.Background(), "dc1"); err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(fake.requests))
+	}
+	if got := fake.requests[0].Header.Get("X-Proxy-Token"); got != "secret" {
+		t.Errorf("list request has X-Proxy-Token %q, want secret", got)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// historyEvent returns an activation event for dc<n> at minute n
+func historyEvent(n int) *model.ActivationEvent {
+	return &model.ActivationEvent{
+		Target:      fmt.Sprintf("dc%d", n),
+		TargetType:  model.ActivationTargetDatacenter,
+		ActivatedBy: "api",
+		Timestamp:   time.Date(2024, 1, 1, 0, n, 0, 0, time.UTC),
+	}
+}
+
+func TestAppendActivationEventPrunes(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		appended   int
+		want       []int // Events left in etcd, oldest first
+	}{
+		{name: "below the maximum", maxEntries: 5, appended: 3, want: []int{1, 2, 3}},
+		{name: "at the maximum", maxEntries: 3, appended: 3, want: []int{1, 2, 3}},
+		{name: "oldest entries are pruned", maxEntries: 2, appended: 5, want: []int{4, 5}},
+		{name: "zero keeps everything", maxEntries: 0, appended: 4, want: []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeEtcdClient(t)
+			client.maxHistoryEntries = tt.maxEntries
+
+			for i := 1; i <= tt.appended; i++ {
+				if err := client.AppendActivationEvent(context.Background(), historyEvent(i)); err != nil {
+					t.Fatalf("AppendActivationEvent() error = %v", err)
+				}
+			}
+
+			var want []string
+			for _, n := range tt.want {
+				want = append(want, fmt.Sprintf("/test/history/%020d", historyEvent(n).Timestamp.UnixNano()))
+			}
+			if got := fake.keys(); !slices.Equal(got, want) {
+				t.Errorf("keys = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListActivationEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		malformed bool
+		want      []string
+	}{
+		{name: "newest first", limit: 0, want: []string{"dc3", "dc2", "dc1"}},
+		{name: "limited", limit: 2, want: []string{"dc3", "dc2"}},
+		{name: "limit above the count", limit: 10, want: []string{"dc3", "dc2", "dc1"}},
+		{name: "malformed entries are skipped", limit: 0, malformed: true, want: []string{"dc3", "dc2", "dc1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeEtcdClient(t)
+			for i := 1; i <= 3; i++ {
+				if err := client.AppendActivationEvent(context.Background(), historyEvent(i)); err != nil {
+					t.Fatalf("AppendActivationEvent() error = %v", err)
+				}
+			}
+			if tt.malformed {
+				if _, err := fake.Put(context.Background(), "/test/history/00000000000000000002", "{not json"); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+
+			events, err := client.ListActivationEvents(context.Background(), tt.limit)
+			if err != nil {
+				t.Fatalf("ListActivationEvents() error = %v", err)
+			}
+
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Target)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
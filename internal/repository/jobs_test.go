@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// jobResponses serves n jobs listed in reverse ID order, each with a summary taking delay
+func jobResponses(n int, delay time.Duration) map[string]fakeResponse {
+	var stubs []nomad.JobListStub
+	responses := make(map[string]fakeResponse, n+1)
+	for i := n - 1; i >= 0; i-- {
+		id := fmt.Sprintf("job-%02d", i)
+		stubs = append(stubs, nomad.JobListStub{ID: id, Namespace: "default"})
+		responses["GET /v1/job/"+id+"/summary"] = fakeResponse{
+			body:  nomad.JobSummary{JobID: id, Summary: map[string]nomad.TaskGroupSummary{"web": {Running: 1}}},
+			delay: delay,
+		}
+	}
+	responses["GET /v1/jobs"] = fakeResponse{body: stubs}
+	return responses
+}
+
+func TestListJobsSummaries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]fakeResponse
+		want      []model.Job
+	}{
+		{
+			name: "counts summed across task groups",
+			responses: map[string]fakeResponse{
+				"GET /v1/jobs": {body: []nomad.JobListStub{{ID: "api", Namespace: "default", Status: "running"}}},
+				"GET /v1/job/api/summary": {body: nomad.JobSummary{JobID: "api", Summary: map[string]nomad.TaskGroupSummary{
+					"web":    {Running: 2, Starting: 1, Failed: 1},
+					"worker": {Running: 1, Queued: 1, Lost: 1},
+				}}},
+			},
+			want: []model.Job{{ID: "api", Namespace: "default", Status: "running", Running: 3, Desired: 5, Failed: 2}},
+		},
+		{
+			name: "summary failure falls back to basic info",
+			responses: map[string]fakeResponse{
+				"GET /v1/jobs":            {body: []nomad.JobListStub{{ID: "api", Namespace: "default", Status: "running"}, {ID: "web", Namespace: "default"}}},
+				"GET /v1/job/api/summary": {status: 500},
+				"GET /v1/job/web/summary": {body: nomad.JobSummary{JobID: "web", Summary: map[string]nomad.TaskGroupSummary{"web": {Running: 1}}}},
+			},
+			want: []model.Job{
+				{ID: "api", Namespace: "default", Status: "running"},
+				{ID: "web", Namespace: "default", Running: 1, Desired: 1},
+			},
+		},
+		{
+			name: "sorted by ID regardless of completion order",
+			responses: map[string]fakeResponse{
+				"GET /v1/jobs":          {body: []nomad.JobListStub{{ID: "c", Namespace: "default"}, {ID: "a", Namespace: "default"}, {ID: "b", Namespace: "default"}}},
+				"GET /v1/job/a/summary": {body: nomad.JobSummary{JobID: "a"}, delay: 30 * time.Millisecond},
+				"GET /v1/job/b/summary": {body: nomad.JobSummary{JobID: "b"}, delay: 15 * time.Millisecond},
+				"GET /v1/job/c/summary": {body: nomad.JobSummary{JobID: "c"}},
+			},
+			want: []model.Job{{ID: "a", Namespace: "default"}, {ID: "b", Namespace: "default"}, {ID: "c", Namespace: "default"}},
+		},
+		{
+			name:      "no jobs",
+			responses: map[string]fakeResponse{"GET /v1/jobs": {body: []nomad.JobListStub{}}},
+			want:      []model.Job{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, srv := newFakeNomad(t, tt.responses)
+			repo := newTestNomadRepository(t, srv)
+
+			jobs, err := repo.ListJobs(context.Background(), "dc1", "")
+			if err != nil {
+				t.Fatalf("ListJobs() error = %v", err)
+			}
+			if len(jobs) != len(tt.want) {
+				t.Fatalf("jobs = %+v, want %+v", jobs, tt.want)
+			}
+			for i := range jobs {
+				if !jobEqual(jobs[i], tt.want[i]) {
+					t.Errorf("jobs[%d] = %+v, want %+v", i, jobs[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestListJobsParallelSummaries checks summaries are fetched concurrently, not one after another
+func TestListJobsParallelSummaries(t *testing.T) {
+	const (
+		count = 2 * maxConcurrentJobSummaries
+		delay = 50 * time.Millisecond
+	)
+	_, srv := newFakeNomad(t, jobResponses(count, delay))
+	repo := newTestNomadRepository(t, srv)
+
+	start := time.Now()
+	jobs, err := repo.ListJobs(context.Background(), "dc1", "")
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+
+	if len(jobs) != count {
+		t.Fatalf("%d jobs, want %d", len(jobs), count)
+	}
+	if !slices.IsSortedFunc(jobs, func(a, b model.Job) int { return strings.Compare(a.ID, b.ID) }) {
+		t.Error("jobs aren't sorted by ID")
+	}
+	// Sequential fetching takes count*delay (1s); two bounded batches take about 2*delay
+	if sequential := count * delay; elapsed >= sequential/2 {
+		t.Errorf("ListJobs() took %v, want well under the sequential %v", elapsed, sequential)
+	}
+}
+
+func BenchmarkListJobs(b *testing.B) {
+	_, srv := newFakeNomad(b, jobResponses(50, 5*time.Millisecond))
+	repo := newTestNomadRepository(b, srv)
+
+	for b.Loop() {
+		if _, err := repo.ListJobs(context.Background(), "dc1", ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func jobEqual(a, b model.Job) bool {
+	return a.ID == b.ID && a.Namespace == b.Namespace && a.Status == b.Status &&
+		a.Running == b.Running && a.Desired == b.Desired && a.Failed == b.Failed
+}
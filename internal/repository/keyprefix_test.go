@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestNewEtcdKeys(t *testing.T) {
+	keys := newEtcdKeys("prod/dc-switcher/")
+
+	want := etcdKeys{
+		activeDatacenter: "prod/dc-switcher/active-datacenter",
+		heartbeatPrefix:  "prod/dc-switcher/heartbeats/",
+		historyPrefix:    "prod/dc-switcher/history/",
+		healthFailures:   "prod/dc-switcher/healthcheck/failures/",
+	}
+	if keys != want {
+		t.Errorf("newEtcdKeys() = %+v, want %+v", keys, want)
+	}
+}
+
+func TestKeyPrefixIsolatesDeployments(t *testing.T) {
+	ctx := context.Background()
+	prod, fake := newFakeEtcdClient(t)
+	staging := fake.newClient(t)
+	staging.keys = newEtcdKeys("/staging/")
+
+	if err := prod.WriteActiveDatacenter(ctx, &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"}); err != nil {
+		t.Fatalf("WriteActiveDatacenter() error = %v", err)
+	}
+	if err := prod.WriteHeartbeat(ctx, "dc1"); err != nil {
+		t.Fatalf("WriteHeartbeat() error = %v", err)
+	}
+	if err := prod.AppendActivationEvent(ctx, &model.ActivationEvent{Target: "dc1", TargetType: "datacenter", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("AppendActivationEvent() error = %v", err)
+	}
+	if err := prod.WriteHealthFailures(ctx, "dc1", &model.HealthFailureCounts{ActiveRegion: "eu", Failures: map[string]int{"eu": 1}}); err != nil {
+		t.Fatalf("WriteHealthFailures() error = %v", err)
+	}
+
+	for _, key := range fake.keys() {
+		if !strings.HasPrefix(key, "/test/") {
+			t.Errorf("key %q written outside the deployment prefix /test/", key)
+		}
+	}
+
+	// The staging deployment shares the etcd cluster but sees none of it
+	if _, err := staging.ReadActiveDatacenter(ctx); !errors.Is(err, ErrNoActiveDatacenter) {
+		t.Errorf("staging ReadActiveDatacenter() error = %v, want %v", err, ErrNoActiveDatacenter)
+	}
+	if hb, err := staging.ReadHeartbeat(ctx, "dc1"); err == nil {
+		t.Errorf("staging ReadHeartbeat() = %+v, want no heartbeat", hb)
+	}
+	if events, err := staging.ListActivationEvents(ctx, 10); err != nil || len(events) != 0 {
+		t.Errorf("staging ListActivationEvents() = %+v, %v, want no events", events, err)
+	}
+	if counts, err := staging.ReadHealthFailures(ctx, "dc1"); err != nil || counts != nil {
+		t.Errorf("staging ReadHealthFailures() = %+v, %v, want nil", counts, err)
+	}
+
+	// Writes of the staging deployment leave the production record alone
+	if err := staging.WriteActiveDatacenter(ctx, &model.ActiveDatacenter{Datacenter: "dc2", Region: "us"}); err != nil {
+		t.Fatalf("staging WriteActiveDatacenter() error = %v", err)
+	}
+	active, err := prod.ReadActiveDatacenter(ctx)
+	if err != nil || active.Datacenter != "dc1" {
+		t.Errorf("prod ReadActiveDatacenter() = %+v, %v, want dc1", active, err)
+	}
+}
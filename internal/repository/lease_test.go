@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestActiveDatacenterLease(t *testing.T) {
+	tests := []struct {
+		name     string
+		leaseTTL time.Duration
+		steps    func(t *testing.T, client *etcdClient, fake *fakeEtcd)
+		wantKey  bool  // Active datacenter key exists at the end
+		wantTTL  int64 // TTL of the lease attached to the key, 0 for none
+	}{
+		{
+			name:     "no lease when disabled",
+			leaseTTL: 0,
+			steps: func(t *testing.T, client *etcdClient, _ *fakeEtcd) {
+				writeActive(t, client, "dc1")
+			},
+			wantKey: true,
+		},
+		{
+			name:     "write grants a lease rounded up to seconds",
+			leaseTTL: 1500 * time.Millisecond,
+			steps: func(t *testing.T, client *etcdClient, _ *fakeEtcd) {
+				writeActive(t, client, "dc1")
+			},
+			wantKey: true,
+			wantTTL: 2,
+		},
+		{
+			name:     "writes reuse the lease",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				first := fake.get(client.keys.activeDatacenter).Lease
+				writeActive(t, client, "dc1")
+				if got := fake.get(client.keys.activeDatacenter).Lease; got != first {
+					t.Errorf("lease = %d after the second write, want %d", got, first)
+				}
+			},
+			wantKey: true,
+			wantTTL: 60,
+		},
+		{
+			name:     "renewal keeps the key",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, _ *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				if err := client.RenewActiveDatacenterLease(context.Background()); err != nil {
+					t.Fatalf("RenewActiveDatacenterLease() error = %v", err)
+				}
+			},
+			wantKey: true,
+			wantTTL: 60,
+		},
+		{
+			name:     "expired lease drops the key and reports the loss",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				fake.expireLease(client.leaseID)
+				if err := client.RenewActiveDatacenterLease(context.Background()); !errors.Is(err, ErrLeaseLost) {
+					t.Fatalf("RenewActiveDatacenterLease() error = %v, want ErrLeaseLost", err)
+				}
+				if client.leaseID != clientv3.NoLease {
+					t.Errorf("lost lease %d is still used", client.leaseID)
+				}
+			},
+		},
+		{
+			name:     "write after a lost lease grants a new one",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				lost := client.leaseID
+				fake.expireLease(lost)
+				_ = client.RenewActiveDatacenterLease(context.Background())
+				writeActive(t, client, "dc1")
+				if client.leaseID == lost {
+					t.Errorf("write reused the lost lease %d", lost)
+				}
+			},
+			wantKey: true,
+			wantTTL: 60,
+		},
+		{
+			name:     "write retries with a new lease when the lease expired unnoticed",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				lost := client.leaseID
+				fake.expireLease(lost)
+				writeActive(t, client, "dc1")
+				if client.leaseID == lost {
+					t.Errorf("write reused the lost lease %d", lost)
+				}
+			},
+			wantKey: true,
+			wantTTL: 60,
+		},
+		{
+			name:     "detached lease leaves later writes unleased",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				leased := client.leaseID
+				client.DetachActiveDatacenterLease()
+				writeActive(t, client, "dc2")
+				// The key outlives the instance's lease
+				fake.expireLease(leased)
+			},
+			wantKey: true,
+		},
+		{
+			name:     "detached lease leaves later claims unleased",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc1")
+				client.DetachActiveDatacenterLease()
+				kv := fake.get(client.keys.activeDatacenter)
+				claimed, _, err := client.TryClaimActiveDatacenter(context.Background(), &model.ActiveDatacenter{Datacenter: "dc2"}, kv.ModRevision)
+				if err != nil || !claimed {
+					t.Fatalf("TryClaimActiveDatacenter() = %v, %v, want a claim", claimed, err)
+				}
+			},
+			wantKey: true,
+		},
+		{
+			name:     "renewal without a lease is a no-op",
+			leaseTTL: time.Minute,
+			steps: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				if err := client.RenewActiveDatacenterLease(context.Background()); err != nil {
+					t.Fatalf("RenewActiveDatacenterLease() error = %v", err)
+				}
+				if fake.requests != 0 {
+					t.Errorf("%d etcd requests, want none", fake.requests)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeEtcdClient(t)
+			client.leaseTTL = tt.leaseTTL
+
+			tt.steps(t, client, fake)
+
+			kv := fake.get(client.keys.activeDatacenter)
+			if (kv != nil) != tt.wantKey {
+				t.Fatalf("active datacenter key exists = %v, want %v", kv != nil, tt.wantKey)
+			}
+			if kv == nil {
+				return
+			}
+			ttl := int64(0)
+			if kv.Lease != 0 {
+				ttl = fake.leases[clientv3.LeaseID(kv.Lease)]
+			}
+			if ttl != tt.wantTTL {
+				t.Errorf("key lease TTL = %d, want %d", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestRenewActiveDatacenterLeaseTimeout(t *testing.T) {
+	client, fake := newFakeEtcdClient(t)
+	client.leaseTTL = time.Minute
+	writeActive(t, client, "dc1")
+	leaseID := client.leaseID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fake.err = context.Canceled
+
+	// A request that didn't reach etcd says nothing about the lease
+	if err := client.RenewActiveDatacenterLease(ctx); err == nil || errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("RenewActiveDatacenterLease() error = %v, want a non lease loss error", err)
+	}
+	if client.leaseID != leaseID {
+		t.Errorf("lease %d dropped after a cancelled renewal", leaseID)
+	}
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+func TestListJobsNamespace(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		jobs          []nomad.JobListStub
+		wantJobs      map[string]string // job ID -> namespace
+		wantNamespace []string          // sorted
+	}{
+		{
+			name:      "configured namespace",
+			namespace: "team-a",
+			jobs:      []nomad.JobListStub{{ID: "api", Namespace: "team-a"}},
+			wantJobs:  map[string]string{"api": "team-a"},
+			wantNamespace: []string{
+				"GET /v1/job/api/summary team-a",
+				"GET /v1/jobs team-a",
+			},
+		},
+		{
+			name:      "all namespaces",
+			namespace: nomad.AllNamespacesNamespace,
+			jobs:      []nomad.JobListStub{{ID: "api", Namespace: "team-a"}, {ID: "web", Namespace: "team-b"}},
+			wantJobs:  map[string]string{"api": "team-a", "web": "team-b"},
+			wantNamespace: []string{
+				"GET /v1/job/api/summary team-a",
+				"GET /v1/job/web/summary team-b",
+				"GET /v1/jobs *",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, map[string]fakeResponse{
+				"GET /v1/jobs":            {body: tt.jobs},
+				"GET /v1/job/api/summary": {body: nomad.JobSummary{JobID: "api"}},
+				"GET /v1/job/web/summary": {body: nomad.JobSummary{JobID: "web"}},
+			})
+			repo := newTestNomadRepository(t, srv)
+			repo.clusters["dc1"].namespace = tt.namespace
+
+			jobs, err := repo.ListJobs(context.Background(), "dc1", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make(map[string]string, len(jobs))
+			for _, job := range jobs {
+				got[job.ID] = job.Namespace
+			}
+			if len(got) != len(tt.wantJobs) {
+				t.Fatalf("jobs = %v, want %v", got, tt.wantJobs)
+			}
+			for id, ns := range tt.wantJobs {
+				if got[id] != ns {
+					t.Errorf("job %s namespace = %q, want %q", id, got[id], ns)
+				}
+			}
+
+			calls := fake.namespaces()
+			slices.Sort(calls)
+			if !equalCalls(calls, tt.wantNamespace) {
+				t.Errorf("requests = %v, want %v", calls, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+func TestJobActionsNamespace(t *testing.T) {
+	jobID := "api"
+
+	tests := []struct {
+		name      string
+		namespace string
+		listed    []nomad.JobListStub
+		action    func(repo *nomadRepository) error
+		wantErr   error
+		wantAny   bool
+		wantCalls []string
+	}{
+		{
+			name:      "stop in the configured namespace",
+			namespace: "team-a",
+			action: func(repo *nomadRepository) error {
+				return repo.StopJob(context.Background(), "dc1", "api", false)
+			},
+			wantCalls: []string{"DELETE /v1/job/api team-a"},
+		},
+		{
+			name:      "start in the configured namespace",
+			namespace: "team-a",
+			action: func(repo *nomadRepository) error {
+				return repo.StartJob(context.Background(), "dc1", "api")
+			},
+			wantCalls: []string{"GET /v1/job/api team-a", "PUT /v1/jobs team-a"},
+		},
+		{
+			name:      "start resolves the namespace of the job",
+			namespace: nomad.AllNamespacesNamespace,
+			listed:    []nomad.JobListStub{{ID: "api", Namespace: "team-b"}, {ID: "api-v2", Namespace: "team-a"}},
+			action: func(repo *nomadRepository) error {
+				return repo.StartJob(context.Background(), "dc1", "api")
+			},
+			wantCalls: []string{"GET /v1/jobs *", "GET /v1/job/api team-b", "PUT /v1/jobs team-b"},
+		},
+		{
+			name:      "stop resolves the namespace of the job",
+			namespace: nomad.AllNamespacesNamespace,
+			listed:    []nomad.JobListStub{{ID: "api", Namespace: "team-b"}},
+			action: func(repo *nomadRepository) error {
+				return repo.StopJob(context.Background(), "dc1", "api", true)
+			},
+			wantCalls: []string{"GET /v1/jobs *", "DELETE /v1/job/api team-b"},
+		},
+		{
+			name:      "job in no namespace",
+			namespace: nomad.AllNamespacesNamespace,
+			listed:    []nomad.JobListStub{{ID: "api-v2", Namespace: "team-a"}},
+			action: func(repo *nomadRepository) error {
+				return repo.StopJob(context.Background(), "dc1", "api", false)
+			},
+			wantErr:   ErrJobNotFound,
+			wantCalls: []string{"GET /v1/jobs *"},
+		},
+		{
+			name:      "job in several namespaces",
+			namespace: nomad.AllNamespacesNamespace,
+			listed:    []nomad.JobListStub{{ID: "api", Namespace: "team-a"}, {ID: "api", Namespace: "team-b"}},
+			action: func(repo *nomadRepository) error {
+				return repo.StopJob(context.Background(), "dc1", "api", false)
+			},
+			wantAny:   true,
+			wantCalls: []string{"GET /v1/jobs *"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, map[string]fakeResponse{
+				"GET /v1/jobs":       {body: tt.listed},
+				"GET /v1/job/api":    {body: nomad.Job{ID: &jobID}},
+				"PUT /v1/jobs":       {body: nomad.JobRegisterResponse{}},
+				"DELETE /v1/job/api": {body: nomad.JobDeregisterResponse{}},
+			})
+			repo := newTestNomadRepository(t, srv)
+			repo.clusters["dc1"].namespace = tt.namespace
+
+			err := tt.action(repo)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAny:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls := fake.namespaces(); !equalCalls(calls, tt.wantCalls) {
+				t.Errorf("requests = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTriggerJobEvaluationsNamespace(t *testing.T) {
+	fake, srv := newFakeNomad(t, map[string]fakeResponse{
+		"GET /v1/jobs": {body: []nomad.JobListStub{
+			{ID: "api", Namespace: "team-a", Status: "running"},
+			{ID: "web", Namespace: "team-b", Status: "running"},
+			{ID: "old", Namespace: "team-a", Status: "dead"},
+		}},
+		"PUT /v1/job/api/evaluate": {body: nomad.JobRegisterResponse{}},
+		"PUT /v1/job/web/evaluate": {body: nomad.JobRegisterResponse{}},
+	})
+	repo := newTestNomadRepository(t, srv)
+	repo.clusters["dc1"].namespace = nomad.AllNamespacesNamespace
+	repo.evalCfg = config.EvaluationsConfig{MaxConcurrent: 2}
+
+	if err := repo.TriggerJobEvaluations(context.Background(), "dc1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := fake.namespaces()
+	slices.Sort(calls)
+	want := []string{"GET /v1/jobs *", "PUT /v1/job/api/evaluate team-a", "PUT /v1/job/web/evaluate team-b"}
+	if !equalCalls(calls, want) {
+		t.Errorf("requests = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestCacheNodeAddresses(t *testing.T) {
+	responses := map[string]fakeResponse{
+		"GET /v1/nodes":   {body: []nomad.NodeListStub{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}, {ID: "n4"}}},
+		"GET /v1/node/n1": {body: nomad.Node{ID: "n1", Name: "node-1", HTTPAddr: "10.0.0.1:4646"}},
+		"GET /v1/node/n2": {body: nomad.Node{ID: "n2", Name: "node-2", HTTPAddr: "10.0.0.2:4646"}},
+		"GET /v1/node/n3": {status: http.StatusInternalServerError},
+		"GET /v1/node/n4": {body: nomad.Node{ID: "n4", Name: "node-4"}}, // No address to cache
+	}
+	_, srv := newFakeNomad(t, responses)
+	repo := newTestNomadRepository(t, srv)
+	meta := repo.clusters["dc1"]
+
+	if err := cacheNodeAddresses(meta, slog.New(slog.DiscardHandler)); err != nil {
+		t.Fatalf("cacheNodeAddresses() error = %v", err)
+	}
+
+	want := map[string]nodeCache{
+		"n1": {HTTPAddr: "10.0.0.1:4646", Name: "node-1"},
+		"n2": {HTTPAddr: "10.0.0.2:4646", Name: "node-2"},
+	}
+	for _, id := range []string{"n1", "n2", "n3", "n4"} {
+		got, ok := meta.getNode(id)
+		wantNode, wantOK := want[id]
+		if ok != wantOK {
+			t.Errorf("%s cached = %v, want %v", id, ok, wantOK)
+			continue
+		}
+		if ok && *got != wantNode {
+			t.Errorf("%s cached as %+v, want %+v", id, *got, wantNode)
+		}
+	}
+}
+
+func TestCacheNodeAddressesListFails(t *testing.T) {
+	_, srv := newFakeNomad(t, map[string]fakeResponse{
+		"GET /v1/nodes": {status: http.StatusInternalServerError},
+	})
+	repo := newTestNomadRepository(t, srv)
+
+	if err := cacheNodeAddresses(repo.clusters["dc1"], slog.New(slog.DiscardHandler)); err == nil {
+		t.Error("cacheNodeAddresses() succeeded although the node list failed")
+	}
+}
+
+func TestFetchNodeInfosInParallel(t *testing.T) {
+	const (
+		nodes = 2 * maxConcurrentNodeInfos
+		delay = 100 * time.Millisecond
+	)
+
+	responses := make(map[string]fakeResponse, nodes)
+	nodeIDs := make([]string, 0, nodes)
+	for i := range nodes {
+		id := fmt.Sprintf("n%d", i)
+		nodeIDs = append(nodeIDs, id)
+		responses["GET /v1/node/"+id] = fakeResponse{body: nomad.Node{ID: id}, delay: delay}
+	}
+	_, srv := newFakeNomad(t, responses)
+	repo := newTestNomadRepository(t, srv)
+
+	start := time.Now()
+	results := fetchNodeInfos(context.Background(), repo.clusters["dc1"], nodeIDs)
+	elapsed := time.Since(start)
+
+	// Two rounds of maxConcurrentNodeInfos requests, far below the time of one request after another
+	if limit := nodes * delay / 2; elapsed >= limit {
+		t.Errorf("fetchNodeInfos() took %v for %d nodes, want well under %v", elapsed, nodes, limit)
+	}
+	if len(results) != nodes {
+		t.Fatalf("got %d results, want %d", len(results), nodes)
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			t.Errorf("%s: %v", nodeIDs[i], result.Error)
+			continue
+		}
+		if result.Value.ID != nodeIDs[i] {
+			t.Errorf("result %d is node %s, want %s in request order", i, result.Value.ID, nodeIDs[i])
+		}
+	}
+}
+
+func TestSetNodeDrainDirectCachesMissingNode(t *testing.T) {
+	client, clientSrv := newFakeNomad(t, map[string]fakeResponse{
+		"POST /v1/node/self/drain": {body: map[string]any{}},
+	})
+	clientAddr := strings.TrimPrefix(clientSrv.URL, "http://")
+	server, srv := newFakeNomad(t, map[string]fakeResponse{
+		"PUT /v1/node/n1/drain": {status: http.StatusInternalServerError},
+		"GET /v1/node/n1":       {body: nomad.Node{ID: "n1", Name: "node-1", HTTPAddr: clientAddr}},
+	})
+
+	repo := newTestNomadRepository(t, srv)
+	repo.nomadCfg.EnableDirectFallback = true
+	repo.nomadCfg.DirectFallbackTimeout = time.Second
+	meta := repo.clusters["dc1"]
+	if _, ok := meta.getNode("n1"); ok {
+		t.Fatal("node cache is not empty")
+	}
+
+	if err := repo.SetNodeDrain(context.Background(), "dc1", "n1", true, model.DrainOptions{Deadline: -1}); err != nil {
+		t.Fatalf("SetNodeDrain() error = %v", err)
+	}
+
+	if calls := client.calls(); !equalCalls(calls, []string{"POST /v1/node/self/drain"}) {
+		t.Errorf("client calls = %v, want the direct drain", calls)
+	}
+	if calls := server.calls(); calls[len(calls)-1] != "GET /v1/node/n1" {
+		t.Errorf("server calls = %v, want the node looked up after the failed drain", calls)
+	}
+	got, ok := meta.getNode("n1")
+	if !ok {
+		t.Fatal("node address was not cached")
+	}
+	if want := (nodeCache{HTTPAddr: clientAddr, Name: "node-1"}); *got != want {
+		t.Errorf("cached %+v, want %+v", *got, want)
+	}
+
+	// The next fallback uses the cached address without looking the node up again
+	lookups := len(server.calls())
+	if err := repo.SetNodeDrain(context.Background(), "dc1", "n1", false, model.DrainOptions{}); err != nil {
+		t.Fatalf("second SetNodeDrain() error = %v", err)
+	}
+	for _, call := range server.calls()[lookups:] {
+		if call == "GET /v1/node/n1" {
+			t.Error("node looked up again although its address was cached")
+		}
+	}
+}
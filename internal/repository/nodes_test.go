@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestListNodesMapsNomadFields(t *testing.T) {
+	_, srv := newFakeNomad(t, map[string]fakeResponse{
+		"GET /v1/nodes": {body: []nomad.NodeListStub{
+			{
+				ID:                    "n1",
+				Name:                  "worker-1",
+				Drain:                 true,
+				SchedulingEligibility: "ineligible",
+				Status:                "ready",
+				Datacenter:            "dc1",
+				NodeClass:             "gpu",
+				Version:               "1.9.3",
+				Address:               "10.0.0.1",
+			},
+			{ID: "n2", Name: "worker-2", SchedulingEligibility: "eligible", Status: "down", Datacenter: "dc1", Version: "1.8.0", Address: "10.0.0.2"},
+		}},
+	})
+	repo := newTestNomadRepository(t, srv)
+
+	nodes, err := repo.ListNodes(context.Background(), "dc1")
+	if err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+
+	want := []model.Node{
+		{
+			ID:                    "n1",
+			Name:                  "worker-1",
+			Drain:                 true,
+			SchedulingEligibility: "ineligible",
+			Status:                "ready",
+			Datacenter:            "dc1",
+			NodeClass:             "gpu",
+			Version:               "1.9.3",
+			Address:               "10.0.0.1",
+		},
+		{ID: "n2", Name: "worker-2", SchedulingEligibility: "eligible", Status: "down", Datacenter: "dc1", Version: "1.8.0", Address: "10.0.0.2"},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("ListNodes() = %+v, want %+v", nodes, want)
+	}
+	for i := range want {
+		if nodes[i].AllocCount != nil || nodes[i].Region != "" {
+			t.Errorf("node %s has listing-only fields set: %+v", nodes[i].ID, nodes[i])
+		}
+		if nodes[i] != want[i] {
+			t.Errorf("node %d = %+v, want %+v", i, nodes[i], want[i])
+		}
+	}
+
+	// Nodes without a class leave the field out of the API response
+	data, err := json.Marshal(nodes[1])
+	if err != nil {
+		t.Fatalf("marshal node: %v", err)
+	}
+	if strings.Contains(string(data), "node_class") {
+		t.Errorf("node without a class encoded as %s", data)
+	}
+	data, err = json.Marshal(nodes[0])
+	if err != nil {
+		t.Fatalf("marshal node: %v", err)
+	}
+	for _, field := range []string{`"node_class":"gpu"`, `"datacenter":"dc1"`, `"version":"1.9.3"`, `"address":"10.0.0.1"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("node encoded as %s, want %s", data, field)
+		}
+	}
+}
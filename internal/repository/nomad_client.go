@@ -2,36 +2,66 @@ package repository
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	nomad "github.com/hashicorp/nomad/api"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/util"
 )
 
+// maxConcurrentJobSummaries bounds parallel job summary requests per ListJobs call
+const maxConcurrentJobSummaries = 10
+
+// maxJobDetailAllocations bounds the recent allocations reported by GetJob
+const maxJobDetailAllocations = 20
+
+// maxConcurrentNodeInfos bounds parallel node info requests when fetching full node details
+const maxConcurrentNodeInfos = 10
+
+// nodeInfoTimeout bounds a single node info request
+const nodeInfoTimeout = 10 * time.Second
+
 // NomadRepository defines the interface for Nomad API operations
 type NomadRepository interface {
 	ListNodes(ctx context.Context, clusterName string) ([]model.Node, error)
-	SetNodeDrain(ctx context.Context, clusterName, nodeID string, drain bool) error
-	CheckLeader(ctx context.Context, clusterName string) (bool, error)
+	ListNodeAllocations(ctx context.Context, clusterName, nodeID string) ([]model.Allocation, error)
+	SetNodeDrain(ctx context.Context, clusterName, nodeID string, drain bool, opts model.DrainOptions) error
+	CheckLeader(ctx context.Context, clusterName string) (leader string, hasLeader bool, err error)
+	CheckClusterHealth(ctx context.Context, clusterName string) (*model.ClusterHealth, error)
 	GetClusterNames() []string
 	GetClusterRegion(clusterName string) (string, error)
+	IsClusterEnabled(clusterName string) bool
 	GetClustersByRegion(region string) []string
 	GetAllRegions() []string
 	TriggerJobEvaluations(ctx context.Context, clusterName string) error
-	ListJobs(ctx context.Context, clusterName string) ([]model.Job, error)
+	ListJobs(ctx context.Context, clusterName, prefix string) ([]model.Job, error)
 	StartJob(ctx context.Context, clusterName, jobID string) error
-	StopJob(ctx context.Context, clusterName, jobID string) error
+	StopJob(ctx context.Context, clusterName, jobID string, purge bool) error
+	RestartJob(ctx context.Context, clusterName, jobID string) (wasStopped bool, err error)
+	GetJob(ctx context.Context, clusterName, jobID string) (*model.JobDetail, error)
 	RetryUnavailableClusters() int
+	ReloadClusters(cfg *config.Config) ClusterReloadResult
+}
+
+// ClusterReloadResult lists the cluster keys affected by a ReloadClusters call
+type ClusterReloadResult struct {
+	Added   []string // New clusters connected and serving
+	Updated []string // Existing clusters whose client was rebuilt from the new config
+	Removed []string // Clusters no longer present in the config
+	Failed  []string // Addresses that could not be (re)connected; existing clients were kept
 }
 
 // nodeCache stores cached information about a node for direct API access
@@ -42,17 +72,42 @@ type nodeCache struct {
 
 // clusterMetadata stores metadata about a cluster
 type clusterMetadata struct {
-	name       string
-	region     string
-	client     *nomad.Client
-	httpClient *http.Client          // HTTP client with TLS config for direct API calls
-	nodeCache  map[string]*nodeCache // nodeID -> nodeCache
+	name        string
+	address     string // Configured address, identifies the cluster across config reloads
+	region      string
+	namespace   string // Nomad namespace for job operations ("*" for all namespaces)
+	enabled     bool   // Disabled clusters are listed but skipped by activation drain logic
+	client      *nomad.Client
+	httpClient  *http.Client          // HTTP client with TLS config for direct API calls
+	headers     http.Header           // Configured extra headers, also sent with direct API calls
+	nodeCache   map[string]*nodeCache // nodeID -> nodeCache, only accessed through getNode and setNode
+	nodeCacheMu sync.RWMutex          // Guards nodeCache, which parallel drains read and lazy lookups write
+}
+
+// getNode returns the cached address information of a node
+func (m *clusterMetadata) getNode(nodeID string) (*nodeCache, bool) {
+	m.nodeCacheMu.RLock()
+	defer m.nodeCacheMu.RUnlock()
+	node, ok := m.nodeCache[nodeID]
+	return node, ok
+}
+
+// setNode caches the address information of a node
+func (m *clusterMetadata) setNode(nodeID string, node *nodeCache) {
+	m.nodeCacheMu.Lock()
+	defer m.nodeCacheMu.Unlock()
+	m.nodeCache[nodeID] = node
 }
 
 // nomadRepository implements NomadRepository interface
 type nomadRepository struct {
+	mu                  sync.RWMutex // Guards clusters
 	clusters            map[string]*clusterMetadata
-	unavailableClusters []config.ClusterConfig // Clusters that failed health check at startup
+	updateMu            sync.Mutex             // Serializes cluster set updates (retry and reload)
+	unavailableClusters []config.ClusterConfig // Clusters that failed health check at startup, guarded by updateMu
+	retryCfg            config.RetryConfig     // Retry policy for Server API drain updates
+	nomadCfg            config.NomadConfig     // Direct Client API fallback settings
+	evalCfg             config.EvaluationsConfig
 	logger              *slog.Logger
 }
 
@@ -97,7 +152,7 @@ func NewNomadRepository(cfg *config.Config, logger *slog.Logger) (NomadRepositor
 		region := cluster.Region
 
 		if name == "" || region == "" {
-			detectedName, detectedRegion, err := detectClusterInfo(client)
+			detectedName, detectedRegion, err := detectClusterInfo(client, logger)
 			if err != nil {
 				logger.Warn("failed to auto-detect cluster info, using fallback values",
 					slog.String("address", cluster.Address),
@@ -142,18 +197,24 @@ func NewNomadRepository(cfg *config.Config, logger *slog.Logger) (NomadRepositor
 
 		metadata := &clusterMetadata{
 			name:       clusterKey, // Use unique key as name
+			address:    cluster.Address,
 			region:     region,
+			namespace:  cluster.Namespace,
 			client:     client,
 			httpClient: httpClient,
+			headers:    extraHeaders(cluster),
+			enabled:    cluster.IsEnabled(),
 			nodeCache:  make(map[string]*nodeCache),
 		}
 
 		// Cache node addresses for fallback direct API access
-		if err := cacheNodeAddresses(metadata, logger); err != nil {
-			logger.Warn("failed to cache node addresses, direct fallback will not be available",
-				slog.String("cluster", clusterKey),
-				slog.String("error", err.Error()),
-			)
+		if cfg.Nomad.EnableDirectFallback {
+			if err := cacheNodeAddresses(metadata, logger); err != nil {
+				logger.Warn("failed to cache node addresses, direct fallback will look nodes up on demand",
+					slog.String("cluster", clusterKey),
+					slog.String("error", err.Error()),
+				)
+			}
 		}
 
 		clusters[clusterKey] = metadata
@@ -178,6 +239,9 @@ func NewNomadRepository(cfg *config.Config, logger *slog.Logger) (NomadRepositor
 	return &nomadRepository{
 		clusters:            clusters,
 		unavailableClusters: unavailable,
+		retryCfg:            cfg.Retry,
+		nomadCfg:            cfg.Nomad,
+		evalCfg:             cfg.Evaluations,
 		logger:              logger,
 	}, nil
 }
@@ -186,6 +250,7 @@ func NewNomadRepository(cfg *config.Config, logger *slog.Logger) (NomadRepositor
 func createNomadClient(cluster config.ClusterConfig) (*nomad.Client, *http.Client, error) {
 	nomadConfig := nomad.DefaultConfig()
 	nomadConfig.Address = cluster.Address
+	nomadConfig.Headers = extraHeaders(cluster)
 
 	// Set region if specified (used for API calls)
 	if cluster.Region != "" {
@@ -223,71 +288,100 @@ func createNomadClient(cluster config.ClusterConfig) (*nomad.Client, *http.Clien
 	return client, httpClient, nil
 }
 
+// extraHeaders returns the configured extra headers of a cluster, nil when there are none
+func extraHeaders(cluster config.ClusterConfig) http.Header {
+	if len(cluster.ExtraHeaders) == 0 {
+		return nil
+	}
+
+	headers := make(http.Header, len(cluster.ExtraHeaders))
+	for name, value := range cluster.ExtraHeaders {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
 // checkClusterHealth checks if Nomad cluster is healthy and reachable
 func checkClusterHealth(client *nomad.Client) (bool, error) {
+	health, err := probeClusterHealth(client)
+	return health.Healthy, err
+}
+
+// probeClusterHealth queries the leader and the agent health of a cluster.
+// The error names the first failed check; Healthy is set only when there is none.
+func probeClusterHealth(client *nomad.Client) (model.ClusterHealth, error) {
+	var health model.ClusterHealth
+
 	// Try to get the leader - this is a simple health check
-	status := client.Status()
-	leader, err := status.Leader()
+	leader, err := client.Status().Leader()
 	if err != nil {
-		return false, fmt.Errorf("failed to get leader: %w", err)
-	}
-
-	if leader == "" {
-		return false, fmt.Errorf("no leader elected")
+		return health, fmt.Errorf("failed to get leader: %w", err)
 	}
+	health.Reachable = true
+	health.Leader = leader
+	health.HasLeader = leader != ""
 
-	// Additionally check agent health
-	agent := client.Agent()
-	health, err := agent.Health()
+	// Additionally check agent health, also without a leader so the report is complete
+	agentHealth, err := client.Agent().Health()
 	if err != nil {
-		return false, fmt.Errorf("failed to get agent health: %w", err)
+		return health, fmt.Errorf("failed to get agent health: %w", err)
 	}
 
 	// Check if agent is alive
-	if health == nil {
-		return false, fmt.Errorf("agent health response is nil")
+	if agentHealth == nil {
+		return health, fmt.Errorf("agent health response is nil")
 	}
-
-	// Check client health if present
-	if health.Client != nil && !health.Client.Ok {
-		return false, fmt.Errorf("client health check failed")
+	if agentHealth.Client != nil {
+		health.Client = &model.AgentCheck{Ok: agentHealth.Client.Ok, Message: agentHealth.Client.Message}
+	}
+	if agentHealth.Server != nil {
+		health.Server = &model.AgentCheck{Ok: agentHealth.Server.Ok, Message: agentHealth.Server.Message}
 	}
 
-	// Check server health if present
-	if health.Server != nil && !health.Server.Ok {
-		return false, fmt.Errorf("server health check failed")
+	switch {
+	case !health.HasLeader:
+		return health, fmt.Errorf("no leader elected")
+	case health.Client != nil && !health.Client.Ok:
+		return health, fmt.Errorf("client health check failed")
+	case health.Server != nil && !health.Server.Ok:
+		return health, fmt.Errorf("server health check failed")
 	}
 
-	return true, nil
+	health.Healthy = true
+	return health, nil
 }
 
 // cacheNodeAddresses fetches and caches node addresses for direct client API access
 func cacheNodeAddresses(meta *clusterMetadata, logger *slog.Logger) error {
 	// List all nodes first
-	nodeStubs, _, err := meta.client.Nodes().List(nil)
+	nodeStubs, _, err := meta.client.Nodes().List(meta.queryOptions(""))
 	if err != nil {
 		return fmt.Errorf("failed to list nodes: %w", err)
 	}
 
-	// Fetch full node info for each node to get HTTPAddr
-	cachedCount := 0
+	// Fetch full node info, which includes HTTPAddr, for all nodes in parallel
+	nodeIDs := make([]string, 0, len(nodeStubs))
 	for _, stub := range nodeStubs {
-		// Get full node info which includes HTTPAddr
-		node, _, err := meta.client.Nodes().Info(stub.ID, nil)
-		if err != nil {
+		nodeIDs = append(nodeIDs, stub.ID)
+	}
+
+	cachedCount, failedCount := 0, 0
+	for i, result := range fetchNodeInfos(context.Background(), meta, nodeIDs) {
+		if result.Error != nil {
 			logger.Warn("failed to get node info, skipping",
 				slog.String("cluster", meta.name),
-				slog.String("node_id", stub.ID),
-				slog.String("error", err.Error()),
+				slog.String("node_id", nodeIDs[i]),
+				slog.String("error", result.Error.Error()),
 			)
+			failedCount++
 			continue
 		}
 
-		if node.HTTPAddr != "" {
-			meta.nodeCache[node.ID] = &nodeCache{
+		if node := result.Value; node.HTTPAddr != "" {
+			meta.setNode(node.ID, &nodeCache{
 				HTTPAddr: node.HTTPAddr,
 				Name:     node.Name,
-			}
+			})
 			cachedCount++
 		}
 	}
@@ -296,56 +390,133 @@ func cacheNodeAddresses(meta *clusterMetadata, logger *slog.Logger) error {
 		slog.String("cluster", meta.name),
 		slog.Int("total_nodes", len(nodeStubs)),
 		slog.Int("cached_nodes", cachedCount),
+		slog.Int("failed_nodes", failedCount),
 	)
 
 	return nil
 }
 
-// detectClusterInfo queries Nomad API to detect cluster name (datacenter) and region
-func detectClusterInfo(client *nomad.Client) (string, string, error) {
-	// Get agent self information
+// fetchNodeInfos fetches the full details of nodeIDs in parallel, bounded by maxConcurrentNodeInfos,
+// with nodeInfoTimeout per request. Results keep the order of nodeIDs; failed lookups carry their error.
+func fetchNodeInfos(ctx context.Context, meta *clusterMetadata, nodeIDs []string) []concurrent.Result[*nomad.Node] {
+	return concurrent.ParallelMapWithLimit(ctx, nodeIDs, func(ctx context.Context, nodeID string) (*nomad.Node, error) {
+		ctx, cancel := context.WithTimeout(ctx, nodeInfoTimeout)
+		defer cancel()
+
+		node, _, err := meta.client.Nodes().Info(nodeID, meta.queryOptions("").WithContext(ctx))
+		return node, err
+	}, maxConcurrentNodeInfos)
+}
+
+// detectClusterInfo queries Nomad API to detect cluster name (datacenter) and region.
+// Nomad versions shape the agent self response differently, so several sources are tried in turn:
+// the agent config, the serf member tags of a server agent, the agent helper calls and finally the
+// datacenter of a registered node. The source that succeeded is logged.
+func detectClusterInfo(client *nomad.Client, logger *slog.Logger) (string, string, error) {
+	var errs []error
+
 	agent := client.Agent()
 	self, err := agent.Self()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to query agent self: %w", err)
+		errs = append(errs, fmt.Errorf("failed to query agent self: %w", err))
+	} else {
+		datacenter, region, err := clusterInfoFromConfig(self.Config)
+		if err == nil {
+			logClusterInfoSource(logger, "agent_self_config", datacenter, region)
+			return datacenter, region, nil
+		}
+		errs = append(errs, fmt.Errorf("agent self config: %w", err))
+
+		if datacenter := self.Member.Tags["dc"]; datacenter != "" {
+			region := cmp.Or(self.Member.Tags["region"], "global")
+			logClusterInfoSource(logger, "agent_member_tags", datacenter, region)
+			return datacenter, region, nil
+		}
+		errs = append(errs, fmt.Errorf("agent member tags: datacenter not found"))
 	}
 
-	// The Self() method returns *AgentSelf which has Config as map[string]interface{}
-	if self.Config == nil {
+	// The agent helpers don't depend on one config shape
+	if datacenter, err := agent.Datacenter(); err == nil && datacenter != "" {
+		region, _ := agent.Region()
+		region = cmp.Or(region, "global")
+		logClusterInfoSource(logger, "agent_helpers", datacenter, region)
+		return datacenter, region, nil
+	} else if err != nil {
+		errs = append(errs, fmt.Errorf("agent datacenter: %w", err))
+	}
+
+	// Last resort: the datacenter registered nodes report
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list nodes: %w", err))
+	} else {
+		for _, node := range nodes {
+			if node.Datacenter == "" {
+				continue
+			}
+			region, _ := agent.Region()
+			region = cmp.Or(region, "global")
+			logClusterInfoSource(logger, "node_list", node.Datacenter, region)
+			return node.Datacenter, region, nil
+		}
+		errs = append(errs, fmt.Errorf("node list: no node reports a datacenter"))
+	}
+
+	return "", "", errors.Join(errs...)
+}
+
+// clusterInfoFromConfig reads the datacenter and region from the agent self config. Besides the
+// top-level keys it looks into the Client and Server sections some Nomad versions nest them in.
+func clusterInfoFromConfig(cfg map[string]any) (string, string, error) {
+	if cfg == nil {
 		return "", "", fmt.Errorf("config section not found in agent self response")
 	}
 
-	// Extract datacenter (this is the cluster name in Nomad)
-	datacenterVal, ok := self.Config["Datacenter"]
-	if !ok {
+	datacenter := configString(cfg, "Datacenter")
+	if datacenter == "" {
 		return "", "", fmt.Errorf("datacenter not found in config")
 	}
-	datacenter, ok := datacenterVal.(string)
-	if !ok || datacenter == "" {
-		return "", "", fmt.Errorf("datacenter is not a valid string")
-	}
 
-	// Extract region
-	region := "global" // Default Nomad region
-	if regionVal, ok := self.Config["Region"]; ok {
-		if regionStr, ok := regionVal.(string); ok && regionStr != "" {
-			region = regionStr
+	region := cmp.Or(configString(cfg, "Region"), "global") // Default Nomad region
+	return datacenter, region, nil
+}
+
+// configString returns the non-empty string value of key at the top level of cfg or in its Client or Server section
+func configString(cfg map[string]any, key string) string {
+	if value, ok := cfg[key].(string); ok && value != "" {
+		return value
+	}
+	for _, section := range []string{"Client", "Server"} {
+		nested, ok := cfg[section].(map[string]any)
+		if !ok {
+			continue
+		}
+		if value, ok := nested[key].(string); ok && value != "" {
+			return value
 		}
 	}
+	return ""
+}
 
-	return datacenter, region, nil
+// logClusterInfoSource logs which source detected the cluster info
+func logClusterInfoSource(logger *slog.Logger, source, datacenter, region string) {
+	logger.Info("detected cluster info",
+		slog.String("source", source),
+		slog.String("datacenter", datacenter),
+		slog.String("region", region),
+	)
 }
 
 // ListNodes returns all nodes in the specified cluster
 func (r *nomadRepository) ListNodes(ctx context.Context, clusterName string) ([]model.Node, error) {
-	clusterMeta, ok := r.clusters[clusterName]
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return nil, fmt.Errorf("cluster %s not found", clusterName)
+		return nil, clusterNotFound(clusterName)
 	}
 
-	nodes, _, err := clusterMeta.client.Nodes().List(nil)
+	nodes, _, err := clusterMeta.client.Nodes().List(clusterMeta.queryOptions("").WithContext(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("failed to list nodes: %w", err)
+		return nil, nomadError("failed to list nodes", err)
 	}
 
 	result := make([]model.Node, 0, len(nodes))
@@ -356,6 +527,10 @@ func (r *nomadRepository) ListNodes(ctx context.Context, clusterName string) ([]
 			Drain:                 n.Drain,
 			SchedulingEligibility: n.SchedulingEligibility,
 			Status:                n.Status,
+			Datacenter:            n.Datacenter,
+			NodeClass:             n.NodeClass,
+			Version:               n.Version,
+			Address:               n.Address,
 		})
 	}
 
@@ -368,28 +543,54 @@ func (r *nomadRepository) ListNodes(ctx context.Context, clusterName string) ([]
 	return result, nil
 }
 
-// SetNodeDrain sets the drain status for a specific node
-// First tries via Server API, falls back to direct Client API if server is unavailable
-func (r *nomadRepository) SetNodeDrain(ctx context.Context, clusterName, nodeID string, drain bool) error {
-	clusterMeta, ok := r.clusters[clusterName]
+// ListNodeAllocations returns all allocations placed on a specific node
+func (r *nomadRepository) ListNodeAllocations(ctx context.Context, clusterName, nodeID string) ([]model.Allocation, error) {
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return fmt.Errorf("cluster %s not found", clusterName)
+		return nil, clusterNotFound(clusterName)
+	}
+
+	queryOpts := clusterMeta.queryOptions("").WithContext(ctx)
+	allocs, _, err := clusterMeta.client.Nodes().Allocations(nodeID, queryOpts)
+	if err != nil {
+		return nil, nomadError(fmt.Sprintf("failed to list allocations for node %s", nodeID), err)
 	}
 
-	var drainSpec *nomad.DrainSpec
-	if drain {
-		drainSpec = &nomad.DrainSpec{
-			Deadline: -1, // Infinite deadline
+	result := make([]model.Allocation, 0, len(allocs))
+	for _, a := range allocs {
+		alloc := model.Allocation{
+			ID:            a.ID,
+			JobID:         a.JobID,
+			TaskGroup:     a.TaskGroup,
+			DesiredStatus: a.DesiredStatus,
+			ClientStatus:  a.ClientStatus,
 		}
+		if a.Job != nil && a.Job.Type != nil {
+			alloc.JobType = *a.Job.Type
+		}
+		result = append(result, alloc)
 	}
 
+	return result, nil
+}
+
+// SetNodeDrain sets the drain status for a specific node
+// First tries via Server API, falls back to direct Client API if server is unavailable
+func (r *nomadRepository) SetNodeDrain(ctx context.Context, clusterName, nodeID string, drain bool, opts model.DrainOptions) error {
+	clusterMeta, ok := r.cluster(clusterName)
+	if !ok {
+		return clusterNotFound(clusterName)
+	}
+
+	drainSpec := buildDrainSpec(drain, opts)
+
 	// markEligible is the opposite of drain
 	// When enabling drain (drain=true), node becomes ineligible (markEligible=false)
 	// When disabling drain (drain=false), node becomes eligible (markEligible=true)
 	markEligible := !drain
 
-	// Try via Server API first
-	_, err := clusterMeta.client.Nodes().UpdateDrain(nodeID, drainSpec, markEligible, nil)
+	// Try via Server API first, retrying transient failures
+	err := r.updateDrainWithRetry(ctx, clusterMeta, nodeID, drainSpec, markEligible)
 	if err == nil {
 		r.logger.Info("updated node drain status via Server API",
 			slog.String("cluster", clusterName),
@@ -400,6 +601,11 @@ func (r *nomadRepository) SetNodeDrain(ctx context.Context, clusterName, nodeID
 		return nil
 	}
 
+	// Don't fall back once the activation has been cancelled, or when node ports are not reachable by design
+	if ctx.Err() != nil || !r.nomadCfg.EnableDirectFallback {
+		return fmt.Errorf("failed to update node drain via Server API: %w", err)
+	}
+
 	// Server API failed - try direct Client API fallback
 	r.logger.Warn("Server API failed, attempting direct Client API fallback",
 		slog.String("cluster", clusterName),
@@ -407,7 +613,7 @@ func (r *nomadRepository) SetNodeDrain(ctx context.Context, clusterName, nodeID
 		slog.String("server_error", err.Error()),
 	)
 
-	fallbackErr := r.setNodeDrainDirect(ctx, clusterMeta, nodeID, drain, markEligible)
+	fallbackErr := r.setNodeDrainDirect(ctx, clusterMeta, nodeID, drainSpec, markEligible)
 	if fallbackErr != nil {
 		return fmt.Errorf("both Server API and Client API failed: server_error=%w, client_error=%v", err, fallbackErr)
 	}
@@ -421,26 +627,103 @@ func (r *nomadRepository) SetNodeDrain(ctx context.Context, clusterName, nodeID
 	return nil
 }
 
-// setNodeDrainDirect sets drain status by making direct HTTP request to Nomad Client API
-func (r *nomadRepository) setNodeDrainDirect(ctx context.Context, meta *clusterMetadata, nodeID string, drain bool, markEligible bool) error {
-	// Get cached node address
-	nodeInfo, ok := meta.nodeCache[nodeID]
-	if !ok {
-		return fmt.Errorf("node %s not found in cache", nodeID)
+// updateDrainWithRetry updates node drain via the Server API with exponential backoff between attempts
+func (r *nomadRepository) updateDrainWithRetry(ctx context.Context, meta *clusterMetadata, nodeID string, drainSpec *nomad.DrainSpec, markEligible bool) error {
+	backoff := r.retryCfg.BaseBackoff
+
+	for attempt := 0; ; attempt++ {
+		// Bound to ctx so cancelled activations don't hang on a stuck node
+		writeOpts := meta.writeOptions("").WithContext(ctx)
+		_, err := meta.client.Nodes().UpdateDrain(nodeID, drainSpec, markEligible, writeOpts)
+		if err == nil || attempt >= r.retryCfg.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		r.logger.Warn("Server API drain update failed, retrying",
+			slog.String("cluster", meta.name),
+			slog.String("node_id", nodeID),
+			slog.Int("attempt", attempt+1),
+			slog.Int("max_retries", r.retryCfg.MaxRetries),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if r.retryCfg.MaxBackoff > 0 && backoff > r.retryCfg.MaxBackoff {
+			backoff = r.retryCfg.MaxBackoff
+		}
 	}
+}
 
-	if nodeInfo.HTTPAddr == "" {
-		return fmt.Errorf("node %s has no cached HTTP address", nodeID)
+// buildDrainSpec translates drain options to a Nomad drain spec (nil disables drain).
+// Nomad treats a zero deadline as "no deadline" and a negative one as "force now",
+// so the config semantics (negative = no deadline, 0 = immediate) are swapped here.
+func buildDrainSpec(drain bool, opts model.DrainOptions) *nomad.DrainSpec {
+	if !drain {
+		return nil
 	}
 
-	// Build drain request payload
-	var drainSpec *nomad.DrainSpec
-	if drain {
-		drainSpec = &nomad.DrainSpec{
-			Deadline: -1, // Infinite deadline
+	var deadline time.Duration
+	switch {
+	case opts.Deadline < 0:
+		deadline = 0 // No deadline
+	case opts.Deadline == 0:
+		deadline = -1 // Force immediately
+	default:
+		deadline = opts.Deadline
+	}
+
+	return &nomad.DrainSpec{
+		Deadline:         deadline,
+		IgnoreSystemJobs: opts.IgnoreSystemJobs,
+	}
+}
+
+// cacheNodeAddress fetches the HTTP address of a node missing from the node cache and caches it
+func (r *nomadRepository) cacheNodeAddress(ctx context.Context, meta *clusterMetadata, nodeID string) (*nodeCache, error) {
+	lookup := fetchNodeInfos(ctx, meta, []string{nodeID})[0]
+	if lookup.Error != nil {
+		return nil, nomadError(fmt.Sprintf("node %s not found in cache and lookup failed", nodeID), lookup.Error)
+	}
+	node := lookup.Value
+	if node.HTTPAddr == "" {
+		return nil, fmt.Errorf("node %s has no HTTP address", nodeID)
+	}
+
+	nodeInfo := &nodeCache{
+		HTTPAddr: node.HTTPAddr,
+		Name:     node.Name,
+	}
+	meta.setNode(nodeID, nodeInfo)
+
+	r.logger.Info("cached node address on demand for direct API access",
+		slog.String("cluster", meta.name),
+		slog.String("node_id", nodeID),
+		slog.String("node_name", node.Name),
+	)
+
+	return nodeInfo, nil
+}
+
+// setNodeDrainDirect sets drain status by making direct HTTP request to Nomad Client API
+func (r *nomadRepository) setNodeDrainDirect(ctx context.Context, meta *clusterMetadata, nodeID string, drainSpec *nomad.DrainSpec, markEligible bool) error {
+	// Get cached node address, looking the node up when the startup cache missed it
+	nodeInfo, ok := meta.getNode(nodeID)
+	if !ok {
+		var err error
+		nodeInfo, err = r.cacheNodeAddress(ctx, meta, nodeID)
+		if err != nil {
+			return err
 		}
 	}
 
+	// Build drain request payload
 	payload := map[string]interface{}{
 		"DrainSpec":    drainSpec,
 		"MarkEligible": markEligible,
@@ -467,12 +750,18 @@ func (r *nomadRepository) setNodeDrainDirect(ctx context.Context, meta *clusterM
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
+	for name, values := range meta.headers {
+		req.Header[name] = values
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// Make request using the HTTP client with TLS config
-	resp, err := meta.httpClient.Do(req)
+	// The fallback timeout bounds the request instead of the timeout shared with the Server API client
+	client := *meta.httpClient
+	client.Timeout = r.nomadCfg.DirectFallbackTimeout
+	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request to %s: %w", url, err)
+		return nomadError(fmt.Sprintf("failed to send request to %s", url), err)
 	}
 	defer resp.Body.Close()
 
@@ -492,18 +781,18 @@ func (r *nomadRepository) setNodeDrainDirect(ctx context.Context, meta *clusterM
 	return nil
 }
 
-// CheckLeader checks if the cluster has an elected leader
-func (r *nomadRepository) CheckLeader(ctx context.Context, clusterName string) (bool, error) {
-	clusterMeta, ok := r.clusters[clusterName]
+// CheckLeader checks if the cluster has an elected leader and returns the leader address
+func (r *nomadRepository) CheckLeader(ctx context.Context, clusterName string) (string, bool, error) {
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return false, fmt.Errorf("cluster %s not found", clusterName)
+		return "", false, clusterNotFound(clusterName)
 	}
 
-	// Get leader from Nomad Status API
+	// Get the leader of the cluster's own region from Nomad Status API
 	status := clusterMeta.client.Status()
-	leader, err := status.Leader()
+	leader, err := status.RegionLeader(clusterMeta.region)
 	if err != nil {
-		return false, fmt.Errorf("failed to get leader: %w", err)
+		return "", false, nomadError("failed to get leader", err)
 	}
 
 	hasLeader := leader != ""
@@ -515,11 +804,64 @@ func (r *nomadRepository) CheckLeader(ctx context.Context, clusterName string) (
 		slog.String("leader", leader),
 	)
 
-	return hasLeader, nil
+	return leader, hasLeader, nil
+}
+
+// CheckClusterHealth re-runs the connection health check of a cluster on demand.
+// Failed checks are reported in the result; the error is only set for unknown clusters.
+func (r *nomadRepository) CheckClusterHealth(ctx context.Context, clusterName string) (*model.ClusterHealth, error) {
+	clusterMeta, ok := r.cluster(clusterName)
+	if !ok {
+		return nil, clusterNotFound(clusterName)
+	}
+
+	health, err := probeClusterHealth(clusterMeta.client)
+	health.Datacenter = clusterName
+	if err != nil {
+		health.Error = err.Error()
+	}
+
+	r.logger.Debug("checked cluster health",
+		slog.String("cluster", clusterName),
+		slog.Bool("healthy", health.Healthy),
+		slog.String("error", health.Error),
+	)
+
+	return &health, nil
+}
+
+// cluster returns the metadata of the named cluster
+func (r *nomadRepository) cluster(name string) (*clusterMetadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.clusters[name]
+	return meta, ok
+}
+
+// uniqueClusterKey returns name, or a name-region key when name is taken.
+// A numeric suffix is added if that is taken too, so a live cluster is never overwritten.
+func (r *nomadRepository) uniqueClusterKey(name, region string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.clusters[name]; !exists {
+		return name
+	}
+
+	key := fmt.Sprintf("%s-%s", name, region)
+	for i := 2; ; i++ {
+		if _, exists := r.clusters[key]; !exists {
+			return key
+		}
+		key = fmt.Sprintf("%s-%s-%d", name, region, i)
+	}
 }
 
 // GetClusterNames returns the list of all configured cluster names (sorted alphabetically)
 func (r *nomadRepository) GetClusterNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	names := make([]string, 0, len(r.clusters))
 	for name := range r.clusters {
 		names = append(names, name)
@@ -530,15 +872,25 @@ func (r *nomadRepository) GetClusterNames() []string {
 
 // GetClusterRegion returns the region for a specific cluster
 func (r *nomadRepository) GetClusterRegion(clusterName string) (string, error) {
-	clusterMeta, ok := r.clusters[clusterName]
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return "", fmt.Errorf("cluster %s not found", clusterName)
+		return "", clusterNotFound(clusterName)
 	}
 	return clusterMeta.region, nil
 }
 
+// IsClusterEnabled reports whether a cluster takes part in activation drain logic.
+// Unknown clusters are reported as disabled.
+func (r *nomadRepository) IsClusterEnabled(clusterName string) bool {
+	clusterMeta, ok := r.cluster(clusterName)
+	return ok && clusterMeta.enabled
+}
+
 // GetClustersByRegion returns all cluster names in a specific region (sorted alphabetically)
 func (r *nomadRepository) GetClustersByRegion(region string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var clusters []string
 	for _, meta := range r.clusters {
 		if meta.region == region {
@@ -551,6 +903,9 @@ func (r *nomadRepository) GetClustersByRegion(region string) []string {
 
 // GetAllRegions returns the list of all unique regions (sorted alphabetically)
 func (r *nomadRepository) GetAllRegions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	regionMap := make(map[string]bool)
 	for _, meta := range r.clusters {
 		regionMap[meta.region] = true
@@ -568,9 +923,9 @@ func (r *nomadRepository) GetAllRegions() []string {
 // This forces Nomad scheduler to re-evaluate job placements, which is useful
 // after un-draining nodes to redistribute allocations
 func (r *nomadRepository) TriggerJobEvaluations(ctx context.Context, clusterName string) error {
-	clusterMeta, ok := r.clusters[clusterName]
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return fmt.Errorf("cluster %s not found", clusterName)
+		return clusterNotFound(clusterName)
 	}
 
 	r.logger.Info("triggering job evaluations",
@@ -579,24 +934,14 @@ func (r *nomadRepository) TriggerJobEvaluations(ctx context.Context, clusterName
 	)
 
 	// List all jobs in the cluster
-	jobs, _, err := clusterMeta.client.Jobs().List(nil)
+	jobs, _, err := clusterMeta.client.Jobs().List(clusterMeta.queryOptions(clusterMeta.namespace))
 	if err != nil {
-		return fmt.Errorf("failed to list jobs: %w", err)
+		return nomadError("failed to list jobs", err)
 	}
 
-	r.logger.Info("found jobs to evaluate",
-		slog.String("cluster", clusterName),
-		slog.Int("job_count", len(jobs)),
-	)
-
-	// Track evaluation results
-	successCount := 0
-	errorCount := 0
-	var errors []string
-
-	// Trigger evaluation for each job
+	// Skip jobs that are stopped/dead
+	liveJobs := make([]*nomad.JobListStub, 0, len(jobs))
 	for _, job := range jobs {
-		// Skip jobs that are stopped/dead
 		if job.Status == "dead" {
 			r.logger.Debug("skipping dead job",
 				slog.String("cluster", clusterName),
@@ -605,33 +950,62 @@ func (r *nomadRepository) TriggerJobEvaluations(ctx context.Context, clusterName
 			)
 			continue
 		}
+		liveJobs = append(liveJobs, job)
+	}
 
-		// Force evaluation for this job
-		evalID, _, err := clusterMeta.client.Jobs().ForceEvaluate(job.ID, nil)
+	r.logger.Info("found jobs to evaluate",
+		slog.String("cluster", clusterName),
+		slog.Int("job_count", len(liveJobs)),
+		slog.Bool("only_unplaced", r.evalCfg.OnlyUnplaced),
+	)
+
+	// Trigger evaluations in parallel (bounded to avoid an evaluation storm)
+	evalResults := concurrent.ParallelMapWithLimit(ctx, liveJobs, func(ctx context.Context, job *nomad.JobListStub) (bool, error) {
+		if r.evalCfg.OnlyUnplaced && !r.needsEvaluation(clusterMeta, clusterName, job) {
+			return false, nil
+		}
+
+		evalID, _, err := clusterMeta.client.Jobs().ForceEvaluate(job.ID, clusterMeta.writeOptions(job.Namespace))
 		if err != nil {
-			errorCount++
-			errMsg := fmt.Sprintf("job %s: %v", job.ID, err)
-			errors = append(errors, errMsg)
 			r.logger.Warn("failed to trigger evaluation for job",
 				slog.String("cluster", clusterName),
 				slog.String("job_id", job.ID),
 				slog.String("error", err.Error()),
 			)
-			continue
+			return false, err
 		}
 
-		successCount++
 		r.logger.Debug("triggered evaluation for job",
 			slog.String("cluster", clusterName),
 			slog.String("job_id", job.ID),
 			slog.String("eval_id", evalID),
 		)
+		return true, nil
+	}, r.evalCfg.MaxConcurrent)
+
+	// Track evaluation results
+	successCount := 0
+	errorCount := 0
+	skippedCount := 0
+	var errors []string
+
+	for i, er := range evalResults {
+		switch {
+		case er.Error != nil:
+			errorCount++
+			errors = append(errors, fmt.Sprintf("job %s: %v", liveJobs[i].ID, er.Error))
+		case !er.Value:
+			skippedCount++
+		default:
+			successCount++
+		}
 	}
 
 	r.logger.Info("job evaluations triggered",
 		slog.String("cluster", clusterName),
 		slog.Int("total_jobs", len(jobs)),
 		slog.Int("success", successCount),
+		slog.Int("skipped", skippedCount),
 		slog.Int("errors", errorCount),
 	)
 
@@ -652,23 +1026,57 @@ func (r *nomadRepository) TriggerJobEvaluations(ctx context.Context, clusterName
 	return nil
 }
 
-// ListJobs returns all jobs in the specified cluster
-func (r *nomadRepository) ListJobs(ctx context.Context, clusterName string) ([]model.Job, error) {
-	clusterMeta, ok := r.clusters[clusterName]
+// needsEvaluation reports whether a job is pending or has allocations waiting to be placed.
+// Jobs whose summary can't be read are evaluated to be safe.
+func (r *nomadRepository) needsEvaluation(clusterMeta *clusterMetadata, clusterName string, job *nomad.JobListStub) bool {
+	if job.Status == "pending" {
+		return true
+	}
+
+	summary, _, err := clusterMeta.client.Jobs().Summary(job.ID, clusterMeta.queryOptions(job.Namespace))
+	if err != nil {
+		r.logger.Warn("failed to get job summary, evaluating job anyway",
+			slog.String("cluster", clusterName),
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return true
+	}
+
+	if summary != nil {
+		for _, tg := range summary.Summary {
+			if tg.Queued > 0 {
+				return true
+			}
+		}
+	}
+
+	r.logger.Debug("skipping evaluation of fully placed job",
+		slog.String("cluster", clusterName),
+		slog.String("job_id", job.ID),
+	)
+	return false
+}
+
+// ListJobs returns the jobs in the specified cluster whose ID starts with prefix (all jobs if empty)
+func (r *nomadRepository) ListJobs(ctx context.Context, clusterName, prefix string) ([]model.Job, error) {
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return nil, fmt.Errorf("cluster %s not found", clusterName)
+		return nil, clusterNotFound(clusterName)
 	}
 
-	// List all jobs
-	jobs, _, err := clusterMeta.client.Jobs().List(nil)
+	// List jobs, letting Nomad apply the ID prefix filter
+	opts := clusterMeta.queryOptions(clusterMeta.namespace).WithContext(ctx)
+	opts.Prefix = prefix
+	jobs, _, err := clusterMeta.client.Jobs().List(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list jobs: %w", err)
+		return nil, nomadError("failed to list jobs", err)
 	}
 
-	result := make([]model.Job, 0, len(jobs))
-	for _, j := range jobs {
+	// Fetch job summaries in parallel (bounded to avoid overloading the Nomad API)
+	summaryResults := concurrent.ParallelMapWithLimit(ctx, jobs, func(ctx context.Context, j *nomad.JobListStub) (model.Job, error) {
 		// Get job summary for allocation counts
-		summary, _, err := clusterMeta.client.Jobs().Summary(j.ID, nil)
+		summary, _, err := clusterMeta.client.Jobs().Summary(j.ID, clusterMeta.queryOptions(j.Namespace).WithContext(ctx))
 		if err != nil {
 			r.logger.Warn("failed to get job summary, using basic info",
 				slog.String("cluster", clusterName),
@@ -676,31 +1084,33 @@ func (r *nomadRepository) ListJobs(ctx context.Context, clusterName string) ([]m
 				slog.String("error", err.Error()),
 			)
 			// Continue with basic info if summary fails
-			result = append(result, model.Job{
+			return model.Job{
 				ID:          j.ID,
 				Name:        j.Name,
+				Namespace:   j.Namespace,
 				Type:        j.Type,
 				Status:      j.Status,
 				Priority:    j.Priority,
 				SubmitTime:  j.SubmitTime,
 				Datacenters: j.Datacenters,
-			})
-			continue
+			}, nil
 		}
 
 		// Calculate total allocations across all task groups
 		var running, desired, failed int
 		if summary != nil && summary.Summary != nil {
 			for _, tg := range summary.Summary {
-				running += tg.Running
-				desired += tg.Queued + tg.Starting + tg.Running
-				failed += tg.Failed + tg.Lost
+				tgRunning, tgDesired, tgFailed := taskGroupCounts(tg)
+				running += tgRunning
+				desired += tgDesired
+				failed += tgFailed
 			}
 		}
 
-		result = append(result, model.Job{
+		return model.Job{
 			ID:          j.ID,
 			Name:        j.Name,
+			Namespace:   j.Namespace,
 			Type:        j.Type,
 			Status:      j.Status,
 			Running:     running,
@@ -709,9 +1119,25 @@ func (r *nomadRepository) ListJobs(ctx context.Context, clusterName string) ([]m
 			Priority:    j.Priority,
 			SubmitTime:  j.SubmitTime,
 			Datacenters: j.Datacenters,
-		})
+		}, nil
+	}, maxConcurrentJobSummaries)
+
+	result := make([]model.Job, 0, len(summaryResults))
+	for _, sr := range summaryResults {
+		if sr.Error != nil {
+			return nil, nomadError("failed to list jobs", sr.Error)
+		}
+		result = append(result, sr.Value)
 	}
 
+	// Keep output deterministic regardless of completion order
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+
 	r.logger.Info("listed jobs",
 		slog.String("cluster", clusterName),
 		slog.String("region", clusterMeta.region),
@@ -723,15 +1149,20 @@ func (r *nomadRepository) ListJobs(ctx context.Context, clusterName string) ([]m
 
 // StartJob starts (registers) a stopped job
 func (r *nomadRepository) StartJob(ctx context.Context, clusterName, jobID string) error {
-	clusterMeta, ok := r.clusters[clusterName]
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return fmt.Errorf("cluster %s not found", clusterName)
+		return clusterNotFound(clusterName)
+	}
+
+	namespace, err := r.resolveJobNamespace(clusterMeta, jobID)
+	if err != nil {
+		return err
 	}
 
 	// Get the job definition first
-	job, _, err := clusterMeta.client.Jobs().Info(jobID, nil)
+	job, _, err := clusterMeta.client.Jobs().Info(jobID, clusterMeta.queryOptions(namespace))
 	if err != nil {
-		return fmt.Errorf("failed to get job info: %w", err)
+		return jobInfoError(jobID, err)
 	}
 
 	// Set Stop to false to start the job
@@ -739,14 +1170,15 @@ func (r *nomadRepository) StartJob(ctx context.Context, clusterName, jobID strin
 	job.Stop = &stop
 
 	// Register the job (this will start it)
-	_, _, err = clusterMeta.client.Jobs().Register(job, nil)
+	_, _, err = clusterMeta.client.Jobs().Register(job, clusterMeta.writeOptions(namespace))
 	if err != nil {
-		return fmt.Errorf("failed to start job: %w", err)
+		return nomadError("failed to start job", err)
 	}
 
 	r.logger.Info("started job",
 		slog.String("cluster", clusterName),
 		slog.String("region", clusterMeta.region),
+		slog.String("namespace", namespace),
 		slog.String("job_id", jobID),
 	)
 
@@ -754,30 +1186,230 @@ func (r *nomadRepository) StartJob(ctx context.Context, clusterName, jobID strin
 }
 
 // StopJob stops (deregisters) a running job
-func (r *nomadRepository) StopJob(ctx context.Context, clusterName, jobID string) error {
-	clusterMeta, ok := r.clusters[clusterName]
+// With purge the job is also removed from Nomad instead of being kept as dead
+func (r *nomadRepository) StopJob(ctx context.Context, clusterName, jobID string, purge bool) error {
+	clusterMeta, ok := r.cluster(clusterName)
 	if !ok {
-		return fmt.Errorf("cluster %s not found", clusterName)
+		return clusterNotFound(clusterName)
+	}
+
+	namespace, err := r.resolveJobNamespace(clusterMeta, jobID)
+	if err != nil {
+		return err
 	}
 
-	// Deregister the job (purge=false keeps it in the system)
-	_, _, err := clusterMeta.client.Jobs().Deregister(jobID, false, nil)
+	// Deregister the job; without purge Nomad keeps it as dead so it can be started again
+	_, _, err = clusterMeta.client.Jobs().Deregister(jobID, purge, clusterMeta.writeOptions(namespace))
 	if err != nil {
-		return fmt.Errorf("failed to stop job: %w", err)
+		return nomadError("failed to stop job", err)
 	}
 
 	r.logger.Info("stopped job",
 		slog.String("cluster", clusterName),
 		slog.String("region", clusterMeta.region),
+		slog.String("namespace", namespace),
 		slog.String("job_id", jobID),
+		slog.Bool("purge", purge),
 	)
 
 	return nil
 }
 
+// RestartJob stops a job and registers it again from the definition read before the stop,
+// so a concurrent change of the job can't slip in between. A job that is already stopped is
+// only started; wasStopped reports that case.
+func (r *nomadRepository) RestartJob(ctx context.Context, clusterName, jobID string) (bool, error) {
+	clusterMeta, ok := r.cluster(clusterName)
+	if !ok {
+		return false, clusterNotFound(clusterName)
+	}
+
+	namespace, err := r.resolveJobNamespace(clusterMeta, jobID)
+	if err != nil {
+		return false, err
+	}
+
+	job, _, err := clusterMeta.client.Jobs().Info(jobID, clusterMeta.queryOptions(namespace).WithContext(ctx))
+	if err != nil {
+		return false, jobInfoError(jobID, err)
+	}
+
+	writeOpts := clusterMeta.writeOptions(namespace).WithContext(ctx)
+	wasStopped := job.Stop != nil && *job.Stop
+	if !wasStopped {
+		if _, _, err := clusterMeta.client.Jobs().Deregister(jobID, false, writeOpts); err != nil {
+			return false, nomadError("failed to stop job", err)
+		}
+	}
+
+	stop := false
+	job.Stop = &stop
+	if _, _, err := clusterMeta.client.Jobs().Register(job, writeOpts); err != nil {
+		// The job stays stopped until it is started again
+		return wasStopped, nomadError("failed to start job after stopping it", err)
+	}
+
+	r.logger.Info("restarted job",
+		slog.String("cluster", clusterName),
+		slog.String("region", clusterMeta.region),
+		slog.String("namespace", namespace),
+		slog.String("job_id", jobID),
+		slog.Bool("was_stopped", wasStopped),
+	)
+
+	return wasStopped, nil
+}
+
+// GetJob returns a job with per task group allocation counts and its most recent allocations
+func (r *nomadRepository) GetJob(ctx context.Context, clusterName, jobID string) (*model.JobDetail, error) {
+	clusterMeta, ok := r.cluster(clusterName)
+	if !ok {
+		return nil, clusterNotFound(clusterName)
+	}
+
+	namespace, err := r.resolveJobNamespace(clusterMeta, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOpts := clusterMeta.queryOptions(namespace).WithContext(ctx)
+	job, _, err := clusterMeta.client.Jobs().Info(jobID, queryOpts)
+	if err != nil {
+		return nil, jobInfoError(jobID, err)
+	}
+
+	summary, _, err := clusterMeta.client.Jobs().Summary(jobID, queryOpts)
+	if err != nil {
+		return nil, nomadError("failed to get job summary", err)
+	}
+
+	allocs, _, err := clusterMeta.client.Jobs().Allocations(jobID, false, queryOpts)
+	if err != nil {
+		return nil, nomadError("failed to list job allocations", err)
+	}
+
+	detail := &model.JobDetail{
+		Job: model.Job{
+			ID:          valueOf(job.ID),
+			Name:        valueOf(job.Name),
+			Namespace:   valueOf(job.Namespace),
+			Type:        valueOf(job.Type),
+			Status:      valueOf(job.Status),
+			Priority:    valueOf(job.Priority),
+			SubmitTime:  valueOf(job.SubmitTime),
+			Datacenters: job.Datacenters,
+		},
+		Version:     valueOf(job.Version),
+		Stopped:     valueOf(job.Stop),
+		TaskGroups:  make([]model.TaskGroupStatus, 0, len(job.TaskGroups)),
+		Allocations: make([]model.Allocation, 0, min(len(allocs), maxJobDetailAllocations)),
+	}
+
+	// Task groups in job definition order; counts come from the summary
+	for _, tg := range job.TaskGroups {
+		group := model.TaskGroupStatus{
+			Name:  valueOf(tg.Name),
+			Count: valueOf(tg.Count),
+		}
+		if summary != nil {
+			if tgSummary, ok := summary.Summary[group.Name]; ok {
+				group.Running, group.Desired, group.Failed = taskGroupCounts(tgSummary)
+				group.Queued = tgSummary.Queued
+				group.Starting = tgSummary.Starting
+				group.Complete = tgSummary.Complete
+			}
+		}
+		detail.Running += group.Running
+		detail.Desired += group.Desired
+		detail.Failed += group.Failed
+		detail.TaskGroups = append(detail.TaskGroups, group)
+	}
+
+	// Most recently modified allocations first
+	sort.Slice(allocs, func(i, j int) bool {
+		return allocs[i].ModifyTime > allocs[j].ModifyTime
+	})
+	for _, a := range allocs[:min(len(allocs), maxJobDetailAllocations)] {
+		detail.Allocations = append(detail.Allocations, model.Allocation{
+			ID:            a.ID,
+			JobID:         a.JobID,
+			JobType:       detail.Type,
+			TaskGroup:     a.TaskGroup,
+			DesiredStatus: a.DesiredStatus,
+			ClientStatus:  a.ClientStatus,
+			NodeID:        a.NodeID,
+			NodeName:      a.NodeName,
+			ModifyTime:    a.ModifyTime,
+		})
+	}
+
+	return detail, nil
+}
+
+// taskGroupCounts returns the running, desired and failed allocations of a task group summary
+func taskGroupCounts(tg nomad.TaskGroupSummary) (running, desired, failed int) {
+	return tg.Running, tg.Queued + tg.Starting + tg.Running, tg.Failed + tg.Lost
+}
+
+// valueOf dereferences an optional Nomad API field, returning the zero value for nil
+func valueOf[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// resolveJobNamespace returns the namespace a job operation should target
+// When the cluster is configured for all namespaces ("*"), the job is looked up across namespaces
+func (r *nomadRepository) resolveJobNamespace(meta *clusterMetadata, jobID string) (string, error) {
+	if meta.namespace != nomad.AllNamespacesNamespace {
+		return meta.namespace, nil
+	}
+
+	opts := meta.queryOptions(nomad.AllNamespacesNamespace)
+	opts.Prefix = jobID
+	jobs, _, err := meta.client.Jobs().List(opts)
+	if err != nil {
+		return "", nomadError("failed to look up job namespace", err)
+	}
+
+	var namespaces []string
+	for _, j := range jobs {
+		if j.ID == jobID {
+			namespaces = append(namespaces, j.Namespace)
+		}
+	}
+
+	switch len(namespaces) {
+	case 0:
+		return "", fmt.Errorf("%w: %s in any namespace", ErrJobNotFound, jobID)
+	case 1:
+		return namespaces[0], nil
+	default:
+		return "", fmt.Errorf("job %s exists in multiple namespaces: %v", jobID, namespaces)
+	}
+}
+
+// queryOptions returns query options targeting the cluster's region and the namespace
+// ("" for the default namespace). The region is set explicitly so that a federated Nomad
+// doesn't forward the query to another region.
+func (m *clusterMetadata) queryOptions(namespace string) *nomad.QueryOptions {
+	return &nomad.QueryOptions{Region: m.region, Namespace: namespace}
+}
+
+// writeOptions returns write options targeting the cluster's region and the namespace
+// ("" for the default namespace)
+func (m *clusterMetadata) writeOptions(namespace string) *nomad.WriteOptions {
+	return &nomad.WriteOptions{Region: m.region, Namespace: namespace}
+}
+
 // RetryUnavailableClusters attempts to connect to previously unavailable clusters
 // Returns number of clusters successfully added
 func (r *nomadRepository) RetryUnavailableClusters() int {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
 	if len(r.unavailableClusters) == 0 {
 		return 0
 	}
@@ -820,7 +1452,7 @@ func (r *nomadRepository) RetryUnavailableClusters() int {
 		region := cluster.Region
 
 		if name == "" || region == "" {
-			detectedName, detectedRegion, err := detectClusterInfo(client)
+			detectedName, detectedRegion, err := detectClusterInfo(client, r.logger)
 			if err != nil {
 				r.logger.Warn("failed to auto-detect cluster info, using fallback",
 					slog.String("address", cluster.Address),
@@ -843,9 +1475,8 @@ func (r *nomadRepository) RetryUnavailableClusters() int {
 		}
 
 		// Check if cluster with this name already exists
-		clusterKey := name
-		if _, exists := r.clusters[name]; exists {
-			clusterKey = fmt.Sprintf("%s-%s", name, region)
+		clusterKey := r.uniqueClusterKey(name, region)
+		if clusterKey != name {
 			r.logger.Info("cluster name already exists, using name-region format",
 				slog.String("original_name", name),
 				slog.String("unique_key", clusterKey),
@@ -862,22 +1493,30 @@ func (r *nomadRepository) RetryUnavailableClusters() int {
 
 		metadata := &clusterMetadata{
 			name:       clusterKey,
+			address:    cluster.Address,
 			region:     region,
+			namespace:  cluster.Namespace,
 			client:     client,
 			httpClient: httpClient,
+			headers:    extraHeaders(cluster),
+			enabled:    cluster.IsEnabled(),
 			nodeCache:  make(map[string]*nodeCache),
 		}
 
 		// Cache node addresses
-		if err := cacheNodeAddresses(metadata, r.logger); err != nil {
-			r.logger.Warn("failed to cache node addresses",
-				slog.String("cluster", clusterKey),
-				slog.String("error", err.Error()),
-			)
+		if r.nomadCfg.EnableDirectFallback {
+			if err := cacheNodeAddresses(metadata, r.logger); err != nil {
+				r.logger.Warn("failed to cache node addresses",
+					slog.String("cluster", clusterKey),
+					slog.String("error", err.Error()),
+				)
+			}
 		}
 
 		// Add to clusters map
+		r.mu.Lock()
 		r.clusters[clusterKey] = metadata
+		r.mu.Unlock()
 		successfullyAdded++
 	}
 
@@ -893,3 +1532,138 @@ func (r *nomadRepository) RetryUnavailableClusters() int {
 
 	return successfullyAdded
 }
+
+// ReloadClusters applies the cluster list of a reloaded configuration.
+// Clusters are matched by address: new addresses are connected and added, existing ones get a
+// client rebuilt from the new config (e.g. rotated TLS certificates) under their current key,
+// and clusters missing from the config are removed. A cluster that fails to connect is skipped
+// and keeps its current client, so a bad reload never takes a working cluster away.
+func (r *nomadRepository) ReloadClusters(cfg *config.Config) ClusterReloadResult {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	var result ClusterReloadResult
+
+	r.mu.RLock()
+	byAddress := make(map[string]*clusterMetadata, len(r.clusters))
+	for _, meta := range r.clusters {
+		byAddress[meta.address] = meta
+	}
+	r.mu.RUnlock()
+
+	configured := make(map[string]bool, len(cfg.Clusters))
+	for _, cluster := range cfg.Clusters {
+		configured[cluster.Address] = true
+	}
+	stillUnavailable := []config.ClusterConfig{}
+
+	for i, cluster := range cfg.Clusters {
+		existing := byAddress[cluster.Address]
+
+		client, httpClient, err := createNomadClient(cluster)
+		if err == nil {
+			if healthy, healthErr := checkClusterHealth(client); !healthy {
+				err = healthErr
+			}
+		}
+		if err != nil {
+			r.logger.Warn("failed to reload cluster, keeping current state",
+				slog.String("address", cluster.Address),
+				slog.String("error", err.Error()),
+			)
+			result.Failed = append(result.Failed, cluster.Address)
+			if existing == nil {
+				stillUnavailable = append(stillUnavailable, cluster)
+			}
+			continue
+		}
+
+		// Existing clusters keep their key so etcd state and cached data stay valid
+		clusterKey := ""
+		region := cluster.Region
+		if existing != nil {
+			clusterKey = existing.name
+			if region == "" {
+				region = existing.region
+			}
+		} else {
+			name := cluster.Name
+			if name == "" || region == "" {
+				detectedName, detectedRegion, err := detectClusterInfo(client, r.logger)
+				if err != nil {
+					r.logger.Warn("failed to auto-detect cluster info, using fallback values",
+						slog.String("address", cluster.Address),
+						slog.String("error", err.Error()),
+					)
+					detectedName = fmt.Sprintf("cluster-%d", i)
+					detectedRegion = "global"
+				}
+				if name == "" {
+					name = detectedName
+				}
+				if region == "" {
+					region = detectedRegion
+				}
+			}
+
+			// A cluster being removed (e.g. moved to a new address) hands its key over
+			clusterKey = name
+			if meta, exists := r.cluster(name); exists && configured[meta.address] {
+				clusterKey = r.uniqueClusterKey(name, region)
+			}
+		}
+
+		metadata := &clusterMetadata{
+			name:       clusterKey,
+			address:    cluster.Address,
+			region:     region,
+			namespace:  cluster.Namespace,
+			client:     client,
+			httpClient: httpClient,
+			headers:    extraHeaders(cluster),
+			enabled:    cluster.IsEnabled(),
+			nodeCache:  make(map[string]*nodeCache),
+		}
+
+		if r.nomadCfg.EnableDirectFallback {
+			if err := cacheNodeAddresses(metadata, r.logger); err != nil {
+				r.logger.Warn("failed to cache node addresses",
+					slog.String("cluster", clusterKey),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+
+		r.mu.Lock()
+		r.clusters[clusterKey] = metadata
+		r.mu.Unlock()
+
+		if existing != nil {
+			result.Updated = append(result.Updated, clusterKey)
+		} else {
+			result.Added = append(result.Added, clusterKey)
+		}
+	}
+
+	// Remove clusters that are no longer configured
+	r.mu.Lock()
+	for key, meta := range r.clusters {
+		if !configured[meta.address] {
+			delete(r.clusters, key)
+			result.Removed = append(result.Removed, key)
+		}
+	}
+	r.mu.Unlock()
+	sort.Strings(result.Removed)
+
+	r.unavailableClusters = stillUnavailable
+
+	r.logger.Info("clusters reloaded",
+		slog.Any("added", result.Added),
+		slog.Any("updated", result.Updated),
+		slog.Any("removed", result.Removed),
+		slog.Any("failed", result.Failed),
+	)
+
+	return result
+}
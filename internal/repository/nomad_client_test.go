@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// fakeNomad is a Nomad HTTP API serving canned responses keyed by "METHOD path"
+type fakeNomad struct {
+	mu        sync.Mutex
+	responses map[string]fakeResponse
+	requests  []*http.Request
+	bodies    []string
+	block     chan struct{}  // When set, every request waits for it to be closed
+	failures  map[string]int // "METHOD path" -> number of requests answered with 500 before the canned response
+}
+
+// fakeResponse is a canned response; body is encoded as JSON
+type fakeResponse struct {
+	status int
+	body   any
+	delay  time.Duration // Slept before answering
+}
+
+func newFakeNomad(t testing.TB, responses map[string]fakeResponse) (*fakeNomad, *httptest.Server) {
+	t.Helper()
+
+	f := &fakeNomad{responses: responses}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeNomad) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.bodies = append(f.bodies, string(body))
+	block := f.block
+	resp, ok := f.responses[r.Method+" "+r.URL.Path]
+	if f.failures[r.Method+" "+r.URL.Path] > 0 {
+		f.failures[r.Method+" "+r.URL.Path]--
+		resp = fakeResponse{status: http.StatusInternalServerError}
+	}
+	f.mu.Unlock()
+
+	if block != nil {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if resp.delay > 0 {
+		time.Sleep(resp.delay)
+	}
+	if resp.status == 0 {
+		resp.status = http.StatusOK
+	}
+	if resp.status != http.StatusOK {
+		http.Error(w, "canned error", resp.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp.body)
+}
+
+// calls returns "METHOD path?query" of every request served so far
+func (f *fakeNomad) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]string, 0, len(f.requests))
+	for _, r := range f.requests {
+		call := r.Method + " " + r.URL.Path
+		if purge := r.URL.Query().Get("purge"); purge != "" {
+			call += "?purge=" + purge
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// namespaces returns "METHOD path namespace" of every request served so far
+func (f *fakeNomad) namespaces() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]string, 0, len(f.requests))
+	for _, r := range f.requests {
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("namespace"))
+	}
+	return calls
+}
+
+// newTestNomadRepository returns a repository with a single cluster "dc1" talking to srv
+func newTestNomadRepository(t testing.TB, srv *httptest.Server) *nomadRepository {
+	t.Helper()
+
+	client, httpClient, err := createNomadClient(config.ClusterConfig{Address: srv.URL, Region: "eu"})
+	if err != nil {
+		t.Fatalf("createNomadClient: %v", err)
+	}
+
+	return &nomadRepository{
+		clusters: map[string]*clusterMetadata{
+			"dc1": {
+				name:       "dc1",
+				address:    srv.URL,
+				region:     "eu",
+				namespace:  "default",
+				enabled:    true,
+				client:     client,
+				httpClient: httpClient,
+				nodeCache:  make(map[string]*nodeCache),
+			},
+		},
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func equalCalls(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRestartJob(t *testing.T) {
+	jobID := "api"
+	stopped := true
+	running := false
+
+	tests := []struct {
+		name           string
+		responses      map[string]fakeResponse
+		wantStopped    bool
+		wantErr        error
+		wantAnyErr     bool
+		wantCalls      []string
+		wantRegistered bool
+	}{
+		{
+			name: "running job is stopped and started",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":    {body: nomad.Job{ID: &jobID, Stop: &running}},
+				"DELETE /v1/job/api": {body: nomad.JobDeregisterResponse{}},
+				"PUT /v1/jobs":       {body: nomad.JobRegisterResponse{}},
+			},
+			wantCalls:      []string{"GET /v1/job/api", "DELETE /v1/job/api?purge=false", "PUT /v1/jobs"},
+			wantRegistered: true,
+		},
+		{
+			name: "stopped job is only started",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api": {body: nomad.Job{ID: &jobID, Stop: &stopped}},
+				"PUT /v1/jobs":    {body: nomad.JobRegisterResponse{}},
+			},
+			wantStopped:    true,
+			wantCalls:      []string{"GET /v1/job/api", "PUT /v1/jobs"},
+			wantRegistered: true,
+		},
+		{
+			name:      "unknown job",
+			responses: map[string]fakeResponse{},
+			wantErr:   ErrJobNotFound,
+			wantCalls: []string{"GET /v1/job/api"},
+		},
+		{
+			name: "failed start leaves the job stopped",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":    {body: nomad.Job{ID: &jobID, Stop: &running}},
+				"DELETE /v1/job/api": {body: nomad.JobDeregisterResponse{}},
+				"PUT /v1/jobs":       {status: http.StatusInternalServerError},
+			},
+			wantErr:   ErrNomadUnavailable,
+			wantCalls: []string{"GET /v1/job/api", "DELETE /v1/job/api?purge=false", "PUT /v1/jobs"},
+		},
+		{
+			name: "rejected stop",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":    {body: nomad.Job{ID: &jobID, Stop: &running}},
+				"DELETE /v1/job/api": {status: http.StatusForbidden},
+			},
+			wantAnyErr: true,
+			wantCalls:  []string{"GET /v1/job/api", "DELETE /v1/job/api?purge=false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, tt.responses)
+			repo := newTestNomadRepository(t, srv)
+
+			wasStopped, err := repo.RestartJob(context.Background(), "dc1", "api")
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if wasStopped != tt.wantStopped {
+				t.Errorf("wasStopped = %v, want %v", wasStopped, tt.wantStopped)
+			}
+			if calls := fake.calls(); !equalCalls(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+
+			if tt.wantRegistered {
+				var req nomad.JobRegisterRequest
+				if err := json.Unmarshal([]byte(fake.bodies[len(fake.bodies)-1]), &req); err != nil {
+					t.Fatalf("decode register request: %v", err)
+				}
+				if req.Job == nil || req.Job.Stop == nil || *req.Job.Stop {
+					t.Errorf("registered job is not marked as running: %+v", req.Job)
+				}
+			}
+		})
+	}
+}
+
+func TestRestartJobHonoursContext(t *testing.T) {
+	fake, srv := newFakeNomad(t, map[string]fakeResponse{})
+	fake.block = make(chan struct{})
+	defer close(fake.block)
+	repo := newTestNomadRepository(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := repo.RestartJob(ctx, "dc1", "api"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RestartJob returned after %v, the request context was not used", elapsed)
+	}
+}
+
+func TestStopJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		purge     bool
+		responses map[string]fakeResponse
+		wantErr   error
+		wantCalls []string
+	}{
+		{
+			name:      "stop keeps the job",
+			responses: map[string]fakeResponse{"DELETE /v1/job/api": {body: nomad.JobDeregisterResponse{}}},
+			wantCalls: []string{"DELETE /v1/job/api?purge=false"},
+		},
+		{
+			name:      "stop with purge",
+			purge:     true,
+			responses: map[string]fakeResponse{"DELETE /v1/job/api": {body: nomad.JobDeregisterResponse{}}},
+			wantCalls: []string{"DELETE /v1/job/api?purge=true"},
+		},
+		{
+			name:      "nomad unavailable",
+			purge:     true,
+			responses: map[string]fakeResponse{"DELETE /v1/job/api": {status: http.StatusBadGateway}},
+			wantErr:   ErrNomadUnavailable,
+			wantCalls: []string{"DELETE /v1/job/api?purge=true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, tt.responses)
+			repo := newTestNomadRepository(t, srv)
+
+			err := repo.StopJob(context.Background(), "dc1", "api", tt.purge)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if calls := fake.calls(); !equalCalls(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// fakeJob returns the Nomad definition of a running service job "api" with a "web" and a "worker" group
+func fakeJob() nomad.Job {
+	id, name, namespace, jobType, status := "api", "api", "default", "service", "running"
+	priority, submitTime, version := 50, int64(1700000000), uint64(3)
+	stop := false
+	web, worker := "web", "worker"
+	webCount, workerCount := 2, 1
+
+	return nomad.Job{
+		ID:          &id,
+		Name:        &name,
+		Namespace:   &namespace,
+		Type:        &jobType,
+		Status:      &status,
+		Priority:    &priority,
+		SubmitTime:  &submitTime,
+		Version:     &version,
+		Stop:        &stop,
+		Datacenters: []string{"dc1"},
+		TaskGroups: []*nomad.TaskGroup{
+			{Name: &web, Count: &webCount},
+			{Name: &worker, Count: &workerCount},
+		},
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	summary := nomad.JobSummary{
+		JobID: "api",
+		Summary: map[string]nomad.TaskGroupSummary{
+			"web":    {Running: 1, Starting: 1, Queued: 1, Failed: 1, Lost: 1, Complete: 4},
+			"worker": {Running: 1},
+		},
+	}
+	allocs := []nomad.AllocationListStub{
+		{ID: "a1", JobID: "api", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running", NodeID: "n1", NodeName: "node-1", ModifyTime: 100},
+		{ID: "a2", JobID: "api", TaskGroup: "worker", DesiredStatus: "run", ClientStatus: "running", NodeID: "n2", NodeName: "node-2", ModifyTime: 300},
+		{ID: "a3", JobID: "api", TaskGroup: "web", DesiredStatus: "stop", ClientStatus: "failed", NodeID: "n1", NodeName: "node-1", ModifyTime: 200},
+	}
+	manyAllocs := make([]nomad.AllocationListStub, maxJobDetailAllocations+5)
+	for i := range manyAllocs {
+		manyAllocs[i] = nomad.AllocationListStub{ID: fmt.Sprintf("a%d", i), JobID: "api", ModifyTime: int64(i)}
+	}
+
+	wantDetail := &model.JobDetail{
+		Job: model.Job{
+			ID:          "api",
+			Name:        "api",
+			Namespace:   "default",
+			Type:        "service",
+			Status:      "running",
+			Running:     2,
+			Desired:     4,
+			Failed:      2,
+			SubmitTime:  1700000000,
+			Priority:    50,
+			Datacenters: []string{"dc1"},
+		},
+		Version: 3,
+		TaskGroups: []model.TaskGroupStatus{
+			{Name: "web", Count: 2, Running: 1, Desired: 3, Failed: 2, Queued: 1, Starting: 1, Complete: 4},
+			{Name: "worker", Count: 1, Running: 1, Desired: 1},
+		},
+		Allocations: []model.Allocation{
+			{ID: "a2", JobID: "api", JobType: "service", TaskGroup: "worker", DesiredStatus: "run", ClientStatus: "running", NodeID: "n2", NodeName: "node-2", ModifyTime: 300},
+			{ID: "a3", JobID: "api", JobType: "service", TaskGroup: "web", DesiredStatus: "stop", ClientStatus: "failed", NodeID: "n1", NodeName: "node-1", ModifyTime: 200},
+			{ID: "a1", JobID: "api", JobType: "service", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running", NodeID: "n1", NodeName: "node-1", ModifyTime: 100},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		responses  map[string]fakeResponse
+		want       *model.JobDetail
+		wantErr    error
+		wantAllocs int
+	}{
+		{
+			name: "maps job, summary and allocations",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":             {body: fakeJob()},
+				"GET /v1/job/api/summary":     {body: summary},
+				"GET /v1/job/api/allocations": {body: allocs},
+			},
+			want: wantDetail,
+		},
+		{
+			name:      "looks the job up across namespaces",
+			namespace: nomad.AllNamespacesNamespace,
+			responses: map[string]fakeResponse{
+				"GET /v1/jobs":                {body: []nomad.JobListStub{{ID: "api", Namespace: "default"}, {ID: "api-v2", Namespace: "other"}}},
+				"GET /v1/job/api":             {body: fakeJob()},
+				"GET /v1/job/api/summary":     {body: summary},
+				"GET /v1/job/api/allocations": {body: allocs},
+			},
+			want: wantDetail,
+		},
+		{
+			name: "keeps only the most recent allocations",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":             {body: fakeJob()},
+				"GET /v1/job/api/summary":     {body: summary},
+				"GET /v1/job/api/allocations": {body: manyAllocs},
+			},
+			wantAllocs: maxJobDetailAllocations,
+		},
+		{
+			name:      "unknown job",
+			responses: map[string]fakeResponse{},
+			wantErr:   ErrJobNotFound,
+		},
+		{
+			name:      "unknown job in any namespace",
+			namespace: nomad.AllNamespacesNamespace,
+			responses: map[string]fakeResponse{"GET /v1/jobs": {body: []nomad.JobListStub{}}},
+			wantErr:   ErrJobNotFound,
+		},
+		{
+			name: "summary unavailable",
+			responses: map[string]fakeResponse{
+				"GET /v1/job/api":         {body: fakeJob()},
+				"GET /v1/job/api/summary": {status: http.StatusServiceUnavailable},
+			},
+			wantErr: ErrNomadUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, tt.responses)
+			repo := newTestNomadRepository(t, srv)
+			if tt.namespace != "" {
+				repo.clusters["dc1"].namespace = tt.namespace
+			}
+
+			got, err := repo.GetJob(context.Background(), "dc1", "api")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detail = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantAllocs > 0 {
+				if len(got.Allocations) != tt.wantAllocs {
+					t.Fatalf("allocations = %d, want %d", len(got.Allocations), tt.wantAllocs)
+				}
+				if newest := got.Allocations[0].ModifyTime; newest != int64(len(manyAllocs)-1) {
+					t.Errorf("first allocation modified at %d, want the newest", newest)
+				}
+			}
+			if tt.namespace == nomad.AllNamespacesNamespace && tt.wantErr == nil {
+				for _, r := range fake.requests[1:] {
+					if ns := r.URL.Query().Get("namespace"); ns != "default" {
+						t.Errorf("%s sent namespace %q, want the resolved default", r.URL.Path, ns)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestNomadCallsTargetClusterRegion(t *testing.T) {
+	jobID := "api"
+	running := false
+
+	tests := []struct {
+		name      string
+		call      func(repo *nomadRepository) error
+		wantPaths []string
+	}{
+		{
+			name: "node list",
+			call: func(repo *nomadRepository) error {
+				_, err := repo.ListNodes(context.Background(), "dc1")
+				return err
+			},
+			wantPaths: []string{"GET /v1/nodes"},
+		},
+		{
+			name: "node drain",
+			call: func(repo *nomadRepository) error {
+				return repo.SetNodeDrain(context.Background(), "dc1", "n1", true, model.DrainOptions{})
+			},
+			wantPaths: []string{"PUT /v1/node/n1/drain"},
+		},
+		{
+			name: "job list",
+			call: func(repo *nomadRepository) error {
+				_, err := repo.ListJobs(context.Background(), "dc1", "")
+				return err
+			},
+			wantPaths: []string{"GET /v1/jobs", "GET /v1/job/api/summary"},
+		},
+		{
+			name: "job register",
+			call: func(repo *nomadRepository) error {
+				return repo.StartJob(context.Background(), "dc1", "api")
+			},
+			wantPaths: []string{"GET /v1/job/api", "PUT /v1/jobs"},
+		},
+		{
+			name: "job deregister",
+			call: func(repo *nomadRepository) error {
+				return repo.StopJob(context.Background(), "dc1", "api", false)
+			},
+			wantPaths: []string{"DELETE /v1/job/api"},
+		},
+		{
+			name: "leader",
+			call: func(repo *nomadRepository) error {
+				_, _, err := repo.CheckLeader(context.Background(), "dc1")
+				return err
+			},
+			wantPaths: []string{"GET /v1/status/leader"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, srv := newFakeNomad(t, map[string]fakeResponse{
+				"GET /v1/nodes":           {body: []nomad.NodeListStub{{ID: "n1"}}},
+				"PUT /v1/node/n1/drain":   {body: nomad.NodeDrainUpdateResponse{}},
+				"GET /v1/jobs":            {body: []nomad.JobListStub{{ID: "api", Namespace: "default"}}},
+				"GET /v1/job/api/summary": {body: nomad.JobSummary{JobID: "api"}},
+				"GET /v1/job/api":         {body: nomad.Job{ID: &jobID, Stop: &running}},
+				"PUT /v1/jobs":            {body: nomad.JobRegisterResponse{}},
+				"DELETE /v1/job/api":      {body: nomad.JobDeregisterResponse{}},
+				"GET /v1/status/leader":   {body: "10.0.0.1:4647"},
+			})
+			repo := newTestNomadRepository(t, srv)
+
+			// A client without a default region sends only what the call targets
+			client, _, err := createNomadClient(config.ClusterConfig{Address: srv.URL})
+			if err != nil {
+				t.Fatalf("createNomadClient: %v", err)
+			}
+			repo.clusters["dc1"].client = client
+			repo.clusters["dc1"].region = "eu-west"
+
+			if err := tt.call(repo); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+
+			if len(fake.requests) != len(tt.wantPaths) {
+				t.Fatalf("requests = %d, want %v", len(fake.requests), tt.wantPaths)
+			}
+			for i, r := range fake.requests {
+				if path := r.Method + " " + r.URL.Path; path != tt.wantPaths[i] {
+					t.Errorf("request %d = %s, want %s", i, path, tt.wantPaths[i])
+				}
+				if region := r.URL.Query().Get("region"); region != "eu-west" {
+					t.Errorf("%s %s sent region %q, want eu-west", r.Method, r.URL.Path, region)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// receiveActive reads count updates from the watch channel; a deleted key is reported as "<deleted>"
+func receiveActive(t *testing.T, updates <-chan *model.ActiveDatacenter, count int) []string {
+	t.Helper()
+
+	var got []string
+	for len(got) < count {
+		select {
+		case info, ok := <-updates:
+			if !ok {
+				t.Fatalf("watch closed after %v", got)
+			}
+			if info == nil {
+				got = append(got, "<deleted>")
+				continue
+			}
+			got = append(got, info.Datacenter)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("received %v, want %d updates", got, count)
+		}
+	}
+	return got
+}
+
+// waitForWatch waits until the fake has n open watch streams
+func waitForWatch(t *testing.T, fake *fakeEtcd, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for fake.watchCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d open watches, want %d", fake.watchCount(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchActiveDatacenter(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes func(t *testing.T, client *etcdClient, fake *fakeEtcd)
+		want    []string
+	}{
+		{
+			name: "updates are forwarded in order",
+			changes: func(t *testing.T, client *etcdClient, _ *fakeEtcd) {
+				writeActive(t, client, "dc2")
+				writeActive(t, client, "dc3")
+			},
+			want: []string{"dc2", "dc3"},
+		},
+		{
+			name: "deletes are forwarded",
+			changes: func(t *testing.T, client *etcdClient, _ *fakeEtcd) {
+				writeActive(t, client, "dc2")
+				if err := client.DeleteActiveDatacenter(context.Background()); err != nil {
+					t.Fatalf("DeleteActiveDatacenter() error = %v", err)
+				}
+				writeActive(t, client, "dc3")
+			},
+			want: []string{"dc2", "<deleted>", "dc3"},
+		},
+		{
+			name: "lease expiry is forwarded as a delete",
+			changes: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				client.leaseTTL = time.Minute
+				writeActive(t, client, "dc2")
+				fake.expireLease(client.leaseID)
+			},
+			want: []string{"dc2", "<deleted>"},
+		},
+		{
+			name: "malformed updates are skipped",
+			changes: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				if _, err := fake.Put(context.Background(), client.keys.activeDatacenter, "{not json"); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+				writeActive(t, client, "dc3")
+			},
+			want: []string{"dc3"},
+		},
+		{
+			name: "reconnects after the watch is broken",
+			changes: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				writeActive(t, client, "dc2")
+				fake.breakWatches()
+				// Written while disconnected, delivered once the watch resumes from its revision
+				writeActive(t, client, "dc3")
+			},
+			want: []string{"dc2", "dc3"},
+		},
+		{
+			name: "resumes from the compacted revision",
+			changes: func(t *testing.T, client *etcdClient, fake *fakeEtcd) {
+				fake.breakWatches()
+				writeActive(t, client, "dc2")
+				writeActive(t, client, "dc3")
+				fake.compact(fake.get(client.keys.activeDatacenter).ModRevision - 1) // dc2 is gone from the history
+			},
+			want: []string{"dc3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeEtcdClient(t)
+			writeActive(t, client, "dc1") // Already current when the watch starts, not reported
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			updates, err := client.WatchActiveDatacenter(ctx)
+			if err != nil {
+				t.Fatalf("WatchActiveDatacenter() error = %v", err)
+			}
+			waitForWatch(t, fake, 1)
+
+			tt.changes(t, client, fake)
+
+			if got := receiveActive(t, updates, len(tt.want)); !slices.Equal(got, tt.want) {
+				t.Errorf("updates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchActiveDatacenterStopsWithContext(t *testing.T) {
+	client, fake := newFakeEtcdClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := client.WatchActiveDatacenter(ctx)
+	if err != nil {
+		t.Fatalf("WatchActiveDatacenter() error = %v", err)
+	}
+	waitForWatch(t, fake, 1)
+
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Fatal("received an update after cancellation")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("watch channel not closed after cancellation")
+	}
+	waitForWatch(t, fake, 0)
+}
+
+func TestWatchActiveDatacenterReadError(t *testing.T) {
+	client, fake := newFakeEtcdClient(t)
+	fake.err = context.DeadlineExceeded
+
+	if _, err := client.WatchActiveDatacenter(context.Background()); err == nil {
+		t.Fatal("WatchActiveDatacenter() error = nil, want the revision read error")
+	}
+	if fake.watchCount() != 0 {
+		t.Error("watch started without a revision")
+	}
+}
+
+// writeActive writes dc as the active datacenter
+func writeActive(t *testing.T, client *etcdClient, dc string) {
+	t.Helper()
+
+	if err := client.WriteActiveDatacenter(context.Background(), &model.ActiveDatacenter{Datacenter: dc}); err != nil {
+		t.Fatalf("WriteActiveDatacenter(%s) error = %v", dc, err)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+// activationClusters returns dc1 and dc2 in region eu and dc3 in region us; dc1 is serving
+func activationClusters() map[string]*mockCluster {
+	return map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+		"dc2": {region: "eu", nodes: testNodes("dc2", 1, true), hasLeader: true},
+		"dc3": {region: "us", nodes: testNodes("dc3", 2, true), hasLeader: true},
+	}
+}
+
+func TestActivationDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		activate    func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error)
+		readOnly    bool
+		wantErr     error
+		wantDrained int
+		wantUndrain int
+		wantPlanned map[string]bool // node ID -> drain after the change
+	}{
+		{
+			name: "datacenter",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", true, false, nil)
+			},
+			wantDrained: 2,
+			wantUndrain: 2,
+			wantPlanned: map[string]bool{"dc1-n1": true, "dc1-n2": true, "dc3-n1": false, "dc3-n2": false},
+		},
+		{
+			name: "datacenter keeps the same region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc2", true, false, nil)
+			},
+			wantUndrain: 1,
+			wantPlanned: map[string]bool{"dc2-n1": false},
+		},
+		{
+			name: "region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "us", true, nil)
+			},
+			wantDrained: 2,
+			wantUndrain: 2,
+			wantPlanned: map[string]bool{"dc1-n1": true, "dc1-n2": true, "dc3-n1": false, "dc3-n2": false},
+		},
+		{
+			name: "allowed in read-only mode",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", true, false, nil)
+			},
+			readOnly:    true,
+			wantDrained: 2,
+			wantUndrain: 2,
+			wantPlanned: map[string]bool{"dc1-n1": true, "dc1-n2": true, "dc3-n1": false, "dc3-n2": false},
+		},
+		{
+			name: "unknown datacenter",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc9", true, false, nil)
+			},
+			wantErr: repository.ErrClusterNotFound,
+		},
+		{
+			name: "unknown region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "ap", true, nil)
+			},
+			wantErr: repository.ErrRegionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(activationClusters())
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, notifier := newTestService(t, repo, etcd, testServiceOptions{readOnly: tt.readOnly})
+
+			result, err := tt.activate(context.Background(), svc)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.drainCalls) != 0 || len(repo.evaluations) != 0 || len(repo.jobCalls) != 0 {
+				t.Errorf("dry run touched Nomad: drains %v, evaluations %v, jobs %v", repo.drainCalls, repo.evaluations, repo.jobCalls)
+			}
+			if etcd.writes != 0 || etcd.claims != 0 || len(etcd.events) != 0 || etcd.current().Datacenter != "dc1" {
+				t.Errorf("dry run touched etcd: %d writes, %d claims, %d events", etcd.writes, etcd.claims, len(etcd.events))
+			}
+			if types := notifier.types(); len(types) != 0 {
+				t.Errorf("dry run sent notifications %v", types)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !result.DryRun {
+				t.Error("result is not marked as a dry run")
+			}
+			if result.DrainedNodes != tt.wantDrained || result.UnDrainedNodes != tt.wantUndrain {
+				t.Errorf("drained/undrained = %d/%d, want %d/%d", result.DrainedNodes, result.UnDrainedNodes, tt.wantDrained, tt.wantUndrain)
+			}
+			if len(result.PlannedChanges) != len(tt.wantPlanned) {
+				t.Fatalf("planned changes = %+v, want %v", result.PlannedChanges, tt.wantPlanned)
+			}
+			for _, change := range result.PlannedChanges {
+				drain, ok := tt.wantPlanned[change.NodeID]
+				if !ok {
+					t.Errorf("unexpected planned change %+v", change)
+					continue
+				}
+				if change.Before.Drain == drain || change.After.Drain != drain {
+					t.Errorf("%s: planned %v -> %v, want drain %v", change.NodeID, change.Before.Drain, change.After.Drain, drain)
+				}
+				wantEligibility := "eligible"
+				if drain {
+					wantEligibility = "ineligible"
+				}
+				if change.After.SchedulingEligibility != wantEligibility {
+					t.Errorf("%s: planned eligibility %q, want %q", change.NodeID, change.After.SchedulingEligibility, wantEligibility)
+				}
+			}
+		})
+	}
+}
+
+func TestActivationPerCluster(t *testing.T) {
+	// dc1 fails to drain one node, dc3 has one node eligible already and dc4 can't be listed
+	clusters := func() map[string]*mockCluster {
+		clusters := activationClusters()
+		clusters["dc1"].drainErr = map[string]error{"dc1-n2": errTestDrain}
+		clusters["dc3"].nodes[0].Drain = false
+		clusters["dc3"].nodes[0].SchedulingEligibility = "eligible"
+		clusters["dc4"] = &mockCluster{region: "ap", listErr: repository.ErrNomadUnavailable, hasLeader: true}
+		return clusters
+	}
+	want := map[string]model.ClusterActivationSummary{
+		"dc1": {Cluster: "dc1", Region: "eu", Drained: 1},
+		"dc2": {Cluster: "dc2", Region: "eu", Skipped: 1},
+		"dc3": {Cluster: "dc3", Region: "us", UnDrained: 1, Skipped: 1},
+		"dc4": {Cluster: "dc4", Region: "ap"},
+	}
+	wantErrors := map[string]int{"dc1": 1, "dc4": 1}
+
+	tests := []struct {
+		name     string
+		activate func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error)
+	}{
+		{
+			name: "datacenter",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			},
+		},
+		{
+			name: "region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "us", false, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(clusters())
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"}), testServiceOptions{})
+
+			result, err := tt.activate(context.Background(), svc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.PerCluster) != len(want) {
+				t.Fatalf("PerCluster = %+v, want %d clusters", result.PerCluster, len(want))
+			}
+			for _, got := range result.PerCluster {
+				w, ok := want[got.Cluster]
+				if !ok {
+					t.Errorf("unexpected cluster %+v", got)
+					continue
+				}
+				if got.Region != w.Region || got.Drained != w.Drained || got.UnDrained != w.UnDrained || got.Skipped != w.Skipped {
+					t.Errorf("%s = %+v, want %+v", got.Cluster, got, w)
+				}
+				if len(got.Errors) != wantErrors[got.Cluster] {
+					t.Errorf("%s errors = %v, want %d", got.Cluster, got.Errors, wantErrors[got.Cluster])
+				}
+				// Cluster errors are reported in the result's errors too
+				for _, errMsg := range got.Errors {
+					if !slices.Contains(result.Errors, errMsg) {
+						t.Errorf("%s error %q missing from the result errors %v", got.Cluster, errMsg, result.Errors)
+					}
+				}
+			}
+			if result.DrainedNodes != 1 || result.UnDrainedNodes != 1 || len(result.Errors) != 2 {
+				t.Errorf("drained/undrained/errors = %d/%d/%v, want 1/1 and 2 errors", result.DrainedNodes, result.UnDrainedNodes, result.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/cache"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+const (
+	// ActiveSummaryTTL is how long the active datacenter summary is cached
+	ActiveSummaryTTL = 5 * time.Second
+
+	activeSummaryCacheKey = "active:summary"
+	activeLeaderTimeout   = 2 * time.Second
+)
+
+// GetActiveSummary returns the active datacenter recorded in etcd with its region and a quick health verdict.
+// Results are cached for ActiveSummaryTTL; repository.ErrNoActiveDatacenter is returned when none is active.
+func (s *datacenterService) GetActiveSummary(ctx context.Context) (*model.ActiveSummary, error) {
+	if !isFresh(ctx) {
+		if summary, ok := cache.GetTyped[*model.ActiveSummary](s.cache, activeSummaryCacheKey); ok {
+			return summary, nil
+		}
+	}
+
+	active, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read active datacenter: %w", err)
+	}
+
+	summary := &model.ActiveSummary{
+		Datacenter:  active.Datacenter,
+		Datacenters: active.Datacenters(),
+		Region:      active.Region,
+	}
+	if summary.Region == "" {
+		if region, err := s.repo.GetClusterRegion(active.Datacenter); err == nil {
+			summary.Region = region
+		}
+	}
+
+	// A hanging leader check must not hold up pollers
+	leaderCtx, cancel := context.WithTimeout(ctx, activeLeaderTimeout)
+	defer cancel()
+
+	_, hasLeader, err := s.repo.CheckLeader(leaderCtx, active.Datacenter)
+	if err != nil {
+		s.logger.Debug("failed to check leader of active datacenter",
+			slog.String("datacenter", active.Datacenter),
+			slog.String("error", err.Error()),
+		)
+	}
+	summary.Healthy = hasLeader && !active.IsStale(s.heartbeatCfg.StaleThreshold)
+
+	s.cache.Set(activeSummaryCacheKey, summary, ActiveSummaryTTL)
+
+	return summary, nil
+}
+
+// invalidateActiveSummary drops the cached active summary after the active datacenter record changed
+func (s *datacenterService) invalidateActiveSummary() {
+	s.cache.Delete(activeSummaryCacheKey)
+}
+
+// GetActiveRegion returns the region of the active datacenter recorded in etcd, or an empty string
+// when etcd records no active datacenter. Unlike GetActiveSummary it is not cached.
+func (s *datacenterService) GetActiveRegion(ctx context.Context) (string, error) {
+	active, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+	if errors.Is(err, repository.ErrNoActiveDatacenter) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read active datacenter: %w", err)
+	}
+
+	if active.Region != "" {
+		return active.Region, nil
+	}
+	region, err := s.repo.GetClusterRegion(active.Datacenter)
+	if err != nil {
+		return "", fmt.Errorf("active datacenter %s: %w", active.Datacenter, err)
+	}
+	return region, nil
+}
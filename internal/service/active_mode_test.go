@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+)
+
+// regionWideClusters returns dc1, dc2 and a disabled dc5 in region eu and dc3 in region us; dc1 is serving
+func regionWideClusters() map[string]*mockCluster {
+	clusters := activationClusters()
+	clusters["dc5"] = &mockCluster{region: "eu", disabled: true, nodes: testNodes("dc5", 1, true), hasLeader: true}
+	return clusters
+}
+
+func TestActiveModeRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		activeMode string
+		activate   func(ctx context.Context, s *datacenterService) error
+		wantDC     string
+		wantActive []string // Active datacenters recorded in etcd
+		wantRegion string
+	}{
+		{
+			name:       "region activation records every enabled datacenter",
+			activeMode: config.ActiveModeRegionWide,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateRegion(ctx, "eu", false, nil)
+				return err
+			},
+			wantDC:     "dc1",
+			wantActive: []string{"dc1", "dc2"},
+			wantRegion: "eu",
+		},
+		{
+			name:       "datacenter activation keeps its siblings active",
+			activeMode: config.ActiveModeRegionWide,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateDatacenter(ctx, "dc2", false, false, nil)
+				return err
+			},
+			wantDC:     "dc2",
+			wantActive: []string{"dc2", "dc1"},
+			wantRegion: "eu",
+		},
+		{
+			name:       "exclusive datacenter activation records only the target",
+			activeMode: config.ActiveModeRegionWide,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateDatacenter(ctx, "dc2", false, true, nil)
+				return err
+			},
+			wantDC:     "dc2",
+			wantActive: []string{"dc2"},
+			wantRegion: "eu",
+		},
+		{
+			name:       "sole instance claim records the region",
+			activeMode: config.ActiveModeRegionWide,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.claimAsSoleInstance(ctx)
+				return err
+			},
+			wantDC:     "dc1",
+			wantActive: []string{"dc1", "dc2"},
+			wantRegion: "eu",
+		},
+		{
+			name:       "single mode records one datacenter",
+			activeMode: config.ActiveModeSingle,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateRegion(ctx, "eu", false, nil)
+				return err
+			},
+			wantDC:     "dc1",
+			wantActive: []string{"dc1"},
+			wantRegion: "eu",
+		},
+		{
+			name:       "single mode sole instance claim",
+			activeMode: config.ActiveModeSingle,
+			activate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.claimAsSoleInstance(ctx)
+				return err
+			},
+			wantDC:     "dc1",
+			wantActive: []string{"dc1"},
+			wantRegion: "eu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(nil)
+			svc, _ := newTestService(t, newMockNomadRepo(regionWideClusters()), etcd, testServiceOptions{activeMode: tt.activeMode})
+
+			if err := tt.activate(context.Background(), svc); err != nil {
+				t.Fatalf("activation error = %v", err)
+			}
+
+			active := etcd.current()
+			if active == nil {
+				t.Fatal("no active datacenter recorded")
+			}
+			if active.Datacenter != tt.wantDC {
+				t.Errorf("datacenter = %q, want %q", active.Datacenter, tt.wantDC)
+			}
+			if got := active.Datacenters(); !slices.Equal(got, tt.wantActive) {
+				t.Errorf("active datacenters = %v, want %v", got, tt.wantActive)
+			}
+			if active.Region != tt.wantRegion {
+				t.Errorf("region = %q, want %q", active.Region, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestRegionWideSiblingKeepsServing(t *testing.T) {
+	// dc2 runs its own instance; dc1 was activated region-wide and dc2 is serving too
+	clusters := activationClusters()
+	clusters["dc2"].nodes = testNodes("dc2", 1, false)
+	repo := newMockNomadRepo(clusters)
+	etcd := newMockEtcdRepo(nil)
+
+	activator, _ := newTestService(t, repo, etcd, testServiceOptions{activeMode: config.ActiveModeRegionWide})
+	if _, err := activator.ActivateDatacenter(context.Background(), "dc1", false, false, nil); err != nil {
+		t.Fatalf("ActivateDatacenter() error = %v", err)
+	}
+
+	sibling, _ := newTestService(t, repo, etcd, testServiceOptions{
+		myDatacenter: "dc2",
+		activeMode:   config.ActiveModeRegionWide,
+		heartbeat:    config.HeartbeatConfig{UpdateInterval: 5 * time.Millisecond, MaxFailures: 3},
+	})
+	sibling.StartHeartbeat(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	sibling.StopHeartbeat()
+	<-sibling.heartbeatDone
+
+	if drained := repo.drained("dc2", true); len(drained) != 0 {
+		t.Errorf("sibling drained %v, want it to keep serving", drained)
+	}
+	if active := etcd.current(); active == nil || !active.IsActive("dc2") {
+		t.Errorf("active datacenter = %+v, want dc2 still active", active)
+	}
+}
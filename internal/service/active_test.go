@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestActiveSummaryInvalidatedOnSwitch(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(ctx context.Context, s *datacenterService) error
+		want  string // active datacenter afterwards, empty when none is active
+	}{
+		{
+			name: "datacenter activation",
+			apply: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+				return err
+			},
+			want: "dc3",
+		},
+		{
+			name: "region activation",
+			apply: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateRegion(ctx, "us", false, nil)
+				return err
+			},
+			want: "dc3",
+		},
+		{
+			name: "deactivation",
+			apply: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.DeactivateDatacenter(ctx, "dc1", nil)
+				return err
+			},
+		},
+		{
+			name: "emergency drain",
+			apply: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.EmergencyDrainAll(ctx)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 1, false), hasLeader: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 1, true), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", LastHeartbeat: time.Now()})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+			ctx := context.Background()
+
+			// Prime the cache with the previous active datacenter
+			if summary, err := svc.GetActiveSummary(ctx); err != nil || summary.Datacenter != "dc1" {
+				t.Fatalf("GetActiveSummary() = %+v, %v before the switch", summary, err)
+			}
+
+			if err := tt.apply(ctx, svc); err != nil {
+				t.Fatalf("switch failed: %v", err)
+			}
+
+			summary, err := svc.GetActiveSummary(ctx)
+			if tt.want == "" {
+				if !errors.Is(err, repository.ErrNoActiveDatacenter) {
+					t.Errorf("GetActiveSummary() = %+v, %v, want ErrNoActiveDatacenter", summary, err)
+				}
+				return
+			}
+			if err != nil || summary.Datacenter != tt.want {
+				t.Errorf("GetActiveSummary() = %+v, %v, want %s", summary, err, tt.want)
+			}
+		})
+	}
+}
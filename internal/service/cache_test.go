@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestJobsCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		jobErr    error
+		jobsTTL   time.Duration
+		steps     func(t *testing.T, ctx context.Context, s *datacenterService)
+		wantLists int
+	}{
+		{
+			name: "repeated listing hits the cache",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 1,
+		},
+		{
+			name: "datacenter info shares the jobs cache",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				dc, err := s.getDatacenterInfo(ctx, "dc1", nil)
+				if err != nil {
+					t.Fatalf("getDatacenterInfo() error = %v", err)
+				}
+				if dc.JobsTotal != 2 || dc.JobsRunning != 1 || dc.JobsStopped != 1 {
+					t.Errorf("jobs total %d, running %d, stopped %d, want 2, 1 and 1", dc.JobsTotal, dc.JobsRunning, dc.JobsStopped)
+				}
+			},
+			wantLists: 1,
+		},
+		{
+			name: "prefix answered from a warm cache",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				if got := getJobs(t, ctx, s, "api"); got != 1 {
+					t.Errorf("%d jobs match the prefix, want 1", got)
+				}
+			},
+			wantLists: 1,
+		},
+		{
+			name: "prefix result isn't cached",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "api")
+				if got := getJobs(t, ctx, s, ""); got != 2 {
+					t.Errorf("%d jobs after a prefix listing, want 2", got)
+				}
+			},
+			wantLists: 2,
+		},
+		{
+			name: "fresh read bypasses the cache",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				getJobs(t, WithFresh(ctx), s, "")
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 2,
+		},
+		{
+			name:    "expired after the jobs TTL",
+			jobsTTL: 10 * time.Millisecond,
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				time.Sleep(20 * time.Millisecond)
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 2,
+		},
+		{
+			name: "start invalidates",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				_, _ = s.StartJob(ctx, "dc1", "worker")
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 2,
+		},
+		{
+			name: "stop invalidates",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				_, _ = s.StopJob(ctx, "dc1", "api", false)
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 2,
+		},
+		{
+			name:   "failed stop still invalidates",
+			jobErr: errors.New("nomad unavailable"),
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				if _, err := s.StopJob(ctx, "dc1", "api", false); err == nil {
+					t.Fatal("StopJob() error = nil, want the repository error")
+				}
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 2,
+		},
+		{
+			name: "other datacenters keep their cache",
+			steps: func(t *testing.T, ctx context.Context, s *datacenterService) {
+				getJobs(t, ctx, s, "")
+				_, _ = s.StopJob(ctx, "dc2", "api", false)
+				getJobs(t, ctx, s, "")
+			},
+			wantLists: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", jobErr: tt.jobErr, jobs: []model.Job{
+					{ID: "api", Status: "running"},
+					{ID: "worker", Status: "dead"},
+				}},
+				"dc2": {region: "eu"},
+			})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{jobsTTL: tt.jobsTTL})
+
+			tt.steps(t, context.Background(), svc)
+
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			if len(repo.jobLists) != tt.wantLists {
+				t.Errorf("%d job listings reached the repository, want %d", len(repo.jobLists), tt.wantLists)
+			}
+		})
+	}
+}
+
+func TestCacheTTLPerResource(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 1, false), jobs: []model.Job{{ID: "api"}}},
+	})
+	svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{
+		nodesTTL: 10 * time.Millisecond,
+		jobsTTL:  time.Minute,
+	})
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := svc.getDatacenterInfo(ctx, "dc1", nil); err != nil {
+			t.Fatalf("getDatacenterInfo() error = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.nodeLists != 2 {
+		t.Errorf("%d node listings, want 2 once the nodes TTL expired", repo.nodeLists)
+	}
+	if len(repo.jobLists) != 1 {
+		t.Errorf("%d job listings, want 1 within the jobs TTL", len(repo.jobLists))
+	}
+}
+
+// getJobs lists the jobs of dc1 whose ID starts with prefix and returns how many matched
+func getJobs(t *testing.T, ctx context.Context, s *datacenterService, prefix string) int {
+	t.Helper()
+
+	list, err := s.GetJobs(ctx, "dc1", model.JobFilter{Prefix: prefix})
+	if err != nil {
+		t.Fatalf("GetJobs(%q) error = %v", prefix, err)
+	}
+	return len(list.Jobs)
+}
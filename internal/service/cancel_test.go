@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationCancellation(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelled func() (context.Context, context.CancelFunc)
+		midway    bool // Cancel on the first node change instead of before the activation
+		wantErr   error
+		maxCalls  int
+	}{
+		{
+			name:      "cancelled before the activation",
+			cancelled: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			wantErr:   context.Canceled,
+		},
+		{
+			name:      "deadline exceeded before the activation",
+			cancelled: func() (context.Context, context.CancelFunc) { return context.WithTimeout(context.Background(), 0) },
+			wantErr:   context.DeadlineExceeded,
+		},
+		{
+			name:      "cancelled mid-operation",
+			cancelled: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			midway:    true,
+			wantErr:   context.Canceled,
+			maxCalls:  4, // Only the node changes already in flight (max concurrent node ops)
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 10, false), hasLeader: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 10, true), hasLeader: true},
+			}
+			repo := newMockNomadRepo(clusters)
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+
+			ctx, cancel := tt.cancelled()
+			defer cancel()
+			if tt.midway {
+				repo.onDrain = func(drainCall) { cancel() }
+			} else {
+				cancel()
+			}
+
+			result, err := svc.ActivateDatacenter(ctx, "dc3", false, false, nil)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if result != nil && !result.Cancelled {
+				t.Error("result is not marked as cancelled")
+			}
+			if len(repo.drainCalls) > tt.maxCalls {
+				t.Errorf("%d nodes touched after cancellation, want at most %d", len(repo.drainCalls), tt.maxCalls)
+			}
+			for _, call := range repo.drainCalls {
+				if call.cluster != repo.drainCalls[0].cluster {
+					t.Errorf("cluster %s touched after %s was cancelled", call.cluster, repo.drainCalls[0].cluster)
+				}
+			}
+			if current := etcd.current(); current.Datacenter != "dc1" {
+				t.Errorf("active datacenter = %s after a cancelled activation, want dc1", current.Datacenter)
+			}
+		})
+	}
+}
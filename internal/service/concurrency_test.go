@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestNodeOperationsConcurrencyLimit(t *testing.T) {
+	operations := []struct {
+		name string
+		run  func(ctx context.Context, s *datacenterService) error
+	}{
+		{
+			name: "ActivateDatacenter",
+			run: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+				return err
+			},
+		},
+		{
+			name: "ActivateRegion",
+			run: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateRegion(ctx, "us", false, nil)
+				return err
+			},
+		},
+		{
+			name: "EnsureSingleActiveDatacenter",
+			run: func(ctx context.Context, s *datacenterService) error {
+				return s.EnsureSingleActiveDatacenter(ctx)
+			},
+		},
+		{
+			name: "DrainAllNodesInRegion",
+			run: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.DrainAllNodesInRegion(ctx, "eu")
+				return err
+			},
+		},
+	}
+	limits := []int{1, 3, 10}
+
+	for _, op := range operations {
+		for _, limit := range limits {
+			t.Run(fmt.Sprintf("%s limit %d", op.name, limit), func(t *testing.T) {
+				// dc1 and dc3 both serve, so every operation changes at least 12 nodes
+				repo := newMockNomadRepo(map[string]*mockCluster{
+					"dc1": {region: "eu", nodes: testNodes("dc1", 12, false), hasLeader: true},
+					"dc3": {region: "us", nodes: testNodes("dc3", 12, false), hasLeader: true},
+				})
+				repo.drainDelay = 5 * time.Millisecond
+				etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+				svc, _ := newTestService(t, repo, etcd, testServiceOptions{maxNodeOps: limit})
+
+				if err := op.run(context.Background(), svc); err != nil {
+					t.Fatalf("%s() error = %v", op.name, err)
+				}
+
+				if len(repo.drainCalls) < 12 {
+					t.Fatalf("%d node changes, want at least 12", len(repo.drainCalls))
+				}
+				if peak := int(repo.maxInFlight.Load()); peak > limit {
+					t.Errorf("limit %d: %d node changes in flight", limit, peak)
+				} else if limit > 1 && peak < 2 {
+					t.Errorf("limit %d: node changes never overlapped", limit)
+				}
+			})
+		}
+	}
+}
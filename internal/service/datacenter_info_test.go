@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestDatacenterInfoHeartbeatAndMyDC(t *testing.T) {
+	const age = 5 * time.Second
+
+	tests := []struct {
+		name       string
+		active     *model.ActiveDatacenter
+		wantActive []string // Datacenters expected to report a heartbeat age
+	}{
+		{
+			name:       "single active datacenter",
+			active:     &model.ActiveDatacenter{Datacenter: "dc2", Region: "eu"},
+			wantActive: []string{"dc2"},
+		},
+		{
+			name:       "active region",
+			active:     &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu", ActiveDatacenters: []string{"dc1", "dc2"}},
+			wantActive: []string{"dc1", "dc2"},
+		},
+		{
+			name: "no active datacenter",
+		},
+	}
+
+	// Each listing must set the fields the same way
+	lists := map[string]func(ctx context.Context, s *datacenterService) ([]model.Datacenter, error){
+		"datacenters": func(ctx context.Context, s *datacenterService) ([]model.Datacenter, error) {
+			return s.ListDatacenters(ctx)
+		},
+		"region datacenters": func(ctx context.Context, s *datacenterService) ([]model.Datacenter, error) {
+			return s.GetDatacentersByRegion(ctx, "eu")
+		},
+		"regions": func(ctx context.Context, s *datacenterService) ([]model.Datacenter, error) {
+			regions, err := s.ListRegions(ctx)
+			var dcs []model.Datacenter
+			for _, region := range regions {
+				dcs = append(dcs, region.Datacenters...)
+			}
+			return dcs, err
+		},
+	}
+
+	for _, tt := range tests {
+		for listName, list := range lists {
+			t.Run(tt.name+"/"+listName, func(t *testing.T) {
+				if tt.active != nil {
+					tt.active.LastHeartbeat = time.Now().Add(-age)
+				}
+				svc, _ := newTestService(t, newMockNomadRepo(activationClusters()), newMockEtcdRepo(tt.active), testServiceOptions{})
+
+				dcs, err := list(context.Background(), svc)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(dcs) == 0 {
+					t.Fatal("no datacenters listed")
+				}
+
+				for _, dc := range dcs {
+					if want := dc.Name == "dc1"; dc.IsMyDC != want {
+						t.Errorf("%s IsMyDC = %v, want %v", dc.Name, dc.IsMyDC, want)
+					}
+
+					if !slices.Contains(tt.wantActive, dc.Name) {
+						if dc.HeartbeatAge != 0 {
+							t.Errorf("%s HeartbeatAge = %d ms, want 0 for an inactive datacenter", dc.Name, dc.HeartbeatAge)
+						}
+						continue
+					}
+					// Allow for the time spent listing
+					if dc.HeartbeatAge < age.Milliseconds() || dc.HeartbeatAge > (age+time.Second).Milliseconds() {
+						t.Errorf("%s HeartbeatAge = %d ms, want about %d", dc.Name, dc.HeartbeatAge, age.Milliseconds())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDatacenterEmptyStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		empty      []string // Datacenters without nodes
+		drained    []string // Datacenters whose nodes are all drained
+		wantDC     map[string]string
+		wantRegion map[string]string
+	}{
+		{
+			name:       "datacenter without nodes",
+			empty:      []string{"dc3"},
+			wantDC:     map[string]string{"dc1": model.DatacenterStatusActive, "dc2": model.DatacenterStatusDraining, "dc3": model.DatacenterStatusEmpty},
+			wantRegion: map[string]string{"us": model.DatacenterStatusEmpty},
+		},
+		{
+			name:       "every datacenter of the region empty",
+			empty:      []string{"dc1", "dc2"},
+			wantDC:     map[string]string{"dc1": model.DatacenterStatusEmpty, "dc2": model.DatacenterStatusEmpty, "dc3": model.DatacenterStatusDraining},
+			wantRegion: map[string]string{"eu": model.DatacenterStatusEmpty, "us": model.DatacenterStatusDraining},
+		},
+		{
+			name:       "empty next to an active sibling",
+			empty:      []string{"dc2"},
+			wantDC:     map[string]string{"dc1": model.DatacenterStatusActive, "dc2": model.DatacenterStatusEmpty},
+			wantRegion: map[string]string{"eu": model.DatacenterStatusActive},
+		},
+		{
+			name:       "empty next to a draining sibling",
+			empty:      []string{"dc2"},
+			drained:    []string{"dc1"},
+			wantDC:     map[string]string{"dc1": model.DatacenterStatusDraining, "dc2": model.DatacenterStatusEmpty},
+			wantRegion: map[string]string{"eu": model.DatacenterStatusDraining},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := activationClusters()
+			for _, name := range tt.empty {
+				clusters[name].nodes = nil
+			}
+			for _, name := range tt.drained {
+				clusters[name].nodes = testNodes(name, 2, true)
+			}
+			svc, _ := newTestService(t, newMockNomadRepo(clusters), newMockEtcdRepo(nil), testServiceOptions{})
+
+			regions, err := svc.ListRegions(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			checked := 0
+			for _, region := range regions {
+				if want, ok := tt.wantRegion[region.Name]; ok && region.Status != want {
+					t.Errorf("region %s Status = %q, want %q", region.Name, region.Status, want)
+				}
+				for _, dc := range region.Datacenters {
+					want, ok := tt.wantDC[dc.Name]
+					if !ok {
+						continue
+					}
+					checked++
+					if dc.Status != want {
+						t.Errorf("%s Status = %q, want %q", dc.Name, dc.Status, want)
+					}
+					if want == model.DatacenterStatusEmpty && (dc.NodesTotal != 0 || dc.NodesDraining != 0 || dc.NodesReady != 0) {
+						t.Errorf("%s nodes = %d total, %d draining, %d ready, want none", dc.Name, dc.NodesTotal, dc.NodesDraining, dc.NodesReady)
+					}
+				}
+			}
+			if checked != len(tt.wantDC) {
+				t.Errorf("listed %d of the datacenters %v", checked, tt.wantDC)
+			}
+		})
+	}
+}
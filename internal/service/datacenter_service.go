@@ -1,18 +1,69 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/cache"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/metrics"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/notify"
 	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/util"
 )
 
+// maxConcurrentJobActions bounds parallel job start/stop calls per bulk job action
+const maxConcurrentJobActions = 10
+
+// maxConcurrentNodeListings bounds parallel node listings of the all-nodes listing
+const maxConcurrentNodeListings = 10
+
+var (
+	// ErrDatacenterNotFound is returned when no cluster is configured for the requested datacenter.
+	// It is the repository error, so both match with errors.Is.
+	ErrDatacenterNotFound = repository.ErrClusterNotFound
+
+	// ErrNodeNotFound is returned when a node does not exist in the requested datacenter
+	ErrNodeNotFound = errors.New("node not found")
+
+	// ErrReadOnly is returned for mutating operations when the service runs in read-only mode
+	ErrReadOnly = errors.New("read-only mode: mutating operations are disabled")
+
+	// ErrShuttingDown is returned for activations requested after shutdown began
+	ErrShuttingDown = errors.New("service is shutting down")
+
+	// ErrDatacenterDisabled is returned when activating a datacenter or region whose clusters are disabled in config
+	ErrDatacenterDisabled = errors.New("datacenter is disabled")
+
+	// ErrActivationInProgress matches an ActivationInProgressError with errors.Is
+	ErrActivationInProgress = errors.New("activation already in progress")
+)
+
+// ActivationInProgressError is returned when an activation starts while another one is running
+type ActivationInProgressError struct {
+	Target string // Datacenter or region being activated by the running activation
+}
+
+func (e *ActivationInProgressError) Error() string {
+	return fmt.Sprintf("activation of %s is already in progress", e.Target)
+}
+
+// Is makes errors.Is(err, ErrActivationInProgress) match
+func (e *ActivationInProgressError) Is(target error) bool {
+	return target == ErrActivationInProgress
+}
+
 // HealthChecker defines interface for health check operations
 type HealthChecker interface {
 	SetActiveRegion(region string)
@@ -24,20 +75,42 @@ type DatacenterService interface {
 	ListRegions(ctx context.Context) ([]model.Region, error)
 	GetDatacentersByRegion(ctx context.Context, region string) ([]model.Datacenter, error)
 	GetRegionDatacenters(ctx context.Context, region string) (*model.Region, error)
-	CheckClusterLeader(ctx context.Context, clusterName string) (bool, error)
+	ListClusters() []model.ClusterInfo
+	CheckClusterLeader(ctx context.Context, clusterName string) (leader string, hasLeader bool, err error)
+	GetClusterLeader(ctx context.Context, dc string) (*model.LeaderStatus, error)
+	GetClusterHealth(ctx context.Context, dc string) (*model.ClusterHealth, error)
+	CheckEtcdConnection(ctx context.Context) error
+	SaveHealthFailures(ctx context.Context, counts *model.HealthFailureCounts) error
+	LoadHealthFailures(ctx context.Context) (*model.HealthFailureCounts, error)
+	HealthSnapshot(ctx context.Context) *model.HealthSnapshot
 	GetNodes(ctx context.Context, dc string) ([]model.Node, error)
-	ActivateDatacenter(ctx context.Context, dc string) (*model.ActivationResult, error)
-	ActivateRegion(ctx context.Context, region string) (*model.ActivationResult, error)
-	DrainAllNodesInRegion(ctx context.Context, region string) error
+	GetNodesWithAllocations(ctx context.Context, dc string) ([]model.Node, error)
+	ListAllNodes(ctx context.Context) (*model.AllNodes, error)
+	SetNodeDrain(ctx context.Context, dc, nodeID string, drain bool, drainOverride *model.DrainOverride) (*model.Node, error)
+	ActivateDatacenter(ctx context.Context, dc string, dryRun, exclusive bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
+	ActivateRegion(ctx context.Context, region string, dryRun bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
+	VerifyActivation(ctx context.Context, dc string, exclusive bool) (*model.ActivationVerification, error)
+	DrainAllNodesInRegion(ctx context.Context, region string) (*model.DrainResult, error)
+	EmergencyDrainAll(ctx context.Context) (*model.ActivationResult, error)
+	DeactivateDatacenter(ctx context.Context, dc string, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
+	DeactivateRegion(ctx context.Context, region string, drainOverride *model.DrainOverride) (*model.ActivationResult, error)
 	EnsureSingleActiveDatacenter(ctx context.Context) error
 	PerformStartupReconciliation(ctx context.Context) error
 	StartHeartbeat(ctx context.Context)
 	StopHeartbeat()
+	Shutdown(ctx context.Context) error
+	RelinquishActive(ctx context.Context) error
 	SetHealthChecker(hc HealthChecker)
-	GetJobs(ctx context.Context, dc string) ([]model.Job, error)
+	GetJobs(ctx context.Context, dc string, filter model.JobFilter) (*model.JobList, error)
+	GetJob(ctx context.Context, dc, jobID string) (*model.JobDetail, error)
 	StartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error)
-	StopJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error)
+	StopJob(ctx context.Context, dc, jobID string, purge bool) (*model.JobActionResult, error)
+	RestartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error)
+	BulkJobAction(ctx context.Context, dc string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error)
 	GetStatus(ctx context.Context) (*model.ServiceStatus, error)
+	GetActiveSummary(ctx context.Context) (*model.ActiveSummary, error)
+	GetActiveRegion(ctx context.Context) (string, error)
+	GetActivationHistory(ctx context.Context, limit int) ([]model.ActivationEvent, error)
 }
 
 // datacenterService implements DatacenterService interface
@@ -45,13 +118,31 @@ type datacenterService struct {
 	repo          repository.NomadRepository
 	etcdRepo      repository.EtcdRepository
 	cache         cache.Cache
-	ttl           time.Duration
+	nodesTTL      time.Duration
+	jobsTTL       time.Duration
 	logger        *slog.Logger
 	healthChecker HealthChecker
 	myDatacenter  string
+	preferredDC   string // Its region is kept active when resolving several active regions
+	activeMode    string // config.ActiveModeSingle or config.ActiveModeRegionWide
 	heartbeatCfg  config.HeartbeatConfig
-	amDrained     bool // Tracks if we intentionally drained our nodes
+	amDrained     atomic.Bool // Tracks if we intentionally drained our nodes; set by activations and the heartbeat loop
 	stopHeartbeat chan struct{}
+	heartbeatDone chan struct{} // Closed when the heartbeat loop has returned
+
+	maxConcurrentNodeOps int                     // Maximum number of simultaneous node drain operations
+	drainOpts            model.DrainOptions      // Default drain options from config
+	activationCfg        config.ActivationConfig // How activations undrain nodes
+	startupCfg           config.StartupConfig    // Startup reconciliation behavior
+	maxNodesAffected     int                     // Node changes an activation may apply without confirmation (0 = no cap)
+	degradedJobRatio     float64                 // Fraction of failing jobs that marks an active region as degraded
+	notifier             notify.Notifier
+	readOnly             bool // Disables all mutating operations
+
+	activationMu     sync.Mutex
+	activationTarget string        // Target of the running activation, empty when none is running
+	activationDone   chan struct{} // Closed when the running activation finishes
+	shuttingDown     bool          // Set by Shutdown, refuses new activations
 }
 
 // clusterNodesInfo stores nodes information for a cluster
@@ -62,35 +153,141 @@ type clusterNodesInfo struct {
 	err         error
 }
 
+// Config holds the settings of the datacenter service, taken from the application config
+type Config struct {
+	MyDatacenter         string                  // Datacenter this instance runs in
+	PreferredDatacenter  string                  // Its region is kept active when resolving several active regions
+	ActiveMode           string                  // config.ActiveModeSingle or config.ActiveModeRegionWide
+	NodesTTL             time.Duration           // Cache TTL of node lists
+	JobsTTL              time.Duration           // Cache TTL of job lists
+	Heartbeat            config.HeartbeatConfig  // Heartbeat timing and quorum behavior
+	Startup              config.StartupConfig    // Startup reconciliation behavior
+	MaxConcurrentNodeOps int                     // Maximum number of simultaneous node drain operations
+	Drain                config.DrainConfig      // Default drain options
+	Activation           config.ActivationConfig // How activations undrain nodes
+	Safety               config.SafetyConfig     // Limits on node changes per activation
+	DegradedJobRatio     float64                 // Fraction of failing jobs that marks an active region as degraded
+	ReadOnly             bool                    // Disables all mutating operations
+}
+
+// NewConfig takes the datacenter service settings from the application config
+func NewConfig(cfg *config.Config) Config {
+	return Config{
+		MyDatacenter:         cfg.MyDatacenter,
+		PreferredDatacenter:  cfg.PreferredDatacenter,
+		ActiveMode:           cfg.ActiveMode,
+		NodesTTL:             cfg.Cache.NodesTTL,
+		JobsTTL:              cfg.Cache.JobsTTL,
+		Heartbeat:            cfg.Heartbeat,
+		Startup:              cfg.Startup,
+		MaxConcurrentNodeOps: cfg.MaxConcurrentNodeOperations,
+		Drain:                cfg.Drain,
+		Activation:           cfg.Activation,
+		Safety:               cfg.Safety,
+		DegradedJobRatio:     cfg.DegradedJobFailureRatio,
+		ReadOnly:             cfg.ReadOnly,
+	}
+}
+
 // NewDatacenterService creates a new datacenter service
 func NewDatacenterService(
 	repo repository.NomadRepository,
 	etcdRepo repository.EtcdRepository,
 	cache cache.Cache,
-	ttl time.Duration,
-	myDatacenter string,
-	heartbeatCfg config.HeartbeatConfig,
+	cfg Config,
+	notifier notify.Notifier,
 	logger *slog.Logger,
 ) DatacenterService {
 	return &datacenterService{
-		repo:          repo,
-		etcdRepo:      etcdRepo,
-		cache:         cache,
-		ttl:           ttl,
-		logger:        logger,
-		myDatacenter:  myDatacenter,
-		heartbeatCfg:  heartbeatCfg,
-		stopHeartbeat: make(chan struct{}),
+		repo:                 repo,
+		etcdRepo:             etcdRepo,
+		cache:                cache,
+		nodesTTL:             cfg.NodesTTL,
+		jobsTTL:              cfg.JobsTTL,
+		logger:               logger,
+		myDatacenter:         cfg.MyDatacenter,
+		preferredDC:          cfg.PreferredDatacenter,
+		activeMode:           cfg.ActiveMode,
+		heartbeatCfg:         cfg.Heartbeat,
+		startupCfg:           cfg.Startup,
+		stopHeartbeat:        make(chan struct{}),
+		heartbeatDone:        make(chan struct{}),
+		maxConcurrentNodeOps: cfg.MaxConcurrentNodeOps,
+		drainOpts: model.DrainOptions{
+			Deadline:         cfg.Drain.Deadline,
+			IgnoreSystemJobs: cfg.Drain.IgnoreSystemJobs,
+		},
+		activationCfg:    cfg.Activation,
+		maxNodesAffected: cfg.Safety.MaxNodesAffected,
+		degradedJobRatio: cfg.DegradedJobRatio,
+		notifier:         notifier,
+		readOnly:         cfg.ReadOnly,
+	}
+}
+
+// acquireActivation marks target as the running activation so overlapping activations can't
+// race on node state. It fails with an ActivationInProgressError while another one runs.
+// The returned function releases the activation.
+func (s *datacenterService) acquireActivation(target string) (func(), error) {
+	s.activationMu.Lock()
+	defer s.activationMu.Unlock()
+
+	if s.shuttingDown {
+		return nil, ErrShuttingDown
+	}
+	if s.activationTarget != "" {
+		return nil, &ActivationInProgressError{Target: s.activationTarget}
+	}
+	s.activationTarget = target
+	done := make(chan struct{})
+	s.activationDone = done
+
+	return func() {
+		s.activationMu.Lock()
+		s.activationTarget = ""
+		s.activationDone = nil
+		s.activationMu.Unlock()
+		close(done)
+	}, nil
+}
+
+// Shutdown refuses new activations and waits for the running one, if any, to finish.
+// It returns an error when ctx is done before the activation finished.
+func (s *datacenterService) Shutdown(ctx context.Context) error {
+	s.activationMu.Lock()
+	s.shuttingDown = true
+	target := s.activationTarget
+	done := s.activationDone
+	s.activationMu.Unlock()
+
+	if done == nil {
+		s.logger.Info("no activation in progress, nothing to wait for",
+			slog.Int("waited_operations", 0))
+		return nil
+	}
+
+	s.logger.Info("waiting for in-progress activation to finish",
+		slog.String("target", target))
+
+	select {
+	case <-done:
+		s.logger.Info("in-progress activation finished",
+			slog.String("target", target),
+			slog.Int("waited_operations", 1))
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("activation of %s did not finish before shutdown: %w", target, ctx.Err())
 	}
 }
 
 // ListDatacenters returns information about all datacenters
 func (s *datacenterService) ListDatacenters(ctx context.Context) ([]model.Datacenter, error) {
 	clusterNames := s.repo.GetClusterNames()
+	active := s.activeDatacenterRecord(ctx)
 
 	// Fetch datacenter info in parallel
 	results := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, name string) (model.Datacenter, error) {
-		dc, err := s.getDatacenterInfo(ctx, name)
+		dc, err := s.getDatacenterInfo(ctx, name, active)
 		if err != nil {
 			s.logger.Error("failed to get datacenter info",
 				slog.String("datacenter", name),
@@ -98,8 +295,10 @@ func (s *datacenterService) ListDatacenters(ctx context.Context) ([]model.Datace
 			)
 			// Return error status for this datacenter instead of failing
 			return model.Datacenter{
-				Name:   name,
-				Status: model.DatacenterStatusError,
+				Name:    name,
+				Status:  model.DatacenterStatusError,
+				IsMyDC:  name == s.myDatacenter,
+				Enabled: s.repo.IsClusterEnabled(name),
 			}, nil
 		}
 		return dc, nil
@@ -114,8 +313,24 @@ func (s *datacenterService) ListDatacenters(ctx context.Context) ([]model.Datace
 	return datacenters, nil
 }
 
+// activeDatacenterRecord reads the active datacenter record from etcd, or returns nil when there is none
+// or it can't be read. List calls read it once and pass it to getDatacenterInfo.
+func (s *datacenterService) activeDatacenterRecord(ctx context.Context) *model.ActiveDatacenter {
+	active, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNoActiveDatacenter) {
+			s.logger.Debug("failed to read active datacenter for heartbeat age",
+				slog.String("error", err.Error()),
+			)
+		}
+		return nil
+	}
+	return active
+}
+
 // getDatacenterInfo retrieves datacenter information with caching
-func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string) (model.Datacenter, error) {
+// The heartbeat age is only set when active, the etcd active datacenter record, names this datacenter
+func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string, active *model.ActiveDatacenter) (model.Datacenter, error) {
 	nodes, err := s.GetNodes(ctx, name)
 	if err != nil {
 		return model.Datacenter{}, err
@@ -131,6 +346,11 @@ func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string)
 		Name:       name,
 		Region:     region,
 		NodesTotal: len(nodes),
+		IsMyDC:     name == s.myDatacenter,
+		Enabled:    s.repo.IsClusterEnabled(name),
+	}
+	if active != nil && active.IsActive(name) {
+		dc.HeartbeatAge = active.HeartbeatAge().Milliseconds()
 	}
 
 	// Calculate status and node statistics
@@ -148,7 +368,10 @@ func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string)
 	}
 
 	// Determine datacenter status
-	if dc.NodesDraining == dc.NodesTotal {
+	if dc.NodesTotal == 0 {
+		// Without nodes there is nothing draining, don't report the cluster as such
+		dc.Status = model.DatacenterStatusEmpty
+	} else if dc.NodesDraining == dc.NodesTotal {
 		dc.Status = model.DatacenterStatusDraining
 	} else if dc.NodesReady > 0 {
 		dc.Status = model.DatacenterStatusActive
@@ -158,7 +381,7 @@ func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string)
 	}
 
 	// Get jobs statistics
-	jobs, err := s.repo.ListJobs(ctx, name)
+	jobs, err := s.listJobs(ctx, name, "")
 	if err != nil {
 		// Log error but don't fail - jobs stats are optional
 		s.logger.Warn("failed to get jobs for datacenter",
@@ -173,19 +396,54 @@ func (s *datacenterService) getDatacenterInfo(ctx context.Context, name string)
 			} else if job.Status == "dead" {
 				dc.JobsStopped++
 			}
+			if job.Failed > 0 {
+				dc.JobsFailing++
+			}
 		}
 	}
 
 	return dc, nil
 }
 
+// freshKey is the context key marking reads that must bypass the cache
+type freshKey struct{}
+
+// WithFresh returns a context under which node and job lists are read from Nomad instead of
+// the cache. The fresh results still refresh the cache for later reads.
+func WithFresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+// isFresh reports whether ctx asks to bypass the cache
+func isFresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshKey{}).(bool)
+	return fresh
+}
+
+// activatedByKey is the context key naming who started an activation
+type activatedByKey struct{}
+
+// WithActivatedBy returns a context under which activations are recorded in etcd,
+// the history and notifications as started by activatedBy instead of the API
+func WithActivatedBy(ctx context.Context, activatedBy string) context.Context {
+	return context.WithValue(ctx, activatedByKey{}, activatedBy)
+}
+
+// activatedBy returns who started the activation of ctx, or fallback when it came from the API
+func activatedBy(ctx context.Context, fallback string) string {
+	if by, ok := ctx.Value(activatedByKey{}).(string); ok && by != "" {
+		return by
+	}
+	return fallback
+}
+
 // GetNodes returns all nodes for a specific datacenter
 func (s *datacenterService) GetNodes(ctx context.Context, dc string) ([]model.Node, error) {
 	cacheKey := fmt.Sprintf("%s:nodes", dc)
 
 	// Try to get from cache
-	if cached, ok := s.cache.Get(cacheKey); ok {
-		if nodes, ok := cached.([]model.Node); ok {
+	if !isFresh(ctx) {
+		if nodes, ok := cache.GetTyped[[]model.Node](s.cache, cacheKey); ok {
 			s.logger.Debug("nodes retrieved from cache",
 				slog.String("datacenter", dc),
 				slog.Int("count", len(nodes)),
@@ -201,29 +459,219 @@ func (s *datacenterService) GetNodes(ctx context.Context, dc string) ([]model.No
 	}
 
 	// Store in cache
-	s.cache.Set(cacheKey, nodes, s.ttl)
+	s.cache.Set(cacheKey, nodes, s.nodesTTL)
+
+	return nodes, nil
+}
+
+// ListAllNodes returns the nodes of every datacenter, grouped by datacenter and fetched in parallel
+// through the node cache. A datacenter whose nodes can't be listed is returned with its error.
+func (s *datacenterService) ListAllNodes(ctx context.Context) (*model.AllNodes, error) {
+	clusterNames := s.repo.GetClusterNames()
+
+	results := concurrent.ParallelMapWithLimit(ctx, clusterNames, func(ctx context.Context, name string) ([]model.Node, error) {
+		return s.GetNodes(ctx, name)
+	}, maxConcurrentNodeListings)
+
+	all := &model.AllNodes{Datacenters: make([]model.DatacenterNodes, 0, len(clusterNames))}
+	for i, result := range results {
+		name := clusterNames[i]
+		region, err := s.repo.GetClusterRegion(name)
+		if err != nil {
+			region = "unknown"
+		}
+
+		group := model.DatacenterNodes{
+			Datacenter: name,
+			Region:     region,
+			Nodes:      []model.Node{},
+		}
+		if result.Error != nil {
+			s.logger.Warn("failed to list nodes of datacenter",
+				slog.String("datacenter", name),
+				slog.String("error", result.Error.Error()),
+			)
+			group.Error = result.Error.Error()
+		} else {
+			// Copy so the cached slice isn't modified
+			for _, node := range result.Value {
+				node.Region = region
+				group.Nodes = append(group.Nodes, node)
+			}
+			all.Total += len(group.Nodes)
+		}
+		all.Datacenters = append(all.Datacenters, group)
+	}
+
+	return all, nil
+}
+
+// GetNodesWithAllocations returns the nodes of a datacenter with their active allocation counts.
+// Counts are fetched in parallel and never cached; a node whose allocations can't be listed
+// is returned without a count.
+func (s *datacenterService) GetNodesWithAllocations(ctx context.Context, dc string) ([]model.Node, error) {
+	cached, err := s.GetNodes(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy so the cached slice is never mutated
+	nodes := append([]model.Node(nil), cached...)
+
+	countResults := concurrent.ParallelMapWithLimit(ctx, nodes, func(ctx context.Context, node model.Node) (int, error) {
+		allocs, err := s.repo.ListNodeAllocations(ctx, dc, node.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		count := 0
+		for _, alloc := range allocs {
+			if alloc.IsActive() {
+				count++
+			}
+		}
+		return count, nil
+	}, s.maxConcurrentNodeOps)
+
+	for i, res := range countResults {
+		if res.Error != nil {
+			s.logger.Warn("failed to list node allocations",
+				slog.String("datacenter", dc),
+				slog.String("node_id", nodes[i].ID),
+				slog.String("error", res.Error.Error()),
+			)
+			continue
+		}
+		count := res.Value
+		nodes[i].AllocCount = &count
+	}
 
 	return nodes, nil
 }
 
+// SetNodeDrain drains or undrains a single node and returns its updated state
+func (s *datacenterService) SetNodeDrain(ctx context.Context, dc, nodeID string, drain bool, drainOverride *model.DrainOverride) (*model.Node, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	s.logger.Info("changing node drain state",
+		slog.String("datacenter", dc),
+		slog.String("node_id", nodeID),
+		slog.Bool("drain", drain),
+	)
+
+	// Validate against live state rather than cache so a stale list can't hide a removed node
+	nodes, err := s.repo.ListNodes(ctx, dc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if findNode(nodes, nodeID) == nil {
+		return nil, fmt.Errorf("%w: %s in datacenter %s", ErrNodeNotFound, nodeID, dc)
+	}
+
+	if err := s.setNodeDrain(ctx, dc, nodeID, drain, s.drainOptions(drainOverride)); err != nil {
+		return nil, fmt.Errorf("failed to set drain=%v on node %s: %w", drain, nodeID, err)
+	}
+
+	// Invalidate cache and re-read the node to report its updated state
+	s.cache.Delete(fmt.Sprintf("%s:nodes", dc))
+
+	nodes, err = s.GetNodes(ctx, dc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read updated node state: %w", err)
+	}
+	node := findNode(nodes, nodeID)
+	if node == nil {
+		return nil, fmt.Errorf("%w: %s in datacenter %s", ErrNodeNotFound, nodeID, dc)
+	}
+
+	s.logger.Info("node drain state changed",
+		slog.String("datacenter", dc),
+		slog.String("node_id", nodeID),
+		slog.Bool("drain", node.Drain),
+		slog.String("scheduling_eligibility", node.SchedulingEligibility),
+	)
+
+	return node, nil
+}
+
+// findNode returns the node with the given ID, or nil if it is not in the list
+func findNode(nodes []model.Node, nodeID string) *model.Node {
+	for i := range nodes {
+		if nodes[i].ID == nodeID {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// enabledClusters filters out clusters disabled in config, which activation leaves untouched
+func (s *datacenterService) enabledClusters(names []string) []string {
+	enabled := make([]string, 0, len(names))
+	for _, name := range names {
+		if !s.repo.IsClusterEnabled(name) {
+			s.logger.Debug("skipping disabled cluster", slog.String("cluster", name))
+			continue
+		}
+		enabled = append(enabled, name)
+	}
+	return enabled
+}
+
 // ActivateDatacenter activates the specified datacenter and drains all datacenters in other regions
 // Uses continue-on-error approach: collects errors but continues with other clusters/nodes
-func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC string) (*model.ActivationResult, error) {
+// When dryRun is true, only planned node changes are computed and nothing is mutated
+// When exclusive is true, other datacenters in the target's region are drained as well
+func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC string, dryRun, exclusive bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	// Dry runs don't mutate anything and stay available in read-only mode
+	if s.readOnly && !dryRun {
+		return nil, ErrReadOnly
+	}
+
+	if !dryRun {
+		release, err := s.acquireActivation(targetDC)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	drainOpts := s.drainOptions(drainOverride)
+
 	s.logger.Info("starting datacenter activation",
 		slog.String("target_datacenter", targetDC),
+		slog.Bool("dry_run", dryRun),
+		slog.Bool("exclusive", exclusive),
+		slog.Duration("drain_deadline", drainOpts.Deadline),
 	)
 
+	start := time.Now()
+
 	result := &model.ActivationResult{
 		Activated: targetDC,
+		DryRun:    dryRun,
+		Exclusive: exclusive,
 		Errors:    []string{},
 	}
 
-	clusterNames := s.repo.GetClusterNames()
+	clusterNames := s.enabledClusters(s.repo.GetClusterNames())
 
 	// Verify target datacenter exists and get its region
 	targetRegion, err := s.repo.GetClusterRegion(targetDC)
 	if err != nil {
-		return nil, fmt.Errorf("target datacenter %s not found: %w", targetDC, err)
+		err = fmt.Errorf("target datacenter: %w", err)
+		if !dryRun {
+			s.recordActivation(targetDC, start, nil, err)
+		}
+		return nil, err
+	}
+	if !s.repo.IsClusterEnabled(targetDC) {
+		err := fmt.Errorf("%w: %s", ErrDatacenterDisabled, targetDC)
+		if !dryRun {
+			s.recordActivation(targetDC, start, nil, err)
+		}
+		return nil, err
 	}
 
 	s.logger.Info("activating datacenter in region",
@@ -242,8 +690,8 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 			return clusterNodesInfo{clusterName: clusterName, err: err}, nil
 		}
 
-		// Skip datacenters in the same region (except target) - preserve their state
-		if clusterRegion == targetRegion && clusterName != targetDC {
+		// Skip datacenters in the same region (except target) - preserve their state unless exclusive
+		if !exclusive && clusterRegion == targetRegion && clusterName != targetDC {
 			s.logger.Debug("skipping datacenter in same region",
 				slog.String("cluster", clusterName),
 				slog.String("region", clusterRegion),
@@ -257,7 +705,7 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 				slog.String("cluster", clusterName),
 				slog.String("error", err.Error()),
 			)
-			return clusterNodesInfo{clusterName: clusterName, err: err}, nil
+			return clusterNodesInfo{clusterName: clusterName, region: clusterRegion, err: err}, nil
 		}
 
 		return clusterNodesInfo{
@@ -267,14 +715,28 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		}, nil
 	})
 
+	// Refuse oversized activations before touching any node
+	if !dryRun {
+		if err := s.checkNodesAffected(ctx, clusterNodesResults, func(info clusterNodesInfo) bool { return info.clusterName != targetDC }); err != nil {
+			s.recordActivation(targetDC, start, nil, err)
+			return nil, err
+		}
+	}
+
 	// Process all datacenters - continue on error, collect errors
 	for _, clusterResult := range clusterNodesResults {
+		// Stop touching further clusters once the activation is cancelled
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			break
+		}
+
 		clusterInfo := clusterResult.Value
 
 		// If error fetching this cluster, add to errors and continue
 		if clusterInfo.err != nil {
 			errMsg := fmt.Sprintf("cluster %s: failed to fetch nodes: %v", clusterInfo.clusterName, clusterInfo.err)
-			result.Errors = append(result.Errors, errMsg)
+			result.AddClusterError(clusterInfo.clusterName, clusterInfo.region, errMsg)
 			s.logger.Warn("skipping cluster due to error",
 				slog.String("cluster", clusterInfo.clusterName),
 				slog.String("error", clusterInfo.err.Error()),
@@ -289,6 +751,7 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 
 		clusterName := clusterInfo.clusterName
 		nodes := clusterInfo.nodes
+		summary := result.Cluster(clusterName, clusterInfo.region)
 
 		// Drain if in different region, activate if target datacenter
 		shouldDrain := clusterName != targetDC
@@ -302,9 +765,15 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		}
 
 		nodesToChange := make([]nodeToChange, 0, len(nodes))
+		pendingChanges := 0
 		for _, node := range nodes {
 			nodeIsEligible := node.SchedulingEligibility == "eligible"
 			alreadyCorrect := (node.Drain == shouldDrain) && (nodeIsEligible == shouldBeEligible)
+			if !alreadyCorrect {
+				pendingChanges++
+			} else {
+				summary.Skipped++
+			}
 
 			nodesToChange = append(nodesToChange, nodeToChange{
 				node:           node,
@@ -313,19 +782,48 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 			})
 		}
 
-		// OPTIMIZATION: Apply changes to nodes in parallel
+		// In dry-run mode only record what would change
+		if dryRun {
+			for _, ntc := range nodesToChange {
+				if ntc.alreadyCorrect {
+					continue
+				}
+				result.PlannedChanges = append(result.PlannedChanges, plannedNodeChange(clusterName, ntc.node, shouldDrain))
+				if shouldDrain {
+					result.DrainedNodes++
+					summary.Drained++
+				} else {
+					result.UnDrainedNodes++
+					summary.UnDrained++
+				}
+			}
+			continue
+		}
+
+		// OPTIMIZATION: Apply changes to nodes in parallel (bounded to avoid Nomad API rate limits)
 		type nodeResult struct {
 			nodeID  string
 			success bool
 		}
 
-		nodeResults := concurrent.ParallelMap(ctx, nodesToChange, func(ctx context.Context, ntc nodeToChange) (nodeResult, error) {
+		// The staged strategy batches only the nodes that change
+		stages := s.undrainStages(ctx, clusterName, clusterInfo.region, shouldDrain, result)
+		if stages != nil {
+			nodesToChange = slices.DeleteFunc(nodesToChange, func(ntc nodeToChange) bool { return ntc.alreadyCorrect })
+		}
+
+		var completedChanges atomic.Int64
+		nodeResults := applyInStages(ctx, nodesToChange, func(ctx context.Context, ntc nodeToChange) (nodeResult, error) {
 			if ntc.alreadyCorrect {
 				return nodeResult{nodeID: "", success: true}, nil // Skip, already correct
 			}
 
 			// Apply the change
-			err := s.repo.SetNodeDrain(ctx, clusterName, ntc.node.ID, shouldDrain)
+			err := s.setNodeDrain(ctx, clusterName, ntc.node.ID, shouldDrain, drainOpts)
+			if !isContextError(err) {
+				completed := int(completedChanges.Add(1))
+				reportProgress(ctx, nodeProgress(clusterName, ntc.node, shouldDrain, completed, pendingChanges, err))
+			}
 			if err != nil {
 				s.logger.Error("failed to set node drain",
 					slog.String("cluster", clusterName),
@@ -337,20 +835,28 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 			}
 
 			return nodeResult{nodeID: ntc.node.ID, success: true}, nil
-		})
+		}, s.maxConcurrentNodeOps, stages)
 
 		// Collect errors and update counters - CONTINUE on error
 		for _, nr := range nodeResults {
+			if isContextError(nr.Error) {
+				// Node was not touched because the activation was cancelled
+				result.Cancelled = true
+				continue
+			}
 			if nr.Error != nil {
 				// Add error but continue with other nodes
 				errMsg := fmt.Sprintf("cluster %s, node %s: %v", clusterName, nr.Value.nodeID, nr.Error)
 				result.Errors = append(result.Errors, errMsg)
+				summary.Errors = append(summary.Errors, errMsg)
 			} else if nr.Value.success && nr.Value.nodeID != "" {
 				// Update counters only for successful changes
 				if shouldDrain {
 					result.DrainedNodes++
+					summary.Drained++
 				} else {
 					result.UnDrainedNodes++
+					summary.UnDrained++
 				}
 			}
 		}
@@ -366,16 +872,24 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		slog.Int("errors_count", len(result.Errors)),
 	)
 
+	if result.Cancelled {
+		return s.cancelActivation(ctx, targetDC, start, result, dryRun)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
 	// Start dead jobs and trigger evaluations for the activated datacenter
 	if result.UnDrainedNodes > 0 {
 		// Start dead jobs BEFORE triggering evaluations so they are included in the eval
 		s.logger.Info("checking for dead jobs to restart",
 			slog.String("datacenter", targetDC),
 		)
-		jobs, err := s.repo.ListJobs(ctx, targetDC)
+		jobs, err := s.repo.ListJobs(ctx, targetDC, "")
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to list jobs for %s: %v", targetDC, err)
-			result.Errors = append(result.Errors, errMsg)
+			result.AddClusterError(targetDC, targetRegion, errMsg)
 			s.logger.Warn("failed to list jobs",
 				slog.String("datacenter", targetDC),
 				slog.String("error", err.Error()),
@@ -391,7 +905,7 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 					)
 					if err := s.repo.StartJob(ctx, targetDC, job.ID); err != nil {
 						errMsg := fmt.Sprintf("failed to start job %s: %v", job.ID, err)
-						result.Errors = append(result.Errors, errMsg)
+						result.AddClusterError(targetDC, targetRegion, errMsg)
 						s.logger.Warn("failed to start job",
 							slog.String("datacenter", targetDC),
 							slog.String("job_id", job.ID),
@@ -402,6 +916,7 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 					}
 				}
 			}
+			s.cache.Delete(fmt.Sprintf("%s:jobs", targetDC))
 			if startedJobs > 0 {
 				s.logger.Info("dead jobs started successfully",
 					slog.String("datacenter", targetDC),
@@ -417,7 +932,7 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		if err := s.repo.TriggerJobEvaluations(ctx, targetDC); err != nil {
 			// Log error but don't fail the activation
 			errMsg := fmt.Sprintf("failed to trigger job evaluations for %s: %v", targetDC, err)
-			result.Errors = append(result.Errors, errMsg)
+			result.AddClusterError(targetDC, targetRegion, errMsg)
 			s.logger.Warn("failed to trigger job evaluations",
 				slog.String("datacenter", targetDC),
 				slog.String("error", err.Error()),
@@ -429,13 +944,8 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		}
 	}
 
-	// Write active datacenter info to etcd
-	activeInfo := &model.ActiveDatacenter{
-		Datacenter:    targetDC,
-		ActivatedAt:   time.Now(),
-		ActivatedBy:   "api",
-		LastHeartbeat: time.Now(),
-	}
+	// Write active datacenter info to etcd; an exclusive activation drained the rest of the region
+	activeInfo := s.newActiveRecord(targetDC, targetRegion, activatedBy(ctx, "api"), exclusive)
 	if err := s.etcdRepo.WriteActiveDatacenter(ctx, activeInfo); err != nil {
 		s.logger.Error("failed to write active datacenter to etcd",
 			"datacenter", targetDC,
@@ -443,9 +953,10 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		// Add to errors but don't fail activation
 		result.Errors = append(result.Errors, fmt.Sprintf("failed to write to etcd: %v", err))
 	} else {
-		s.logger.Info("wrote active datacenter to etcd", "datacenter", targetDC)
+		s.logger.Info("wrote active datacenter to etcd", "datacenters", activeInfo.Datacenters())
+		s.invalidateActiveSummary()
 		// Update local state
-		s.amDrained = false
+		s.setAmDrained(false)
 	}
 
 	// Update health checker to monitor the region of the newly activated datacenter
@@ -453,12 +964,190 @@ func (s *datacenterService) ActivateDatacenter(ctx context.Context, targetDC str
 		s.healthChecker.SetActiveRegion(targetRegion)
 	}
 
+	s.recordStuckNodes(ctx, result)
+	s.recordActivation(targetDC, start, result, nil)
+	reason := "datacenter activated via API"
+	if by := activatedBy(ctx, ""); by != "" {
+		reason = "datacenter activated by " + by
+	}
+	s.recordActivationEvent(ctx, model.ActivationTargetDatacenter, activatedBy(ctx, "api"), result)
+	s.notifier.Notify(model.NotificationEvent{
+		Type:       model.NotificationActivation,
+		Region:     targetRegion,
+		Datacenter: targetDC,
+		Reason:     reason,
+		ErrorCount: len(result.Errors),
+	})
+
 	return result, nil
 }
 
+// newActiveRecord builds the active datacenter record naming dc of region as active.
+// In region-wide mode every enabled datacenter of region is recorded as active, dc first,
+// unless onlyDC is set because the other datacenters of the region were drained.
+func (s *datacenterService) newActiveRecord(dc, region, activatedBy string, onlyDC bool) *model.ActiveDatacenter {
+	now := time.Now()
+	record := &model.ActiveDatacenter{
+		Datacenter:    dc,
+		Region:        region,
+		ActivatedAt:   now,
+		ActivatedBy:   activatedBy,
+		LastHeartbeat: now,
+	}
+	if s.activeMode != config.ActiveModeRegionWide || onlyDC {
+		return record
+	}
+
+	record.ActiveDatacenters = []string{dc}
+	for _, name := range s.enabledClusters(s.repo.GetClustersByRegion(region)) {
+		if name != dc {
+			record.ActiveDatacenters = append(record.ActiveDatacenters, name)
+		}
+	}
+	return record
+}
+
+// cancelActivation finalizes an activation that was interrupted by context cancellation
+// Remaining steps (job restarts, evaluations, etcd write) are skipped
+func (s *datacenterService) cancelActivation(ctx context.Context, target string, start time.Time, result *model.ActivationResult, dryRun bool) (*model.ActivationResult, error) {
+	cause := ctx.Err()
+	if cause == nil {
+		cause = context.Canceled
+	}
+
+	err := fmt.Errorf("activation of %s cancelled: %w", target, cause)
+	result.Errors = append(result.Errors, err.Error())
+
+	s.logger.Warn("activation cancelled, remaining nodes were not touched",
+		slog.String("target", target),
+		slog.Int("drained_nodes", result.DrainedNodes),
+		slog.Int("un_drained_nodes", result.UnDrainedNodes),
+		slog.String("error", cause.Error()),
+	)
+
+	if !dryRun {
+		s.recordActivation(target, start, result, err)
+	}
+
+	return result, err
+}
+
+// isContextError reports whether err was caused by context cancellation or deadline
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// recordActivation records activation metrics for the given target
+func (s *datacenterService) recordActivation(target string, start time.Time, result *model.ActivationResult, err error) {
+	outcome := metrics.ResultSuccess
+	switch {
+	case err != nil || result == nil:
+		outcome = metrics.ResultError
+	case result.IsPartial():
+		outcome = metrics.ResultPartial
+	case len(result.Errors) > 0:
+		outcome = metrics.ResultError
+	}
+
+	metrics.ActivationsTotal.WithLabelValues(target, outcome).Inc()
+	metrics.ActivationDuration.Observe(time.Since(start).Seconds())
+}
+
+// recordActivationEvent appends the activation to the etcd history audit trail
+// Failures are logged only - history must never fail an activation
+func (s *datacenterService) recordActivationEvent(ctx context.Context, targetType, activatedBy string, result *model.ActivationResult) {
+	event := &model.ActivationEvent{
+		Target:         cmp.Or(result.Activated, result.Deactivated),
+		TargetType:     targetType,
+		ActivatedBy:    activatedBy,
+		Timestamp:      time.Now(),
+		DrainedNodes:   result.DrainedNodes,
+		UnDrainedNodes: result.UnDrainedNodes,
+		ErrorCount:     len(result.Errors),
+	}
+
+	if err := s.etcdRepo.AppendActivationEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to record activation event",
+			slog.String("target", event.Target),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// GetActivationHistory returns the most recent activation events, newest first
+func (s *datacenterService) GetActivationHistory(ctx context.Context, limit int) ([]model.ActivationEvent, error) {
+	events, err := s.etcdRepo.ListActivationEvents(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activation events: %w", err)
+	}
+	return events, nil
+}
+
+// setNodeDrain updates node drain status via the repository and records drain metrics
+func (s *datacenterService) setNodeDrain(ctx context.Context, clusterName, nodeID string, drain bool, opts model.DrainOptions) error {
+	if err := s.repo.SetNodeDrain(ctx, clusterName, nodeID, drain, opts); err != nil {
+		return err
+	}
+
+	if drain {
+		metrics.NodesDrainedTotal.Inc()
+	}
+
+	return nil
+}
+
+// drainOptions returns the configured drain options with any per-request override applied
+func (s *datacenterService) drainOptions(override *model.DrainOverride) model.DrainOptions {
+	opts := s.drainOpts
+	if override == nil {
+		return opts
+	}
+	if override.Deadline != nil {
+		opts.Deadline = *override.Deadline
+	}
+	if override.IgnoreSystemJobs != nil {
+		opts.IgnoreSystemJobs = *override.IgnoreSystemJobs
+	}
+	return opts
+}
+
+// setAmDrained updates whether this instance has drained its own datacenter
+func (s *datacenterService) setAmDrained(drained bool) {
+	s.amDrained.Store(drained)
+
+	if drained {
+		metrics.AmDrained.Set(1)
+	} else {
+		metrics.AmDrained.Set(0)
+	}
+}
+
+// plannedNodeChange describes the state change that setting drain would apply to a node
+func plannedNodeChange(clusterName string, node model.Node, drain bool) model.PlannedNodeChange {
+	after := model.NodeState{
+		Drain:                 drain,
+		SchedulingEligibility: "eligible",
+	}
+	if drain {
+		after.SchedulingEligibility = "ineligible"
+	}
+
+	return model.PlannedNodeChange{
+		Cluster:  clusterName,
+		NodeID:   node.ID,
+		NodeName: node.Name,
+		Before: model.NodeState{
+			Drain:                 node.Drain,
+			SchedulingEligibility: node.SchedulingEligibility,
+		},
+		After: after,
+	}
+}
+
 // ListRegions returns information about all regions with their datacenters
 func (s *datacenterService) ListRegions(ctx context.Context) ([]model.Region, error) {
 	regionNames := s.repo.GetAllRegions()
+	active := s.activeDatacenterRecord(ctx)
 
 	// Fetch region info in parallel with timeout for each region
 	results := concurrent.ParallelMap(ctx, regionNames, func(ctx context.Context, regionName string) (model.Region, error) {
@@ -466,7 +1155,7 @@ func (s *datacenterService) ListRegions(ctx context.Context) ([]model.Region, er
 		regionCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
-		region, err := s.getRegionInfo(regionCtx, regionName)
+		region, err := s.getRegionInfo(regionCtx, regionName, active)
 		if err != nil {
 			s.logger.Error("failed to get region info",
 				slog.String("region", regionName),
@@ -492,7 +1181,7 @@ func (s *datacenterService) ListRegions(ctx context.Context) ([]model.Region, er
 }
 
 // getRegionInfo retrieves region information including all its datacenters
-func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string) (model.Region, error) {
+func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string, active *model.ActiveDatacenter) (model.Region, error) {
 	clusterNames := s.repo.GetClustersByRegion(regionName)
 
 	// Fetch datacenter info in parallel with timeout for each datacenter
@@ -501,7 +1190,7 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 		dcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
 
-		dc, err := s.getDatacenterInfo(dcCtx, name)
+		dc, err := s.getDatacenterInfo(dcCtx, name, active)
 		if err != nil {
 			s.logger.Error("failed to get datacenter info",
 				slog.String("datacenter", name),
@@ -510,9 +1199,11 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 			)
 			// Return error status for this datacenter instead of failing
 			return model.Datacenter{
-				Name:   name,
-				Region: regionName,
-				Status: model.DatacenterStatusError,
+				Name:    name,
+				Region:  regionName,
+				Status:  model.DatacenterStatusError,
+				IsMyDC:  name == s.myDatacenter,
+				Enabled: s.repo.IsClusterEnabled(name),
 			}, nil
 		}
 		return dc, nil
@@ -522,10 +1213,12 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 	datacenters := make([]model.Datacenter, 0, len(results))
 	activeCount := 0
 	drainingCount := 0
+	emptyCount := 0
 	errorCount := 0
 	totalJobs := 0
 	runningJobs := 0
 	stoppedJobs := 0
+	failingJobs := 0
 
 	for _, result := range results {
 		dc := result.Value
@@ -537,6 +1230,8 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 			activeCount++
 		case model.DatacenterStatusDraining:
 			drainingCount++
+		case model.DatacenterStatusEmpty:
+			emptyCount++
 		case model.DatacenterStatusError:
 			errorCount++
 		}
@@ -545,16 +1240,21 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 		totalJobs += dc.JobsTotal
 		runningJobs += dc.JobsRunning
 		stoppedJobs += dc.JobsStopped
+		failingJobs += dc.JobsFailing
 	}
 
-	// Determine region status
+	// Determine region status, datacenters without nodes don't count towards it
 	regionStatus := model.DatacenterStatusActive
 	if errorCount > 0 {
 		regionStatus = model.DatacenterStatusError
-	} else if drainingCount == len(clusterNames) {
+	} else if emptyCount == len(clusterNames) {
+		regionStatus = model.DatacenterStatusEmpty
+	} else if drainingCount+emptyCount == len(clusterNames) {
 		regionStatus = model.DatacenterStatusDraining
 	} else if activeCount > 0 && drainingCount > 0 {
-		regionStatus = "partial" // Some DCs active, some draining
+		regionStatus = model.RegionStatusPartial // Some DCs active, some draining
+	} else if jobsDegraded(failingJobs, totalJobs, s.degradedJobRatio) {
+		regionStatus = model.RegionStatusDegraded // All DCs active, but many jobs are failing
 	}
 
 	return model.Region{
@@ -564,15 +1264,22 @@ func (s *datacenterService) getRegionInfo(ctx context.Context, regionName string
 		JobsTotal:   totalJobs,
 		JobsRunning: runningJobs,
 		JobsStopped: stoppedJobs,
+		JobsFailing: failingJobs,
 	}, nil
 }
 
+// jobsDegraded reports whether the fraction of failing jobs reached ratio
+func jobsDegraded(failingJobs, totalJobs int, ratio float64) bool {
+	return totalJobs > 0 && float64(failingJobs)/float64(totalJobs) >= ratio
+}
+
 // GetDatacentersByRegion returns all datacenters in a specific region
 func (s *datacenterService) GetDatacentersByRegion(ctx context.Context, region string) ([]model.Datacenter, error) {
 	clusterNames := s.repo.GetClustersByRegion(region)
 	if len(clusterNames) == 0 {
-		return nil, fmt.Errorf("region %s not found or has no datacenters", region)
+		return nil, fmt.Errorf("%w: %s has no datacenters", repository.ErrRegionNotFound, region)
 	}
+	active := s.activeDatacenterRecord(ctx)
 
 	// Fetch datacenter info in parallel with timeout for each datacenter
 	results := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, name string) (model.Datacenter, error) {
@@ -580,7 +1287,7 @@ func (s *datacenterService) GetDatacentersByRegion(ctx context.Context, region s
 		dcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
 
-		dc, err := s.getDatacenterInfo(dcCtx, name)
+		dc, err := s.getDatacenterInfo(dcCtx, name, active)
 		if err != nil {
 			s.logger.Error("failed to get datacenter info",
 				slog.String("datacenter", name),
@@ -589,9 +1296,11 @@ func (s *datacenterService) GetDatacentersByRegion(ctx context.Context, region s
 			)
 			// Return error status for this datacenter instead of failing
 			return model.Datacenter{
-				Name:   name,
-				Region: region,
-				Status: model.DatacenterStatusError,
+				Name:    name,
+				Region:  region,
+				Status:  model.DatacenterStatusError,
+				IsMyDC:  name == s.myDatacenter,
+				Enabled: s.repo.IsClusterEnabled(name),
 			}, nil
 		}
 		return dc, nil
@@ -608,23 +1317,56 @@ func (s *datacenterService) GetDatacentersByRegion(ctx context.Context, region s
 
 // ActivateRegion activates all datacenters in a specific region and drains all others
 // Uses continue-on-error approach: collects errors but continues with other clusters/nodes
-func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion string) (*model.ActivationResult, error) {
+// When dryRun is true, only planned node changes are computed and nothing is mutated
+func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion string, dryRun bool, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	// Dry runs don't mutate anything and stay available in read-only mode
+	if s.readOnly && !dryRun {
+		return nil, ErrReadOnly
+	}
+
+	if !dryRun {
+		release, err := s.acquireActivation(targetRegion)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	drainOpts := s.drainOptions(drainOverride)
+
 	s.logger.Info("starting region activation",
 		slog.String("target_region", targetRegion),
+		slog.Bool("dry_run", dryRun),
+		slog.Duration("drain_deadline", drainOpts.Deadline),
 	)
 
+	start := time.Now()
+
 	// Verify target region exists
 	targetClusters := s.repo.GetClustersByRegion(targetRegion)
 	if len(targetClusters) == 0 {
-		return nil, fmt.Errorf("region %s not found or has no datacenters", targetRegion)
+		err := fmt.Errorf("%w: %s has no datacenters", repository.ErrRegionNotFound, targetRegion)
+		if !dryRun {
+			s.recordActivation(targetRegion, start, nil, err)
+		}
+		return nil, err
+	}
+	targetClusters = s.enabledClusters(targetClusters)
+	if len(targetClusters) == 0 {
+		err := fmt.Errorf("%w: all datacenters in %s are disabled", ErrDatacenterDisabled, targetRegion)
+		if !dryRun {
+			s.recordActivation(targetRegion, start, nil, err)
+		}
+		return nil, err
 	}
 
 	result := &model.ActivationResult{
 		Activated: targetRegion,
+		DryRun:    dryRun,
 		Errors:    []string{},
 	}
 
-	allClusters := s.repo.GetClusterNames()
+	allClusters := s.enabledClusters(s.repo.GetClusterNames())
 
 	// OPTIMIZATION: Fetch nodes from all clusters in parallel
 	clusterNodesResults := concurrent.ParallelMap(ctx, allClusters, func(ctx context.Context, clusterName string) (clusterNodesInfo, error) {
@@ -643,7 +1385,7 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 				slog.String("cluster", clusterName),
 				slog.String("error", err.Error()),
 			)
-			return clusterNodesInfo{clusterName: clusterName, err: err}, nil
+			return clusterNodesInfo{clusterName: clusterName, region: clusterRegion, err: err}, nil
 		}
 
 		return clusterNodesInfo{
@@ -653,14 +1395,28 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		}, nil
 	})
 
+	// Refuse oversized activations before touching any node
+	if !dryRun {
+		if err := s.checkNodesAffected(ctx, clusterNodesResults, func(info clusterNodesInfo) bool { return info.region != targetRegion }); err != nil {
+			s.recordActivation(targetRegion, start, nil, err)
+			return nil, err
+		}
+	}
+
 	// Process all datacenters - continue on error, collect errors
 	for _, clusterResult := range clusterNodesResults {
+		// Stop touching further clusters once the activation is cancelled
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			break
+		}
+
 		clusterInfo := clusterResult.Value
 
 		// If error fetching this cluster, add to errors and continue
 		if clusterInfo.err != nil {
 			errMsg := fmt.Sprintf("cluster %s: failed to fetch nodes: %v", clusterInfo.clusterName, clusterInfo.err)
-			result.Errors = append(result.Errors, errMsg)
+			result.AddClusterError(clusterInfo.clusterName, clusterInfo.region, errMsg)
 			s.logger.Warn("skipping cluster due to error",
 				slog.String("cluster", clusterInfo.clusterName),
 				slog.String("error", clusterInfo.err.Error()),
@@ -671,6 +1427,7 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		clusterName := clusterInfo.clusterName
 		nodes := clusterInfo.nodes
 		clusterRegion := clusterInfo.region
+		summary := result.Cluster(clusterName, clusterRegion)
 
 		// Determine if nodes should be drained (drain all except target region)
 		shouldDrain := clusterRegion != targetRegion
@@ -684,9 +1441,15 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		}
 
 		nodesToChange := make([]nodeToChange, 0, len(nodes))
+		pendingChanges := 0
 		for _, node := range nodes {
 			nodeIsEligible := node.SchedulingEligibility == "eligible"
 			alreadyCorrect := (node.Drain == shouldDrain) && (nodeIsEligible == shouldBeEligible)
+			if !alreadyCorrect {
+				pendingChanges++
+			} else {
+				summary.Skipped++
+			}
 
 			nodesToChange = append(nodesToChange, nodeToChange{
 				node:           node,
@@ -695,19 +1458,48 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 			})
 		}
 
-		// OPTIMIZATION: Apply changes to nodes in parallel
-		type nodeResult struct {
-			nodeID  string
-			success bool
-		}
-
-		nodeResults := concurrent.ParallelMap(ctx, nodesToChange, func(ctx context.Context, ntc nodeToChange) (nodeResult, error) {
-			if ntc.alreadyCorrect {
-				return nodeResult{nodeID: "", success: true}, nil // Skip, already correct
+		// In dry-run mode only record what would change
+		if dryRun {
+			for _, ntc := range nodesToChange {
+				if ntc.alreadyCorrect {
+					continue
+				}
+				result.PlannedChanges = append(result.PlannedChanges, plannedNodeChange(clusterName, ntc.node, shouldDrain))
+				if shouldDrain {
+					result.DrainedNodes++
+					summary.Drained++
+				} else {
+					result.UnDrainedNodes++
+					summary.UnDrained++
+				}
+			}
+			continue
+		}
+
+		// OPTIMIZATION: Apply changes to nodes in parallel (bounded to avoid Nomad API rate limits)
+		type nodeResult struct {
+			nodeID  string
+			success bool
+		}
+
+		// The staged strategy batches only the nodes that change
+		stages := s.undrainStages(ctx, clusterName, clusterInfo.region, shouldDrain, result)
+		if stages != nil {
+			nodesToChange = slices.DeleteFunc(nodesToChange, func(ntc nodeToChange) bool { return ntc.alreadyCorrect })
+		}
+
+		var completedChanges atomic.Int64
+		nodeResults := applyInStages(ctx, nodesToChange, func(ctx context.Context, ntc nodeToChange) (nodeResult, error) {
+			if ntc.alreadyCorrect {
+				return nodeResult{nodeID: "", success: true}, nil // Skip, already correct
 			}
 
 			// Apply the change
-			err := s.repo.SetNodeDrain(ctx, clusterName, ntc.node.ID, shouldDrain)
+			err := s.setNodeDrain(ctx, clusterName, ntc.node.ID, shouldDrain, drainOpts)
+			if !isContextError(err) {
+				completed := int(completedChanges.Add(1))
+				reportProgress(ctx, nodeProgress(clusterName, ntc.node, shouldDrain, completed, pendingChanges, err))
+			}
 			if err != nil {
 				s.logger.Error("failed to set node drain",
 					slog.String("cluster", clusterName),
@@ -719,20 +1511,28 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 			}
 
 			return nodeResult{nodeID: ntc.node.ID, success: true}, nil
-		})
+		}, s.maxConcurrentNodeOps, stages)
 
 		// Collect errors and update counters - CONTINUE on error
 		for _, nr := range nodeResults {
+			if isContextError(nr.Error) {
+				// Node was not touched because the activation was cancelled
+				result.Cancelled = true
+				continue
+			}
 			if nr.Error != nil {
 				// Add error but continue with other nodes
 				errMsg := fmt.Sprintf("cluster %s, node %s: %v", clusterName, nr.Value.nodeID, nr.Error)
 				result.Errors = append(result.Errors, errMsg)
+				summary.Errors = append(summary.Errors, errMsg)
 			} else if nr.Value.success && nr.Value.nodeID != "" {
 				// Update counters only for successful changes
 				if shouldDrain {
 					result.DrainedNodes++
+					summary.Drained++
 				} else {
 					result.UnDrainedNodes++
+					summary.UnDrained++
 				}
 			}
 		}
@@ -748,6 +1548,14 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		slog.Int("errors_count", len(result.Errors)),
 	)
 
+	if result.Cancelled {
+		return s.cancelActivation(ctx, targetRegion, start, result, dryRun)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
 	// Start dead jobs and trigger evaluations for all datacenters in the activated region
 	if result.UnDrainedNodes > 0 {
 		// Start dead jobs BEFORE triggering evaluations so they are included in the eval
@@ -757,10 +1565,10 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		)
 		totalStartedJobs := 0
 		for _, clusterName := range targetClusters {
-			jobs, err := s.repo.ListJobs(ctx, clusterName)
+			jobs, err := s.repo.ListJobs(ctx, clusterName, "")
 			if err != nil {
 				errMsg := fmt.Sprintf("failed to list jobs for %s: %v", clusterName, err)
-				result.Errors = append(result.Errors, errMsg)
+				result.AddClusterError(clusterName, targetRegion, errMsg)
 				s.logger.Warn("failed to list jobs",
 					slog.String("datacenter", clusterName),
 					slog.String("error", err.Error()),
@@ -777,7 +1585,7 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 					)
 					if err := s.repo.StartJob(ctx, clusterName, job.ID); err != nil {
 						errMsg := fmt.Sprintf("datacenter %s, job %s: %v", clusterName, job.ID, err)
-						result.Errors = append(result.Errors, errMsg)
+						result.AddClusterError(clusterName, targetRegion, errMsg)
 						s.logger.Warn("failed to start job",
 							slog.String("datacenter", clusterName),
 							slog.String("job_id", job.ID),
@@ -788,6 +1596,7 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 					}
 				}
 			}
+			s.cache.Delete(fmt.Sprintf("%s:jobs", clusterName))
 		}
 		if totalStartedJobs > 0 {
 			s.logger.Info("dead jobs started successfully in region",
@@ -801,11 +1610,10 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 			slog.String("region", targetRegion),
 			slog.Int("datacenters", len(targetClusters)),
 		)
-		evalErrors := []string{}
 		for _, clusterName := range targetClusters {
 			if err := s.repo.TriggerJobEvaluations(ctx, clusterName); err != nil {
 				errMsg := fmt.Sprintf("datacenter %s: %v", clusterName, err)
-				evalErrors = append(evalErrors, errMsg)
+				result.AddClusterError(clusterName, targetRegion, errMsg)
 				s.logger.Warn("failed to trigger job evaluations",
 					slog.String("datacenter", clusterName),
 					slog.String("error", err.Error()),
@@ -816,21 +1624,12 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 				)
 			}
 		}
-
-		if len(evalErrors) > 0 {
-			result.Errors = append(result.Errors, evalErrors...)
-		}
 	}
 
-	// Write active datacenter info to etcd (choose first DC in region as active)
+	// Write active datacenter info to etcd: the first DC in the region, or every DC in region-wide mode
 	if len(targetClusters) > 0 {
 		activeDatacenter := targetClusters[0]
-		activeInfo := &model.ActiveDatacenter{
-			Datacenter:    activeDatacenter,
-			ActivatedAt:   time.Now(),
-			ActivatedBy:   "api-region",
-			LastHeartbeat: time.Now(),
-		}
+		activeInfo := s.newActiveRecord(activeDatacenter, targetRegion, activatedBy(ctx, "api-region"), false)
 		if err := s.etcdRepo.WriteActiveDatacenter(ctx, activeInfo); err != nil {
 			s.logger.Error("failed to write active datacenter to etcd",
 				"datacenter", activeDatacenter,
@@ -840,11 +1639,12 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 			result.Errors = append(result.Errors, fmt.Sprintf("failed to write to etcd: %v", err))
 		} else {
 			s.logger.Info("wrote active datacenter to etcd",
-				"datacenter", activeDatacenter,
+				"datacenters", activeInfo.Datacenters(),
 				"region", targetRegion)
+			s.invalidateActiveSummary()
 			// Update local state if this is my datacenter
-			if activeDatacenter == s.myDatacenter {
-				s.amDrained = false
+			if activeInfo.IsActive(s.myDatacenter) {
+				s.setAmDrained(false)
 			}
 		}
 	}
@@ -854,15 +1654,57 @@ func (s *datacenterService) ActivateRegion(ctx context.Context, targetRegion str
 		s.healthChecker.SetActiveRegion(targetRegion)
 	}
 
+	s.recordStuckNodes(ctx, result)
+	s.recordActivation(targetRegion, start, result, nil)
+	reason := "region activated via API"
+	if by := activatedBy(ctx, ""); by != "" {
+		reason = "region activated by " + by
+	}
+	s.recordActivationEvent(ctx, model.ActivationTargetRegion, activatedBy(ctx, "api-region"), result)
+	s.notifier.Notify(model.NotificationEvent{
+		Type:       model.NotificationActivation,
+		Region:     targetRegion,
+		Reason:     reason,
+		ErrorCount: len(result.Errors),
+	})
+
 	return result, nil
 }
 
+// chooseRegionToKeep picks which of several active regions stays active, in order of preference:
+// the region of the active datacenter recorded in etcd, the region of the preferred datacenter, the region
+// of my datacenter, then the alphabetically first region. activeRegions must be sorted. It also returns the selection reason.
+func (s *datacenterService) chooseRegionToKeep(ctx context.Context, activeRegions []string) (string, string) {
+	if activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx); err == nil {
+		if region, err := s.repo.GetClusterRegion(activeInfo.Datacenter); err == nil && slices.Contains(activeRegions, region) {
+			return region, "etcd_active_datacenter"
+		}
+	}
+
+	if s.preferredDC != "" {
+		if region, err := s.repo.GetClusterRegion(s.preferredDC); err == nil && slices.Contains(activeRegions, region) {
+			return region, "preferred_datacenter"
+		}
+	}
+
+	if region, err := s.repo.GetClusterRegion(s.myDatacenter); err == nil && slices.Contains(activeRegions, region) {
+		return region, "my_datacenter"
+	}
+
+	return activeRegions[0], "alphabetical"
+}
+
 // EnsureSingleActiveDatacenter ensures only one region is active at startup
-// If multiple regions have active datacenters, it keeps the first region active and drains all others
+// If multiple regions have active datacenters, it keeps one active (see chooseRegionToKeep) and drains all others
 func (s *datacenterService) EnsureSingleActiveDatacenter(ctx context.Context) error {
+	if s.readOnly {
+		s.logger.Info("read-only mode, skipping single active region enforcement")
+		return nil
+	}
+
 	s.logger.Info("checking region states at startup")
 
-	clusterNames := s.repo.GetClusterNames()
+	clusterNames := s.enabledClusters(s.repo.GetClusterNames())
 
 	// OPTIMIZATION: Fetch nodes from all clusters in parallel
 	type clusterNodesWithRegion struct {
@@ -938,17 +1780,24 @@ func (s *datacenterService) EnsureSingleActiveDatacenter(ctx context.Context) er
 		return nil
 	}
 
-	// Multiple active regions found - keep first one, drain others
+	// Multiple active regions found - keep one deterministically, drain others
 	var activeRegions []string
 	for region := range activeDatacentersByRegion {
 		activeRegions = append(activeRegions, region)
 	}
+	sort.Strings(activeRegions)
 
-	keepActiveRegion := activeRegions[0]
-	drainRegions := activeRegions[1:]
+	keepActiveRegion, reason := s.chooseRegionToKeep(ctx, activeRegions)
+	var drainRegions []string
+	for _, region := range activeRegions {
+		if region != keepActiveRegion {
+			drainRegions = append(drainRegions, region)
+		}
+	}
 
 	s.logger.Warn("multiple active regions detected at startup",
 		slog.String("keeping_active_region", keepActiveRegion),
+		slog.String("selection_reason", reason),
 		slog.Int("draining_regions_count", len(drainRegions)),
 		slog.Any("draining_regions", drainRegions),
 	)
@@ -986,9 +1835,9 @@ func (s *datacenterService) EnsureSingleActiveDatacenter(ctx context.Context) er
 			}
 		}
 
-		// OPTIMIZATION: Drain all nodes in parallel
-		drainResults := concurrent.ParallelMap(ctx, nodesToDrain, func(ctx context.Context, ntd nodeToDrain) (string, error) {
-			err := s.repo.SetNodeDrain(ctx, ntd.clusterName, ntd.node.ID, true)
+		// OPTIMIZATION: Drain all nodes in parallel (bounded to avoid Nomad API rate limits)
+		drainResults := concurrent.ParallelMapWithLimit(ctx, nodesToDrain, func(ctx context.Context, ntd nodeToDrain) (string, error) {
+			err := s.setNodeDrain(ctx, ntd.clusterName, ntd.node.ID, true, s.drainOpts)
 			if err != nil {
 				s.logger.Error("failed to drain node during startup sync",
 					slog.String("cluster", ntd.clusterName),
@@ -1003,7 +1852,7 @@ func (s *datacenterService) EnsureSingleActiveDatacenter(ctx context.Context) er
 				slog.String("node_id", ntd.node.ID),
 			)
 			return ntd.clusterName, nil
-		})
+		}, s.maxConcurrentNodeOps)
 
 		// Collect unique cluster names that were modified to invalidate cache
 		modifiedClusters := make(map[string]bool)
@@ -1029,20 +1878,100 @@ func (s *datacenterService) EnsureSingleActiveDatacenter(ctx context.Context) er
 
 // GetRegionDatacenters returns detailed information about a specific region and its datacenters
 func (s *datacenterService) GetRegionDatacenters(ctx context.Context, region string) (*model.Region, error) {
-	regionInfo, err := s.getRegionInfo(ctx, region)
+	regionInfo, err := s.getRegionInfo(ctx, region, s.activeDatacenterRecord(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get region info: %w", err)
 	}
 	return &regionInfo, nil
 }
 
-// CheckClusterLeader checks if the specified cluster has an elected leader
-func (s *datacenterService) CheckClusterLeader(ctx context.Context, clusterName string) (bool, error) {
-	hasLeader, err := s.repo.CheckLeader(ctx, clusterName)
+// ListClusters returns the connected clusters with their detected regions, without querying Nomad
+func (s *datacenterService) ListClusters() []model.ClusterInfo {
+	clusterNames := s.repo.GetClusterNames()
+	clusters := make([]model.ClusterInfo, 0, len(clusterNames))
+	for _, name := range clusterNames {
+		region, err := s.repo.GetClusterRegion(name)
+		if err != nil {
+			continue // Removed by a concurrent reload
+		}
+		clusters = append(clusters, model.ClusterInfo{Name: name, Region: region})
+	}
+	return clusters
+}
+
+// CheckClusterLeader checks if the specified cluster has an elected leader and returns its address
+func (s *datacenterService) CheckClusterLeader(ctx context.Context, clusterName string) (string, bool, error) {
+	leader, hasLeader, err := s.repo.CheckLeader(ctx, clusterName)
 	if err != nil {
-		return false, fmt.Errorf("failed to check leader: %w", err)
+		return "", false, fmt.Errorf("failed to check leader: %w", err)
 	}
-	return hasLeader, nil
+	return leader, hasLeader, nil
+}
+
+// GetClusterLeader returns the leader status of a datacenter's Nomad cluster
+func (s *datacenterService) GetClusterLeader(ctx context.Context, dc string) (*model.LeaderStatus, error) {
+	if !slices.Contains(s.repo.GetClusterNames(), dc) {
+		return nil, fmt.Errorf("%w: %s", ErrDatacenterNotFound, dc)
+	}
+
+	leader, hasLeader, err := s.CheckClusterLeader(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LeaderStatus{
+		Datacenter: dc,
+		HasLeader:  hasLeader,
+		Leader:     leader,
+	}, nil
+}
+
+// GetClusterHealth runs the leader and agent health check of a datacenter's Nomad cluster.
+// It is never cached, so dashboards see the current state.
+func (s *datacenterService) GetClusterHealth(ctx context.Context, dc string) (*model.ClusterHealth, error) {
+	return s.repo.CheckClusterHealth(ctx, dc)
+}
+
+// CheckEtcdConnection checks that etcd is reachable
+func (s *datacenterService) CheckEtcdConnection(ctx context.Context) error {
+	if err := s.etcdRepo.Ping(ctx); err != nil {
+		return fmt.Errorf("etcd is unreachable: %w", err)
+	}
+	return nil
+}
+
+// SaveHealthFailures saves the health checker failure counters of this instance in etcd
+func (s *datacenterService) SaveHealthFailures(ctx context.Context, counts *model.HealthFailureCounts) error {
+	return s.etcdRepo.WriteHealthFailures(ctx, s.myDatacenter, counts)
+}
+
+// LoadHealthFailures reads the failure counters this instance saved, or nil when there are none
+func (s *datacenterService) LoadHealthFailures(ctx context.Context) (*model.HealthFailureCounts, error) {
+	return s.etcdRepo.ReadHealthFailures(ctx, s.myDatacenter)
+}
+
+// HealthSnapshot checks etcd connectivity and leader availability of every cluster
+func (s *datacenterService) HealthSnapshot(ctx context.Context) *model.HealthSnapshot {
+	snapshot := &model.HealthSnapshot{
+		EtcdConnected: s.etcdRepo.Ping(ctx) == nil,
+		Clusters:      make(map[string]bool),
+	}
+
+	clusterNames := s.repo.GetClusterNames()
+	leaderResults := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, clusterName string) (bool, error) {
+		_, hasLeader, err := s.repo.CheckLeader(ctx, clusterName)
+		return hasLeader, err
+	})
+
+	for i, result := range leaderResults {
+		healthy := result.Error == nil && result.Value
+		snapshot.Clusters[clusterNames[i]] = healthy
+		if healthy {
+			snapshot.HealthyClusters++
+		}
+	}
+
+	return snapshot
 }
 
 // SetHealthChecker sets the health checker instance for notifying about region changes
@@ -1051,11 +1980,30 @@ func (s *datacenterService) SetHealthChecker(hc HealthChecker) {
 }
 
 // DrainAllNodesInRegion drains all nodes in all datacenters in the specified region
-func (s *datacenterService) DrainAllNodesInRegion(ctx context.Context, region string) error {
-	// Get all clusters in this region
-	clusterNames := s.repo.GetClustersByRegion(region)
+// Failures are collected per cluster; an error is returned only when nothing could be drained
+func (s *datacenterService) DrainAllNodesInRegion(ctx context.Context, region string) (*model.DrainResult, error) {
+	if s.readOnly {
+		s.logger.Warn("read-only mode, auto-drain suppressed",
+			slog.String("region", region),
+		)
+		return nil, ErrReadOnly
+	}
+
+	// An activation running at the same time would fight the drain over node state
+	release, err := s.acquireActivation(region)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Get all clusters in this region; disabled clusters are left alone like in activations
+	allClusters := s.repo.GetClustersByRegion(region)
+	if len(allClusters) == 0 {
+		return nil, fmt.Errorf("no clusters found in region %s", region)
+	}
+	clusterNames := s.enabledClusters(allClusters)
 	if len(clusterNames) == 0 {
-		return fmt.Errorf("no clusters found in region %s", region)
+		return nil, fmt.Errorf("%w: all datacenters in %s are disabled", ErrDatacenterDisabled, region)
 	}
 
 	s.logger.Info("draining all nodes in region",
@@ -1063,55 +2011,85 @@ func (s *datacenterService) DrainAllNodesInRegion(ctx context.Context, region st
 		slog.Int("cluster_count", len(clusterNames)),
 	)
 
-	var errors []string
+	result := &model.DrainResult{
+		Region:     region,
+		PerCluster: make([]model.ClusterDrainResult, len(clusterNames)),
+	}
+	clusterIndex := make(map[string]int, len(clusterNames))
+	for i, clusterName := range clusterNames {
+		result.PerCluster[i].Cluster = clusterName
+		clusterIndex[clusterName] = i
+	}
 
-	// Drain nodes in all clusters in parallel
-	drainResults := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, clusterName string) (int, error) {
-		// Get nodes for this cluster
+	// Fetch nodes from all clusters in parallel
+	nodeResults := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, clusterName string) ([]model.Node, error) {
 		nodes, err := s.GetNodes(ctx, clusterName)
 		if err != nil {
 			s.logger.Error("failed to get nodes for draining",
 				slog.String("cluster", clusterName),
 				slog.String("error", err.Error()),
 			)
-			return 0, err
+			return nil, err
 		}
+		return nodes, nil
+	})
 
-		drainedCount := 0
-		// Drain each node that is not already drained
-		for _, node := range nodes {
+	// Collect nodes that are not already drained
+	type nodeToDrain struct {
+		clusterName string
+		node        model.Node
+	}
+	var nodesToDrain []nodeToDrain
+
+	for i, nodeResult := range nodeResults {
+		cluster := &result.PerCluster[i]
+		if nodeResult.Error != nil {
+			cluster.Errors = append(cluster.Errors, fmt.Sprintf("failed to list nodes: %v", nodeResult.Error))
+			continue
+		}
+
+		result.ClustersProcessed++
+		cluster.NodesTotal = len(nodeResult.Value)
+		for _, node := range nodeResult.Value {
 			if !node.Drain {
-				err := s.repo.SetNodeDrain(ctx, clusterName, node.ID, true)
-				if err != nil {
-					s.logger.Error("failed to drain node",
-						slog.String("cluster", clusterName),
-						slog.String("node_id", node.ID),
-						slog.String("node_name", node.Name),
-						slog.String("error", err.Error()),
-					)
-					continue
-				}
-				drainedCount++
+				nodesToDrain = append(nodesToDrain, nodeToDrain{clusterName: cluster.Cluster, node: node})
 			}
 		}
+	}
+
+	// Drain nodes in parallel (bounded to avoid Nomad API rate limits)
+	drainResults := concurrent.ParallelMapWithLimit(ctx, nodesToDrain, func(ctx context.Context, ntd nodeToDrain) (string, error) {
+		if err := s.setNodeDrain(ctx, ntd.clusterName, ntd.node.ID, true, s.drainOpts); err != nil {
+			s.logger.Error("failed to drain node",
+				slog.String("cluster", ntd.clusterName),
+				slog.String("node_id", ntd.node.ID),
+				slog.String("node_name", ntd.node.Name),
+				slog.String("error", err.Error()),
+			)
+			return ntd.clusterName, err
+		}
+		return ntd.clusterName, nil
+	}, s.maxConcurrentNodeOps)
+
+	// Count drained nodes per cluster
+	for i, drainResult := range drainResults {
+		ntd := nodesToDrain[i]
+		cluster := &result.PerCluster[clusterIndex[ntd.clusterName]]
+		if drainResult.Error != nil {
+			cluster.Errors = append(cluster.Errors, fmt.Sprintf("failed to drain node %s: %v", ntd.node.Name, drainResult.Error))
+			continue
+		}
+		cluster.NodesDrained++
+		result.NodesDrained++
+	}
 
+	for _, cluster := range result.PerCluster {
 		s.logger.Info("drained nodes in cluster",
-			slog.String("cluster", clusterName),
-			slog.Int("drained_count", drainedCount),
-			slog.Int("total_nodes", len(nodes)),
+			slog.String("cluster", cluster.Cluster),
+			slog.Int("drained_count", cluster.NodesDrained),
+			slog.Int("total_nodes", cluster.NodesTotal),
+			slog.Int("error_count", len(cluster.Errors)),
 		)
-
-		return drainedCount, nil
-	})
-
-	// Collect results and errors
-	totalDrained := 0
-	for _, result := range drainResults {
-		if result.Error != nil {
-			errors = append(errors, fmt.Sprintf("cluster drain error: %v", result.Error))
-		} else {
-			totalDrained += result.Value
-		}
 	}
 
 	// Invalidate cache for all clusters in region
@@ -1121,28 +2099,114 @@ func (s *datacenterService) DrainAllNodesInRegion(ctx context.Context, region st
 
 	s.logger.Info("completed draining region",
 		slog.String("region", region),
-		slog.Int("total_drained_nodes", totalDrained),
-		slog.Int("error_count", len(errors)),
+		slog.Int("clusters_processed", result.ClustersProcessed),
+		slog.Int("total_drained_nodes", result.NodesDrained),
+		slog.Int("error_count", result.ErrorCount()),
 	)
 
-	if len(errors) > 0 {
-		return fmt.Errorf("some drain operations failed: %v", errors)
+	if result.NodesDrained == 0 && result.ErrorCount() > 0 {
+		return result, fmt.Errorf("all drain operations failed in region %s: %s", region, strings.Join(result.AllErrors(), "; "))
 	}
 
-	return nil
+	return result, nil
 }
 
-// GetJobs returns all jobs for a specific datacenter
-func (s *datacenterService) GetJobs(ctx context.Context, dc string) ([]model.Job, error) {
-	jobs, err := s.repo.ListJobs(ctx, dc)
+// GetJobs returns all jobs for a specific datacenter (cached)
+func (s *datacenterService) GetJobs(ctx context.Context, dc string, filter model.JobFilter) (*model.JobList, error) {
+	jobs, err := s.listJobs(ctx, dc, filter.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]model.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+		if filter.Type != "" && job.Type != filter.Type {
+			continue
+		}
+		matched = append(matched, job)
+	}
+
+	result := &model.JobList{
+		Total:  len(matched),
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+
+	// Apply pagination to the filtered list
+	start := min(filter.Offset, len(matched))
+	end := len(matched)
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, end)
+	}
+	result.Jobs = matched[start:end]
+
+	return result, nil
+}
+
+// listJobs returns the jobs of a datacenter whose ID starts with prefix.
+// The full list is cached; prefix lookups are answered from the cache when
+// it is warm and otherwise delegated to Nomad's prefix listing.
+func (s *datacenterService) listJobs(ctx context.Context, dc, prefix string) ([]model.Job, error) {
+	cacheKey := fmt.Sprintf("%s:jobs", dc)
+
+	// Try to get from cache
+	if !isFresh(ctx) {
+		if jobs, ok := cache.GetTyped[[]model.Job](s.cache, cacheKey); ok {
+			s.logger.Debug("jobs retrieved from cache",
+				slog.String("datacenter", dc),
+				slog.Int("count", len(jobs)),
+			)
+			return filterJobsByPrefix(jobs, prefix), nil
+		}
+	}
+
+	jobs, err := s.repo.ListJobs(ctx, dc, prefix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
+
+	// Only the full list is cached, a prefix result would poison other lookups
+	if prefix == "" {
+		s.cache.Set(cacheKey, jobs, s.jobsTTL)
+	}
+
 	return jobs, nil
 }
 
+// filterJobsByPrefix returns the jobs whose ID starts with prefix
+func filterJobsByPrefix(jobs []model.Job, prefix string) []model.Job {
+	if prefix == "" {
+		return jobs
+	}
+
+	filtered := make([]model.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if strings.HasPrefix(job.ID, prefix) {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+// GetJob returns a job of the specified datacenter with its task groups and recent allocations
+// It always reads from Nomad, so a drill-down never shows stale allocations
+func (s *datacenterService) GetJob(ctx context.Context, dc, jobID string) (*model.JobDetail, error) {
+	job, err := s.repo.GetJob(ctx, dc, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	return job, nil
+}
+
 // StartJob starts a stopped job in the specified datacenter
 func (s *datacenterService) StartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
 	s.logger.Info("starting job",
 		slog.String("datacenter", dc),
 		slog.String("job_id", jobID),
@@ -1156,6 +2220,7 @@ func (s *datacenterService) StartJob(ctx context.Context, dc, jobID string) (*mo
 	}
 
 	err := s.repo.StartJob(ctx, dc, jobID)
+	s.cache.Delete(fmt.Sprintf("%s:jobs", dc))
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to start job %s: %v", jobID, err)
 		result.Errors = append(result.Errors, errMsg)
@@ -1177,10 +2242,16 @@ func (s *datacenterService) StartJob(ctx context.Context, dc, jobID string) (*mo
 }
 
 // StopJob stops a running job in the specified datacenter
-func (s *datacenterService) StopJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error) {
+// With purge the job is removed from Nomad, so it no longer shows up as dead and can't be started again
+func (s *datacenterService) StopJob(ctx context.Context, dc, jobID string, purge bool) (*model.JobActionResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
 	s.logger.Info("stopping job",
 		slog.String("datacenter", dc),
 		slog.String("job_id", jobID),
+		slog.Bool("purge", purge),
 	)
 
 	result := &model.JobActionResult{
@@ -1190,7 +2261,8 @@ func (s *datacenterService) StopJob(ctx context.Context, dc, jobID string) (*mod
 		Errors:  []string{},
 	}
 
-	err := s.repo.StopJob(ctx, dc, jobID)
+	err := s.repo.StopJob(ctx, dc, jobID, purge)
+	s.cache.Delete(fmt.Sprintf("%s:jobs", dc))
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to stop job %s: %v", jobID, err)
 		result.Errors = append(result.Errors, errMsg)
@@ -1203,6 +2275,7 @@ func (s *datacenterService) StopJob(ctx context.Context, dc, jobID string) (*mod
 	}
 
 	result.Success = true
+	result.Purged = purge
 	s.logger.Info("job stopped successfully",
 		slog.String("datacenter", dc),
 		slog.String("job_id", jobID),
@@ -1211,6 +2284,113 @@ func (s *datacenterService) StopJob(ctx context.Context, dc, jobID string) (*mod
 	return result, nil
 }
 
+// RestartJob stops a job and starts it again with the same definition in one call
+// A job that is already stopped is only started
+func (s *datacenterService) RestartJob(ctx context.Context, dc, jobID string) (*model.JobActionResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	s.logger.Info("restarting job",
+		slog.String("datacenter", dc),
+		slog.String("job_id", jobID),
+	)
+
+	result := &model.JobActionResult{
+		JobID:   jobID,
+		Action:  "restart",
+		Success: false,
+		Errors:  []string{},
+	}
+
+	wasStopped, err := s.repo.RestartJob(ctx, dc, jobID)
+	s.cache.Delete(fmt.Sprintf("%s:jobs", dc))
+	result.AlreadyStopped = wasStopped
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to restart job %s: %v", jobID, err)
+		result.Errors = append(result.Errors, errMsg)
+		s.logger.Error("failed to restart job",
+			slog.String("datacenter", dc),
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		return result, err
+	}
+
+	result.Success = true
+	s.logger.Info("job restarted successfully",
+		slog.String("datacenter", dc),
+		slog.String("job_id", jobID),
+		slog.Bool("already_stopped", wasStopped),
+	)
+
+	return result, nil
+}
+
+// BulkJobAction starts or stops several jobs of a datacenter in parallel.
+// A failing job doesn't stop the others; every job gets its own result.
+func (s *datacenterService) BulkJobAction(ctx context.Context, dc string, req model.BulkJobActionRequest) (*model.BulkJobActionResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	jobIDs := req.JobIDs
+	if req.All {
+		jobs, err := s.GetJobs(ctx, dc, req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		jobIDs = make([]string, len(jobs.Jobs))
+		for i, job := range jobs.Jobs {
+			jobIDs[i] = job.ID
+		}
+	}
+
+	s.logger.Info("executing bulk job action",
+		slog.String("datacenter", dc),
+		slog.String("action", req.Action),
+		slog.Int("jobs", len(jobIDs)),
+	)
+
+	jobResults := concurrent.ParallelMapWithLimit(ctx, jobIDs, func(ctx context.Context, jobID string) (*model.JobActionResult, error) {
+		if req.Action == "stop" {
+			return s.StopJob(ctx, dc, jobID, req.Purge)
+		}
+		return s.StartJob(ctx, dc, jobID)
+	}, maxConcurrentJobActions)
+
+	result := &model.BulkJobActionResult{
+		Action:  req.Action,
+		Results: make([]model.JobActionResult, len(jobResults)),
+	}
+	for i, res := range jobResults {
+		if res.Value == nil {
+			// Skipped because the context was cancelled before the action started
+			res.Value = &model.JobActionResult{
+				JobID:  jobIDs[i],
+				Action: req.Action,
+				Errors: []string{fmt.Sprintf("failed to %s job %s: %v", req.Action, jobIDs[i], res.Error)},
+			}
+		}
+		result.Results[i] = *res.Value
+
+		if res.Error != nil {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	s.logger.Info("bulk job action completed",
+		slog.String("datacenter", dc),
+		slog.String("action", req.Action),
+		slog.Int("succeeded", result.Succeeded),
+		slog.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
 // PerformStartupReconciliation reads active datacenter from etcd and reconciles local state
 func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) error {
 	s.logger.Info("performing startup reconciliation with etcd")
@@ -1219,24 +2399,37 @@ func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) er
 	activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
 	if err != nil {
 		s.logger.Warn("no active datacenter found in etcd", "error", err.Error())
+
+		// Only a missing key proves that no instance is active; read errors always drain
+		if s.startupCfg.AutoActivateIfSoleInstance && errors.Is(err, repository.ErrNoActiveDatacenter) {
+			claimed, claimErr := s.claimAsSoleInstance(ctx)
+			if claimErr != nil {
+				s.logger.Warn("failed to stay active as sole instance", "error", claimErr.Error())
+			} else if claimed {
+				s.setAmDrained(false)
+				return nil
+			}
+		}
+
 		// No active datacenter in etcd - stay drained for safety
 		s.logger.Info("no active datacenter in etcd, draining my nodes for safety")
 		allDrained, drainErr := s.drainMyNodes(ctx)
 		if drainErr != nil {
 			return fmt.Errorf("failed to drain nodes: %w", drainErr)
 		}
-		s.amDrained = allDrained
+		s.setAmDrained(allDrained)
 		return nil
 	}
 
 	s.logger.Info("found active datacenter in etcd",
 		"datacenter", activeInfo.Datacenter,
+		"active_datacenters", activeInfo.Datacenters(),
 		"activated_at", activeInfo.ActivatedAt,
 		"heartbeat_age", activeInfo.HeartbeatAge(),
 	)
 
 	// Check if I should be active
-	if activeInfo.Datacenter != s.myDatacenter {
+	if !activeInfo.IsActive(s.myDatacenter) {
 		// Another DC is active
 		s.logger.Info("another datacenter is active, ensuring my nodes are drained",
 			"active_dc", activeInfo.Datacenter)
@@ -1244,7 +2437,7 @@ func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) er
 		if drainErr != nil {
 			return fmt.Errorf("failed to drain nodes: %w", drainErr)
 		}
-		s.amDrained = allDrained
+		s.setAmDrained(allDrained)
 		return nil
 	}
 
@@ -1258,7 +2451,7 @@ func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) er
 		if drainErr != nil {
 			return fmt.Errorf("failed to drain nodes: %w", drainErr)
 		}
-		s.amDrained = allDrained
+		s.setAmDrained(allDrained)
 		return nil
 	}
 
@@ -1273,10 +2466,43 @@ func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) er
 	}
 
 	if !allDrained {
-		// Nodes are active - this is my old heartbeat, I was active before restart
+		// Nodes are active - this is my old heartbeat, I was active before restart.
+		// Re-claim the key atomically so a concurrent activation elsewhere isn't overwritten.
+		// The record is kept as is, so the other active datacenters of a region-wide activation stay listed.
+		claim := &model.ActiveDatacenter{
+			Datacenter:        activeInfo.Datacenter,
+			Region:            activeInfo.Region,
+			ActiveDatacenters: activeInfo.ActiveDatacenters,
+			ActivatedAt:       activeInfo.ActivatedAt,
+			ActivatedBy:       activeInfo.ActivatedBy,
+			LastHeartbeat:     time.Now(),
+		}
+		claimed, holder, claimErr := s.etcdRepo.TryClaimActiveDatacenter(ctx, claim, activeInfo.Revision)
+		if claimErr != nil {
+			return fmt.Errorf("failed to claim active datacenter: %w", claimErr)
+		}
+		if claimed {
+			s.invalidateActiveSummary()
+		}
+
+		if !claimed && (holder == nil || !holder.IsActive(s.myDatacenter)) {
+			holderDC := ""
+			if holder != nil {
+				holderDC = holder.Datacenter
+			}
+			s.logger.Warn("lost active datacenter claim during startup, draining my nodes",
+				"active_dc", holderDC)
+			allDrained, drainErr := s.drainMyNodes(ctx)
+			if drainErr != nil {
+				return fmt.Errorf("failed to drain nodes: %w", drainErr)
+			}
+			s.setAmDrained(allDrained)
+			return nil
+		}
+
 		s.logger.Info("found my old heartbeat with active nodes, resuming as active",
 			"heartbeat_age", age)
-		s.amDrained = false
+		s.setAmDrained(false)
 		return nil
 	}
 
@@ -1285,14 +2511,14 @@ func (s *datacenterService) PerformStartupReconciliation(ctx context.Context) er
 		// Fresh heartbeat but nodes are drained - someone drained us recently
 		s.logger.Info("fresh heartbeat but nodes are drained, staying drained",
 			"heartbeat_age", age)
-		s.amDrained = true
+		s.setAmDrained(true)
 		return nil
 	}
 
 	// Stale heartbeat and nodes drained - can resume as active if needed
 	s.logger.Info("stale heartbeat with drained nodes, staying drained for safety",
 		"heartbeat_age", age)
-	s.amDrained = true
+	s.setAmDrained(true)
 	return nil
 }
 
@@ -1318,6 +2544,12 @@ func (s *datacenterService) checkNodesAreDrained(ctx context.Context) (bool, err
 // drainMyNodes drains all nodes in my datacenter
 // Returns true if all nodes are now drained (or were already drained), false otherwise
 func (s *datacenterService) drainMyNodes(ctx context.Context) (bool, error) {
+	if s.readOnly {
+		s.logger.Warn("read-only mode, drain of my nodes suppressed",
+			"datacenter", s.myDatacenter)
+		return false, nil
+	}
+
 	nodes, err := s.GetNodes(ctx, s.myDatacenter)
 	if err != nil {
 		return false, fmt.Errorf("failed to get nodes: %w", err)
@@ -1332,7 +2564,7 @@ func (s *datacenterService) drainMyNodes(ctx context.Context) (bool, error) {
 			continue
 		}
 
-		if err := s.repo.SetNodeDrain(ctx, s.myDatacenter, node.ID, true); err != nil {
+		if err := s.setNodeDrain(ctx, s.myDatacenter, node.ID, true, s.drainOpts); err != nil {
 			s.logger.Error("failed to drain node",
 				"node_id", node.ID,
 				"node_name", node.Name,
@@ -1357,12 +2589,90 @@ func (s *datacenterService) drainMyNodes(ctx context.Context) (bool, error) {
 
 	// Return true if all nodes are drained (either already or just drained)
 	allDrained := (drainedCount + alreadyDrainedCount) == len(nodes)
+
+	// Optionally wait until allocations have actually left the drained nodes
+	if allDrained && s.heartbeatCfg.WaitForDrainComplete {
+		if err := s.waitForDrainComplete(ctx, nodes); err != nil {
+			return false, err
+		}
+	}
+
 	return allDrained, nil
 }
 
+// waitForDrainComplete polls allocations on my nodes until none are active or the timeout elapses.
+// A timeout is logged but not treated as an error, so the datacenter is still reported as drained.
+func (s *datacenterService) waitForDrainComplete(ctx context.Context, nodes []model.Node) error {
+	deadline := time.Now().Add(s.heartbeatCfg.DrainCompleteTimeout)
+
+	s.logger.Info("waiting for allocations to leave drained nodes",
+		"datacenter", s.myDatacenter,
+		"timeout", s.heartbeatCfg.DrainCompleteTimeout)
+
+	for {
+		remaining := s.countActiveAllocations(ctx, nodes)
+		if remaining == 0 {
+			s.logger.Info("drain complete, no active allocations left",
+				"datacenter", s.myDatacenter)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			s.logger.Warn("timed out waiting for drain to complete",
+				"datacenter", s.myDatacenter,
+				"active_allocations", remaining,
+				"timeout", s.heartbeatCfg.DrainCompleteTimeout)
+			return nil
+		}
+
+		s.logger.Debug("drain in progress",
+			"datacenter", s.myDatacenter,
+			"active_allocations", remaining)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for drain to complete: %w", ctx.Err())
+		case <-s.stopHeartbeat:
+			return fmt.Errorf("waiting for drain to complete: heartbeat stopped")
+		case <-time.After(s.heartbeatCfg.DrainPollInterval):
+		}
+	}
+}
+
+// countActiveAllocations counts pending or running allocations on the given nodes of my datacenter.
+// System job allocations are ignored when drains leave them in place. Nodes whose allocations
+// can't be listed are counted as having one active allocation so the wait continues.
+func (s *datacenterService) countActiveAllocations(ctx context.Context, nodes []model.Node) int {
+	active := 0
+	for _, node := range nodes {
+		allocs, err := s.repo.ListNodeAllocations(ctx, s.myDatacenter, node.ID)
+		if err != nil {
+			s.logger.Warn("failed to list node allocations",
+				"node_id", node.ID,
+				"error", err.Error())
+			active++
+			continue
+		}
+
+		for _, alloc := range allocs {
+			if !alloc.IsActive() {
+				continue
+			}
+			if s.drainOpts.IgnoreSystemJobs && alloc.JobType == "system" {
+				continue
+			}
+			active++
+		}
+	}
+	return active
+}
+
 // StartHeartbeat starts the heartbeat update goroutine
 func (s *datacenterService) StartHeartbeat(ctx context.Context) {
-	go s.heartbeatLoop(ctx)
+	go func() {
+		defer close(s.heartbeatDone)
+		s.heartbeatLoop(ctx)
+	}()
 }
 
 // StopHeartbeat stops the heartbeat update goroutine
@@ -1372,13 +2682,21 @@ func (s *datacenterService) StopHeartbeat() {
 
 // heartbeatLoop periodically updates heartbeat in etcd with fail-safe logic
 func (s *datacenterService) heartbeatLoop(ctx context.Context) {
-	ticker := time.NewTicker(s.heartbeatCfg.UpdateInterval)
-	defer ticker.Stop()
+	// A timer re-armed with a jittered interval spreads writes of instances sharing the same interval
+	timer := time.NewTimer(util.Jitter(s.heartbeatCfg.UpdateInterval, s.heartbeatCfg.JitterPercent))
+	defer timer.Stop()
 
 	consecutiveFailures := 0
+	var lastActive *model.ActiveDatacenter // Last record naming my datacenter, re-claimed if the key disappears
+
+	// Watch etcd so activations made by other instances are noticed immediately
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	activeUpdates := s.watchActiveDatacenter(watchCtx)
 
 	s.logger.Info("started heartbeat updater",
 		"interval", s.heartbeatCfg.UpdateInterval,
+		"jitter_percent", s.heartbeatCfg.JitterPercent,
 		"max_failures", s.heartbeatCfg.MaxFailures)
 
 	for {
@@ -1386,35 +2704,73 @@ func (s *datacenterService) heartbeatLoop(ctx context.Context) {
 		case <-s.stopHeartbeat:
 			s.logger.Info("stopping heartbeat updater")
 			return
-		case <-ticker.C:
+		case activeInfo, ok := <-activeUpdates:
+			if !ok {
+				s.logger.Warn("active datacenter watch closed, will re-establish on next heartbeat")
+				activeUpdates = nil
+				continue
+			}
+
+			// Another instance may have switched the active datacenter
+			s.invalidateActiveSummary()
+
+			if activeInfo == nil {
+				// The key was deleted or expired with its lease; re-claim it now rather than on the next heartbeat
+				if err := s.reclaimActiveDatacenter(ctx, lastActive); err != nil {
+					s.logger.Warn("failed to re-claim deleted active datacenter, retrying on next heartbeat",
+						"error", err.Error())
+				}
+				continue
+			}
+			if !activeInfo.IsActive(s.myDatacenter) {
+				s.drainForActiveDatacenter(ctx, activeInfo.Datacenter)
+			}
+		case <-timer.C:
+			timer.Reset(util.Jitter(s.heartbeatCfg.UpdateInterval, s.heartbeatCfg.JitterPercent))
+
+			if activeUpdates == nil {
+				activeUpdates = s.watchActiveDatacenter(watchCtx)
+			}
+
 			// Read active datacenter from etcd
-			activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+			activeInfo, err := s.readActiveDatacenterWithRetry(ctx)
+			if errors.Is(err, repository.ErrNoActiveDatacenter) {
+				// The key was cleared (e.g. by an emergency drain) or its lease expired while etcd was unreachable
+				if err := s.reclaimActiveDatacenter(ctx, lastActive); err != nil {
+					consecutiveFailures++
+					metrics.HeartbeatFailuresTotal.Inc()
+					s.logger.Warn("failed to re-claim missing active datacenter",
+						"failures", consecutiveFailures,
+						"max_failures", s.heartbeatCfg.MaxFailures,
+						"error", err.Error())
+					s.drainOnQuorumLoss(ctx, consecutiveFailures)
+					continue
+				}
+				consecutiveFailures = 0
+				continue
+			}
 			if err != nil {
 				consecutiveFailures++
+				metrics.HeartbeatFailuresTotal.Inc()
 				s.logger.Warn("failed to read active datacenter from etcd",
 					"failures", consecutiveFailures,
+					"max_failures", s.heartbeatCfg.MaxFailures,
 					"error", err.Error())
+				s.drainOnQuorumLoss(ctx, consecutiveFailures)
 				continue
 			}
 
-			// Check if another DC is now active
-			if activeInfo.Datacenter != s.myDatacenter {
-				if !s.amDrained {
-					s.logger.Info("another datacenter is now active, draining my nodes",
-						"active_dc", activeInfo.Datacenter)
-					allDrained, drainErr := s.drainMyNodes(ctx)
-					if drainErr != nil {
-						s.logger.Error("failed to drain nodes", "error", drainErr.Error())
-					} else {
-						s.amDrained = allDrained
-					}
-				}
+			// Check if another DC is now active (fallback in case a watch update was missed)
+			if !activeInfo.IsActive(s.myDatacenter) {
+				s.drainForActiveDatacenter(ctx, activeInfo.Datacenter)
+				lastActive = nil
 				consecutiveFailures = 0
 				continue
 			}
+			lastActive = activeInfo
 
 			// I should be active - check if I was activated externally
-			if s.amDrained {
+			if s.amDrained.Load() {
 				// Check actual node state - maybe I was activated by another instance via API
 				nodes, err := s.GetNodes(ctx, s.myDatacenter)
 				if err != nil {
@@ -1435,7 +2791,7 @@ func (s *datacenterService) heartbeatLoop(ctx context.Context) {
 					s.logger.Info("detected external activation - nodes are active, resuming heartbeat",
 						"active_nodes", activeNodes,
 						"total_nodes", len(nodes))
-					s.amDrained = false
+					s.setAmDrained(false)
 				} else {
 					// Nodes are still drained but fresh heartbeat exists - another instance running?
 					heartbeatAge := activeInfo.HeartbeatAge()
@@ -1453,26 +2809,27 @@ func (s *datacenterService) heartbeatLoop(ctx context.Context) {
 				}
 			}
 
+			// Keep the active datacenter key lease alive; a lost lease is re-granted by the write below,
+			// or by reclaimActiveDatacenter once the key expired with it
+			if err := s.etcdRepo.RenewActiveDatacenterLease(ctx); err != nil {
+				if errors.Is(err, repository.ErrLeaseLost) {
+					s.logger.Warn("active datacenter lease expired, re-acquiring", "error", err.Error())
+				} else {
+					s.logger.Error("failed to renew active datacenter lease", "error", err.Error())
+				}
+			}
+
 			// Try to update heartbeat
-			activeInfo.LastHeartbeat = time.Now()
-			err = s.etcdRepo.WriteActiveDatacenter(ctx, activeInfo)
+			err = s.writeHeartbeat(ctx, activeInfo)
 			if err != nil {
 				consecutiveFailures++
+				metrics.HeartbeatFailuresTotal.Inc()
 				s.logger.Error("failed to update heartbeat in etcd",
 					"failures", consecutiveFailures,
 					"max_failures", s.heartbeatCfg.MaxFailures,
 					"error", err.Error())
 
-				if consecutiveFailures >= s.heartbeatCfg.MaxFailures && !s.amDrained {
-					s.logger.Error("lost etcd quorum - draining nodes to prevent split-brain",
-						"failures", consecutiveFailures)
-					allDrained, drainErr := s.drainMyNodes(ctx)
-					if drainErr != nil {
-						s.logger.Error("failed to drain nodes during etcd failure", "error", drainErr.Error())
-					} else {
-						s.amDrained = allDrained
-					}
-				}
+				s.drainOnQuorumLoss(ctx, consecutiveFailures)
 			} else {
 				// Success
 				if consecutiveFailures > 0 {
@@ -1482,7 +2839,7 @@ func (s *datacenterService) heartbeatLoop(ctx context.Context) {
 				consecutiveFailures = 0
 
 				// Log warning if we're successfully writing but are drained
-				if s.amDrained {
+				if s.amDrained.Load() {
 					s.logger.Warn("successfully writing to etcd but nodes are drained",
 						"action_required", "manual activation via API needed")
 				}
@@ -1491,26 +2848,194 @@ func (s *datacenterService) heartbeatLoop(ctx context.Context) {
 	}
 }
 
+// reclaimActiveDatacenter re-claims the missing active datacenter key while my datacenter is serving,
+// e.g. after its lease expired during an etcd partition longer than the stale threshold; otherwise
+// a peer starting as sole instance could claim it while my nodes keep serving. The record of last
+// is restored when known. A key cleared on purpose (emergency drain, deactivation) comes with my
+// nodes drained and is left missing. The claim only succeeds while the key doesn't exist.
+func (s *datacenterService) reclaimActiveDatacenter(ctx context.Context, last *model.ActiveDatacenter) error {
+	if s.amDrained.Load() {
+		s.logger.Debug("no active datacenter recorded in etcd")
+		return nil
+	}
+
+	// Another instance may have drained my nodes just now, so don't trust the cached node list
+	allDrained, err := s.checkNodesAreDrained(WithFresh(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check node states: %w", err)
+	}
+	if allDrained {
+		s.logger.Info("no active datacenter recorded in etcd and my nodes are drained, staying drained")
+		s.setAmDrained(true)
+		return nil
+	}
+
+	// Drops a lease that expired with the key, so the claim grants a new one
+	if err := s.etcdRepo.RenewActiveDatacenterLease(ctx); err != nil && !errors.Is(err, repository.ErrLeaseLost) {
+		return err
+	}
+
+	now := time.Now()
+	claim := &model.ActiveDatacenter{
+		Datacenter:    s.myDatacenter,
+		ActivatedAt:   now,
+		ActivatedBy:   "heartbeat",
+		LastHeartbeat: now,
+	}
+	if last != nil {
+		claim.Datacenter = last.Datacenter
+		claim.Region = last.Region
+		claim.ActiveDatacenters = last.ActiveDatacenters
+		claim.ActivatedAt = last.ActivatedAt
+		claim.ActivatedBy = last.ActivatedBy
+	}
+
+	claimed, holder, err := s.etcdRepo.TryClaimActiveDatacenter(ctx, claim, 0)
+	if err != nil {
+		return fmt.Errorf("failed to claim active datacenter: %w", err)
+	}
+	if !claimed {
+		// The watch or the next heartbeat follows the new holder
+		holderDC := ""
+		if holder != nil {
+			holderDC = holder.Datacenter
+		}
+		s.logger.Warn("another instance claimed the missing active datacenter first",
+			"active_dc", holderDC)
+		return nil
+	}
+
+	s.invalidateActiveSummary()
+	s.logger.Warn("active datacenter key was missing while my nodes are serving, re-claimed it",
+		"datacenter", claim.Datacenter)
+	return nil
+}
+
+// readActiveDatacenterWithRetry reads the active datacenter, retrying failed reads with backoff
+// so a transient etcd blip doesn't count towards MaxFailures. A missing key is not retried.
+func (s *datacenterService) readActiveDatacenterWithRetry(ctx context.Context) (*model.ActiveDatacenter, error) {
+	backoff := s.heartbeatCfg.ReadRetryBackoff
+	for attempt := 0; ; attempt++ {
+		activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+		if err == nil || errors.Is(err, repository.ErrNoActiveDatacenter) || attempt >= s.heartbeatCfg.ReadRetries {
+			return activeInfo, err
+		}
+
+		s.logger.Debug("failed to read active datacenter from etcd, retrying",
+			"attempt", attempt+1,
+			"backoff", backoff,
+			"error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-s.stopHeartbeat:
+			return nil, err
+		case <-time.After(backoff):
+		}
+		s.etcdRepo.RotateEndpoints(ctx)
+		backoff *= 2
+	}
+}
+
+// writeHeartbeat refreshes the heartbeat of the active datacenter record read from etcd.
+// In region-wide mode every active datacenter heartbeats the same record, so the write is guarded
+// by its revision: it neither overwrites a newer activation nor fails when a peer heartbeated first.
+func (s *datacenterService) writeHeartbeat(ctx context.Context, activeInfo *model.ActiveDatacenter) error {
+	activeInfo.LastHeartbeat = time.Now()
+	if s.activeMode != config.ActiveModeRegionWide {
+		return s.etcdRepo.WriteActiveDatacenter(ctx, activeInfo)
+	}
+
+	claimed, holder, err := s.etcdRepo.TryClaimActiveDatacenter(ctx, activeInfo, activeInfo.Revision)
+	if err != nil {
+		return err
+	}
+	if !claimed && holder != nil {
+		s.logger.Debug("active datacenter record changed since read, skipping heartbeat write",
+			"holder", holder.Datacenter)
+	}
+	return nil
+}
+
+// drainOnQuorumLoss drains my nodes once consecutive heartbeat failures reach MaxFailures
+func (s *datacenterService) drainOnQuorumLoss(ctx context.Context, consecutiveFailures int) {
+	if consecutiveFailures < s.heartbeatCfg.MaxFailures || s.amDrained.Load() {
+		return
+	}
+
+	s.logger.Error("lost etcd quorum - draining nodes to prevent split-brain",
+		"failures", consecutiveFailures)
+	allDrained, drainErr := s.drainMyNodes(ctx)
+	event := model.NotificationEvent{
+		Type:       model.NotificationQuorumLossDrain,
+		Datacenter: s.myDatacenter,
+		Reason:     fmt.Sprintf("lost etcd quorum after %d consecutive heartbeat failures", consecutiveFailures),
+	}
+	if drainErr != nil {
+		s.logger.Error("failed to drain nodes during etcd failure", "error", drainErr.Error())
+		event.ErrorCount = 1
+	} else {
+		s.setAmDrained(allDrained)
+	}
+	s.notifier.Notify(event)
+}
+
+// watchActiveDatacenter starts watching the active datacenter key, returning nil if the watch can't be established
+func (s *datacenterService) watchActiveDatacenter(ctx context.Context) <-chan *model.ActiveDatacenter {
+	updates, err := s.etcdRepo.WatchActiveDatacenter(ctx)
+	if err != nil {
+		s.logger.Warn("failed to watch active datacenter, relying on heartbeat polling",
+			"error", err.Error())
+		return nil
+	}
+	return updates
+}
+
+// drainForActiveDatacenter drains my nodes once another datacenter has become active
+func (s *datacenterService) drainForActiveDatacenter(ctx context.Context, activeDC string) {
+	if s.amDrained.Load() {
+		return
+	}
+
+	s.logger.Info("another datacenter is now active, draining my nodes",
+		"active_dc", activeDC)
+	allDrained, err := s.drainMyNodes(ctx)
+	if err != nil {
+		s.logger.Error("failed to drain nodes", "error", err.Error())
+		return
+	}
+	s.setAmDrained(allDrained)
+}
+
 // GetStatus returns the current status of the dc-switcher service including heartbeat info
 func (s *datacenterService) GetStatus(ctx context.Context) (*model.ServiceStatus, error) {
 	status := &model.ServiceStatus{
 		MyDatacenter:      s.myDatacenter,
-		AmDrained:         s.amDrained,
+		AmDrained:         s.amDrained.Load(),
 		HeartbeatInterval: s.heartbeatCfg.UpdateInterval.Milliseconds(),
 		StaleThreshold:    s.heartbeatCfg.StaleThreshold.Milliseconds(),
 	}
 
+	cacheStats := s.cache.Stats()
+	status.Cache = model.CacheStats{
+		Hits:   cacheStats.Hits,
+		Misses: cacheStats.Misses,
+		Items:  cacheStats.Items,
+	}
+
+	// Use the background ping result so a missing active datacenter key is not reported as a lost connection
+	status.EtcdConnected = s.etcdRepo.Connected()
+
 	// Try to read active datacenter from etcd
 	activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
 	if err != nil {
 		// etcd not connected or no active datacenter
-		status.EtcdConnected = false
 		return status, nil
 	}
 
-	// etcd connected successfully
-	status.EtcdConnected = true
 	status.ActiveDatacenter = activeInfo.Datacenter
+	status.ActiveDatacenters = activeInfo.Datacenters()
 	status.LastHeartbeat = activeInfo.LastHeartbeat
 	status.ActivatedAt = activeInfo.ActivatedAt
 	status.ActivatedBy = activeInfo.ActivatedBy
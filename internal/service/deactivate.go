@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+// DeactivateDatacenter drains every node of dc without activating another datacenter.
+// If dc is the active datacenter recorded in etcd, the record is cleared.
+func (s *datacenterService) DeactivateDatacenter(ctx context.Context, dc string, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	if _, err := s.repo.GetClusterRegion(dc); err != nil {
+		return nil, fmt.Errorf("target datacenter: %w", err)
+	}
+
+	return s.deactivate(ctx, dc, model.ActivationTargetDatacenter, []string{dc}, drainOverride)
+}
+
+// DeactivateRegion drains every node of every datacenter in region without activating another region.
+// If the active datacenter recorded in etcd belongs to region, the record is cleared.
+func (s *datacenterService) DeactivateRegion(ctx context.Context, region string, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	clusterNames := s.repo.GetClustersByRegion(region)
+	if len(clusterNames) == 0 {
+		return nil, fmt.Errorf("%w: %s has no datacenters", repository.ErrRegionNotFound, region)
+	}
+
+	return s.deactivate(ctx, region, model.ActivationTargetRegion, clusterNames, drainOverride)
+}
+
+// deactivate drains clusterNames and relinquishes the active datacenter record if it points to one of them
+func (s *datacenterService) deactivate(ctx context.Context, target, targetType string, clusterNames []string, drainOverride *model.DrainOverride) (*model.ActivationResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	release, err := s.acquireActivation(target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	drainOpts := s.drainOptions(drainOverride)
+
+	s.logger.Info("starting deactivation",
+		slog.String("target", target),
+		slog.String("target_type", targetType),
+		slog.Int("cluster_count", len(clusterNames)),
+		slog.Duration("drain_deadline", drainOpts.Deadline),
+	)
+
+	result := &model.ActivationResult{
+		Deactivated: target,
+		Errors:      []string{},
+	}
+	s.drainClusters(ctx, clusterNames, drainOpts, result)
+
+	if result.Cancelled {
+		return s.cancelActivation(ctx, target, start, result, false)
+	}
+
+	// Relinquish the active datacenter only when it was one of the deactivated datacenters.
+	// With several active datacenters the record keeps the ones that were not deactivated.
+	cleared, err := s.relinquishDeactivated(ctx, clusterNames)
+	if err != nil {
+		s.logger.Error("failed to relinquish active datacenter", slog.String("error", err.Error()))
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	s.markDrainedIfIncluded(result)
+
+	// The health checker detects a region that is still active on its next check
+	if cleared && s.healthChecker != nil {
+		s.healthChecker.SetActiveRegion("")
+	}
+
+	s.logger.Info("deactivation completed",
+		slog.String("target", target),
+		slog.Int("drained_nodes", result.DrainedNodes),
+		slog.Bool("active_datacenter_cleared", cleared),
+		slog.Int("errors_count", len(result.Errors)),
+	)
+
+	s.recordStuckNodes(ctx, result)
+	s.recordActivation(target, start, result, nil)
+	s.recordActivationEvent(ctx, targetType, "api-deactivate", result)
+	s.notifier.Notify(model.NotificationEvent{
+		Type:       model.NotificationDeactivation,
+		Reason:     fmt.Sprintf("%s %s deactivated via API", targetType, target),
+		ErrorCount: len(result.Errors),
+	})
+
+	return result, nil
+}
+
+// maxRelinquishAttempts bounds how often deactivation and relinquishing re-read an active datacenter record that changed
+const maxRelinquishAttempts = 3
+
+// relinquishDeactivated removes clusterNames from the active datacenter record and reports whether
+// the record was deleted. Changes are guarded by the revision read, like TryClaimActiveDatacenter,
+// so a claim another instance made in the meantime is never overwritten; the record is read again then.
+func (s *datacenterService) relinquishDeactivated(ctx context.Context, clusterNames []string) (bool, error) {
+	for attempt := 1; attempt <= maxRelinquishAttempts; attempt++ {
+		activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+		if errors.Is(err, repository.ErrNoActiveDatacenter) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read active datacenter from etcd: %w", err)
+		}
+
+		remaining := slices.DeleteFunc(slices.Clone(activeInfo.Datacenters()), func(dc string) bool {
+			return slices.Contains(clusterNames, dc)
+		})
+		if len(remaining) == len(activeInfo.Datacenters()) {
+			return false, nil
+		}
+
+		var done bool
+		if len(remaining) > 0 {
+			update := *activeInfo
+			update.Datacenter = remaining[0]
+			update.ActiveDatacenters = remaining
+			if done, _, err = s.etcdRepo.TryClaimActiveDatacenter(ctx, &update, activeInfo.Revision); err != nil {
+				return false, fmt.Errorf("failed to update active datacenters in etcd: %w", err)
+			}
+		} else if done, err = s.etcdRepo.TryDeleteActiveDatacenter(ctx, activeInfo.Revision); err != nil {
+			return false, fmt.Errorf("failed to clear active datacenter in etcd: %w", err)
+		}
+		if done {
+			s.invalidateActiveSummary()
+			return len(remaining) == 0, nil
+		}
+
+		s.logger.Info("active datacenter changed since read, reading it again",
+			slog.Int("attempt", attempt),
+		)
+	}
+
+	return false, fmt.Errorf("active datacenter kept changing, left as is")
+}
+
+// drainClusters drains the nodes of clusterNames that can still take allocations, in parallel,
+// and adds the outcome to result. Node state is read from Nomad directly, bypassing the cache.
+func (s *datacenterService) drainClusters(ctx context.Context, clusterNames []string, drainOpts model.DrainOptions, result *model.ActivationResult) {
+	nodeResults := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, clusterName string) ([]model.Node, error) {
+		return s.repo.ListNodes(ctx, clusterName)
+	})
+
+	type nodeToDrain struct {
+		clusterName string
+		node        model.Node
+	}
+	var nodesToDrain []nodeToDrain
+
+	for i, nodeResult := range nodeResults {
+		clusterName := clusterNames[i]
+		region, _ := s.repo.GetClusterRegion(clusterName)
+
+		if nodeResult.Error != nil {
+			errMsg := fmt.Sprintf("cluster %s: failed to fetch nodes: %v", clusterName, nodeResult.Error)
+			result.AddClusterError(clusterName, region, errMsg)
+			continue
+		}
+
+		summary := result.Cluster(clusterName, region)
+		for _, node := range nodeResult.Value {
+			if !node.IsReady() {
+				summary.Skipped++
+				continue
+			}
+			nodesToDrain = append(nodesToDrain, nodeToDrain{clusterName: clusterName, node: node})
+		}
+	}
+
+	drainResults := concurrent.ParallelMapWithLimit(ctx, nodesToDrain, func(ctx context.Context, ntd nodeToDrain) (struct{}, error) {
+		err := s.setNodeDrain(ctx, ntd.clusterName, ntd.node.ID, true, drainOpts)
+		if err != nil && !isContextError(err) {
+			s.logger.Error("failed to drain node",
+				slog.String("cluster", ntd.clusterName),
+				slog.String("node_id", ntd.node.ID),
+				slog.String("node_name", ntd.node.Name),
+				slog.String("error", err.Error()),
+			)
+		}
+		return struct{}{}, err
+	}, s.maxConcurrentNodeOps)
+
+	for i, drainResult := range drainResults {
+		ntd := nodesToDrain[i]
+		summary := result.Cluster(ntd.clusterName, "")
+		switch {
+		case isContextError(drainResult.Error):
+			result.Cancelled = true
+		case drainResult.Error != nil:
+			errMsg := fmt.Sprintf("cluster %s, node %s: %v", ntd.clusterName, ntd.node.ID, drainResult.Error)
+			result.Errors = append(result.Errors, errMsg)
+			summary.Errors = append(summary.Errors, errMsg)
+		default:
+			result.DrainedNodes++
+			summary.Drained++
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		s.cache.Delete(fmt.Sprintf("%s:nodes", clusterName))
+	}
+}
+
+// markDrainedIfIncluded records that my datacenter is drained when result drained it without errors
+func (s *datacenterService) markDrainedIfIncluded(result *model.ActivationResult) {
+	for _, summary := range result.PerCluster {
+		if summary.Cluster == s.myDatacenter && len(summary.Errors) == 0 {
+			s.setAmDrained(true)
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestDeactivateDatacenterRelinquishesActiveRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		active     *model.ActiveDatacenter
+		beforeTxn  func(m *mockEtcdRepo)
+		wantActive []string // datacenters of the record afterwards, nil when the key is deleted
+		wantErrors bool
+	}{
+		{
+			name:   "sole active datacenter clears the record",
+			active: &model.ActiveDatacenter{Datacenter: "dc1"},
+		},
+		{
+			name:       "other active datacenters stay recorded",
+			active:     &model.ActiveDatacenter{Datacenter: "dc1", ActiveDatacenters: []string{"dc1", "dc2"}},
+			wantActive: []string{"dc2"},
+		},
+		{
+			name:       "record of another datacenter is left alone",
+			active:     &model.ActiveDatacenter{Datacenter: "dc3"},
+			wantActive: []string{"dc3"},
+		},
+		{
+			name:   "concurrent claim by another instance is not overwritten",
+			active: &model.ActiveDatacenter{Datacenter: "dc1"},
+			beforeTxn: func(m *mockEtcdRepo) {
+				if m.active.Datacenter == "dc1" {
+					m.store(&model.ActiveDatacenter{Datacenter: "dc3"})
+				}
+			},
+			wantActive: []string{"dc3"},
+		},
+		{
+			name:   "heartbeat written in between is read again",
+			active: &model.ActiveDatacenter{Datacenter: "dc1"},
+			beforeTxn: func() func(m *mockEtcdRepo) {
+				heartbeats := 0
+				return func(m *mockEtcdRepo) {
+					if heartbeats++; heartbeats == 1 {
+						m.store(m.active)
+					}
+				}
+			}(),
+		},
+		{
+			name:   "record that keeps changing is reported and left as is",
+			active: &model.ActiveDatacenter{Datacenter: "dc1"},
+			beforeTxn: func(m *mockEtcdRepo) {
+				m.store(m.active)
+			},
+			wantActive: []string{"dc1"},
+			wantErrors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+				"dc2": {region: "eu", nodes: testNodes("dc2", 1, false)},
+				"dc3": {region: "us", nodes: testNodes("dc3", 1, false)},
+			})
+			etcd := newMockEtcdRepo(tt.active)
+			etcd.beforeTxn = tt.beforeTxn
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+
+			result, err := svc.DeactivateDatacenter(context.Background(), "dc1", nil)
+			if err != nil {
+				t.Fatalf("DeactivateDatacenter() error = %v", err)
+			}
+			if got := len(result.Errors) > 0; got != tt.wantErrors {
+				t.Errorf("result errors = %v, want errors %v", result.Errors, tt.wantErrors)
+			}
+			if got := len(repo.drained("dc1", true)); got != 2 {
+				t.Errorf("drained %d nodes of dc1, want 2", got)
+			}
+
+			current := etcd.current()
+			if tt.wantActive == nil {
+				if current != nil {
+					t.Errorf("record = %+v, want it deleted", current)
+				}
+				return
+			}
+			if current == nil || !slices.Equal(current.Datacenters(), tt.wantActive) {
+				t.Errorf("record = %+v, want datacenters %v", current, tt.wantActive)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationDrainOptions(t *testing.T) {
+	immediate := time.Duration(0)
+	infinite := time.Duration(-1)
+	ignore := true
+
+	tests := []struct {
+		name     string
+		cfg      config.DrainConfig
+		override *model.DrainOverride
+		region   bool
+		want     model.DrainOptions
+	}{
+		{
+			name: "configured deadline",
+			cfg:  config.DrainConfig{Deadline: 10 * time.Minute},
+			want: model.DrainOptions{Deadline: 10 * time.Minute},
+		},
+		{
+			name: "configured infinite deadline",
+			cfg:  config.DrainConfig{Deadline: -1, IgnoreSystemJobs: true},
+			want: model.DrainOptions{Deadline: -1, IgnoreSystemJobs: true},
+		},
+		{
+			name:     "override to immediate",
+			cfg:      config.DrainConfig{Deadline: 10 * time.Minute},
+			override: &model.DrainOverride{Deadline: &immediate},
+			want:     model.DrainOptions{Deadline: 0},
+		},
+		{
+			name:     "override keeps unset fields",
+			cfg:      config.DrainConfig{Deadline: 10 * time.Minute},
+			override: &model.DrainOverride{IgnoreSystemJobs: &ignore},
+			want:     model.DrainOptions{Deadline: 10 * time.Minute, IgnoreSystemJobs: true},
+		},
+		{
+			name:     "region activation override",
+			cfg:      config.DrainConfig{Deadline: time.Minute},
+			override: &model.DrainOverride{Deadline: &infinite},
+			region:   true,
+			want:     model.DrainOptions{Deadline: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(activationClusters())
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{drain: tt.cfg})
+
+			var err error
+			if tt.region {
+				_, err = svc.ActivateRegion(context.Background(), "us", false, tt.override)
+			} else {
+				_, err = svc.ActivateDatacenter(context.Background(), "dc3", false, false, tt.override)
+			}
+			if err != nil {
+				t.Fatalf("activation error = %v", err)
+			}
+
+			drains := 0
+			for _, call := range repo.drainCalls {
+				if !call.drain {
+					continue
+				}
+				drains++
+				if call.opts != tt.want {
+					t.Errorf("%s drained with %+v, want %+v", call.nodeID, call.opts, tt.want)
+				}
+			}
+			if drains == 0 {
+				t.Fatal("no node was drained")
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDrainAllNodesInRegionSkipsDisabledClusters(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusters    map[string]*mockCluster
+		wantDrained map[string]int // cluster -> drained nodes
+		wantErr     error
+	}{
+		{
+			name: "disabled cluster keeps serving",
+			clusters: map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+				"dc2": {region: "eu", nodes: testNodes("dc2", 2, false), disabled: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 1, false)},
+			},
+			wantDrained: map[string]int{"dc1": 2},
+		},
+		{
+			name: "all clusters of the region disabled",
+			clusters: map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), disabled: true},
+			},
+			wantDrained: map[string]int{},
+			wantErr:     ErrDatacenterDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(tt.clusters)
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+			result, err := svc.DrainAllNodesInRegion(context.Background(), "eu")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DrainAllNodesInRegion() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, name := range repo.GetClusterNames() {
+				if got := len(repo.drained(name, true)); got != tt.wantDrained[name] {
+					t.Errorf("cluster %s: drained %d nodes, want %d", name, got, tt.wantDrained[name])
+				}
+			}
+			if err != nil {
+				return
+			}
+
+			var clusters []string
+			for _, cluster := range result.PerCluster {
+				clusters = append(clusters, cluster.Cluster)
+			}
+			if !slices.Equal(clusters, []string{"dc1"}) {
+				t.Errorf("result clusters = %v, want [dc1]", clusters)
+			}
+		})
+	}
+}
+
+func TestDrainAllNodesInRegionWaitsForActivation(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+	})
+	svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+	release, err := svc.acquireActivation("us")
+	if err != nil {
+		t.Fatalf("acquireActivation() error = %v", err)
+	}
+
+	_, err = svc.DrainAllNodesInRegion(context.Background(), "eu")
+	var inProgress *ActivationInProgressError
+	if !errors.As(err, &inProgress) || inProgress.Target != "us" {
+		t.Fatalf("DrainAllNodesInRegion() during an activation error = %v, want activation of us in progress", err)
+	}
+	if got := len(repo.drained("dc1", true)); got != 0 {
+		t.Fatalf("drained %d nodes during an activation, want none", got)
+	}
+
+	release()
+	if _, err := svc.DrainAllNodesInRegion(context.Background(), "eu"); err != nil {
+		t.Fatalf("DrainAllNodesInRegion() after the activation error = %v", err)
+	}
+	if got := len(repo.drained("dc1", true)); got != 2 {
+		t.Errorf("drained %d nodes after the activation, want 2", got)
+	}
+
+	// The drain itself holds the activation slot while it runs
+	if _, err := svc.acquireActivation("us"); err != nil {
+		t.Errorf("activation slot still held after the drain: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// drainingAllocs returns allocations that stay running until the given poll, then complete
+func drainingAllocs(until int) func(nodeID string, poll int) ([]model.Allocation, error) {
+	return func(nodeID string, poll int) ([]model.Allocation, error) {
+		status := "running"
+		if poll >= until {
+			status = "complete"
+		}
+		return []model.Allocation{{ID: nodeID + "-a1", JobType: "service", ClientStatus: status}}, nil
+	}
+}
+
+func TestDrainMyNodesWaitsForDrainComplete(t *testing.T) {
+	tests := []struct {
+		name        string
+		wait        bool
+		timeout     time.Duration
+		ignoreSys   bool
+		allocs      func(nodeID string, poll int) ([]model.Allocation, error)
+		cancel      bool // Cancel the context once allocations are listed
+		wantDrained bool
+		wantErr     bool
+		wantPolls   int // ListNodeAllocations calls per node, -1 for several
+	}{
+		{
+			name:        "disabled doesn't poll",
+			allocs:      drainingAllocs(3),
+			wantDrained: true,
+		},
+		{
+			name:        "waits until allocations leave",
+			wait:        true,
+			allocs:      drainingAllocs(3),
+			wantDrained: true,
+			wantPolls:   3,
+		},
+		{
+			name:        "already empty nodes",
+			wait:        true,
+			allocs:      drainingAllocs(1),
+			wantDrained: true,
+			wantPolls:   1,
+		},
+		{
+			name:    "list errors keep waiting",
+			wait:    true,
+			timeout: time.Second,
+			allocs: func(nodeID string, poll int) ([]model.Allocation, error) {
+				if poll < 2 {
+					return nil, errors.New("nomad unavailable")
+				}
+				return nil, nil
+			},
+			wantDrained: true,
+			wantPolls:   2,
+		},
+		{
+			name:      "system jobs ignored when drains leave them",
+			wait:      true,
+			ignoreSys: true,
+			allocs: func(nodeID string, poll int) ([]model.Allocation, error) {
+				return []model.Allocation{{ID: nodeID + "-sys", JobType: "system", ClientStatus: "running"}}, nil
+			},
+			wantDrained: true,
+			wantPolls:   1,
+		},
+		{
+			name:        "timeout still reports drained",
+			wait:        true,
+			timeout:     30 * time.Millisecond,
+			allocs:      drainingAllocs(1000),
+			wantDrained: true,
+			wantPolls:   -1,
+		},
+		{
+			name:      "cancelled context fails the wait",
+			wait:      true,
+			allocs:    drainingAllocs(1000),
+			cancel:    true,
+			wantErr:   true,
+			wantPolls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			repo.onListAllocations = func(nodeID string, poll int) ([]model.Allocation, error) {
+				if tt.cancel {
+					cancel()
+				}
+				return tt.allocs(nodeID, poll)
+			}
+			if tt.timeout == 0 {
+				tt.timeout = time.Minute
+			}
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{
+				heartbeat: config.HeartbeatConfig{
+					WaitForDrainComplete: tt.wait,
+					DrainCompleteTimeout: tt.timeout,
+					DrainPollInterval:    5 * time.Millisecond,
+				},
+				drain: config.DrainConfig{IgnoreSystemJobs: tt.ignoreSys},
+			})
+
+			drained, err := svc.drainMyNodes(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("drainMyNodes() error = %v, want error %v", err, tt.wantErr)
+			}
+			if drained != tt.wantDrained {
+				t.Errorf("drainMyNodes() = %v, want %v", drained, tt.wantDrained)
+			}
+			if got := len(repo.drained("dc1", true)); got != 2 {
+				t.Errorf("drained %d nodes, want 2", got)
+			}
+
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			for _, node := range testNodes("dc1", 2, false) {
+				polls := repo.allocPolls[node.ID]
+				if tt.wantPolls < 0 && polls < 2 {
+					t.Errorf("node %s polled %d times before the timeout, want several", node.ID, polls)
+				}
+				if tt.wantPolls >= 0 && polls != tt.wantPolls {
+					t.Errorf("node %s polled %d times, want %d", node.ID, polls, tt.wantPolls)
+				}
+			}
+		})
+	}
+}
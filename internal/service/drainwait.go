@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// drainWaitKey is the context key asking activations to wait for allocations to leave drained nodes
+type drainWaitKey struct{}
+
+// WithDrainWait returns a context under which activations and deactivations poll the nodes they
+// drained and report those still running allocations in ActivationResult.StuckNodes
+func WithDrainWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, drainWaitKey{}, true)
+}
+
+// shouldWaitForDrain reports whether ctx was marked with WithDrainWait
+func shouldWaitForDrain(ctx context.Context) bool {
+	wait, _ := ctx.Value(drainWaitKey{}).(bool)
+	return wait
+}
+
+// recordStuckNodes polls the drained nodes of every cluster the activation drained nodes in, until no
+// allocations are left on them or heartbeat.drain_complete_timeout elapses, and records the nodes
+// still running allocations in result.StuckNodes. It does nothing unless ctx was marked with WithDrainWait.
+func (s *datacenterService) recordStuckNodes(ctx context.Context, result *model.ActivationResult) {
+	if !shouldWaitForDrain(ctx) || result.DryRun || result.Cancelled {
+		return
+	}
+
+	drained := make(map[string][]model.Node)
+	for _, summary := range result.PerCluster {
+		if summary.Drained == 0 {
+			continue
+		}
+		nodes, err := s.repo.ListNodes(ctx, summary.Cluster)
+		if err != nil {
+			result.AddClusterError(summary.Cluster, summary.Region, fmt.Sprintf("failed to list nodes to check drain completion: %v", err))
+			continue
+		}
+		// Nomad clears the drain flag once a drain completes, the node stays ineligible
+		drained[summary.Cluster] = slices.DeleteFunc(nodes, func(node model.Node) bool {
+			return !node.Drain && node.SchedulingEligibility == "eligible"
+		})
+	}
+	if len(drained) == 0 {
+		return
+	}
+
+	deadline := time.Now().Add(s.heartbeatCfg.DrainCompleteTimeout)
+	for {
+		stuck := s.nodesWithAllocations(ctx, drained)
+		if len(stuck) == 0 {
+			s.logger.Info("allocations left all drained nodes")
+			return
+		}
+
+		if time.Now().After(deadline) {
+			s.logger.Warn("allocations still running on drained nodes",
+				slog.Any("stuck_nodes", stuck),
+				slog.Duration("timeout", s.heartbeatCfg.DrainCompleteTimeout),
+			)
+			result.StuckNodes = stuck
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			result.StuckNodes = stuck
+			return
+		case <-time.After(s.heartbeatCfg.DrainPollInterval):
+		}
+	}
+}
+
+// nodesWithAllocations returns the drained nodes, as "cluster/node", that still run pending or running
+// allocations. System jobs are skipped if the drain options leave them running. Nodes whose
+// allocations can't be listed are included, as their drain can't be confirmed.
+func (s *datacenterService) nodesWithAllocations(ctx context.Context, drained map[string][]model.Node) []string {
+	var stuck []string
+	for clusterName, nodes := range drained {
+		results := concurrent.ParallelMapWithLimit(ctx, nodes, func(ctx context.Context, node model.Node) (bool, error) {
+			allocs, err := s.repo.ListNodeAllocations(ctx, clusterName, node.ID)
+			if err != nil {
+				return true, err
+			}
+			return slices.ContainsFunc(allocs, func(alloc model.Allocation) bool {
+				return alloc.IsActive() && !(s.drainOpts.IgnoreSystemJobs && alloc.JobType == "system")
+			}), nil
+		}, s.maxConcurrentNodeOps)
+
+		for i, result := range results {
+			if result.Error != nil {
+				s.logger.Warn("failed to list node allocations",
+					slog.String("cluster", clusterName),
+					slog.String("node_id", nodes[i].ID),
+					slog.String("error", result.Error.Error()),
+				)
+			}
+			if result.Value || result.Error != nil {
+				stuck = append(stuck, clusterName+"/"+nodes[i].Name)
+			}
+		}
+	}
+	slices.Sort(stuck)
+	return stuck
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// emergencyDrainTarget names the emergency drain in the activation lock and metrics
+const emergencyDrainTarget = "emergency-drain"
+
+// EmergencyDrainAll drains every node of every cluster in parallel and deletes the active
+// datacenter key, so no datacenter is left active. Node state is read from Nomad directly,
+// bypassing the cache. The result has no activated target and lists the drained nodes per cluster.
+func (s *datacenterService) EmergencyDrainAll(ctx context.Context) (*model.ActivationResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	release, err := s.acquireActivation(emergencyDrainTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	drainOpts := s.drainOptions(nil)
+	clusterNames := s.repo.GetClusterNames()
+
+	s.logger.Warn("starting emergency drain of all datacenters",
+		slog.Int("cluster_count", len(clusterNames)),
+		slog.Duration("drain_deadline", drainOpts.Deadline),
+	)
+
+	result := &model.ActivationResult{Errors: []string{}}
+	s.drainClusters(ctx, clusterNames, drainOpts, result)
+
+	if result.Cancelled {
+		return s.cancelActivation(ctx, emergencyDrainTarget, start, result, false)
+	}
+
+	// Nothing is active anymore; instances must not keep or renew the old active datacenter
+	if err := s.etcdRepo.DeleteActiveDatacenter(ctx); err != nil {
+		s.logger.Error("failed to clear active datacenter in etcd", slog.String("error", err.Error()))
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to clear active datacenter in etcd: %v", err))
+	}
+	s.invalidateActiveSummary()
+
+	s.markDrainedIfIncluded(result)
+
+	// Stop monitoring the previously active region, there is no region to fail over from
+	if s.healthChecker != nil {
+		s.healthChecker.SetActiveRegion("")
+	}
+
+	s.logger.Warn("emergency drain completed",
+		slog.Int("drained_nodes", result.DrainedNodes),
+		slog.Int("errors_count", len(result.Errors)),
+	)
+
+	s.recordStuckNodes(ctx, result)
+	s.recordActivation(emergencyDrainTarget, start, result, nil)
+	s.recordActivationEvent(ctx, model.ActivationTargetAll, "api-emergency", result)
+	s.notifier.Notify(model.NotificationEvent{
+		Type:       model.NotificationEmergencyDrain,
+		Reason:     "all datacenters drained via emergency API",
+		ErrorCount: len(result.Errors),
+	})
+
+	return result, nil
+}
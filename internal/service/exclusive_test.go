@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationExclusive(t *testing.T) {
+	tests := []struct {
+		name        string
+		exclusive   bool // Expected in the result
+		activate    func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error)
+		wantDrain   map[string]int // cluster -> nodes drained
+		wantUndrain map[string]int // cluster -> nodes undrained
+	}{
+		{
+			name: "siblings preserved by default",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc2", false, false, nil)
+			},
+			wantDrain:   map[string]int{"dc3": 1},
+			wantUndrain: map[string]int{"dc2": 1},
+		},
+		{
+			name: "exclusive drains siblings",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc2", false, true, nil)
+			},
+			exclusive:   true,
+			wantDrain:   map[string]int{"dc1": 2, "dc3": 1, "dc4": 1},
+			wantUndrain: map[string]int{"dc2": 1},
+		},
+		{
+			name: "exclusive dry run changes nothing",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc2", true, true, nil)
+			},
+			exclusive: true,
+		},
+		{
+			name: "region activation undrains every sibling",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "eu", false, nil)
+			},
+			wantDrain:   map[string]int{"dc3": 1},
+			wantUndrain: map[string]int{"dc2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+				"dc2": {region: "eu", nodes: testNodes("dc2", 1, true), hasLeader: true},
+				"dc4": {region: "eu", nodes: testNodes("dc4", 1, false), hasLeader: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 1, false), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+
+			result, err := tt.activate(context.Background(), svc)
+			if err != nil {
+				t.Fatalf("activation error = %v", err)
+			}
+			if result.Exclusive != tt.exclusive {
+				t.Errorf("result exclusive = %v, want %v", result.Exclusive, tt.exclusive)
+			}
+
+			gotDrain, gotUndrain := map[string]int{}, map[string]int{}
+			for _, dc := range []string{"dc1", "dc2", "dc3", "dc4"} {
+				if n := len(repo.drained(dc, true)); n > 0 {
+					gotDrain[dc] = n
+				}
+				if n := len(repo.drained(dc, false)); n > 0 {
+					gotUndrain[dc] = n
+				}
+			}
+			if !maps.Equal(gotDrain, tt.wantDrain) {
+				t.Errorf("drained %v, want %v", gotDrain, tt.wantDrain)
+			}
+			if !maps.Equal(gotUndrain, tt.wantUndrain) {
+				t.Errorf("undrained %v, want %v", gotUndrain, tt.wantUndrain)
+			}
+		})
+	}
+}
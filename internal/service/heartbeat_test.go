@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestReclaimActiveDatacenter(t *testing.T) {
+	last := &model.ActiveDatacenter{
+		Datacenter:        "dc1",
+		Region:            "eu",
+		ActiveDatacenters: []string{"dc1", "dc2"},
+		ActivatedBy:       "api",
+	}
+
+	tests := []struct {
+		name        string
+		drained     bool // my nodes are drained in Nomad
+		amDrained   bool
+		last        *model.ActiveDatacenter
+		holder      *model.ActiveDatacenter // key written by a peer before the claim
+		claimErr    error
+		wantErr     bool
+		wantHolder  string // datacenter recorded afterwards, empty when the key stays missing
+		wantActive  []string
+		wantDrained bool
+	}{
+		{
+			name:       "serving datacenter restores its last record",
+			last:       last,
+			wantHolder: "dc1",
+			wantActive: []string{"dc1", "dc2"},
+		},
+		{
+			name:       "serving datacenter without a known record claims itself",
+			wantHolder: "dc1",
+		},
+		{
+			name:        "drained nodes leave a cleared key missing",
+			drained:     true,
+			last:        last,
+			wantDrained: true,
+		},
+		{
+			name:        "intentionally drained instance does not claim",
+			amDrained:   true,
+			wantDrained: true,
+		},
+		{
+			name:       "peer claimed the key first",
+			holder:     &model.ActiveDatacenter{Datacenter: "dc3"},
+			wantHolder: "dc3",
+		},
+		{
+			name:     "failed claim is reported",
+			claimErr: errors.New("etcd unavailable"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, tt.drained)},
+			})
+			etcd := newMockEtcdRepo(tt.holder)
+			etcd.claimErr = tt.claimErr
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+			svc.setAmDrained(tt.amDrained)
+
+			err := svc.reclaimActiveDatacenter(context.Background(), tt.last)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reclaimActiveDatacenter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			current := etcd.current()
+			switch {
+			case tt.wantHolder == "" && current != nil:
+				t.Errorf("key holds %q, want it missing", current.Datacenter)
+			case tt.wantHolder != "" && (current == nil || current.Datacenter != tt.wantHolder):
+				t.Errorf("key holds %+v, want %q", current, tt.wantHolder)
+			case tt.wantActive != nil && !slices.Equal(current.ActiveDatacenters, tt.wantActive):
+				t.Errorf("active datacenters = %v, want %v", current.ActiveDatacenters, tt.wantActive)
+			}
+			if svc.amDrained.Load() != tt.wantDrained {
+				t.Errorf("amDrained = %v, want %v", svc.amDrained.Load(), tt.wantDrained)
+			}
+		})
+	}
+}
+
+// runHeartbeat runs the heartbeat loop until cond holds or the timeout expires
+func runHeartbeat(t *testing.T, svc *datacenterService, cond func() bool) {
+	t.Helper()
+
+	svc.StartHeartbeat(context.Background())
+	defer func() {
+		svc.StopHeartbeat()
+		<-svc.heartbeatDone
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for !cond() {
+		select {
+		case <-deadline:
+			t.Fatal("condition not reached before timeout")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestHeartbeatLoopReclaimsExpiredKey(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+	})
+	etcd := newMockEtcdRepo(nil) // The lease expired with the key
+	svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+		heartbeat: config.HeartbeatConfig{UpdateInterval: 10 * time.Millisecond, MaxFailures: 3},
+	})
+
+	runHeartbeat(t, svc, func() bool { return etcd.current() != nil })
+
+	if got := etcd.current().Datacenter; got != "dc1" {
+		t.Errorf("re-claimed datacenter = %q, want dc1", got)
+	}
+}
+
+func TestHeartbeatLoopCountsFailedReclaims(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+	})
+	etcd := newMockEtcdRepo(nil)
+	etcd.claimErr = errors.New("etcd unavailable")
+	svc, notifier := newTestService(t, repo, etcd, testServiceOptions{
+		heartbeat: config.HeartbeatConfig{UpdateInterval: 10 * time.Millisecond, MaxFailures: 2},
+	})
+
+	// Failed re-claims count towards MaxFailures and end in the quorum loss drain
+	runHeartbeat(t, svc, func() bool { return len(repo.drained("dc1", true)) == 2 })
+
+	if !slices.Contains(notifier.types(), model.NotificationQuorumLossDrain) {
+		t.Errorf("notifications = %v, want a quorum loss drain", notifier.types())
+	}
+}
+
+func TestReadActiveDatacenterWithRetry(t *testing.T) {
+	errEtcd := errors.New("etcd unavailable")
+
+	tests := []struct {
+		name         string
+		active       *model.ActiveDatacenter
+		readFailures int // 0 keeps failing
+		readErr      error
+		retries      int
+		wantErr      error
+		wantReads    int // Every retry is preceded by an endpoint rotation
+	}{
+		{
+			name:      "successful read is not retried",
+			active:    &model.ActiveDatacenter{Datacenter: "dc1"},
+			retries:   2,
+			wantReads: 1,
+		},
+		{
+			name:         "transient failure is retried",
+			active:       &model.ActiveDatacenter{Datacenter: "dc1"},
+			readErr:      errEtcd,
+			readFailures: 2,
+			retries:      2,
+			wantReads:    3,
+		},
+		{
+			name:      "persistent failure is returned after the retries",
+			active:    &model.ActiveDatacenter{Datacenter: "dc1"},
+			readErr:   errEtcd,
+			retries:   2,
+			wantErr:   errEtcd,
+			wantReads: 3,
+		},
+		{
+			name:      "missing key is not retried",
+			retries:   2,
+			wantErr:   repository.ErrNoActiveDatacenter,
+			wantReads: 1,
+		},
+		{
+			name:      "no retries configured",
+			active:    &model.ActiveDatacenter{Datacenter: "dc1"},
+			readErr:   errEtcd,
+			wantErr:   errEtcd,
+			wantReads: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(tt.active)
+			etcd.readErr = tt.readErr
+			etcd.readFailures = tt.readFailures
+			svc, _ := newTestService(t, newMockNomadRepo(nil), etcd, testServiceOptions{
+				heartbeat: config.HeartbeatConfig{ReadRetries: tt.retries, ReadRetryBackoff: time.Millisecond},
+			})
+
+			info, err := svc.readActiveDatacenterWithRetry(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readActiveDatacenterWithRetry() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && info.Datacenter != tt.active.Datacenter {
+				t.Errorf("datacenter = %q, want %q", info.Datacenter, tt.active.Datacenter)
+			}
+			if etcd.reads != tt.wantReads {
+				t.Errorf("reads = %d, want %d", etcd.reads, tt.wantReads)
+			}
+			if etcd.rotations != tt.wantReads-1 {
+				t.Errorf("endpoint rotations = %d, want %d", etcd.rotations, tt.wantReads-1)
+			}
+		})
+	}
+}
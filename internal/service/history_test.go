@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationRecordsHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		activate  func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error)
+		appendErr error
+		want      *model.ActivationEvent
+	}{
+		{
+			name: "datacenter",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			},
+			want: &model.ActivationEvent{Target: "dc3", TargetType: model.ActivationTargetDatacenter, ActivatedBy: "api", DrainedNodes: 2, UnDrainedNodes: 2},
+		},
+		{
+			name: "datacenter started by the health checker",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(WithActivatedBy(ctx, "failover"), "dc3", false, false, nil)
+			},
+			want: &model.ActivationEvent{Target: "dc3", TargetType: model.ActivationTargetDatacenter, ActivatedBy: "failover", DrainedNodes: 2, UnDrainedNodes: 2},
+		},
+		{
+			name: "region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "us", false, nil)
+			},
+			want: &model.ActivationEvent{Target: "us", TargetType: model.ActivationTargetRegion, ActivatedBy: "api-region", DrainedNodes: 2, UnDrainedNodes: 2},
+		},
+		{
+			name: "history failure doesn't fail the activation",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			},
+			appendErr: errors.New("etcd unavailable"),
+		},
+		{
+			name: "failed activation isn't recorded",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc9", false, false, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			etcd.appendErr = tt.appendErr
+			svc, _ := newTestService(t, newMockNomadRepo(activationClusters()), etcd, testServiceOptions{})
+
+			result, err := tt.activate(context.Background(), svc)
+			if tt.appendErr != nil && (err != nil || result == nil) {
+				t.Fatalf("activation failed with a history error: %v", err)
+			}
+
+			if tt.want == nil {
+				if len(etcd.events) != 0 {
+					t.Errorf("events = %+v, want none", etcd.events)
+				}
+				return
+			}
+			if len(etcd.events) != 1 {
+				t.Fatalf("events = %+v, want one", etcd.events)
+			}
+			got := etcd.events[0]
+			if got.Timestamp.IsZero() {
+				t.Error("event has no timestamp")
+			}
+			got.Timestamp = tt.want.Timestamp
+			if got != *tt.want {
+				t.Errorf("event = %+v, want %+v", got, *tt.want)
+			}
+			if active := etcd.current(); active == nil || active.ActivatedBy != tt.want.ActivatedBy {
+				t.Errorf("active datacenter = %+v, want activated by %q", active, tt.want.ActivatedBy)
+			}
+		})
+	}
+}
+
+func TestGetActivationHistory(t *testing.T) {
+	etcd := newMockEtcdRepo(nil)
+	for _, target := range []string{"dc1", "dc2", "dc3"} {
+		_ = etcd.AppendActivationEvent(context.Background(), &model.ActivationEvent{Target: target})
+	}
+	svc, _ := newTestService(t, newMockNomadRepo(nil), etcd, testServiceOptions{})
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{limit: 1, want: []string{"dc3"}},
+		{limit: 2, want: []string{"dc3", "dc2"}},
+		{limit: 50, want: []string{"dc3", "dc2", "dc1"}},
+	}
+
+	for _, tt := range tests {
+		events, err := svc.GetActivationHistory(context.Background(), tt.limit)
+		if err != nil {
+			t.Fatalf("GetActivationHistory(%d) error = %v", tt.limit, err)
+		}
+		var got []string
+		for _, event := range events {
+			got = append(got, event.Target)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("GetActivationHistory(%d) = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestRestartJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		cluster            *mockCluster
+		readOnly           bool
+		wantErr            error
+		wantSuccess        bool
+		wantAlreadyStopped bool
+		wantCalls          int
+	}{
+		{
+			name:        "running job",
+			cluster:     &mockCluster{region: "eu"},
+			wantSuccess: true,
+			wantCalls:   1,
+		},
+		{
+			name:               "stopped job is only started",
+			cluster:            &mockCluster{region: "eu", stopped: map[string]bool{"api": true}},
+			wantSuccess:        true,
+			wantAlreadyStopped: true,
+			wantCalls:          1,
+		},
+		{
+			name:      "unknown job",
+			cluster:   &mockCluster{region: "eu", jobErr: repository.ErrJobNotFound},
+			wantErr:   repository.ErrJobNotFound,
+			wantCalls: 1,
+		},
+		{
+			name:     "read-only",
+			cluster:  &mockCluster{region: "eu"},
+			readOnly: true,
+			wantErr:  ErrReadOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": tt.cluster})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{readOnly: tt.readOnly})
+			svc.cache.Set("dc1:jobs", &model.JobList{}, 0)
+
+			result, err := svc.RestartJob(context.Background(), "dc1", "api")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.jobCalls) != tt.wantCalls {
+				t.Fatalf("repository calls = %v, want %d", repo.jobCalls, tt.wantCalls)
+			}
+			if tt.readOnly {
+				if result != nil {
+					t.Errorf("result = %+v, want nil", result)
+				}
+				return
+			}
+
+			if result.Action != "restart" || result.JobID != "api" {
+				t.Errorf("result = %+v, want a restart of api", result)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantSuccess)
+			}
+			if result.AlreadyStopped != tt.wantAlreadyStopped {
+				t.Errorf("AlreadyStopped = %v, want %v", result.AlreadyStopped, tt.wantAlreadyStopped)
+			}
+			if tt.wantErr != nil && len(result.Errors) == 0 {
+				t.Error("failed restart has no error message")
+			}
+			if _, cached := svc.cache.Get("dc1:jobs"); cached {
+				t.Error("job list cache was not invalidated")
+			}
+		})
+	}
+}
+
+func TestStopJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		purge      bool
+		jobErr     error
+		wantErr    error
+		wantPurged bool
+	}{
+		{name: "stop keeps the job"},
+		{name: "stop with purge", purge: true, wantPurged: true},
+		{name: "failed purge is not reported as purged", purge: true, jobErr: repository.ErrNomadUnavailable, wantErr: repository.ErrNomadUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": {region: "eu", jobErr: tt.jobErr}})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+			result, err := svc.StopJob(context.Background(), "dc1", "api", tt.purge)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Purged != tt.wantPurged {
+				t.Errorf("Purged = %v, want %v", result.Purged, tt.wantPurged)
+			}
+			want := jobCall{action: "stop", cluster: "dc1", jobID: "api", purge: tt.purge}
+			if len(repo.jobCalls) != 1 || repo.jobCalls[0] != want {
+				t.Errorf("repository calls = %v, want [%v]", repo.jobCalls, want)
+			}
+		})
+	}
+}
+
+func TestBulkJobActionPurge(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       model.BulkJobActionRequest
+		wantPurge bool
+	}{
+		{
+			name: "stop keeps the jobs",
+			req:  model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api", "web"}},
+		},
+		{
+			name:      "stop with purge",
+			req:       model.BulkJobActionRequest{Action: "stop", Purge: true, JobIDs: []string{"api", "web"}},
+			wantPurge: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": {region: "eu"}})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+			result, err := svc.BulkJobAction(context.Background(), "dc1", tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Succeeded != 2 || result.Failed != 0 {
+				t.Errorf("succeeded/failed = %d/%d, want 2/0", result.Succeeded, result.Failed)
+			}
+			for _, res := range result.Results {
+				if res.Purged != tt.wantPurge {
+					t.Errorf("%s: Purged = %v, want %v", res.JobID, res.Purged, tt.wantPurge)
+				}
+			}
+			for _, call := range repo.jobCalls {
+				if call.action != "stop" || call.purge != tt.wantPurge {
+					t.Errorf("repository call = %+v, want a stop with purge %v", call, tt.wantPurge)
+				}
+			}
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	detail := &model.JobDetail{Job: model.Job{ID: "api", Status: "running"}, Version: 1}
+
+	tests := []struct {
+		name    string
+		cluster *mockCluster
+		want    *model.JobDetail
+		wantErr error
+	}{
+		{
+			name:    "found",
+			cluster: &mockCluster{region: "eu", details: map[string]*model.JobDetail{"api": detail}},
+			want:    detail,
+		},
+		{
+			name:    "unknown job",
+			cluster: &mockCluster{region: "eu"},
+			wantErr: repository.ErrJobNotFound,
+		},
+		{
+			name:    "nomad unavailable",
+			cluster: &mockCluster{region: "eu", jobErr: repository.ErrNomadUnavailable},
+			wantErr: repository.ErrNomadUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": tt.cluster})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+			got, err := svc.GetJob(context.Background(), "dc1", "api")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detail = %+v, want %+v", got, tt.want)
+			}
+			if len(repo.jobCalls) != 1 {
+				t.Errorf("repository calls = %v, want one lookup", repo.jobCalls)
+			}
+		})
+	}
+}
+
+func TestGetJobsFilter(t *testing.T) {
+	jobs := []model.Job{
+		{ID: "api", Type: "service", Status: "running"},
+		{ID: "api-worker", Type: "service", Status: "dead"},
+		{ID: "backup", Type: "batch", Status: "dead"},
+		{ID: "logs", Type: "system", Status: "running"},
+		{ID: "web", Type: "service", Status: "running"},
+	}
+
+	tests := []struct {
+		name       string
+		filter     model.JobFilter
+		warm       bool // List every job first so the cache answers the filtered listing
+		wantIDs    []string
+		wantTotal  int
+		wantPrefix string // Prefix of the last listing that reached the repository
+	}{
+		{name: "no filter", wantIDs: []string{"api", "api-worker", "backup", "logs", "web"}, wantTotal: 5},
+		{name: "status", filter: model.JobFilter{Status: "dead"}, wantIDs: []string{"api-worker", "backup"}, wantTotal: 2},
+		{name: "type", filter: model.JobFilter{Type: "service"}, wantIDs: []string{"api", "api-worker", "web"}, wantTotal: 3},
+		{name: "status and type", filter: model.JobFilter{Status: "running", Type: "service"}, wantIDs: []string{"api", "web"}, wantTotal: 2},
+		{name: "prefix listed by nomad", filter: model.JobFilter{Prefix: "api"}, wantIDs: []string{"api", "api-worker"}, wantTotal: 2, wantPrefix: "api"},
+		{name: "prefix answered from the cache", filter: model.JobFilter{Prefix: "api"}, warm: true, wantIDs: []string{"api", "api-worker"}, wantTotal: 2},
+		{name: "prefix and status", filter: model.JobFilter{Prefix: "api", Status: "running"}, wantIDs: []string{"api"}, wantTotal: 1, wantPrefix: "api"},
+		{name: "limit", filter: model.JobFilter{Limit: 2}, wantIDs: []string{"api", "api-worker"}, wantTotal: 5},
+		{name: "offset", filter: model.JobFilter{Offset: 3}, wantIDs: []string{"logs", "web"}, wantTotal: 5},
+		{name: "limit and offset", filter: model.JobFilter{Limit: 2, Offset: 1}, wantIDs: []string{"api-worker", "backup"}, wantTotal: 5},
+		{name: "page of a filtered list", filter: model.JobFilter{Type: "service", Limit: 1, Offset: 1}, wantIDs: []string{"api-worker"}, wantTotal: 3},
+		{name: "offset past the end", filter: model.JobFilter{Offset: 10}, wantIDs: []string{}, wantTotal: 5},
+		{name: "no match", filter: model.JobFilter{Status: "pending"}, wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": {region: "eu", jobs: jobs}})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+			ctx := context.Background()
+			if tt.warm {
+				getJobs(t, ctx, svc, "")
+			}
+
+			list, err := svc.GetJobs(ctx, "dc1", tt.filter)
+			if err != nil {
+				t.Fatalf("GetJobs() error = %v", err)
+			}
+
+			ids := make([]string, 0, len(list.Jobs))
+			for _, job := range list.Jobs {
+				ids = append(ids, job.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("jobs = %v, want %v", ids, tt.wantIDs)
+			}
+			if list.Total != tt.wantTotal || list.Limit != tt.filter.Limit || list.Offset != tt.filter.Offset {
+				t.Errorf("total %d, limit %d, offset %d, want %d, %d and %d",
+					list.Total, list.Limit, list.Offset, tt.wantTotal, tt.filter.Limit, tt.filter.Offset)
+			}
+			if prefixes := repo.jobLists; prefixes[len(prefixes)-1] != tt.wantPrefix {
+				t.Errorf("last repository listing used prefix %q, want %q", prefixes[len(prefixes)-1], tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestBulkJobAction(t *testing.T) {
+	errJob := errors.New("job rejected")
+	jobs := []model.Job{
+		{ID: "api", Type: "service", Status: "running"},
+		{ID: "backup", Type: "batch", Status: "dead"},
+		{ID: "web", Type: "service", Status: "running"},
+	}
+
+	tests := []struct {
+		name          string
+		req           model.BulkJobActionRequest
+		jobErrs       map[string]error
+		wantCalls     []string // Job IDs acted on, sorted
+		wantSucceeded int
+		wantFailed    []string // Job IDs whose result carries errors
+	}{
+		{
+			name:          "all succeed",
+			req:           model.BulkJobActionRequest{Action: "start", JobIDs: []string{"api", "web"}},
+			wantCalls:     []string{"api", "web"},
+			wantSucceeded: 2,
+		},
+		{
+			name:          "partial failure continues",
+			req:           model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api", "backup", "web"}},
+			jobErrs:       map[string]error{"backup": errJob},
+			wantCalls:     []string{"api", "backup", "web"},
+			wantSucceeded: 2,
+			wantFailed:    []string{"backup"},
+		},
+		{
+			name:       "every job fails",
+			req:        model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api", "web"}},
+			jobErrs:    map[string]error{"api": errJob, "web": errJob},
+			wantCalls:  []string{"api", "web"},
+			wantFailed: []string{"api", "web"},
+		},
+		{
+			name:          "all jobs matching a filter",
+			req:           model.BulkJobActionRequest{Action: "stop", All: true, Filter: model.JobFilter{Type: "service"}},
+			wantCalls:     []string{"api", "web"},
+			wantSucceeded: 2,
+		},
+		{
+			name:          "all jobs",
+			req:           model.BulkJobActionRequest{Action: "start", All: true},
+			wantCalls:     []string{"api", "backup", "web"},
+			wantSucceeded: 3,
+		},
+		{
+			name: "filter matching nothing",
+			req:  model.BulkJobActionRequest{Action: "start", All: true, Filter: model.JobFilter{Status: "pending"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": {region: "eu", jobs: jobs, jobErrs: tt.jobErrs}})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+
+			result, err := svc.BulkJobAction(context.Background(), "dc1", tt.req)
+			if err != nil {
+				t.Fatalf("BulkJobAction() error = %v", err)
+			}
+
+			var calls []string
+			for _, call := range repo.jobCalls {
+				if call.action != tt.req.Action {
+					t.Errorf("repository call %+v, want action %s", call, tt.req.Action)
+				}
+				calls = append(calls, call.jobID)
+			}
+			slices.Sort(calls)
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("acted on %v, want %v", calls, tt.wantCalls)
+			}
+
+			if result.Action != tt.req.Action || result.Succeeded != tt.wantSucceeded || result.Failed != len(tt.wantFailed) {
+				t.Errorf("action %s, succeeded/failed = %d/%d, want %s, %d/%d",
+					result.Action, result.Succeeded, result.Failed, tt.req.Action, tt.wantSucceeded, len(tt.wantFailed))
+			}
+			if len(result.Results) != len(tt.wantCalls) {
+				t.Fatalf("%d results, want %d", len(result.Results), len(tt.wantCalls))
+			}
+			var failed []string
+			for _, res := range result.Results {
+				if res.Success == (len(res.Errors) > 0) {
+					t.Errorf("%s: success %v with errors %v", res.JobID, res.Success, res.Errors)
+				}
+				if !res.Success {
+					failed = append(failed, res.JobID)
+				}
+			}
+			if !slices.Equal(failed, tt.wantFailed) {
+				t.Errorf("failed jobs = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
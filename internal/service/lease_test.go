@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestHeartbeatLoopRenewsLease(t *testing.T) {
+	tests := []struct {
+		name         string
+		active       string
+		renewErr     error
+		wantRenewals bool
+		wantWrites   bool
+	}{
+		{name: "renewed while active", active: "dc1", wantRenewals: true, wantWrites: true},
+		{name: "lost lease is re-acquired by the write", active: "dc1", renewErr: repository.ErrLeaseLost, wantRenewals: true, wantWrites: true},
+		{name: "failed renewal doesn't stop the heartbeat", active: "dc1", renewErr: errors.New("etcd unavailable"), wantRenewals: true, wantWrites: true},
+		{name: "not renewed while another datacenter is active", active: "dc3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 2, false), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: tt.active, LastHeartbeat: time.Now()})
+			etcd.renewErr = tt.renewErr
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+				heartbeat: config.HeartbeatConfig{UpdateInterval: 5 * time.Millisecond, MaxFailures: 3},
+			})
+
+			svc.StartHeartbeat(context.Background())
+			time.Sleep(50 * time.Millisecond)
+			svc.StopHeartbeat()
+			<-svc.heartbeatDone
+
+			etcd.mu.Lock()
+			renewals, writes := etcd.renewals, etcd.writes
+			etcd.mu.Unlock()
+
+			if (renewals > 0) != tt.wantRenewals {
+				t.Errorf("%d lease renewals, want renewals %v", renewals, tt.wantRenewals)
+			}
+			if (writes > 0) != tt.wantWrites {
+				t.Errorf("%d heartbeat writes, want writes %v", writes, tt.wantWrites)
+			}
+			if tt.wantWrites && len(repo.drained("dc1", true)) != 0 {
+				t.Errorf("drained %v while renewing", repo.drained("dc1", true))
+			}
+		})
+	}
+}
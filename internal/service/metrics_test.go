@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/metrics"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		drainErr    map[string]error
+		wantResult  string
+		wantDrained float64
+	}{
+		{
+			name:        "activation of another region drains mine",
+			target:      "dc3",
+			wantResult:  metrics.ResultSuccess,
+			wantDrained: 2,
+		},
+		{
+			name:        "partial activation",
+			target:      "dc3",
+			drainErr:    map[string]error{"dc1-n2": errTestDrain},
+			wantResult:  metrics.ResultPartial,
+			wantDrained: 1,
+		},
+		{
+			name:       "unknown target",
+			target:     "dc9",
+			wantResult: metrics.ResultError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := activationClusters()
+			clusters["dc1"].drainErr = tt.drainErr
+			repo := newMockNomadRepo(clusters)
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"}), testServiceOptions{})
+			svc.setAmDrained(true)
+
+			activations := metrics.ActivationsTotal.WithLabelValues(tt.target, tt.wantResult)
+			activationsBefore := testutil.ToFloat64(activations)
+			drainedBefore := testutil.ToFloat64(metrics.NodesDrainedTotal)
+
+			_, _ = svc.ActivateDatacenter(context.Background(), tt.target, false, false, nil)
+
+			if got := testutil.ToFloat64(activations) - activationsBefore; got != 1 {
+				t.Errorf("activations{%s,%s} increased by %v, want 1", tt.target, tt.wantResult, got)
+			}
+			if got := testutil.ToFloat64(metrics.NodesDrainedTotal) - drainedBefore; got != tt.wantDrained {
+				t.Errorf("nodes drained increased by %v, want %v", got, tt.wantDrained)
+			}
+		})
+	}
+}
+
+func TestAmDrainedGauge(t *testing.T) {
+	svc, _ := newTestService(t, newMockNomadRepo(nil), newMockEtcdRepo(nil), testServiceOptions{})
+
+	svc.setAmDrained(true)
+	if got := testutil.ToFloat64(metrics.AmDrained); got != 1 {
+		t.Errorf("am_drained = %v after draining, want 1", got)
+	}
+	svc.setAmDrained(false)
+	if got := testutil.ToFloat64(metrics.AmDrained); got != 0 {
+		t.Errorf("am_drained = %v after activating, want 0", got)
+	}
+}
@@ -0,0 +1,714 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/cache"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+// mockCluster is the state of one cluster in mockNomadRepo
+type mockCluster struct {
+	region    string
+	disabled  bool
+	nodes     []model.Node
+	allocs    map[string][]model.Allocation // node ID -> allocations
+	listErr   error                         // returned by ListNodes
+	drainErr  map[string]error              // node ID -> error returned by SetNodeDrain
+	leader    string
+	hasLeader bool
+	leaderErr error
+	health    *model.ClusterHealth
+	jobs      []model.Job
+	jobErr    error            // returned by every job action
+	jobErrs   map[string]error // job ID -> error returned by its job actions
+	details   map[string]*model.JobDetail
+	stopped   map[string]bool // job ID -> stopped, used by RestartJob
+}
+
+// drainCall records a SetNodeDrain call
+type drainCall struct {
+	cluster string
+	nodeID  string
+	drain   bool
+	opts    model.DrainOptions
+}
+
+// jobCall records a job action
+type jobCall struct {
+	action  string
+	cluster string
+	jobID   string
+	purge   bool
+}
+
+// errTestDrain is a node drain failure injected through mockCluster.drainErr
+var errTestDrain = errors.New("drain rejected")
+
+// mockNomadRepo is an in-memory repository.NomadRepository
+type mockNomadRepo struct {
+	mu          sync.Mutex
+	clusters    map[string]*mockCluster
+	drainCalls  []drainCall
+	jobCalls    []jobCall
+	evaluations []string // clusters whose jobs were re-evaluated
+	nodeLists   int      // ListNodes calls
+	jobLists    []string // prefix of every ListJobs call
+
+	// onDrain runs for every SetNodeDrain call while the repository is locked, e.g. to cancel the activation
+	onDrain func(call drainCall)
+
+	// onListAllocations, when set, answers ListNodeAllocations with poll counting the calls per node from 1
+	onListAllocations func(nodeID string, poll int) ([]model.Allocation, error)
+	allocPolls        map[string]int // node ID -> ListNodeAllocations calls
+
+	// drainDelay keeps every SetNodeDrain call in flight, unlocked, so overlapping calls can be counted
+	drainDelay     time.Duration
+	drainsInFlight atomic.Int32
+	maxInFlight    atomic.Int32
+}
+
+func newMockNomadRepo(clusters map[string]*mockCluster) *mockNomadRepo {
+	return &mockNomadRepo{clusters: clusters}
+}
+
+func (m *mockNomadRepo) cluster(name string) (*mockCluster, error) {
+	c, ok := m.clusters[name]
+	if !ok {
+		return nil, repository.ErrClusterNotFound
+	}
+	return c, nil
+}
+
+func (m *mockNomadRepo) ListNodes(_ context.Context, clusterName string) ([]model.Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	m.nodeLists++
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+	return slices.Clone(c.nodes), nil
+}
+
+func (m *mockNomadRepo) ListNodeAllocations(_ context.Context, clusterName, nodeID string) ([]model.Allocation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if m.allocPolls == nil {
+		m.allocPolls = make(map[string]int)
+	}
+	m.allocPolls[nodeID]++
+	if m.onListAllocations != nil {
+		return m.onListAllocations(nodeID, m.allocPolls[nodeID])
+	}
+	return slices.Clone(c.allocs[nodeID]), nil
+}
+
+func (m *mockNomadRepo) SetNodeDrain(_ context.Context, clusterName, nodeID string, drain bool, opts model.DrainOptions) error {
+	if m.drainDelay > 0 {
+		inFlight := m.drainsInFlight.Add(1)
+		defer m.drainsInFlight.Add(-1)
+		for peak := m.maxInFlight.Load(); inFlight > peak && !m.maxInFlight.CompareAndSwap(peak, inFlight); {
+			peak = m.maxInFlight.Load()
+		}
+		time.Sleep(m.drainDelay)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	call := drainCall{cluster: clusterName, nodeID: nodeID, drain: drain, opts: opts}
+	m.drainCalls = append(m.drainCalls, call)
+	if m.onDrain != nil {
+		m.onDrain(call)
+	}
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := c.drainErr[nodeID]; err != nil {
+		return err
+	}
+	for i := range c.nodes {
+		if c.nodes[i].ID == nodeID {
+			c.nodes[i].Drain = drain
+			c.nodes[i].SchedulingEligibility = "eligible"
+			if drain {
+				c.nodes[i].SchedulingEligibility = "ineligible"
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockNomadRepo) CheckLeader(_ context.Context, clusterName string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return "", false, err
+	}
+	return c.leader, c.hasLeader, c.leaderErr
+}
+
+func (m *mockNomadRepo) CheckClusterHealth(_ context.Context, clusterName string) (*model.ClusterHealth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if c.health != nil {
+		return c.health, nil
+	}
+	return &model.ClusterHealth{Datacenter: clusterName, Healthy: c.hasLeader, Reachable: true, HasLeader: c.hasLeader, Leader: c.leader}, nil
+}
+
+func (m *mockNomadRepo) GetClusterNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.clusters))
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (m *mockNomadRepo) GetClusterRegion(clusterName string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return "", err
+	}
+	return c.region, nil
+}
+
+func (m *mockNomadRepo) IsClusterEnabled(clusterName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.clusters[clusterName]
+	return ok && !c.disabled
+}
+
+func (m *mockNomadRepo) GetClustersByRegion(region string) []string {
+	var names []string
+	for _, name := range m.GetClusterNames() {
+		if m.clusters[name].region == region {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (m *mockNomadRepo) GetAllRegions() []string {
+	var regions []string
+	for _, name := range m.GetClusterNames() {
+		if region := m.clusters[name].region; !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	slices.Sort(regions)
+	return regions
+}
+
+func (m *mockNomadRepo) TriggerJobEvaluations(_ context.Context, clusterName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evaluations = append(m.evaluations, clusterName)
+	return nil
+}
+
+func (m *mockNomadRepo) ListJobs(_ context.Context, clusterName, prefix string) ([]model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	m.jobLists = append(m.jobLists, prefix)
+	var jobs []model.Job
+	for _, job := range c.jobs {
+		if strings.HasPrefix(job.ID, prefix) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// jobAction records a job action and returns the job's or the cluster's job error
+func (m *mockNomadRepo) jobAction(action, clusterName, jobID string, purge bool) (*mockCluster, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobCalls = append(m.jobCalls, jobCall{action: action, cluster: clusterName, jobID: jobID, purge: purge})
+	c, err := m.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.jobErrs[jobID]; err != nil {
+		return c, err
+	}
+	return c, c.jobErr
+}
+
+func (m *mockNomadRepo) StartJob(_ context.Context, clusterName, jobID string) error {
+	_, err := m.jobAction("start", clusterName, jobID, false)
+	return err
+}
+
+func (m *mockNomadRepo) StopJob(_ context.Context, clusterName, jobID string, purge bool) error {
+	_, err := m.jobAction("stop", clusterName, jobID, purge)
+	return err
+}
+
+func (m *mockNomadRepo) RestartJob(_ context.Context, clusterName, jobID string) (bool, error) {
+	c, err := m.jobAction("restart", clusterName, jobID, false)
+	if c == nil {
+		return false, err
+	}
+	return c.stopped[jobID], err
+}
+
+func (m *mockNomadRepo) GetJob(_ context.Context, clusterName, jobID string) (*model.JobDetail, error) {
+	c, err := m.jobAction("get", clusterName, jobID, false)
+	if err != nil {
+		return nil, err
+	}
+	detail, ok := c.details[jobID]
+	if !ok {
+		return nil, repository.ErrJobNotFound
+	}
+	return detail, nil
+}
+
+func (m *mockNomadRepo) RetryUnavailableClusters() int { return 0 }
+
+func (m *mockNomadRepo) ReloadClusters(*config.Config) repository.ClusterReloadResult {
+	return repository.ClusterReloadResult{}
+}
+
+// drained returns the drain calls made for cluster with the given drain flag
+func (m *mockNomadRepo) drained(cluster string, drain bool) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var nodes []string
+	for _, call := range m.drainCalls {
+		if call.cluster == cluster && call.drain == drain {
+			nodes = append(nodes, call.nodeID)
+		}
+	}
+	return nodes
+}
+
+// mockEtcdRepo is an in-memory repository.EtcdRepository
+type mockEtcdRepo struct {
+	mu             sync.Mutex
+	active         *model.ActiveDatacenter
+	revision       int64
+	readErr        error
+	readFailures   int // reads failing with readErr before it is cleared, 0 keeps failing
+	reads          int
+	writeErr       error
+	claimErr       error
+	renewErr       error
+	pingErr        error
+	appendErr      error
+	watchErr       error
+	watchUpdates   chan *model.ActiveDatacenter // Updates delivered by WatchActiveDatacenter, nil for none
+	watches        int
+	writes         int
+	claims         int
+	deletes        int
+	renewals       int
+	rotations      int // RotateEndpoints calls
+	leaseDetached  bool
+	unleasedWrites int // Writes and claims made after DetachActiveDatacenterLease
+	events         []model.ActivationEvent
+	healthFailures map[string]*model.HealthFailureCounts
+
+	// beforeTxn runs before a revision-guarded transaction compares revisions, e.g. to simulate
+	// another instance changing the key between a read and the write; it may call store
+	beforeTxn func(m *mockEtcdRepo)
+}
+
+func newMockEtcdRepo(active *model.ActiveDatacenter) *mockEtcdRepo {
+	m := &mockEtcdRepo{healthFailures: make(map[string]*model.HealthFailureCounts)}
+	if active != nil {
+		m.store(active)
+	}
+	return m
+}
+
+// store saves a copy of info as the key's new revision
+func (m *mockEtcdRepo) store(info *model.ActiveDatacenter) {
+	if m.leaseDetached {
+		m.unleasedWrites++
+	}
+	m.revision++
+	stored := *info
+	stored.ActiveDatacenters = slices.Clone(info.ActiveDatacenters)
+	stored.Revision = m.revision
+	m.active = &stored
+}
+
+// current returns a copy of the active datacenter record, nil when the key is missing
+func (m *mockEtcdRepo) current() *model.ActiveDatacenter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active == nil {
+		return nil
+	}
+	info := *m.active
+	return &info
+}
+
+func (m *mockEtcdRepo) WriteActiveDatacenter(_ context.Context, info *model.ActiveDatacenter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.writes++
+	m.store(info)
+	return nil
+}
+
+func (m *mockEtcdRepo) ReadActiveDatacenter(context.Context) (*model.ActiveDatacenter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reads++
+	if m.readErr != nil {
+		err := m.readErr
+		if m.readFailures > 0 {
+			if m.readFailures--; m.readFailures == 0 {
+				m.readErr = nil
+			}
+		}
+		return nil, err
+	}
+	if m.active == nil {
+		return nil, repository.ErrNoActiveDatacenter
+	}
+	info := *m.active
+	return &info, nil
+}
+
+func (m *mockEtcdRepo) TryClaimActiveDatacenter(_ context.Context, info *model.ActiveDatacenter, expectedRevision int64) (bool, *model.ActiveDatacenter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.claimErr != nil {
+		return false, nil, m.claimErr
+	}
+	if m.beforeTxn != nil {
+		m.beforeTxn(m)
+	}
+	current := int64(0)
+	if m.active != nil {
+		current = m.active.Revision
+	}
+	if current != expectedRevision {
+		if m.active == nil {
+			return false, nil, nil
+		}
+		holder := *m.active
+		return false, &holder, nil
+	}
+	m.claims++
+	m.store(info)
+	return true, nil, nil
+}
+
+func (m *mockEtcdRepo) WatchActiveDatacenter(ctx context.Context) (<-chan *model.ActiveDatacenter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.watches++
+	if m.watchErr != nil {
+		return nil, m.watchErr
+	}
+
+	// Forward the updates sent by the test; closing its channel closes the watch
+	ch := make(chan *model.ActiveDatacenter)
+	updates := m.watchUpdates
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case info, ok := <-updates:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- info:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// watchCount returns the number of watches started
+func (m *mockEtcdRepo) watchCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.watches
+}
+
+func (m *mockEtcdRepo) DeleteActiveDatacenter(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.deletes++
+	m.active = nil
+	return nil
+}
+
+func (m *mockEtcdRepo) TryDeleteActiveDatacenter(_ context.Context, expectedRevision int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeErr != nil {
+		return false, m.writeErr
+	}
+	if m.beforeTxn != nil {
+		m.beforeTxn(m)
+	}
+	if m.active == nil || m.active.Revision != expectedRevision {
+		return false, nil
+	}
+	m.deletes++
+	m.active = nil
+	return true, nil
+}
+
+func (m *mockEtcdRepo) RenewActiveDatacenterLease(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.renewals++
+	return m.renewErr
+}
+
+func (m *mockEtcdRepo) DetachActiveDatacenterLease() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leaseDetached = true
+}
+
+func (m *mockEtcdRepo) WriteHeartbeat(context.Context, string) error { return nil }
+
+func (m *mockEtcdRepo) ReadHeartbeat(_ context.Context, datacenter string) (*model.HeartbeatInfo, error) {
+	return &model.HeartbeatInfo{Datacenter: datacenter, LastSeen: time.Now()}, nil
+}
+
+func (m *mockEtcdRepo) Ping(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pingErr
+}
+
+func (m *mockEtcdRepo) Connected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pingErr == nil
+}
+
+func (m *mockEtcdRepo) RotateEndpoints(context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rotations++
+}
+
+func (m *mockEtcdRepo) AppendActivationEvent(_ context.Context, event *model.ActivationEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.appendErr != nil {
+		return m.appendErr
+	}
+	m.events = append([]model.ActivationEvent{*event}, m.events...)
+	return nil
+}
+
+func (m *mockEtcdRepo) ListActivationEvents(_ context.Context, limit int) ([]model.ActivationEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.events[:min(limit, len(m.events))]), nil
+}
+
+func (m *mockEtcdRepo) WriteHealthFailures(_ context.Context, instance string, counts *model.HealthFailureCounts) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.healthFailures[instance] = counts
+	return nil
+}
+
+func (m *mockEtcdRepo) ReadHealthFailures(_ context.Context, instance string) (*model.HealthFailureCounts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.healthFailures[instance], nil
+}
+
+func (m *mockEtcdRepo) Close() error { return nil }
+
+// recordingNotifier collects notification events
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []model.NotificationEvent
+}
+
+func (n *recordingNotifier) Notify(event model.NotificationEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.events = append(n.events, event)
+}
+
+// types returns the types of the recorded events
+func (n *recordingNotifier) types() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	types := make([]string, 0, len(n.events))
+	for _, event := range n.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// testServiceOptions overrides the defaults of newTestService
+type testServiceOptions struct {
+	myDatacenter string
+	preferredDC  string
+	activeMode   string
+	heartbeat    config.HeartbeatConfig
+	startup      config.StartupConfig
+	drain        config.DrainConfig
+	activation   config.ActivationConfig
+	safety       config.SafetyConfig
+	readOnly     bool
+	maxNodeOps   int           // Maximum concurrent node operations, 4 when unset
+	nodesTTL     time.Duration // Node list cache TTL, a minute when unset
+	jobsTTL      time.Duration // Job list cache TTL, a minute when unset
+}
+
+// newTestService returns a service over the mocks with "dc1" as my datacenter
+func newTestService(t *testing.T, repo *mockNomadRepo, etcd *mockEtcdRepo, opts testServiceOptions) (*datacenterService, *recordingNotifier) {
+	t.Helper()
+
+	if opts.myDatacenter == "" {
+		opts.myDatacenter = "dc1"
+	}
+	if opts.activeMode == "" {
+		opts.activeMode = config.ActiveModeSingle
+	}
+	if opts.heartbeat.StaleThreshold == 0 {
+		opts.heartbeat.StaleThreshold = time.Minute
+	}
+	if opts.maxNodeOps == 0 {
+		opts.maxNodeOps = 4
+	}
+	if opts.heartbeat.UpdateInterval == 0 {
+		opts.heartbeat.UpdateInterval = time.Second
+	}
+	if opts.nodesTTL == 0 {
+		opts.nodesTTL = time.Minute
+	}
+	if opts.jobsTTL == 0 {
+		opts.jobsTTL = time.Minute
+	}
+
+	notifier := &recordingNotifier{}
+	svc := NewDatacenterService(
+		repo,
+		etcd,
+		cache.New(time.Minute, time.Minute),
+		Config{
+			MyDatacenter:         opts.myDatacenter,
+			PreferredDatacenter:  opts.preferredDC,
+			ActiveMode:           opts.activeMode,
+			NodesTTL:             opts.nodesTTL,
+			JobsTTL:              opts.jobsTTL,
+			Heartbeat:            opts.heartbeat,
+			Startup:              opts.startup,
+			MaxConcurrentNodeOps: opts.maxNodeOps,
+			Drain:                opts.drain,
+			Activation:           opts.activation,
+			Safety:               opts.safety,
+			DegradedJobRatio:     0.5,
+			ReadOnly:             opts.readOnly,
+		},
+		notifier,
+		slog.New(slog.DiscardHandler),
+	)
+	return svc.(*datacenterService), notifier
+}
+
+// testNodes returns count eligible, ready nodes of datacenter with IDs <datacenter>-n<i>
+func testNodes(datacenter string, count int, drained bool) []model.Node {
+	nodes := make([]model.Node, 0, count)
+	for i := range count {
+		node := model.Node{
+			ID:                    datacenter + "-n" + string(rune('1'+i)),
+			Name:                  datacenter + "-node",
+			Status:                "ready",
+			Datacenter:            datacenter,
+			SchedulingEligibility: "eligible",
+		}
+		if drained {
+			node.Drain = true
+			node.SchedulingEligibility = "ineligible"
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestSetNodeDrain(t *testing.T) {
+	tests := []struct {
+		name         string
+		dc           string
+		nodeID       string
+		drain        bool
+		startDrained bool
+		drainErr     error
+		readOnly     bool
+		wantErr      error
+		wantCalls    int
+	}{
+		{name: "drain", dc: "dc1", nodeID: "dc1-n1", drain: true, wantCalls: 1},
+		{name: "undrain", dc: "dc1", nodeID: "dc1-n1", startDrained: true, wantCalls: 1},
+		{name: "unknown node", dc: "dc1", nodeID: "dc1-n9", drain: true, wantErr: ErrNodeNotFound},
+		{name: "unknown datacenter", dc: "dc9", nodeID: "dc1-n1", drain: true, wantErr: repository.ErrClusterNotFound},
+		{name: "read-only", dc: "dc1", nodeID: "dc1-n1", drain: true, readOnly: true, wantErr: ErrReadOnly},
+		{name: "drain failure", dc: "dc1", nodeID: "dc1-n1", drain: true, drainErr: errTestDrain, wantErr: errTestDrain, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &mockCluster{region: "eu", nodes: testNodes("dc1", 2, tt.startDrained), hasLeader: true}
+			if tt.drainErr != nil {
+				cluster.drainErr = map[string]error{tt.nodeID: tt.drainErr}
+			}
+			repo := newMockNomadRepo(map[string]*mockCluster{"dc1": cluster})
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{readOnly: tt.readOnly})
+
+			// Cache the node list before the change
+			if _, err := svc.GetNodes(context.Background(), "dc1"); err != nil {
+				t.Fatalf("GetNodes() error = %v", err)
+			}
+
+			node, err := svc.SetNodeDrain(context.Background(), tt.dc, tt.nodeID, tt.drain, nil)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetNodeDrain() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.drainCalls) != tt.wantCalls {
+				t.Errorf("%d drain calls, want %d", len(repo.drainCalls), tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if node.ID != tt.nodeID || node.Drain != tt.drain {
+				t.Errorf("node = %s drain %v, want %s drain %v", node.ID, node.Drain, tt.nodeID, tt.drain)
+			}
+			// The cached list was invalidated, so the change is visible without ?fresh
+			nodes, err := svc.GetNodes(context.Background(), "dc1")
+			if err != nil {
+				t.Fatalf("GetNodes() error = %v", err)
+			}
+			if cached := findNode(nodes, tt.nodeID); cached == nil || cached.Drain != tt.drain {
+				t.Errorf("cached node = %+v, want drain %v", cached, tt.drain)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestGetNodesWithAllocations(t *testing.T) {
+	allocs := map[string][]model.Allocation{
+		"dc1-n1": {{ID: "a1", ClientStatus: "running"}, {ID: "a2", ClientStatus: "pending"}, {ID: "a3", ClientStatus: "complete"}},
+		"dc1-n2": {{ID: "a4", ClientStatus: "failed"}},
+	}
+
+	tests := []struct {
+		name       string
+		withAllocs bool
+		listErr    map[string]error // node ID -> ListNodeAllocations error
+		want       map[string]int   // node ID -> alloc count, missing when unset
+		wantPolls  int
+	}{
+		{name: "not requested", want: map[string]int{}},
+		{name: "requested", withAllocs: true, want: map[string]int{"dc1-n1": 2, "dc1-n2": 0, "dc1-n3": 0}, wantPolls: 3},
+		{
+			name:       "node whose allocations can't be listed",
+			withAllocs: true,
+			listErr:    map[string]error{"dc1-n2": errors.New("node unreachable")},
+			want:       map[string]int{"dc1-n1": 2, "dc1-n3": 0},
+			wantPolls:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 3, false), allocs: allocs},
+			})
+			repo.onListAllocations = func(nodeID string, _ int) ([]model.Allocation, error) {
+				return allocs[nodeID], tt.listErr[nodeID]
+			}
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+			ctx := context.Background()
+
+			getNodes := svc.GetNodes
+			if tt.withAllocs {
+				getNodes = svc.GetNodesWithAllocations
+			}
+			nodes, err := getNodes(ctx, "dc1")
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, node := range nodes {
+				if node.AllocCount != nil {
+					got[node.ID] = *node.AllocCount
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("alloc counts = %v, want %v", got, tt.want)
+			}
+			for id, count := range tt.want {
+				if c, ok := got[id]; !ok || c != count {
+					t.Errorf("node %s alloc count = %v, want %d", id, got[id], count)
+				}
+			}
+
+			polls := 0
+			for _, n := range repo.allocPolls {
+				polls += n
+			}
+			if polls != tt.wantPolls {
+				t.Errorf("%d allocation listings, want %d", polls, tt.wantPolls)
+			}
+
+			// Counts must not leak into the cached node list
+			cached, err := svc.GetNodes(ctx, "dc1")
+			if err != nil {
+				t.Fatalf("GetNodes() error = %v", err)
+			}
+			for _, node := range cached {
+				if node.AllocCount != nil {
+					t.Errorf("cached node %s has alloc count %d", node.ID, *node.AllocCount)
+				}
+			}
+		})
+	}
+}
+
+func TestListAllNodes(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false)},
+		"dc2": {region: "eu", nodes: testNodes("dc2", 1, true)},
+		"dc3": {region: "us", listErr: errors.New("nomad unavailable")},
+	})
+	svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+	ctx := context.Background()
+
+	all, err := svc.ListAllNodes(ctx)
+	if err != nil {
+		t.Fatalf("ListAllNodes() error = %v", err)
+	}
+
+	if all.Total != 3 || len(all.Datacenters) != 3 {
+		t.Fatalf("total = %d with %d datacenters, want 3 with 3", all.Total, len(all.Datacenters))
+	}
+	want := []struct {
+		datacenter, region string
+		nodes              int
+		failed             bool
+	}{
+		{datacenter: "dc1", region: "eu", nodes: 2},
+		{datacenter: "dc2", region: "eu", nodes: 1},
+		{datacenter: "dc3", region: "us", failed: true},
+	}
+	for i, w := range want {
+		group := all.Datacenters[i]
+		if group.Datacenter != w.datacenter || group.Region != w.region || len(group.Nodes) != w.nodes || (group.Error != "") != w.failed {
+			t.Errorf("group %d = %+v, want %s in %s with %d nodes, failed %v", i, group, w.datacenter, w.region, w.nodes, w.failed)
+		}
+		if group.Nodes == nil {
+			t.Errorf("%s nodes are nil, want an empty list", group.Datacenter)
+		}
+		for _, node := range group.Nodes {
+			if node.Datacenter != w.datacenter || node.Region != w.region {
+				t.Errorf("node %s in %s/%s, want %s/%s", node.ID, node.Datacenter, node.Region, w.datacenter, w.region)
+			}
+		}
+	}
+
+	// The region is only added to the listing, not to the cached nodes
+	nodes, err := svc.GetNodes(ctx, "dc1")
+	if err != nil {
+		t.Fatalf("GetNodes() error = %v", err)
+	}
+	if nodes[0].Region != "" {
+		t.Errorf("cached node region = %q, want it unset", nodes[0].Region)
+	}
+
+	// Listings that could be cached come from the cache, fresh reads go to Nomad
+	repo.mu.Lock()
+	lists := repo.nodeLists
+	repo.mu.Unlock()
+	if _, err := svc.ListAllNodes(ctx); err != nil {
+		t.Fatalf("ListAllNodes() error = %v", err)
+	}
+	repo.mu.Lock()
+	if cached := repo.nodeLists - lists; cached != 1 {
+		t.Errorf("cached listing listed nodes %d times, want 1 for the failed datacenter", cached)
+	}
+	lists = repo.nodeLists
+	repo.mu.Unlock()
+	if _, err := svc.ListAllNodes(WithFresh(ctx)); err != nil {
+		t.Fatalf("ListAllNodes() error = %v", err)
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if fresh := repo.nodeLists - lists; fresh != 3 {
+		t.Errorf("fresh listing listed nodes %d times, want 3", fresh)
+	}
+}
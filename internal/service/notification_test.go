@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationNotifications(t *testing.T) {
+	tests := []struct {
+		name     string
+		activate func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error)
+		drainErr map[string]error // Injected into dc1
+		want     []model.NotificationEvent
+	}{
+		{
+			name: "datacenter",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			},
+			want: []model.NotificationEvent{{Type: model.NotificationActivation, Region: "us", Datacenter: "dc3", Reason: "datacenter activated via API"}},
+		},
+		{
+			name: "region",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateRegion(ctx, "us", false, nil)
+			},
+			want: []model.NotificationEvent{{Type: model.NotificationActivation, Region: "us", Reason: "region activated via API"}},
+		},
+		{
+			name: "partial activation counts errors",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			},
+			drainErr: map[string]error{"dc1-n1": errTestDrain},
+			want:     []model.NotificationEvent{{Type: model.NotificationActivation, Region: "us", Datacenter: "dc3", Reason: "datacenter activated via API", ErrorCount: 1}},
+		},
+		{
+			name: "dry run isn't notified",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc3", true, false, nil)
+			},
+		},
+		{
+			name: "failed activation isn't notified",
+			activate: func(ctx context.Context, s *datacenterService) (*model.ActivationResult, error) {
+				return s.ActivateDatacenter(ctx, "dc9", false, false, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			clusters := activationClusters()
+			clusters["dc1"].drainErr = tt.drainErr
+			svc, notifier := newTestService(t, newMockNomadRepo(clusters), etcd, testServiceOptions{})
+
+			_, _ = tt.activate(context.Background(), svc)
+
+			notifier.mu.Lock()
+			defer notifier.mu.Unlock()
+			if len(notifier.events) != len(tt.want) {
+				t.Fatalf("notifications = %+v, want %+v", notifier.events, tt.want)
+			}
+			for i, got := range notifier.events {
+				if got != tt.want[i] {
+					t.Errorf("notification = %+v, want %+v", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// splitBrainClusters returns dc1 in eu, dc3 in us and dc4 in ap, all serving
+func splitBrainClusters() map[string]*mockCluster {
+	return map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 1, false), hasLeader: true},
+		"dc3": {region: "us", nodes: testNodes("dc3", 1, false), hasLeader: true},
+		"dc4": {region: "ap", nodes: testNodes("dc4", 1, false), hasLeader: true},
+	}
+}
+
+func TestChooseRegionToKeep(t *testing.T) {
+	tests := []struct {
+		name        string
+		active      *model.ActiveDatacenter // Record in etcd
+		myDC        string
+		preferredDC string
+		wantRegion  string
+		wantReason  string
+	}{
+		{
+			name:        "etcd record wins over the preferred datacenter",
+			active:      &model.ActiveDatacenter{Datacenter: "dc4", Region: "ap"},
+			preferredDC: "dc3",
+			wantRegion:  "ap",
+			wantReason:  "etcd_active_datacenter",
+		},
+		{
+			name:        "preferred datacenter wins over mine",
+			myDC:        "dc1",
+			preferredDC: "dc3",
+			wantRegion:  "us",
+			wantReason:  "preferred_datacenter",
+		},
+		{
+			name:        "preferred datacenter outside the active regions",
+			myDC:        "dc1",
+			preferredDC: "dc9",
+			wantRegion:  "eu",
+			wantReason:  "my_datacenter",
+		},
+		{
+			name:        "etcd record of an inactive region falls through",
+			active:      &model.ActiveDatacenter{Datacenter: "dc9", Region: "sa"},
+			myDC:        "dc4",
+			preferredDC: "dc3",
+			wantRegion:  "us",
+			wantReason:  "preferred_datacenter",
+		},
+		{
+			name:       "no preference",
+			myDC:       "dc9",
+			wantRegion: "ap",
+			wantReason: "alphabetical",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(splitBrainClusters())
+			etcd := newMockEtcdRepo(tt.active)
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{myDatacenter: tt.myDC, preferredDC: tt.preferredDC})
+
+			region, reason := svc.chooseRegionToKeep(context.Background(), []string{"ap", "eu", "us"})
+			if region != tt.wantRegion || reason != tt.wantReason {
+				t.Errorf("chooseRegionToKeep() = %q (%s), want %q (%s)", region, reason, tt.wantRegion, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEnsureSingleActiveKeepsPreferredRegion(t *testing.T) {
+	repo := newMockNomadRepo(splitBrainClusters())
+	etcd := newMockEtcdRepo(nil)
+	svc, _ := newTestService(t, repo, etcd, testServiceOptions{myDatacenter: "dc1", preferredDC: "dc3"})
+
+	if err := svc.EnsureSingleActiveDatacenter(context.Background()); err != nil {
+		t.Fatalf("EnsureSingleActiveDatacenter() error = %v", err)
+	}
+
+	var drained []string
+	for _, call := range repo.drainCalls {
+		if !call.drain {
+			t.Errorf("unexpected undrain of %s", call.nodeID)
+			continue
+		}
+		drained = append(drained, call.cluster)
+	}
+	slices.Sort(drained)
+	if want := []string{"dc1", "dc4"}; !slices.Equal(drained, want) {
+		t.Errorf("drained %v, want %v with the preferred datacenter's region us kept", drained, want)
+	}
+}
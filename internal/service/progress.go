@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// progressKey is the context key of the activation progress channel
+type progressKey struct{}
+
+// WithProgress returns a context under which activations send an event to ch after every node change.
+// Sending gives up once ctx is done, so the consumer may stop reading after cancelling it.
+func WithProgress(ctx context.Context, ch chan<- model.ActivationProgress) context.Context {
+	return context.WithValue(ctx, progressKey{}, ch)
+}
+
+// reportProgress sends event to the progress channel of ctx, if there is one
+func reportProgress(ctx context.Context, event model.ActivationProgress) {
+	ch, ok := ctx.Value(progressKey{}).(chan<- model.ActivationProgress)
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- event:
+	case <-ctx.Done():
+	}
+}
+
+// nodeProgress builds the progress event of a finished node change
+func nodeProgress(clusterName string, node model.Node, drain bool, completed, total int, err error) model.ActivationProgress {
+	event := model.ActivationProgress{
+		Cluster:   clusterName,
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		Drain:     drain,
+		Completed: completed,
+		Total:     total,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestActivationReportsProgress(t *testing.T) {
+	repo := newMockNomadRepo(activationClusters())
+	etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+	svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+
+	progress := make(chan model.ActivationProgress)
+	var events []model.ActivationProgress
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for event := range progress {
+			events = append(events, event)
+		}
+	}()
+
+	result, err := svc.ActivateDatacenter(WithProgress(context.Background(), progress), "dc3", false, false, nil)
+	close(progress)
+	<-collected
+	if err != nil {
+		t.Fatalf("ActivateDatacenter() error = %v", err)
+	}
+
+	if want := result.DrainedNodes + result.UnDrainedNodes; len(events) != want {
+		t.Fatalf("got %d progress events, want one per node change (%d): %+v", len(events), want, events)
+	}
+	last := make(map[string]int) // cluster -> last completed count
+	for _, event := range events {
+		wantDrain := event.Cluster == "dc1"
+		if event.Drain != wantDrain || event.Error != "" {
+			t.Errorf("event %+v, want drain %v without error", event, wantDrain)
+		}
+		if event.Total != 2 || event.Completed != last[event.Cluster]+1 {
+			t.Errorf("event %+v after %d completed, want the next of 2", event, last[event.Cluster])
+		}
+		last[event.Cluster] = event.Completed
+	}
+}
+
+func TestReportProgressWithoutChannel(t *testing.T) {
+	// Activations without a progress channel must not block
+	reportProgress(context.Background(), model.ActivationProgress{})
+}
+
+func TestReportProgressGivesUpWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nobody reads the channel; the cancelled context must release the sender
+	reportProgress(WithProgress(ctx, make(chan model.ActivationProgress)), model.ActivationProgress{})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(ctx context.Context, s *datacenterService) error
+	}{
+		{
+			name: "activate datacenter",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateDatacenter(ctx, "dc3", false, false, nil)
+				return err
+			},
+		},
+		{
+			name: "activate region",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.ActivateRegion(ctx, "us", false, nil)
+				return err
+			},
+		},
+		{
+			name: "drain node",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.SetNodeDrain(ctx, "dc1", "dc1-n1", true, nil)
+				return err
+			},
+		},
+		{
+			name: "start job",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.StartJob(ctx, "dc1", "api")
+				return err
+			},
+		},
+		{
+			name: "stop job",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.StopJob(ctx, "dc1", "api", false)
+				return err
+			},
+		},
+		{
+			name: "bulk job action",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.BulkJobAction(ctx, "dc1", model.BulkJobActionRequest{Action: "stop", JobIDs: []string{"api"}})
+				return err
+			},
+		},
+		{
+			name: "automatic region drain",
+			mutate: func(ctx context.Context, s *datacenterService) error {
+				_, err := s.DrainAllNodesInRegion(ctx, "eu")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := activationClusters()
+			clusters["dc1"].jobs = []model.Job{{ID: "api", Status: "running"}}
+			repo := newMockNomadRepo(clusters)
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{readOnly: true})
+
+			if err := tt.mutate(context.Background(), svc); !errors.Is(err, ErrReadOnly) {
+				t.Fatalf("error = %v, want ErrReadOnly", err)
+			}
+
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			if len(repo.drainCalls) != 0 || len(repo.jobCalls) != 0 {
+				t.Errorf("drain calls %+v and job calls %+v, want none", repo.drainCalls, repo.jobCalls)
+			}
+			if etcd.writes != 0 {
+				t.Errorf("%d etcd writes, want none", etcd.writes)
+			}
+		})
+	}
+}
+
+func TestReadOnlyModeSuppressesDrainOfMyNodes(t *testing.T) {
+	repo := newMockNomadRepo(activationClusters())
+	svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{readOnly: true})
+
+	drained, err := svc.drainMyNodes(context.Background())
+	if err != nil || drained {
+		t.Fatalf("drainMyNodes() = %v, %v, want false without error", drained, err)
+	}
+	if calls := repo.drained("dc1", true); len(calls) != 0 {
+		t.Errorf("drained %v in read-only mode", calls)
+	}
+}
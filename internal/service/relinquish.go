@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+// RelinquishActive removes my datacenter from the active datacenter key on graceful shutdown,
+// so an instance restarting in its place finds no fresh heartbeat and can claim it again.
+// Nodes are left as they are. The key is deleted when no other datacenter stays active.
+// It waits for the heartbeat loop, stopped by StopHeartbeat, so no heartbeat rewrites the key.
+// Like a deactivation, the change is guarded by the revision read, and the record kept for the
+// other datacenters is written without this instance's lease, which expires once it exits.
+func (s *datacenterService) RelinquishActive(ctx context.Context) error {
+	if s.readOnly {
+		return nil
+	}
+
+	select {
+	case <-s.heartbeatDone:
+	case <-ctx.Done():
+		return fmt.Errorf("heartbeat updater did not stop: %w", ctx.Err())
+	}
+
+	activeInfo, err := s.etcdRepo.ReadActiveDatacenter(ctx)
+	if errors.Is(err, repository.ErrNoActiveDatacenter) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read active datacenter: %w", err)
+	}
+	if !activeInfo.IsActive(s.myDatacenter) {
+		s.logger.Info("my datacenter is not active, nothing to relinquish",
+			slog.String("active_dc", activeInfo.Datacenter))
+		return nil
+	}
+
+	s.etcdRepo.DetachActiveDatacenterLease()
+
+	cleared, err := s.relinquishDeactivated(ctx, []string{s.myDatacenter})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("relinquished active datacenter on shutdown",
+		slog.String("datacenter", s.myDatacenter),
+		slog.Bool("key_deleted", cleared))
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestRelinquishActive(t *testing.T) {
+	tests := []struct {
+		name         string
+		active       *model.ActiveDatacenter
+		readOnly     bool
+		changeBefore *model.ActiveDatacenter // Written by another instance between the read and the write
+		want         []string                // Active datacenters at the end, nil when the key is gone
+		wantDetached bool
+	}{
+		{
+			name:         "sole active datacenter deletes the key",
+			active:       &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"},
+			wantDetached: true,
+		},
+		{
+			name:         "region-wide record keeps the other datacenters",
+			active:       &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu", ActiveDatacenters: []string{"dc1", "dc2"}},
+			want:         []string{"dc2"},
+			wantDetached: true,
+		},
+		{
+			name:   "another active datacenter is left alone",
+			active: &model.ActiveDatacenter{Datacenter: "dc3", Region: "us"},
+			want:   []string{"dc3"},
+		},
+		{
+			name: "missing key is a no-op",
+		},
+		{
+			name:     "read-only mode leaves the key",
+			active:   &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"},
+			readOnly: true,
+			want:     []string{"dc1"},
+		},
+		{
+			name:         "concurrent activation is not overwritten",
+			active:       &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu", ActiveDatacenters: []string{"dc1", "dc2"}},
+			changeBefore: &model.ActiveDatacenter{Datacenter: "dc3", Region: "us"},
+			want:         []string{"dc3"},
+			wantDetached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(tt.active)
+			if tt.changeBefore != nil {
+				etcd.beforeTxn = func(m *mockEtcdRepo) {
+					m.store(tt.changeBefore)
+					m.beforeTxn = nil
+				}
+			}
+			svc, _ := newTestService(t, newMockNomadRepo(nil), etcd, testServiceOptions{readOnly: tt.readOnly})
+			close(svc.heartbeatDone) // StopHeartbeat has returned
+
+			if err := svc.RelinquishActive(context.Background()); err != nil {
+				t.Fatalf("RelinquishActive() error = %v", err)
+			}
+
+			active := etcd.current()
+			var got []string
+			if active != nil {
+				got = active.Datacenters()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("active datacenters = %v, want %v", got, tt.want)
+			}
+			if etcd.leaseDetached != tt.wantDetached {
+				t.Errorf("lease detached = %v, want %v", etcd.leaseDetached, tt.wantDetached)
+			}
+			if tt.changeBefore == nil && len(tt.want) > 0 && tt.wantDetached && etcd.unleasedWrites != 1 {
+				t.Errorf("%d writes without the lease, want the remaining record written without it", etcd.unleasedWrites)
+			}
+		})
+	}
+}
+
+func TestRelinquishActiveWaitsForHeartbeat(t *testing.T) {
+	etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+	svc, _ := newTestService(t, newMockNomadRepo(nil), etcd, testServiceOptions{})
+
+	// The heartbeat loop never stopped
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := svc.RelinquishActive(ctx); err == nil {
+		t.Fatal("RelinquishActive() error = nil, want the heartbeat wait to time out")
+	}
+	if etcd.current() == nil {
+		t.Error("key deleted while the heartbeat loop may still write it")
+	}
+}
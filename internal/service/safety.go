@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// ErrTooManyNodesAffected matches a TooManyNodesAffectedError with errors.Is
+var ErrTooManyNodesAffected = errors.New("activation would change too many nodes")
+
+// TooManyNodesAffectedError is returned when an unconfirmed activation would change more nodes
+// than safety.max_nodes_affected allows
+type TooManyNodesAffectedError struct {
+	Affected int // Nodes the activation would drain or undrain
+	Max      int // Configured cap
+}
+
+func (e *TooManyNodesAffectedError) Error() string {
+	return fmt.Sprintf("activation would change %d nodes, more than the allowed %d; confirm to proceed", e.Affected, e.Max)
+}
+
+// Is makes errors.Is(err, ErrTooManyNodesAffected) match
+func (e *TooManyNodesAffectedError) Is(target error) bool {
+	return target == ErrTooManyNodesAffected
+}
+
+// confirmedKey is the context key marking an activation as confirmed
+type confirmedKey struct{}
+
+// WithConfirmed returns a context under which activations may change more nodes than safety.max_nodes_affected
+func WithConfirmed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, confirmedKey{}, true)
+}
+
+// isConfirmed reports whether ctx was marked with WithConfirmed
+func isConfirmed(ctx context.Context) bool {
+	confirmed, _ := ctx.Value(confirmedKey{}).(bool)
+	return confirmed
+}
+
+// nodeNeedsChange reports whether node must be drained or undrained to match drain
+func nodeNeedsChange(node model.Node, drain bool) bool {
+	return node.Drain != drain || (node.SchedulingEligibility == "eligible") == drain
+}
+
+// checkNodesAffected counts the node changes an activation is about to apply, before anything is mutated,
+// and refuses the activation when the count exceeds the configured cap and ctx isn't confirmed.
+// drain reports whether the nodes of a cluster are drained by the activation.
+func (s *datacenterService) checkNodesAffected(ctx context.Context, clusters []concurrent.Result[clusterNodesInfo], drain func(info clusterNodesInfo) bool) error {
+	if s.maxNodesAffected <= 0 {
+		return nil
+	}
+
+	affected := 0
+	for _, cluster := range clusters {
+		info := cluster.Value
+		if info.err != nil {
+			continue
+		}
+		shouldDrain := drain(info)
+		for _, node := range info.nodes {
+			if nodeNeedsChange(node, shouldDrain) {
+				affected++
+			}
+		}
+	}
+
+	if affected <= s.maxNodesAffected {
+		return nil
+	}
+	if isConfirmed(ctx) {
+		s.logger.Warn("confirmed activation exceeds the node change cap",
+			slog.Int("affected_nodes", affected),
+			slog.Int("max_nodes_affected", s.maxNodesAffected),
+		)
+		return nil
+	}
+
+	return &TooManyNodesAffectedError{Affected: affected, Max: s.maxNodesAffected}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestMaxNodesAffected(t *testing.T) {
+	// Both activations drain dc1's two nodes and undrain dc3's two nodes
+	activations := map[string]func(ctx context.Context, s *datacenterService, dryRun bool) (*model.ActivationResult, error){
+		"datacenter": func(ctx context.Context, s *datacenterService, dryRun bool) (*model.ActivationResult, error) {
+			return s.ActivateDatacenter(ctx, "dc3", dryRun, false, nil)
+		},
+		"region": func(ctx context.Context, s *datacenterService, dryRun bool) (*model.ActivationResult, error) {
+			return s.ActivateRegion(ctx, "us", dryRun, nil)
+		},
+	}
+
+	tests := []struct {
+		name      string
+		max       int
+		confirmed bool
+		dryRun    bool
+		wantErr   bool
+	}{
+		{name: "no cap", max: 0},
+		{name: "under the cap", max: 5},
+		{name: "at the cap", max: 4},
+		{name: "over the cap", max: 3, wantErr: true},
+		{name: "over the cap confirmed", max: 3, confirmed: true},
+		{name: "over the cap dry run", max: 3, dryRun: true},
+	}
+
+	for activation, activate := range activations {
+		for _, tt := range tests {
+			t.Run(activation+"/"+tt.name, func(t *testing.T) {
+				repo := newMockNomadRepo(activationClusters())
+				etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+				svc, _ := newTestService(t, repo, etcd, testServiceOptions{safety: config.SafetyConfig{MaxNodesAffected: tt.max}})
+
+				ctx := context.Background()
+				if tt.confirmed {
+					ctx = WithConfirmed(ctx)
+				}
+				result, err := activate(ctx, svc, tt.dryRun)
+
+				if !tt.wantErr {
+					if err != nil {
+						t.Fatalf("activation error = %v", err)
+					}
+					if result.DrainedNodes != 2 || result.UnDrainedNodes != 2 {
+						t.Errorf("drained/undrained = %d/%d, want 2/2", result.DrainedNodes, result.UnDrainedNodes)
+					}
+					return
+				}
+
+				var tooMany *TooManyNodesAffectedError
+				if !errors.As(err, &tooMany) || !errors.Is(err, ErrTooManyNodesAffected) {
+					t.Fatalf("activation error = %v, want TooManyNodesAffectedError", err)
+				}
+				if tooMany.Affected != 4 || tooMany.Max != tt.max {
+					t.Errorf("error reports %d of %d nodes, want 4 of %d", tooMany.Affected, tooMany.Max, tt.max)
+				}
+				if len(repo.drainCalls) != 0 || etcd.writes != 0 {
+					t.Errorf("refused activation changed state: drains %v, %d etcd writes", repo.drainCalls, etcd.writes)
+				}
+			})
+		}
+	}
+}
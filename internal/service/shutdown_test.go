@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestShutdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		running     bool          // An activation holds the slot when Shutdown is called
+		finishAfter time.Duration // When the running activation finishes, never when zero
+		timeout     time.Duration
+		wantErr     error
+	}{
+		{name: "no activation running", timeout: time.Second},
+		{name: "waits for the running activation", running: true, finishAfter: 50 * time.Millisecond, timeout: 5 * time.Second},
+		{name: "gives up at the deadline", running: true, timeout: 50 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(activationClusters())
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{})
+
+			finished := make(chan struct{})
+			if tt.running {
+				release, err := svc.acquireActivation("dc3")
+				if err != nil {
+					t.Fatalf("acquireActivation() error = %v", err)
+				}
+				if tt.finishAfter > 0 {
+					go func() {
+						time.Sleep(tt.finishAfter)
+						close(finished)
+						release()
+					}()
+				} else {
+					t.Cleanup(release)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			err := svc.Shutdown(ctx)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Shutdown() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.finishAfter > 0 {
+				select {
+				case <-finished:
+				default:
+					t.Error("Shutdown() returned before the activation finished")
+				}
+			}
+
+			// Whatever happened to the running activation, no new one may start
+			if _, err := svc.ActivateDatacenter(context.Background(), "dc2", false, false, nil); !errors.Is(err, ErrShuttingDown) {
+				t.Errorf("activation after Shutdown() error = %v, want %v", err, ErrShuttingDown)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// claimAsSoleInstance claims the active datacenter key for my datacenter when no instance holds it
+// and my nodes are still serving, so a lone instance keeps serving instead of draining itself.
+// The claim only succeeds while the key doesn't exist, so instances starting at the same time
+// can't all stay active. It returns whether the key was claimed.
+func (s *datacenterService) claimAsSoleInstance(ctx context.Context) (bool, error) {
+	if s.readOnly {
+		return false, nil
+	}
+
+	allDrained, err := s.checkNodesAreDrained(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check node states: %w", err)
+	}
+	if allDrained {
+		s.logger.Info("my nodes are drained, nothing to keep active as sole instance")
+		return false, nil
+	}
+
+	region, _ := s.repo.GetClusterRegion(s.myDatacenter)
+	claim := s.newActiveRecord(s.myDatacenter, region, "startup", false)
+	claimed, holder, err := s.etcdRepo.TryClaimActiveDatacenter(ctx, claim, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim active datacenter: %w", err)
+	}
+	if !claimed {
+		holderDC := ""
+		if holder != nil {
+			holderDC = holder.Datacenter
+		}
+		s.logger.Warn("another instance claimed the active datacenter first",
+			"active_dc", holderDC)
+		return false, nil
+	}
+
+	s.invalidateActiveSummary()
+	s.logger.Warn("no instance is active, claimed the active datacenter as sole instance",
+		"datacenter", s.myDatacenter)
+	return true, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestClaimAsSoleInstance(t *testing.T) {
+	tests := []struct {
+		name        string
+		active      *model.ActiveDatacenter
+		drained     bool
+		readOnly    bool
+		wantClaimed bool
+		wantActive  string
+	}{
+		{name: "no active datacenter", wantClaimed: true, wantActive: "dc1"},
+		{name: "another instance holds the key", active: &model.ActiveDatacenter{Datacenter: "dc3"}, wantActive: "dc3"},
+		{name: "my nodes are drained", drained: true},
+		{name: "read-only", readOnly: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, tt.drained), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(tt.active)
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{readOnly: tt.readOnly})
+
+			claimed, err := svc.claimAsSoleInstance(context.Background())
+			if err != nil {
+				t.Fatalf("claimAsSoleInstance() error = %v", err)
+			}
+			if claimed != tt.wantClaimed {
+				t.Errorf("claimed = %v, want %v", claimed, tt.wantClaimed)
+			}
+
+			active := ""
+			if current := etcd.current(); current != nil {
+				active = current.Datacenter
+			}
+			if active != tt.wantActive {
+				t.Errorf("active datacenter = %q, want %q", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestClaimAsSoleInstanceConcurrent(t *testing.T) {
+	// Instances of three datacenters start at the same time against one etcd
+	clusters := map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+		"dc2": {region: "eu", nodes: testNodes("dc2", 2, false), hasLeader: true},
+		"dc3": {region: "us", nodes: testNodes("dc3", 2, false), hasLeader: true},
+	}
+	etcd := newMockEtcdRepo(nil)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners []string
+	)
+	for _, dc := range []string{"dc1", "dc2", "dc3"} {
+		svc, _ := newTestService(t, newMockNomadRepo(clusters), etcd, testServiceOptions{myDatacenter: dc})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			claimed, err := svc.claimAsSoleInstance(context.Background())
+			if err != nil {
+				t.Errorf("%s: claimAsSoleInstance() error = %v", dc, err)
+				return
+			}
+			if claimed {
+				mu.Lock()
+				winners = append(winners, dc)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(winners) != 1 {
+		t.Fatalf("winners = %v, want exactly one", winners)
+	}
+	if got := etcd.current().Datacenter; got != winners[0] {
+		t.Errorf("active datacenter = %q, want the winner %q", got, winners[0])
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestGetStatus(t *testing.T) {
+	activatedAt := time.Now().Add(-time.Hour).UTC()
+	lastHeartbeat := time.Now().Add(-5 * time.Second).UTC()
+
+	tests := []struct {
+		name          string
+		active        *model.ActiveDatacenter
+		pingErr       error
+		readErr       error
+		amDrained     bool
+		wantActive    string
+		wantConnected bool
+	}{
+		{
+			name: "active datacenter",
+			active: &model.ActiveDatacenter{
+				Datacenter:    "dc2",
+				Region:        "us",
+				ActivatedAt:   activatedAt,
+				ActivatedBy:   "api",
+				LastHeartbeat: lastHeartbeat,
+			},
+			amDrained:     true,
+			wantActive:    "dc2",
+			wantConnected: true,
+		},
+		{
+			name:          "no active datacenter",
+			wantConnected: true,
+		},
+		{
+			name:    "etcd unreachable",
+			pingErr: errors.New("connection refused"),
+			readErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(tt.active)
+			etcd.pingErr = tt.pingErr
+			etcd.readErr = tt.readErr
+			svc, _ := newTestService(t, newMockNomadRepo(nil), etcd, testServiceOptions{
+				heartbeat: config.HeartbeatConfig{UpdateInterval: 10 * time.Second, StaleThreshold: 30 * time.Second},
+			})
+			svc.setAmDrained(tt.amDrained)
+
+			status, err := svc.GetStatus(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status.MyDatacenter != "dc1" || status.AmDrained != tt.amDrained {
+				t.Errorf("my datacenter/drained = %s/%v, want dc1/%v", status.MyDatacenter, status.AmDrained, tt.amDrained)
+			}
+			if status.HeartbeatInterval != 10000 || status.StaleThreshold != 30000 {
+				t.Errorf("interval/threshold = %d/%d ms, want 10000/30000", status.HeartbeatInterval, status.StaleThreshold)
+			}
+			if status.EtcdConnected != tt.wantConnected {
+				t.Errorf("EtcdConnected = %v, want %v", status.EtcdConnected, tt.wantConnected)
+			}
+			if status.ActiveDatacenter != tt.wantActive {
+				t.Errorf("ActiveDatacenter = %q, want %q", status.ActiveDatacenter, tt.wantActive)
+			}
+
+			if tt.active == nil {
+				if !status.LastHeartbeat.IsZero() || status.HeartbeatAge != 0 || status.ActivatedBy != "" {
+					t.Errorf("heartbeat fields set without an active datacenter: %+v", status)
+				}
+				return
+			}
+			if !slices.Equal(status.ActiveDatacenters, []string{"dc2"}) {
+				t.Errorf("ActiveDatacenters = %v, want [dc2]", status.ActiveDatacenters)
+			}
+			if !status.ActivatedAt.Equal(activatedAt) || status.ActivatedBy != "api" || !status.LastHeartbeat.Equal(lastHeartbeat) {
+				t.Errorf("activation fields = %v/%s/%v, want %v/api/%v", status.ActivatedAt, status.ActivatedBy, status.LastHeartbeat, activatedAt, lastHeartbeat)
+			}
+			if status.HeartbeatAge < 5000 || status.HeartbeatAge > 60000 {
+				t.Errorf("HeartbeatAge = %d ms, want about 5000", status.HeartbeatAge)
+			}
+		})
+	}
+}
+
+func TestHealthSnapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		pingErr      error
+		clusters     map[string]*mockCluster
+		wantEtcd     bool
+		wantClusters map[string]bool
+		wantReady    bool
+	}{
+		{
+			name: "etcd connected and leaders elected",
+			clusters: map[string]*mockCluster{
+				"dc1": {region: "eu", hasLeader: true},
+				"dc2": {region: "eu", hasLeader: true},
+			},
+			wantEtcd:     true,
+			wantClusters: map[string]bool{"dc1": true, "dc2": true},
+			wantReady:    true,
+		},
+		{
+			name: "one cluster down is still ready",
+			clusters: map[string]*mockCluster{
+				"dc1": {region: "eu", hasLeader: true},
+				"dc2": {region: "eu", leaderErr: errors.New("connection refused")},
+			},
+			wantEtcd:     true,
+			wantClusters: map[string]bool{"dc1": true, "dc2": false},
+			wantReady:    true,
+		},
+		{
+			name: "all clusters down",
+			clusters: map[string]*mockCluster{
+				"dc1": {region: "eu"},
+				"dc2": {region: "eu", leaderErr: errors.New("connection refused")},
+			},
+			wantEtcd:     true,
+			wantClusters: map[string]bool{"dc1": false, "dc2": false},
+		},
+		{
+			name:         "etcd unreachable",
+			pingErr:      errors.New("connection refused"),
+			clusters:     map[string]*mockCluster{"dc1": {region: "eu", hasLeader: true}},
+			wantClusters: map[string]bool{"dc1": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcd := newMockEtcdRepo(nil)
+			etcd.pingErr = tt.pingErr
+			svc, _ := newTestService(t, newMockNomadRepo(tt.clusters), etcd, testServiceOptions{})
+
+			got := svc.HealthSnapshot(context.Background())
+
+			if got.EtcdConnected != tt.wantEtcd {
+				t.Errorf("etcd connected = %v, want %v", got.EtcdConnected, tt.wantEtcd)
+			}
+			healthy := 0
+			for name, want := range tt.wantClusters {
+				if got.Clusters[name] != want {
+					t.Errorf("cluster %s healthy = %v, want %v", name, got.Clusters[name], want)
+				}
+				if want {
+					healthy++
+				}
+			}
+			if got.HealthyClusters != healthy {
+				t.Errorf("healthy clusters = %d, want %d", got.HealthyClusters, healthy)
+			}
+			if got.IsReady() != tt.wantReady {
+				t.Errorf("IsReady() = %v, want %v", got.IsReady(), tt.wantReady)
+			}
+		})
+	}
+}
+
+func TestGetStatusCacheStats(t *testing.T) {
+	repo := newMockNomadRepo(map[string]*mockCluster{
+		"dc1": {region: "eu", nodes: testNodes("dc1", 1, false)},
+		"dc2": {region: "eu", nodes: testNodes("dc2", 1, false)},
+	})
+	svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{})
+	ctx := context.Background()
+
+	// Two misses fill the cache, the repeated read of dc1 is a hit
+	for _, dc := range []string{"dc1", "dc2", "dc1"} {
+		if _, err := svc.GetNodes(ctx, dc); err != nil {
+			t.Fatalf("GetNodes(%s) error = %v", dc, err)
+		}
+	}
+
+	status, err := svc.GetStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	want := model.CacheStats{Hits: 1, Misses: 2, Items: 2}
+	if status.Cache != want {
+		t.Errorf("cache stats = %+v, want %+v", status.Cache, want)
+	}
+}
+
+// Run with -race: activations and the heartbeat loop both record whether my nodes are drained
+// while the API reads it for the status
+func TestGetStatusDuringActivations(t *testing.T) {
+	repo := newMockNomadRepo(activationClusters())
+	etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+	svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+		heartbeat: config.HeartbeatConfig{UpdateInterval: time.Millisecond, MaxFailures: 3},
+	})
+
+	svc.StartHeartbeat(context.Background())
+	defer func() {
+		svc.StopHeartbeat()
+		<-svc.heartbeatDone
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, dc := range []string{"dc3", "dc1", "dc2", "dc1"} {
+			_, _ = svc.ActivateDatacenter(context.Background(), dc, false, false, nil)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if _, err := svc.GetStatus(context.Background()); err != nil {
+			t.Fatalf("GetStatus() error = %v", err)
+		}
+	}
+}
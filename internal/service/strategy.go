@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// strategyKey is the context key of a per-request activation strategy
+type strategyKey struct{}
+
+// WithActivationStrategy returns a context under which activations undrain nodes with strategy
+// instead of the configured one. The strategy must pass config.ValidateActivationStrategy.
+func WithActivationStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, strategyKey{}, strategy)
+}
+
+// activationStrategy returns the strategy of ctx, falling back to the configured one
+func (s *datacenterService) activationStrategy(ctx context.Context) string {
+	if strategy, ok := ctx.Value(strategyKey{}).(string); ok && strategy != "" {
+		return strategy
+	}
+	return s.activationCfg.Strategy
+}
+
+// undrainStages describes how the staged strategy undrains the nodes of a cluster
+type undrainStages struct {
+	batchSize  int
+	pause      time.Duration
+	afterBatch func(ctx context.Context) // Called after every batch but the last, before the pause
+}
+
+// undrainStages returns the stages to undrain clusterName with, or nil when its nodes are changed all at once.
+// Between batches job evaluations are triggered, so the scheduler places allocations on the undrained nodes
+// before more nodes become eligible; evaluation errors are recorded in result.
+func (s *datacenterService) undrainStages(ctx context.Context, clusterName, region string, drain bool, result *model.ActivationResult) *undrainStages {
+	if drain || s.activationStrategy(ctx) != config.ActivationStrategyStaged {
+		return nil
+	}
+
+	return &undrainStages{
+		batchSize: s.activationCfg.BatchSize,
+		pause:     s.activationCfg.BatchPause,
+		afterBatch: func(ctx context.Context) {
+			if err := s.repo.TriggerJobEvaluations(ctx, clusterName); err != nil && !isContextError(err) {
+				errMsg := fmt.Sprintf("failed to trigger job evaluations for %s: %v", clusterName, err)
+				result.AddClusterError(clusterName, region, errMsg)
+				s.logger.Warn("failed to trigger job evaluations between undrain batches",
+					slog.String("cluster", clusterName),
+					slog.String("error", err.Error()),
+				)
+			}
+		},
+	}
+}
+
+// applyInStages runs fn on every item in parallel like concurrent.ParallelMapWithLimit.
+// With stages, items are processed in batches of stages.batchSize, waiting stages.pause between batches.
+// Items not started because ctx was cancelled during a pause report ctx.Err().
+func applyInStages[T any, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T) (R, error), maxConcurrent int, stages *undrainStages) []concurrent.Result[R] {
+	if stages == nil || stages.batchSize <= 0 || len(items) <= stages.batchSize {
+		return concurrent.ParallelMapWithLimit(ctx, items, fn, maxConcurrent)
+	}
+
+	results := make([]concurrent.Result[R], 0, len(items))
+	for start := 0; start < len(items); start += stages.batchSize {
+		if start > 0 {
+			if stages.afterBatch != nil {
+				stages.afterBatch(ctx)
+			}
+			select {
+			case <-time.After(stages.pause):
+			case <-ctx.Done():
+				for i := start; i < len(items); i++ {
+					results = append(results, concurrent.Result[R]{Error: ctx.Err(), Index: i})
+				}
+				return results
+			}
+		}
+
+		end := min(start+stages.batchSize, len(items))
+		for _, r := range concurrent.ParallelMapWithLimit(ctx, items[start:end], fn, maxConcurrent) {
+			r.Index += start
+			results = append(results, r)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestApplyInStages(t *testing.T) {
+	const pause = 30 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		items       int
+		stages      *undrainStages
+		wantBatches int
+	}{
+		{name: "no stages", items: 5, wantBatches: 1},
+		{name: "batches of two", items: 5, stages: &undrainStages{batchSize: 2, pause: pause}, wantBatches: 3},
+		{name: "batch as large as the items", items: 5, stages: &undrainStages{batchSize: 5, pause: pause}, wantBatches: 1},
+		{name: "zero batch size", items: 5, stages: &undrainStages{pause: pause}, wantBatches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.items)
+			for i := range items {
+				items[i] = i
+			}
+			afterBatches := 0
+			if tt.stages != nil {
+				tt.stages.afterBatch = func(context.Context) { afterBatches++ }
+			}
+
+			start := time.Now()
+			results := applyInStages(context.Background(), items, func(_ context.Context, item int) (int, error) {
+				return item * 10, nil
+			}, 4, tt.stages)
+			elapsed := time.Since(start)
+
+			if len(results) != tt.items {
+				t.Fatalf("got %d results, want %d", len(results), tt.items)
+			}
+			for i, r := range results {
+				if r.Index != i || r.Value != i*10 || r.Error != nil {
+					t.Errorf("result %d = %+v, want index %d value %d", i, r, i, i*10)
+				}
+			}
+			if afterBatches != tt.wantBatches-1 {
+				t.Errorf("afterBatch called %d times, want %d", afterBatches, tt.wantBatches-1)
+			}
+			if wantPause := time.Duration(tt.wantBatches-1) * pause; elapsed < wantPause {
+				t.Errorf("took %v, want at least %v of pauses", elapsed, wantPause)
+			}
+		})
+	}
+}
+
+func TestApplyInStagesCancelledDuringPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stages := &undrainStages{batchSize: 2, pause: time.Hour, afterBatch: func(context.Context) { cancel() }}
+	results := applyInStages(ctx, []int{0, 1, 2, 3, 4}, func(_ context.Context, item int) (int, error) {
+		return item, nil
+	}, 4, stages)
+
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+	for i, r := range results {
+		wantErr := error(nil)
+		if i >= 2 {
+			wantErr = context.Canceled
+		}
+		if r.Index != i || !errors.Is(r.Error, wantErr) {
+			t.Errorf("result %d = %+v, want index %d with error %v", i, r, i, wantErr)
+		}
+	}
+}
+
+func TestStagedActivation(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string // Configured strategy
+		override  string // Per-request strategy
+		wantEvals []int  // Evaluations of dc3 before each of its undrains, sorted
+	}{
+		{name: "immediate", strategy: config.ActivationStrategyImmediate, wantEvals: []int{0, 0, 0, 0, 0}},
+		{name: "staged", strategy: config.ActivationStrategyStaged, wantEvals: []int{0, 0, 1, 1, 2}},
+		{name: "staged per request", strategy: config.ActivationStrategyImmediate, override: config.ActivationStrategyStaged, wantEvals: []int{0, 0, 1, 1, 2}},
+		{name: "immediate per request", strategy: config.ActivationStrategyStaged, override: config.ActivationStrategyImmediate, wantEvals: []int{0, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const pause = 30 * time.Millisecond
+			clusters := activationClusters()
+			clusters["dc3"].nodes = testNodes("dc3", 5, true)
+			repo := newMockNomadRepo(clusters)
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"})
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+				activation: config.ActivationConfig{Strategy: tt.strategy, BatchSize: 2, BatchPause: pause},
+			})
+
+			// Called with the repository locked, so the evaluations can be read directly
+			var evals []int
+			var first, last time.Time
+			repo.onDrain = func(call drainCall) {
+				if call.cluster != "dc3" || call.drain {
+					return
+				}
+				evals = append(evals, countOf(repo.evaluations, "dc3"))
+				if first.IsZero() {
+					first = time.Now()
+				}
+				last = time.Now()
+			}
+
+			ctx := context.Background()
+			if tt.override != "" {
+				ctx = WithActivationStrategy(ctx, tt.override)
+			}
+			result, err := svc.ActivateDatacenter(ctx, "dc3", false, false, nil)
+			if err != nil {
+				t.Fatalf("ActivateDatacenter() error = %v", err)
+			}
+			if result.UnDrainedNodes != 5 {
+				t.Errorf("undrained %d nodes, want 5", result.UnDrainedNodes)
+			}
+
+			slices.Sort(evals)
+			if !slices.Equal(evals, tt.wantEvals) {
+				t.Errorf("evaluations before each undrain = %v, want %v", evals, tt.wantEvals)
+			}
+			batches := tt.wantEvals[len(tt.wantEvals)-1] + 1
+			if wantPause := time.Duration(batches-1) * pause; last.Sub(first) < wantPause {
+				t.Errorf("undrains spread over %v, want at least %v of pauses", last.Sub(first), wantPause)
+			}
+		})
+	}
+}
+
+// countOf returns how often value occurs in values
+func countOf(values []string, value string) int {
+	count := 0
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// namedNodes returns testNodes with a distinct name per node, as StuckNodes reports names
+func namedNodes(datacenter string, count int, drained bool) []model.Node {
+	nodes := testNodes(datacenter, count, drained)
+	for i := range nodes {
+		nodes[i].Name = nodes[i].ID + "-name"
+	}
+	return nodes
+}
+
+func TestActivationReportsStuckNodes(t *testing.T) {
+	// stuckOn keeps allocations running on the given nodes, the others are empty
+	stuckOn := func(nodeIDs ...string) func(nodeID string, poll int) ([]model.Allocation, error) {
+		return func(nodeID string, _ int) ([]model.Allocation, error) {
+			if !slices.Contains(nodeIDs, nodeID) {
+				return nil, nil
+			}
+			return []model.Allocation{{ID: nodeID + "-a1", JobType: "service", ClientStatus: "running"}}, nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		wait      bool
+		dryRun    bool
+		ignoreSys bool
+		allocs    func(nodeID string, poll int) ([]model.Allocation, error)
+		wantStuck []string
+		wantPolls bool // Allocations of the drained nodes are listed
+	}{
+		{
+			name:   "not requested",
+			allocs: stuckOn("dc1-n1", "dc1-n2"),
+		},
+		{
+			name:   "dry run",
+			wait:   true,
+			dryRun: true,
+			allocs: stuckOn("dc1-n1", "dc1-n2"),
+		},
+		{
+			name:      "allocations leave",
+			wait:      true,
+			allocs:    drainingAllocs(3),
+			wantPolls: true,
+		},
+		{
+			name:      "lingering allocations",
+			wait:      true,
+			allocs:    stuckOn("dc1-n2"),
+			wantStuck: []string{"dc1/dc1-n2-name"},
+			wantPolls: true,
+		},
+		{
+			name:      "all nodes stuck",
+			wait:      true,
+			allocs:    stuckOn("dc1-n1", "dc1-n2"),
+			wantStuck: []string{"dc1/dc1-n1-name", "dc1/dc1-n2-name"},
+			wantPolls: true,
+		},
+		{
+			name: "unlisted allocations count as stuck",
+			wait: true,
+			allocs: func(nodeID string, _ int) ([]model.Allocation, error) {
+				if nodeID == "dc1-n1" {
+					return nil, errors.New("nomad unavailable")
+				}
+				return nil, nil
+			},
+			wantStuck: []string{"dc1/dc1-n1-name"},
+			wantPolls: true,
+		},
+		{
+			name:      "system jobs ignored when drains leave them",
+			wait:      true,
+			ignoreSys: true,
+			allocs: func(nodeID string, _ int) ([]model.Allocation, error) {
+				return []model.Allocation{{ID: nodeID + "-sys", JobType: "system", ClientStatus: "running"}}, nil
+			},
+			wantPolls: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "us", nodes: namedNodes("dc1", 2, false), hasLeader: true},
+				"dc2": {region: "eu", nodes: namedNodes("dc2", 1, true), hasLeader: true},
+			})
+			repo.onListAllocations = tt.allocs
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{
+				heartbeat: config.HeartbeatConfig{
+					DrainCompleteTimeout: 50 * time.Millisecond,
+					DrainPollInterval:    5 * time.Millisecond,
+				},
+				drain: config.DrainConfig{IgnoreSystemJobs: tt.ignoreSys},
+			})
+
+			ctx := context.Background()
+			if tt.wait {
+				ctx = WithDrainWait(ctx)
+			}
+			result, err := svc.ActivateDatacenter(ctx, "dc2", tt.dryRun, false, nil)
+			if err != nil {
+				t.Fatalf("ActivateDatacenter() error = %v", err)
+			}
+
+			if !slices.Equal(result.StuckNodes, tt.wantStuck) {
+				t.Errorf("StuckNodes = %v, want %v", result.StuckNodes, tt.wantStuck)
+			}
+
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			if polled := repo.allocPolls["dc1-n1"] > 0; polled != tt.wantPolls {
+				t.Errorf("allocations of drained nodes listed %v, want %v", polled, tt.wantPolls)
+			}
+			// Only the nodes the activation drained are checked
+			if polls := repo.allocPolls["dc2-n1"]; polls != 0 {
+				t.Errorf("activated node polled %d times, want 0", polls)
+			}
+		})
+	}
+}
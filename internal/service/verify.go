@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/concurrent"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+// VerifyActivation compares the live state of every cluster with the state an activation of dc
+// leads to: the target's nodes eligible, datacenters in other regions (and in the same region when
+// exclusive is true, outside region-wide mode) drained with no active allocations left. Disabled
+// clusters are expected unchanged. Node and allocation state is read from Nomad directly, bypassing the cache.
+func (s *datacenterService) VerifyActivation(ctx context.Context, dc string, exclusive bool) (*model.ActivationVerification, error) {
+	targetRegion, err := s.repo.GetClusterRegion(dc)
+	if err != nil {
+		return nil, fmt.Errorf("target datacenter: %w", err)
+	}
+
+	clusterNames := s.repo.GetClusterNames()
+	results := concurrent.ParallelMap(ctx, clusterNames, func(ctx context.Context, clusterName string) (model.ClusterVerification, error) {
+		return s.verifyCluster(ctx, clusterName, dc, targetRegion, exclusive), nil
+	})
+
+	report := &model.ActivationVerification{
+		Datacenter: dc,
+		Exclusive:  exclusive,
+		Converged:  true,
+		Clusters:   make([]model.ClusterVerification, 0, len(results)),
+	}
+	for _, result := range results {
+		report.Clusters = append(report.Clusters, result.Value)
+		if !result.Value.Converged {
+			report.Converged = false
+		}
+	}
+
+	s.logger.Info("verified activation",
+		slog.String("datacenter", dc),
+		slog.Bool("exclusive", exclusive),
+		slog.Bool("converged", report.Converged),
+	)
+
+	return report, nil
+}
+
+// verifyCluster checks one cluster against the state expected after activating targetDC
+func (s *datacenterService) verifyCluster(ctx context.Context, clusterName, targetDC, targetRegion string, exclusive bool) model.ClusterVerification {
+	cv := model.ClusterVerification{Cluster: clusterName}
+
+	region, err := s.repo.GetClusterRegion(clusterName)
+	if err != nil {
+		cv.Error = err.Error()
+		return cv
+	}
+	cv.Region = region
+
+	// Activation skips disabled clusters, and in region-wide mode the target's siblings serve with it
+	switch {
+	case clusterName == targetDC:
+		cv.Expected = model.ExpectedEligible
+	case !s.repo.IsClusterEnabled(clusterName):
+		cv.Expected = model.ExpectedUnchanged
+	case region == targetRegion && (!exclusive || s.activeMode == config.ActiveModeRegionWide):
+		cv.Expected = model.ExpectedUnchanged
+	default:
+		cv.Expected = model.ExpectedDrained
+	}
+
+	nodes, err := s.repo.ListNodes(ctx, clusterName)
+	if err != nil {
+		cv.Error = fmt.Sprintf("failed to list nodes: %v", err)
+		return cv
+	}
+	cv.NodesTotal = len(nodes)
+
+	if cv.Expected == model.ExpectedUnchanged {
+		cv.NodesMatching = len(nodes)
+		cv.Converged = true
+		return cv
+	}
+
+	expected := model.NodeState{Drain: false, SchedulingEligibility: "eligible"}
+	if cv.Expected == model.ExpectedDrained {
+		expected = model.NodeState{Drain: true, SchedulingEligibility: "ineligible"}
+	}
+
+	for _, node := range nodes {
+		// The drain flag clears once a drain completes, the node stays ineligible
+		if node.IsReady() == (cv.Expected == model.ExpectedEligible) {
+			cv.NodesMatching++
+			continue
+		}
+		cv.MismatchedNodes = append(cv.MismatchedNodes, model.NodeMismatch{
+			NodeID:   node.ID,
+			NodeName: node.Name,
+			Expected: expected,
+			Actual: model.NodeState{
+				Drain:                 node.Drain,
+				SchedulingEligibility: node.SchedulingEligibility,
+			},
+		})
+	}
+
+	active, err := s.countClusterAllocations(ctx, clusterName, nodes, cv.Expected == model.ExpectedDrained)
+	if err != nil {
+		cv.Error = err.Error()
+		return cv
+	}
+	cv.ActiveAllocations = active
+
+	// Drained clusters have converged only once their allocations have moved away
+	cv.Converged = len(cv.MismatchedNodes) == 0 && (cv.Expected != model.ExpectedDrained || active == 0)
+
+	return cv
+}
+
+// countClusterAllocations counts pending and running allocations on nodes of a cluster.
+// When drained is true, system jobs are skipped if the drain options leave them running.
+func (s *datacenterService) countClusterAllocations(ctx context.Context, clusterName string, nodes []model.Node, drained bool) (int, error) {
+	results := concurrent.ParallelMapWithLimit(ctx, nodes, func(ctx context.Context, node model.Node) (int, error) {
+		allocs, err := s.repo.ListNodeAllocations(ctx, clusterName, node.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		active := 0
+		for _, alloc := range allocs {
+			if !alloc.IsActive() {
+				continue
+			}
+			if drained && s.drainOpts.IgnoreSystemJobs && alloc.JobType == "system" {
+				continue
+			}
+			active++
+		}
+		return active, nil
+	}, s.maxConcurrentNodeOps)
+
+	total := 0
+	for i, result := range results {
+		if result.Error != nil {
+			return 0, fmt.Errorf("failed to list allocations for node %s: %w", nodes[i].ID, result.Error)
+		}
+		total += result.Value
+	}
+	return total, nil
+}
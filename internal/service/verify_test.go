@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/repository"
+)
+
+func TestVerifyActivation(t *testing.T) {
+	running := []model.Allocation{{ID: "a1", JobType: "service", ClientStatus: "running"}}
+
+	tests := []struct {
+		name          string
+		clusters      func() map[string]*mockCluster
+		dc            string
+		exclusive     bool
+		activeMode    string
+		wantErr       error
+		wantConverged bool
+		wantExpected  map[string]string   // cluster -> expected state
+		wantMismatch  map[string][]string // cluster -> mismatched node IDs
+		wantActive    map[string]int      // cluster -> active allocations
+	}{
+		{
+			name: "converged",
+			clusters: func() map[string]*mockCluster {
+				clusters := activationClusters()
+				clusters["dc1"].nodes = testNodes("dc1", 2, true)
+				clusters["dc3"].nodes = testNodes("dc3", 2, false)
+				clusters["dc3"].allocs = map[string][]model.Allocation{"dc3-n1": running}
+				return clusters
+			},
+			dc:            "dc3",
+			wantConverged: true,
+			wantExpected:  map[string]string{"dc1": model.ExpectedDrained, "dc2": model.ExpectedDrained, "dc3": model.ExpectedEligible},
+			wantActive:    map[string]int{"dc3": 1},
+		},
+		{
+			name: "drained cluster with an eligible node and live allocations",
+			clusters: func() map[string]*mockCluster {
+				clusters := activationClusters()
+				clusters["dc1"].nodes = testNodes("dc1", 2, true)
+				clusters["dc1"].nodes[1].Drain = false
+				clusters["dc1"].nodes[1].SchedulingEligibility = "eligible"
+				clusters["dc1"].allocs = map[string][]model.Allocation{"dc1-n2": running}
+				clusters["dc3"].nodes = testNodes("dc3", 2, false)
+				return clusters
+			},
+			dc:           "dc3",
+			wantExpected: map[string]string{"dc1": model.ExpectedDrained, "dc2": model.ExpectedDrained, "dc3": model.ExpectedEligible},
+			wantMismatch: map[string][]string{"dc1": {"dc1-n2"}},
+			wantActive:   map[string]int{"dc1": 1},
+		},
+		{
+			name:         "same region left unchanged",
+			clusters:     activationClusters,
+			dc:           "dc1",
+			wantExpected: map[string]string{"dc1": model.ExpectedEligible, "dc2": model.ExpectedUnchanged, "dc3": model.ExpectedDrained},
+			// dc2 keeps its drained nodes
+			wantConverged: true,
+		},
+		{
+			name:         "exclusive drains the same region",
+			clusters:     activationClusters,
+			dc:           "dc1",
+			exclusive:    true,
+			wantExpected: map[string]string{"dc1": model.ExpectedEligible, "dc2": model.ExpectedDrained, "dc3": model.ExpectedDrained},
+			// dc2 is drained already
+			wantConverged: true,
+		},
+		{
+			name:          "exclusive in region-wide mode keeps the region",
+			clusters:      activationClusters,
+			dc:            "dc1",
+			exclusive:     true,
+			activeMode:    config.ActiveModeRegionWide,
+			wantExpected:  map[string]string{"dc1": model.ExpectedEligible, "dc2": model.ExpectedUnchanged, "dc3": model.ExpectedDrained},
+			wantConverged: true,
+		},
+		{
+			name: "disabled cluster left unchanged",
+			clusters: func() map[string]*mockCluster {
+				clusters := activationClusters()
+				clusters["dc4"] = &mockCluster{region: "us", disabled: true, nodes: testNodes("dc4", 1, false), allocs: map[string][]model.Allocation{"dc4-n1": running}}
+				return clusters
+			},
+			dc:            "dc1",
+			wantExpected:  map[string]string{"dc1": model.ExpectedEligible, "dc2": model.ExpectedUnchanged, "dc3": model.ExpectedDrained, "dc4": model.ExpectedUnchanged},
+			wantConverged: true,
+		},
+		{
+			name:     "unknown datacenter",
+			clusters: activationClusters,
+			dc:       "dc9",
+			wantErr:  repository.ErrClusterNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(tt.clusters())
+			svc, _ := newTestService(t, repo, newMockEtcdRepo(nil), testServiceOptions{activeMode: tt.activeMode})
+
+			report, err := svc.VerifyActivation(context.Background(), tt.dc, tt.exclusive)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyActivation() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if report.Converged != tt.wantConverged {
+				t.Errorf("Converged = %v, want %v", report.Converged, tt.wantConverged)
+			}
+			if len(report.Clusters) != len(tt.wantExpected) {
+				t.Fatalf("clusters = %d, want %d", len(report.Clusters), len(tt.wantExpected))
+			}
+			for _, cv := range report.Clusters {
+				if cv.Error != "" {
+					t.Errorf("%s: Error = %q", cv.Cluster, cv.Error)
+				}
+				if cv.Expected != tt.wantExpected[cv.Cluster] {
+					t.Errorf("%s: Expected = %q, want %q", cv.Cluster, cv.Expected, tt.wantExpected[cv.Cluster])
+				}
+				if cv.ActiveAllocations != tt.wantActive[cv.Cluster] && cv.Expected != model.ExpectedUnchanged {
+					t.Errorf("%s: ActiveAllocations = %d, want %d", cv.Cluster, cv.ActiveAllocations, tt.wantActive[cv.Cluster])
+				}
+
+				wantMismatch := tt.wantMismatch[cv.Cluster]
+				if len(cv.MismatchedNodes) != len(wantMismatch) {
+					t.Fatalf("%s: MismatchedNodes = %+v, want %v", cv.Cluster, cv.MismatchedNodes, wantMismatch)
+				}
+				for i, mismatch := range cv.MismatchedNodes {
+					if mismatch.NodeID != wantMismatch[i] {
+						t.Errorf("%s: mismatched node = %s, want %s", cv.Cluster, mismatch.NodeID, wantMismatch[i])
+					}
+					if !mismatch.Expected.Drain || mismatch.Actual.Drain || mismatch.Actual.SchedulingEligibility != "eligible" {
+						t.Errorf("%s: mismatch = %+v, want drained expected, eligible actual", cv.Cluster, mismatch)
+					}
+				}
+				if cv.NodesMatching != cv.NodesTotal-len(wantMismatch) {
+					t.Errorf("%s: NodesMatching = %d, want %d", cv.Cluster, cv.NodesMatching, cv.NodesTotal-len(wantMismatch))
+				}
+				if converged := len(wantMismatch) == 0 && (cv.Expected != model.ExpectedDrained || tt.wantActive[cv.Cluster] == 0); cv.Converged != converged {
+					t.Errorf("%s: Converged = %v, want %v", cv.Cluster, cv.Converged, converged)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/config"
+	"github.com/kirychukyurii/webitel-dc-switcher/internal/model"
+)
+
+func TestHeartbeatLoopWatchesActiveDatacenter(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    time.Duration // Heartbeat interval; an hour leaves the watch as the only trigger
+		watchErr    error
+		update      *model.ActiveDatacenter // Sent through the watch when set
+		closeWatch  bool
+		wantDrained int
+		wantWatches int
+	}{
+		{
+			name:        "another datacenter activated",
+			interval:    time.Hour,
+			update:      &model.ActiveDatacenter{Datacenter: "dc3", Region: "us"},
+			wantDrained: 2,
+			wantWatches: 1,
+		},
+		{
+			name:        "my datacenter activated",
+			interval:    time.Hour,
+			update:      &model.ActiveDatacenter{Datacenter: "dc1", Region: "eu"},
+			wantWatches: 1,
+		},
+		{
+			name:        "closed watch is re-established on the next heartbeat",
+			interval:    10 * time.Millisecond,
+			closeWatch:  true,
+			wantWatches: 2,
+		},
+		{
+			name:        "heartbeat polling covers a failed watch",
+			interval:    10 * time.Millisecond,
+			watchErr:    errors.New("etcd unavailable"),
+			wantDrained: 2,
+			wantWatches: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, false), hasLeader: true},
+				"dc3": {region: "us", nodes: testNodes("dc3", 2, true), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(&model.ActiveDatacenter{Datacenter: "dc1", Region: "eu", LastHeartbeat: time.Now()})
+			etcd.watchErr = tt.watchErr
+			etcd.watchUpdates = make(chan *model.ActiveDatacenter)
+			if tt.watchErr != nil {
+				// Polling reads the record written by the other instance
+				etcd.store(&model.ActiveDatacenter{Datacenter: "dc3", Region: "us", LastHeartbeat: time.Now()})
+			}
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+				heartbeat: config.HeartbeatConfig{UpdateInterval: tt.interval, MaxFailures: 3},
+			})
+
+			svc.StartHeartbeat(context.Background())
+			defer func() {
+				svc.StopHeartbeat()
+				<-svc.heartbeatDone
+			}()
+
+			if tt.update != nil {
+				select {
+				case etcd.watchUpdates <- tt.update:
+				case <-time.After(2 * time.Second):
+					t.Fatal("heartbeat loop isn't reading the watch")
+				}
+			}
+			if tt.closeWatch {
+				close(etcd.watchUpdates)
+			}
+
+			deadline := time.After(2 * time.Second)
+			for len(repo.drained("dc1", true)) < tt.wantDrained || etcd.watchCount() < tt.wantWatches {
+				select {
+				case <-deadline:
+					t.Fatalf("drained %d nodes and started %d watches, want %d and %d",
+						len(repo.drained("dc1", true)), etcd.watchCount(), tt.wantDrained, tt.wantWatches)
+				case <-time.After(5 * time.Millisecond):
+				}
+			}
+
+			// Give an unexpected drain the time to happen
+			time.Sleep(20 * time.Millisecond)
+			if got := len(repo.drained("dc1", true)); got != tt.wantDrained {
+				t.Errorf("drained %d nodes, want %d", got, tt.wantDrained)
+			}
+		})
+	}
+}
+
+func TestHeartbeatLoopReclaimsExpiredActiveDatacenter(t *testing.T) {
+	tests := []struct {
+		name      string
+		drained   bool // My nodes were drained on purpose
+		wantClaim bool
+	}{
+		{name: "serving datacenter re-claims at once", wantClaim: true},
+		{name: "drained datacenter leaves the key missing", drained: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNomadRepo(map[string]*mockCluster{
+				"dc1": {region: "eu", nodes: testNodes("dc1", 2, tt.drained), hasLeader: true},
+			})
+			etcd := newMockEtcdRepo(nil) // The key expired with its lease
+			etcd.watchUpdates = make(chan *model.ActiveDatacenter)
+			svc, _ := newTestService(t, repo, etcd, testServiceOptions{
+				heartbeat: config.HeartbeatConfig{UpdateInterval: time.Hour, MaxFailures: 3},
+			})
+			svc.setAmDrained(tt.drained)
+
+			svc.StartHeartbeat(context.Background())
+			defer func() {
+				svc.StopHeartbeat()
+				<-svc.heartbeatDone
+			}()
+
+			// The expiry reaches the loop as a nil update, long before the next heartbeat; the watch
+			// forwards one update at a time, so the third send returns once the first was handled
+			for range 3 {
+				select {
+				case etcd.watchUpdates <- nil:
+				case <-time.After(2 * time.Second):
+					t.Fatal("heartbeat loop isn't reading the watch")
+				}
+			}
+
+			active := etcd.current()
+			if (active != nil) != tt.wantClaim {
+				t.Fatalf("active datacenter = %+v, want claimed %v", active, tt.wantClaim)
+			}
+			if active != nil && active.Datacenter != "dc1" {
+				t.Errorf("claimed datacenter = %q, want dc1", active.Datacenter)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Jitter randomizes interval by up to percent of its length in either direction.
+// The spread is uniform, so the average interval stays equal to the configured one.
+func Jitter(interval time.Duration, percent int) time.Duration {
+	if percent <= 0 || interval <= 0 {
+		return interval
+	}
+
+	spread := float64(interval) * float64(min(percent, 100)) / 100
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
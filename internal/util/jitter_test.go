@@ -0,0 +1,52 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	const samples = 10000
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		percent  int
+		wantMin  time.Duration
+		wantMax  time.Duration
+	}{
+		{name: "no jitter", interval: 10 * time.Second, percent: 0, wantMin: 10 * time.Second, wantMax: 10 * time.Second},
+		{name: "negative percent", interval: 10 * time.Second, percent: -5, wantMin: 10 * time.Second, wantMax: 10 * time.Second},
+		{name: "zero interval", interval: 0, percent: 20, wantMin: 0, wantMax: 0},
+		{name: "20 percent", interval: 10 * time.Second, percent: 20, wantMin: 8 * time.Second, wantMax: 12 * time.Second},
+		{name: "capped at 100 percent", interval: 10 * time.Second, percent: 150, wantMin: 0, wantMax: 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var total time.Duration
+			lowest, highest := tt.wantMax, tt.wantMin
+			for range samples {
+				got := Jitter(tt.interval, tt.percent)
+				if got < tt.wantMin || got > tt.wantMax {
+					t.Fatalf("Jitter(%v, %d) = %v, want within [%v, %v]", tt.interval, tt.percent, got, tt.wantMin, tt.wantMax)
+				}
+				total += got
+				lowest, highest = min(lowest, got), max(highest, got)
+			}
+
+			if tt.wantMin == tt.wantMax {
+				return
+			}
+			// The intervals spread over the band instead of clustering in one spot
+			band := tt.wantMax - tt.wantMin
+			if highest-lowest < band*9/10 {
+				t.Errorf("intervals spread over [%v, %v], want most of [%v, %v]", lowest, highest, tt.wantMin, tt.wantMax)
+			}
+			// The average stays at the configured interval, within 3% of it
+			if avg := total / samples; avg < tt.interval*97/100 || avg > tt.interval*103/100 {
+				t.Errorf("average interval = %v, want about %v", avg, tt.interval)
+			}
+		})
+	}
+}
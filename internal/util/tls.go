@@ -42,3 +42,38 @@ func LoadTLSConfig(cfg *config.TLSConfig) (*tls.Config, error) {
 
 	return tlsConfig, nil
 }
+
+// LoadServerTLSConfig loads the TLS configuration of the HTTP server.
+// When a CA is configured, clients must present a certificate signed by it.
+func LoadServerTLSConfig(cfg *config.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.CA != "" {
+		caCert, err := os.ReadFile(cfg.CA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to append CA certificate")
+		}
+
+		tlsConfig.ClientCAs = caPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
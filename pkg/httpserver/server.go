@@ -2,11 +2,10 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 )
 
@@ -16,63 +15,72 @@ type Server struct {
 	logger *slog.Logger
 }
 
-// New creates a new HTTP server
-func New(addr string, handler http.Handler, readTimeout, writeTimeout time.Duration, logger *slog.Logger) *Server {
+// Timeouts holds the http.Server timeouts; zero values follow the net/http defaults
+type Timeouts struct {
+	Read       time.Duration // Reading the whole request, including the body
+	ReadHeader time.Duration // Reading the request headers (zero falls back to Read)
+	Write      time.Duration // Writing the response
+	Idle       time.Duration // Waiting for the next request on a keep-alive connection (zero falls back to Read)
+}
+
+// New creates a new HTTP server. It serves HTTPS when tlsConfig is not nil.
+func New(addr string, handler http.Handler, timeouts Timeouts, tlsConfig *tls.Config, logger *slog.Logger) *Server {
 	return &Server{
 		server: &http.Server{
-			Addr:         addr,
-			Handler:      handler,
-			ReadTimeout:  readTimeout,
-			WriteTimeout: writeTimeout,
+			Addr:              addr,
+			Handler:           handler,
+			ReadTimeout:       timeouts.Read,
+			ReadHeaderTimeout: timeouts.ReadHeader,
+			WriteTimeout:      timeouts.Write,
+			IdleTimeout:       timeouts.Idle,
+			TLSConfig:         tlsConfig,
 		},
 		logger: logger,
 	}
 }
 
-// Run starts the HTTP server and handles graceful shutdown
-func (s *Server) Run() error {
-	// Channel to listen for interrupt signals
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-
-	// Channel to notify when server has shut down
+// Start serves requests in the background and returns immediately.
+// The returned channel receives the error that stopped the server; it is closed without
+// an error once the server stops because of Shutdown.
+func (s *Server) Start() <-chan error {
 	serverErrors := make(chan error, 1)
 
-	// Start server in a goroutine
 	go func() {
+		defer close(serverErrors)
+
 		s.logger.Info("starting http server",
 			slog.String("addr", s.server.Addr),
+			slog.Bool("tls", s.server.TLSConfig != nil),
 		)
-		serverErrors <- s.server.ListenAndServe()
-	}()
 
-	// Block until we receive a signal or an error
-	select {
-	case err := <-serverErrors:
-		if err != nil && err != http.ErrServerClosed {
-			return err
+		var err error
+		if s.server.TLSConfig != nil {
+			// Certificates come from TLSConfig
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
 		}
-	case sig := <-quit:
-		s.logger.Info("received shutdown signal",
-			slog.String("signal", sig.String()),
-		)
+		if !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- err
+		}
+	}()
 
-		// Create a context with timeout for graceful shutdown
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	return serverErrors
+}
 
-		// Attempt graceful shutdown
-		if err := s.server.Shutdown(ctx); err != nil {
-			s.logger.Error("graceful shutdown failed, forcing shutdown",
-				slog.String("error", err.Error()),
-			)
-			if err := s.server.Close(); err != nil {
-				return err
-			}
+// Shutdown stops accepting connections and waits for active requests to finish.
+// Connections still open when ctx is done are closed forcibly.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Error("graceful shutdown failed, forcing shutdown",
+			slog.String("error", err.Error()),
+		)
+		if err := s.server.Close(); err != nil {
+			return err
 		}
-
-		s.logger.Info("server stopped gracefully")
 	}
 
+	s.logger.Info("server stopped gracefully")
+
 	return nil
 }
@@ -0,0 +1,291 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a local address that nothing listens on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+// get requests url until the server answers or the deadline passes, and returns the body
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read the response: %v", err)
+			}
+			return string(body)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server never answered: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// hello answers every request with "hello"
+var hello = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	_, _ = io.WriteString(w, "hello")
+})
+
+func TestStartAndShutdown(t *testing.T) {
+	addr := freeAddr(t)
+	srv := New(addr, hello, Timeouts{}, nil, slog.New(slog.DiscardHandler))
+
+	serverErrors := srv.Start()
+	if body := get(t, http.DefaultClient, "http://"+addr); body != "hello" {
+		t.Errorf("body = %q, want hello", body)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case err, ok := <-serverErrors:
+		if ok {
+			t.Errorf("server stopped with %v, want the channel closed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server errors channel not closed after Shutdown")
+	}
+
+	if _, err := http.Get("http://" + addr); err == nil {
+		t.Error("server still answers after Shutdown")
+	}
+}
+
+func TestStartReportsListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	srv := New(ln.Addr().String(), hello, Timeouts{}, nil, slog.New(slog.DiscardHandler))
+
+	select {
+	case err := <-srv.Start():
+		if err == nil {
+			t.Error("server on a taken address stopped without an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server on a taken address didn't report an error")
+	}
+}
+
+func TestShutdownWaitsForRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+
+	addr := freeAddr(t)
+	srv := New(addr, slow, Timeouts{}, nil, slog.New(slog.DiscardHandler))
+	srv.Start()
+
+	// Wait for the listener, then send the request that keeps the server busy
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server never listened: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	bodies := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			bodies <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		bodies <- string(body)
+	}()
+	<-started
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- srv.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("Shutdown returned while a request was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if body := <-bodies; body != "done" {
+		t.Errorf("body = %q, want done", body)
+	}
+	if err := <-shutdownDone; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+// selfSignedCert returns a certificate for 127.0.0.1 and a pool trusting it
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dc-switcher test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestStartServesTLS(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+	addr := freeAddr(t)
+	srv := New(addr, hello, Timeouts{}, &tls.Config{Certificates: []tls.Certificate{cert}}, slog.New(slog.DiscardHandler))
+	srv.Start()
+	defer srv.Shutdown(context.Background())
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	defer client.CloseIdleConnections()
+
+	// The certificate comes from the TLS config, not from certificate files
+	if body := get(t, client, "https://"+addr); body != "hello" {
+		t.Errorf("body = %q, want hello", body)
+	}
+
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatalf("plain HTTP request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("plain HTTP status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestNewTimeouts(t *testing.T) {
+	timeouts := Timeouts{Read: time.Second, ReadHeader: 2 * time.Second, Write: 3 * time.Second, Idle: 4 * time.Second}
+	srv := New(":0", hello, timeouts, nil, slog.New(slog.DiscardHandler))
+
+	got := Timeouts{
+		Read:       srv.server.ReadTimeout,
+		ReadHeader: srv.server.ReadHeaderTimeout,
+		Write:      srv.server.WriteTimeout,
+		Idle:       srv.server.IdleTimeout,
+	}
+	if got != timeouts {
+		t.Errorf("server timeouts = %+v, want %+v", got, timeouts)
+	}
+}
+
+func TestReadHeaderTimeoutClosesSlowClients(t *testing.T) {
+	addr := freeAddr(t)
+	srv := New(addr, hello, Timeouts{ReadHeader: 100 * time.Millisecond}, nil, slog.New(slog.DiscardHandler))
+	srv.Start()
+	defer srv.Shutdown(context.Background())
+	get(t, http.DefaultClient, "http://"+addr)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Send part of the headers and stall, like a slowloris client
+	if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\n"); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	start := time.Now()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _ = io.ReadAll(conn)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("connection closed after %v, want about the header timeout", elapsed)
+	}
+}
+
+func TestIdleTimeoutClosesKeepAliveConnections(t *testing.T) {
+	addr := freeAddr(t)
+	srv := New(addr, hello, Timeouts{Idle: 100 * time.Millisecond}, nil, slog.New(slog.DiscardHandler))
+	srv.Start()
+	defer srv.Shutdown(context.Background())
+	get(t, http.DefaultClient, "http://"+addr)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\n\r\n"); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	// The response arrives, then the idle connection is closed
+	start := time.Now()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	response, _ := io.ReadAll(conn)
+	if !strings.HasSuffix(string(response), "hello") {
+		t.Errorf("response = %q, want the hello body", response)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("idle connection closed after %v, want about the idle timeout", elapsed)
+	}
+}